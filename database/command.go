@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MigrateUsage describes the arguments of MigrateCommand
+const MigrateUsage = `usage: migrate <command>
+  up           apply every pending migration
+  down [N]     take back the last N migrations, 1 by default
+  goto V       migrate up or down to version V, 0 taking back every migration
+  force V      set the version to V without migrating, after a failed migration was fixed by hand
+  status       list the migrations and the version of the database`
+
+// ErrUsage is returned by MigrateCommand for arguments it does not understand
+var ErrUsage = errors.New(MigrateUsage)
+
+// MigrateCommand runs the migrate subcommand of the binary with the arguments following it, e.g. ["goto", "5"]
+func MigrateCommand(ctx context.Context, m *Migrator, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch command, rest := args[0], args[1:]; {
+	case command == "up" && len(rest) == 0:
+		return m.Up(ctx)
+	case command == "down" && len(rest) <= 1:
+		steps := 1
+		if len(rest) == 1 {
+			var err error
+			if steps, err = strconv.Atoi(rest[0]); err != nil || steps < 1 {
+				return ErrUsage
+			}
+		}
+		return m.Down(ctx, steps)
+	case (command == "goto" || command == "force") && len(rest) == 1:
+		version, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil || version < 0 {
+			return ErrUsage
+		}
+		if command == "force" {
+			return m.Force(ctx, version)
+		}
+		return m.Goto(ctx, version)
+	case command == "status" && len(rest) == 0:
+		statuses, version, dirty, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(w, "%d %-40s %s\n", status.Version, status.Name, state)
+		}
+		if dirty {
+			fmt.Fprintf(w, "version %d, dirty\n", version)
+		} else {
+			fmt.Fprintf(w, "version %d\n", version)
+		}
+		return nil
+	}
+	return ErrUsage
+}
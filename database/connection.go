@@ -1,26 +1,71 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
 )
 
-func Connect() (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASS"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
+// MemoryDriver is the DB_DRIVER running the app with no database, see Memory
+const MemoryDriver = "memory"
+
+// Memory tells whether DB_DRIVER asks for no database: brands, products and orders are then kept in memory by
+// the repositories and the rest of the data is in an in-memory SQLite, both gone when the app stops
+func Memory() bool {
+	return strings.EqualFold(os.Getenv("DB_DRIVER"), MemoryDriver)
+}
+
+// Connect opens the database of DB_DRIVER, mysql by default, postgres, sqlite3 or memory. SQLite takes the
+// path of its file from DB_NAME.
+func Connect() (*sql.DB, dialect.Dialect, error) {
+	if Memory() {
+		return connectMemory()
+	}
+
+	dbDialect, err := dialect.Get(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dsn string
+	switch dbDialect {
+	case dialect.Postgres:
+		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			os.Getenv("DB_HOST"),
+			os.Getenv("DB_PORT"),
+			os.Getenv("DB_USER"),
+			os.Getenv("DB_PASS"),
+			os.Getenv("DB_NAME"),
+			getEnv("DB_SSLMODE", "disable"),
+		)
+	case dialect.SQLite:
+		dsn = SQLiteDSN(os.Getenv("DB_NAME"))
+	default:
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
+			os.Getenv("DB_USER"),
+			os.Getenv("DB_PASS"),
+			os.Getenv("DB_HOST"),
+			os.Getenv("DB_PORT"),
+			os.Getenv("DB_NAME"),
+		)
+	}
 
-	db, err := sql.Open("mysql", dsn)
+	db, err := sql.Open(dbDialect.Name(), dsn)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if dbDialect == dialect.SQLite {
+		//one writer at a time, the others wait for the lock instead of failing with "database is locked"
+		db.SetMaxOpenConns(1)
 	}
 
 	err = db.Ping()
@@ -28,5 +73,33 @@ func Connect() (*sql.DB, error) {
 		log.Fatalln(err)
 	}
 
-	return db, nil
+	return db, dbDialect, nil
+}
+
+func connectMemory() (*sql.DB, dialect.Dialect, error) {
+	db, err := sql.Open(dialect.SQLite.Name(), SQLiteDSN(":memory:"))
+	if err != nil {
+		return nil, nil, err
+	}
+	//every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+
+	if err = NewMigrator(db, dialect.SQLite).Up(context.Background()); err != nil {
+		return nil, nil, err
+	}
+
+	return db, dialect.SQLite, nil
+}
+
+// SQLiteDSN opens the file at path with foreign keys enforced and transactions taking the write lock when
+// they begin, which stands in for SELECT ... FOR UPDATE
+func SQLiteDSN(path string) string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on&_txlock=immediate&_busy_timeout=5000", path)
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
@@ -0,0 +1,80 @@
+// Package databasetest runs a repository test suite against every database the repositories support: an
+// in-memory SQLite always, PostgreSQL and MySQL when POSTGRES_TEST_DSN and MYSQL_TEST_DSN name a database
+// that can be wiped, e.g. "host=localhost user=test dbname=test sslmode=disable" and "test:test@tcp(localhost)/test".
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+)
+
+// Suite gets a database with every migration applied and nothing else in it
+type Suite func(t *testing.T, db *sql.DB, dialect dialect.Dialect)
+
+// Run runs suite once per database, each as a subtest named after the driver
+func Run(t *testing.T, suite Suite) {
+	targets := []struct {
+		dialect dialect.Dialect
+		dsn     string
+	}{
+		{dialect.SQLite, database.SQLiteDSN(":memory:")},
+		{dialect.Postgres, os.Getenv("POSTGRES_TEST_DSN")},
+		{dialect.MySQL, mysqlDSN(os.Getenv("MYSQL_TEST_DSN"))},
+	}
+
+	for _, target := range targets {
+		target := target
+		t.Run(target.dialect.Name(), func(t *testing.T) {
+			if target.dsn == "" {
+				t.Skipf("no %s database to test against", target.dialect.Name())
+			}
+			db := open(t, target.dialect, target.dsn)
+			defer db.Close()
+			suite(t, db, target.dialect)
+		})
+	}
+}
+
+func mysqlDSN(dsn string) string {
+	if dsn == "" || strings.Contains(dsn, "parseTime") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&parseTime=true"
+	}
+	return dsn + "?parseTime=true"
+}
+
+func open(t *testing.T, d dialect.Dialect, dsn string) *sql.DB {
+	db, err := sql.Open(d.Name(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == dialect.SQLite {
+		//every connection to :memory: is a database of its own
+		db.SetMaxOpenConns(1)
+	}
+
+	migrations, err := database.Migrations(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//a database kept from an earlier run is taken down first, errors are from what is already gone
+	ctx := context.Background()
+	for i := len(migrations) - 1; i >= 0; i-- {
+		database.Exec(ctx, db, migrations[i].Down)
+	}
+	db.Exec(`DROP TABLE IF EXISTS schema_migrations`)
+
+	if err = database.NewMigrator(db, d).Up(ctx); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	return db
+}
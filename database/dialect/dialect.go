@@ -0,0 +1,310 @@
+// Package dialect holds what differs between the SQL of the databases the repositories run on. Queries are
+// written once with ? placeholders and portable SQL, the dialect fills in the rest.
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Execer is satisfied by *sql.DB and *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type Dialect interface {
+	// Name is the database/sql driver name
+	Name() string
+	// Migrations is the directory of database/migrations holding the migrations written for the database
+	Migrations() string
+	// Rebind turns the ? placeholders of query into the ones of the database
+	Rebind(query string) string
+	// Insert runs an INSERT written with ? placeholders and returns the id of the new row
+	Insert(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error)
+	// InsertIgnore turns an INSERT into one skipping the rows that conflict with a unique key
+	InsertIgnore(query string) string
+	// ForUpdate is appended to a SELECT to lock the rows it reads until the transaction ends
+	ForUpdate() string
+	// AddDays is the date of expr moved by days
+	AddDays(expr string, days int) string
+	// AddSeconds is the datetime of expr moved by seconds
+	AddSeconds(expr string, seconds int) string
+	// Lock waits for the lock keeping other processes from migrating the database, held by conn until Unlock
+	Lock(ctx context.Context, conn *sql.Conn) error
+	Unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+// ErrLocked is returned by Lock when another process held the lock for too long
+var ErrLocked = errors.New("the database is being migrated by another process")
+
+var (
+	// LockTimeout is how long Lock waits for another process to release the lock
+	LockTimeout = 60 * time.Second
+	// LockRetry is the wait between two tries of a database that can't wait for a lock by itself
+	LockRetry = time.Second
+)
+
+const (
+	MySQLName    = "mysql"
+	PostgresName = "postgres"
+	SQLiteName   = "sqlite3"
+)
+
+var (
+	MySQL    Dialect = mysql{}
+	Postgres Dialect = postgres{}
+	SQLite   Dialect = sqlite{}
+)
+
+// Get returns the dialect of a driver name, "postgresql" and "sqlite" being accepted too
+func Get(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", MySQLName:
+		return MySQL, nil
+	case PostgresName, "postgresql":
+		return Postgres, nil
+	case SQLiteName, "sqlite":
+		return SQLite, nil
+	}
+	return nil, fmt.Errorf("unknown database driver %q, use %s, %s or %s", name, MySQLName, PostgresName, SQLiteName)
+}
+
+type mysql struct{}
+
+func (mysql) Name() string {
+	return MySQLName
+}
+
+func (mysql) Migrations() string {
+	return "mysql"
+}
+
+func (mysql) Rebind(query string) string {
+	return query
+}
+
+func (mysql) Insert(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error) {
+	return lastInsertId(ctx, db, query, args...)
+}
+
+func (mysql) InsertIgnore(query string) string {
+	return replaceInsert(query, "INSERT IGNORE")
+}
+
+func (mysql) ForUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (mysql) AddDays(expr string, days int) string {
+	return fmt.Sprintf("DATE_ADD(%s, INTERVAL %d DAY)", expr, days)
+}
+
+func (mysql) AddSeconds(expr string, seconds int) string {
+	return fmt.Sprintf("DATE_ADD(%s, INTERVAL %d SECOND)", expr, seconds)
+}
+
+// Lock uses a named lock of the database the connection is on
+func (mysql) Lock(ctx context.Context, conn *sql.Conn) error {
+	var locked sql.NullInt64
+	err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), ?)`, int(LockTimeout.Seconds())).Scan(&locked)
+	if err != nil {
+		return err
+	}
+	if locked.Int64 != 1 {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (mysql) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))`)
+	return err
+}
+
+type postgres struct{}
+
+func (postgres) Name() string {
+	return PostgresName
+}
+
+func (postgres) Migrations() string {
+	return "postgres"
+}
+
+// Rebind numbers the placeholders $1, $2 and so on, leaving the ? in string literals alone
+func (postgres) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	quoted := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Insert asks for the id with RETURNING, the driver has no LastInsertId
+func (p postgres) Insert(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, p.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgres) InsertIgnore(query string) string {
+	return query + " ON CONFLICT DO NOTHING"
+}
+
+func (postgres) ForUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (postgres) AddDays(expr string, days int) string {
+	return fmt.Sprintf("(CAST(%s AS DATE) + %d)", expr, days)
+}
+
+func (postgres) AddSeconds(expr string, seconds int) string {
+	return fmt.Sprintf("(%s + INTERVAL '%d seconds')", expr, seconds)
+}
+
+// Lock uses an advisory lock keyed by the name of the database, tried every LockRetry until LockTimeout
+// passed like GET_LOCK of mysql
+func (postgres) Lock(ctx context.Context, conn *sql.Conn) error {
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		var locked bool
+		err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext(current_database() || '.schema_migrations'))`).Scan(&locked)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().Add(LockRetry).After(deadline) {
+			return ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(LockRetry):
+		}
+	}
+}
+
+func (postgres) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext(current_database() || '.schema_migrations'))`)
+	return err
+}
+
+type sqlite struct{}
+
+func (sqlite) Name() string {
+	return SQLiteName
+}
+
+func (sqlite) Migrations() string {
+	return "sqlite"
+}
+
+// sqliteKeywords are the tables named after a keyword SQLite does not take as a name unless it is quoted
+var sqliteKeywords = map[string]bool{"transaction": true}
+
+// Rebind quotes the tables in sqliteKeywords, leaving string literals alone
+func (sqlite) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	quoted := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		if c == '\'' {
+			quoted = !quoted
+		}
+		if quoted || !isWordStart(c) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(query) && (isWordStart(query[end]) || (query[end] >= '0' && query[end] <= '9')) {
+			end++
+		}
+		word := query[i:end]
+		if sqliteKeywords[strings.ToLower(word)] && (i == 0 || query[i-1] != '"') {
+			word = `"` + word + `"`
+		}
+		b.WriteString(word)
+		i = end
+	}
+	return b.String()
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (s sqlite) Insert(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error) {
+	return lastInsertId(ctx, db, s.Rebind(query), args...)
+}
+
+func (sqlite) InsertIgnore(query string) string {
+	return replaceInsert(query, "INSERT OR IGNORE")
+}
+
+// ForUpdate is empty, SQLite has no row locks and Connect opens it with _txlock=immediate so a transaction
+// holds the write lock of the database from its start
+func (sqlite) ForUpdate() string {
+	return ""
+}
+
+func (sqlite) AddDays(expr string, days int) string {
+	return fmt.Sprintf("DATE(%s, '%+d day')", expr, days)
+}
+
+func (sqlite) AddSeconds(expr string, seconds int) string {
+	return fmt.Sprintf("DATETIME(%s, '%+d seconds')", expr, seconds)
+}
+
+// Lock opens a transaction holding the write lock of the database, the migrations run in it on conn and
+// Unlock commits them
+func (sqlite) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`)
+	return err
+}
+
+func (sqlite) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `COMMIT`)
+	return err
+}
+
+func lastInsertId(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func replaceInsert(query string, insert string) string {
+	trimmed := strings.TrimLeft(query, " \t\n")
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "INSERT") {
+		return query
+	}
+	return insert + trimmed[6:]
+}
@@ -0,0 +1,80 @@
+package dialect_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+)
+
+func TestRebind(t *testing.T) {
+	query := `UPDATE transaction SET status = ?, reason = 'why?' WHERE transaction.id = ? AND transactionNumber = ?`
+
+	assert.Equal(t, query, dialect.MySQL.Rebind(query))
+	assert.Equal(t, `UPDATE transaction SET status = $1, reason = 'why?' WHERE transaction.id = $2 AND transactionNumber = $3`, dialect.Postgres.Rebind(query))
+	assert.Equal(t, `UPDATE "transaction" SET status = ?, reason = 'why?' WHERE "transaction".id = ? AND transactionNumber = ?`, dialect.SQLite.Rebind(query))
+}
+
+func TestInsertIgnore(t *testing.T) {
+	query := "INSERT INTO product_category (productId, categoryId) VALUES (?,?)"
+
+	assert.Equal(t, "INSERT IGNORE INTO product_category (productId, categoryId) VALUES (?,?)", dialect.MySQL.InsertIgnore(query))
+	assert.Equal(t, query+" ON CONFLICT DO NOTHING", dialect.Postgres.InsertIgnore(query))
+	assert.Equal(t, "INSERT OR IGNORE INTO product_category (productId, categoryId) VALUES (?,?)", dialect.SQLite.InsertIgnore(query))
+}
+
+func TestAddSeconds(t *testing.T) {
+	assert.Equal(t, "DATE_ADD(CURRENT_TIMESTAMP, INTERVAL -90 SECOND)", dialect.MySQL.AddSeconds("CURRENT_TIMESTAMP", -90))
+	assert.Equal(t, "(CURRENT_TIMESTAMP + INTERVAL '-90 seconds')", dialect.Postgres.AddSeconds("CURRENT_TIMESTAMP", -90))
+	assert.Equal(t, "DATETIME(CURRENT_TIMESTAMP, '-90 seconds')", dialect.SQLite.AddSeconds("CURRENT_TIMESTAMP", -90))
+}
+
+func TestGet(t *testing.T) {
+	for name, expected := range map[string]dialect.Dialect{"": dialect.MySQL, "postgresql": dialect.Postgres, "sqlite": dialect.SQLite} {
+		d, err := dialect.Get(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, d)
+	}
+
+	_, err := dialect.Get("oracle")
+	assert.NotNil(t, err)
+}
+
+func TestPostgresLock(t *testing.T) {
+	timeout, retry := dialect.LockTimeout, dialect.LockRetry
+	dialect.LockTimeout, dialect.LockRetry = 50*time.Millisecond, 10*time.Millisecond
+	defer func() { dialect.LockTimeout, dialect.LockRetry = timeout, retry }()
+
+	postgres, _ := dialect.Get("postgres")
+	lock := `SELECT pg_try_advisory_lock\(hashtext\(current_database\(\) \|\| '.schema_migrations'\)\)`
+
+	t.Run("Test Postgres Lock Retries Until Released", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectQuery(lock).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+		mock.ExpectQuery(lock).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+
+		conn, _ := db.Conn(context.TODO())
+		err := postgres.Lock(context.TODO(), conn)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Postgres Lock Gives Up", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		for i := 0; i < 5; i++ {
+			mock.ExpectQuery(lock).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+		}
+
+		conn, _ := db.Conn(context.TODO())
+		err := postgres.Lock(context.TODO(), conn)
+
+		assert.Equal(t, dialect.ErrLocked, err)
+	})
+}
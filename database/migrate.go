@@ -0,0 +1,302 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/migrations"
+)
+
+// Migration is the pair of files of one version, as paths of migrations.FS
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and whether the database has it
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// ErrDirty is returned when the last migration failed halfway, the database has to be fixed by hand and its
+// version set with Force before migrating again
+var ErrDirty = errors.New("the last migration failed halfway, fix the database and force its version")
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// Migrations lists the migrations of the dialect in the order they apply
+func Migrations(d dialect.Dialect) ([]Migration, error) {
+	ups, err := fs.Glob(migrations.FS, path.Join(d.Migrations(), "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ups)
+
+	list := make([]Migration, len(ups))
+	for i, up := range ups {
+		match := migrationFile.FindStringSubmatch(path.Base(up))
+		if match == nil {
+			return nil, fmt.Errorf("%s is not named <version>_<name>.up.sql", up)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = Migration{Version: version, Name: match[2], Up: up, Down: strings.TrimSuffix(up, ".up.sql") + ".down.sql"}
+	}
+	return list, nil
+}
+
+var statementEnd = regexp.MustCompile(`;\s*(\n|$)`)
+
+// Statements splits a migration into its statements, which end with a ; at the end of a line
+func Statements(sql string) []string {
+	var statements []string
+	for _, statement := range statementEnd.Split(sql, -1) {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// Exec runs every statement of a migration file of migrations.FS
+func Exec(ctx context.Context, db dialect.Execer, file string) error {
+	data, err := migrations.FS.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	for _, statement := range Statements(string(data)) {
+		if _, err = db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%s: %w", path.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// Migrator applies the embedded migrations of its dialect. The version of the database is kept in
+// schema_migrations the way golang-migrate keeps it, so a database migrated with either is known to the other.
+type Migrator struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+	// Log is told about every migration run, it may be nil
+	Log func(format string, args ...interface{})
+}
+
+func NewMigrator(db *sql.DB, dialect dialect.Dialect) *Migrator {
+	return &Migrator{DB: db, Dialect: dialect}
+}
+
+// Up applies every migration the database does not have
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(current int64, list []Migration) (int64, error) {
+		if len(list) == 0 {
+			return current, nil
+		}
+		return list[len(list)-1].Version, nil
+	})
+}
+
+// Down takes back the last steps migrations
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.run(ctx, func(current int64, list []Migration) (int64, error) {
+		i := indexOf(list, current) - steps
+		if i < 0 {
+			return 0, nil
+		}
+		return list[i].Version, nil
+	})
+}
+
+// Goto migrates up or down to version, 0 taking back every migration
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	return m.run(ctx, func(current int64, list []Migration) (int64, error) {
+		if version != 0 && indexOf(list, version) < 0 {
+			return 0, fmt.Errorf("there is no migration %d", version)
+		}
+		return version, nil
+	})
+}
+
+// Force sets the version of the database without running anything and clears the dirty flag, after a failed
+// migration was fixed by hand
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	list, err := Migrations(m.Dialect)
+	if err != nil {
+		return err
+	}
+	if version != 0 && indexOf(list, version) < 0 {
+		return fmt.Errorf("there is no migration %d", version)
+	}
+
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// Status lists every migration with whether the database has it, and the version of the database
+func (m *Migrator) Status(ctx context.Context) (statuses []MigrationStatus, version int64, dirty bool, err error) {
+	list, err := Migrations(m.Dialect)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	err = m.locked(ctx, func(conn *sql.Conn) error {
+		version, dirty, err = m.version(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	for _, migration := range list {
+		statuses = append(statuses, MigrationStatus{Migration: migration, Applied: migration.Version <= version})
+	}
+	return statuses, version, dirty, nil
+}
+
+// run migrates from the current version to the one target picks, one migration at a time. Every step is
+// recorded dirty before it runs and clean after, a failure leaves the database dirty at that step.
+func (m *Migrator) run(ctx context.Context, target func(current int64, list []Migration) (int64, error)) error {
+	list, err := Migrations(m.Dialect)
+	if err != nil {
+		return err
+	}
+
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("version %d: %w", current, ErrDirty)
+		}
+		if current != 0 && indexOf(list, current) < 0 {
+			return fmt.Errorf("the database is at version %d, which has no migration", current)
+		}
+
+		to, err := target(current, list)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range list {
+			if migration.Version <= current || migration.Version > to {
+				continue
+			}
+			if err = m.step(ctx, conn, migration, true, migration.Version); err != nil {
+				return err
+			}
+		}
+
+		for i := len(list) - 1; i >= 0; i-- {
+			migration := list[i]
+			if migration.Version > current || migration.Version <= to {
+				continue
+			}
+			var previous int64
+			if i > 0 {
+				previous = list[i-1].Version
+			}
+			if err = m.step(ctx, conn, migration, false, previous); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// step runs the up or down file of the migration, leaving the database at version
+func (m *Migrator) step(ctx context.Context, conn *sql.Conn, migration Migration, up bool, version int64) error {
+	file, direction := migration.Down, "down"
+	if up {
+		file, direction = migration.Up, "up"
+	}
+	if m.Log != nil {
+		m.Log("%d_%s %s", migration.Version, migration.Name, direction)
+	}
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if err := Exec(ctx, conn, file); err != nil {
+		return err
+	}
+	return m.setVersion(ctx, conn, version, false)
+}
+
+// locked runs fn on a connection holding the migration lock, with schema_migrations created
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err = m.Dialect.Lock(ctx, conn); err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`)
+	if err == nil {
+		err = fn(conn)
+	}
+
+	if unlockErr := m.Dialect.Unlock(ctx, conn); err == nil {
+		err = unlockErr
+	}
+	return err
+}
+
+// version is 0 when no migration was applied, golang-migrate writes -1 when the first one failed going down
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if version < 0 {
+		version = 0
+	}
+	return version, dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	if _, err := conn.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version == 0 && !dirty {
+		return nil
+	}
+	if version == 0 {
+		version = -1
+	}
+	_, err := conn.ExecContext(ctx, m.Dialect.Rebind(`INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)`), version, dirty)
+	return err
+}
+
+func indexOf(list []Migration, version int64) int {
+	for i, migration := range list {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
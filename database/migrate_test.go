@@ -0,0 +1,168 @@
+package database_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+)
+
+func newMigrator(t *testing.T) (*database.Migrator, []database.Migration) {
+	db, err := sql.Open("sqlite3", database.SQLiteDSN(":memory:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := database.Migrations(dialect.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return database.NewMigrator(db, dialect.SQLite), migrations
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestMigrations(t *testing.T) {
+	migrations, err := database.Migrations(dialect.SQLite)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20221006103444), migrations[0].Version)
+	assert.Equal(t, "create_table_brand", migrations[0].Name)
+	assert.Equal(t, "sqlite/20221006103444_create_table_brand.down.sql", migrations[0].Down)
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+}
+
+func TestStatements(t *testing.T) {
+	statements := database.Statements("CREATE TABLE a (b TEXT DEFAULT ';');\n\nDROP TABLE c;\n")
+	assert.Equal(t, []string{"CREATE TABLE a (b TEXT DEFAULT ';')", "DROP TABLE c"}, statements)
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("up then down", func(t *testing.T) {
+		m, migrations := newMigrator(t)
+		last := migrations[len(migrations)-1].Version
+
+		assert.Nil(t, m.Up(ctx))
+		statuses, version, dirty, err := m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, last, version)
+		assert.False(t, dirty)
+		assert.Len(t, statuses, len(migrations))
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+		}
+		assert.True(t, tableExists(t, m.DB, "brand"))
+
+		//up again has nothing to do
+		assert.Nil(t, m.Up(ctx))
+
+		assert.Nil(t, m.Down(ctx, 1))
+		statuses, version, _, err = m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, migrations[len(migrations)-2].Version, version)
+		assert.False(t, statuses[len(statuses)-1].Applied)
+
+		assert.Nil(t, m.Down(ctx, len(migrations)))
+		_, version, _, err = m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), version)
+		assert.False(t, tableExists(t, m.DB, "brand"))
+		assert.False(t, tableExists(t, m.DB, "product"))
+		assert.False(t, tableExists(t, m.DB, "transaction"))
+		assert.False(t, tableExists(t, m.DB, "transaction_detail"))
+	})
+
+	t.Run("goto", func(t *testing.T) {
+		m, migrations := newMigrator(t)
+
+		assert.Nil(t, m.Goto(ctx, migrations[1].Version))
+		assert.True(t, tableExists(t, m.DB, "product"))
+		assert.False(t, tableExists(t, m.DB, "transaction"))
+
+		assert.Nil(t, m.Goto(ctx, migrations[3].Version))
+		assert.True(t, tableExists(t, m.DB, "transaction_detail"))
+
+		assert.Nil(t, m.Goto(ctx, migrations[0].Version))
+		assert.True(t, tableExists(t, m.DB, "brand"))
+		assert.False(t, tableExists(t, m.DB, "product"))
+
+		assert.Nil(t, m.Goto(ctx, 0))
+		assert.False(t, tableExists(t, m.DB, "brand"))
+
+		assert.NotNil(t, m.Goto(ctx, 42))
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		m, migrations := newMigrator(t)
+
+		assert.Nil(t, m.Goto(ctx, migrations[1].Version))
+		_, err := m.DB.Exec(`UPDATE schema_migrations SET dirty = 1`)
+		assert.Nil(t, err)
+
+		err = m.Up(ctx)
+		assert.True(t, errors.Is(err, database.ErrDirty))
+
+		_, version, dirty, err := m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, migrations[1].Version, version)
+		assert.True(t, dirty)
+
+		assert.Nil(t, m.Force(ctx, migrations[1].Version))
+		assert.Nil(t, m.Up(ctx))
+		assert.True(t, tableExists(t, m.DB, "transaction_detail"))
+	})
+
+	t.Run("failed first migration down", func(t *testing.T) {
+		m, migrations := newMigrator(t)
+
+		//golang-migrate records the version before the first migration as -1
+		assert.Nil(t, m.Goto(ctx, migrations[0].Version))
+		_, err := m.DB.Exec(`UPDATE schema_migrations SET version = -1`)
+		assert.Nil(t, err)
+
+		_, version, dirty, err := m.Status(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), version)
+		assert.False(t, dirty)
+	})
+}
+
+func TestMigrateCommand(t *testing.T) {
+	ctx := context.Background()
+	m, migrations := newMigrator(t)
+
+	var out bytes.Buffer
+	assert.Nil(t, database.MigrateCommand(ctx, m, []string{"up"}, &out))
+	assert.Nil(t, database.MigrateCommand(ctx, m, []string{"down", "2"}, &out))
+	assert.Nil(t, database.MigrateCommand(ctx, m, []string{"status"}, &out))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, len(migrations)+1)
+	assert.Contains(t, lines[0], "create_table_brand")
+	assert.True(t, strings.HasSuffix(lines[0], "applied"))
+	assert.True(t, strings.HasSuffix(lines[len(lines)-2], "pending"))
+	assert.Equal(t, "version 20261019", lines[len(lines)-1][:16])
+
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"goto"}, {"goto", "x"}, {"force", "-1"}, {"up", "1"}} {
+		assert.Equal(t, database.ErrUsage, database.MigrateCommand(ctx, m, args, &out))
+	}
+}
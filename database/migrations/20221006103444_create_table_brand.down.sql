@@ -1 +0,0 @@
-DROP table IF EXISTS brand;
\ No newline at end of file
@@ -0,0 +1,8 @@
+// Package migrations embeds the migrations of every database the repositories run on, in a directory per
+// dialect named by dialect.Dialect.Migrations
+package migrations
+
+import "embed"
+
+//go:embed mysql postgres sqlite
+var FS embed.FS
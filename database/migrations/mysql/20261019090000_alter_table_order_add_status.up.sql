@@ -0,0 +1,5 @@
+ALTER TABLE transaction
+  ADD COLUMN status varchar(30) NOT NULL DEFAULT 'PENDING',
+  ADD COLUMN cancelReason varchar(255) NULL DEFAULT NULL,
+  ADD COLUMN cancelledBy varchar(100) NULL DEFAULT NULL,
+  ADD COLUMN cancelledAt datetime(0) NULL DEFAULT NULL;
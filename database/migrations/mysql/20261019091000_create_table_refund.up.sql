@@ -0,0 +1,18 @@
+CREATE TABLE refund  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  transactionId int(11) NOT NULL,
+  amount double NOT NULL DEFAULT 0,
+  reason varchar(255) NOT NULL,
+  createdBy varchar(100) NOT NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB;
+
+CREATE TABLE refund_detail  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  refundId int(11) NOT NULL,
+  transactionDetailId int(11) NOT NULL,
+  qty int(11) NOT NULL,
+  amount double NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB;
@@ -0,0 +1,13 @@
+CREATE TABLE payment  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  transactionId int(11) NOT NULL,
+  provider varchar(50) NOT NULL,
+  providerReference varchar(100) NULL DEFAULT NULL,
+  amount double NOT NULL DEFAULT 0,
+  refundedAmount double NOT NULL DEFAULT 0,
+  status varchar(30) NOT NULL,
+  errorMessage varchar(255) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB;
@@ -0,0 +1,11 @@
+CREATE TABLE payment_webhook_event  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  provider varchar(50) NOT NULL,
+  eventId varchar(100) NULL DEFAULT NULL,
+  payload text NOT NULL,
+  status varchar(30) NOT NULL,
+  errorMessage varchar(255) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_payment_webhook_event_provider_event (provider, eventId)
+) ENGINE = InnoDB;
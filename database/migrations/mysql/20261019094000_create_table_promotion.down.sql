@@ -0,0 +1,9 @@
+ALTER TABLE transaction_detail
+  DROP COLUMN discount;
+
+ALTER TABLE transaction
+  DROP COLUMN subtotal,
+  DROP COLUMN discountTotal;
+
+DROP TABLE IF EXISTS transaction_discount;
+DROP TABLE IF EXISTS promotion;
@@ -0,0 +1,41 @@
+CREATE TABLE promotion  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  code varchar(50) NOT NULL,
+  title varchar(100) NOT NULL,
+  type varchar(20) NOT NULL,
+  value double NOT NULL DEFAULT 0,
+  minOrderValue double NOT NULL DEFAULT 0,
+  brandId int(11) NULL DEFAULT NULL,
+  productId int(11) NULL DEFAULT NULL,
+  startAt datetime(0) NOT NULL,
+  endAt datetime(0) NULL DEFAULT NULL,
+  usageLimit int(11) NOT NULL DEFAULT 0,
+  usageCount int(11) NOT NULL DEFAULT 0,
+  perCustomerLimit int(11) NOT NULL DEFAULT 0,
+  stackable tinyint(1) NOT NULL DEFAULT 0,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_promotion_code (code)
+) ENGINE = InnoDB;
+
+CREATE TABLE transaction_discount  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  transactionId int(11) NOT NULL,
+  promotionId int(11) NOT NULL,
+  code varchar(50) NOT NULL,
+  customer varchar(100) NOT NULL,
+  amount double NOT NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_transaction_discount_promotion_customer (promotionId, customer)
+) ENGINE = InnoDB;
+
+ALTER TABLE transaction
+  ADD COLUMN subtotal double NOT NULL DEFAULT 0,
+  ADD COLUMN discountTotal double NOT NULL DEFAULT 0;
+
+UPDATE transaction SET subtotal = totalTransaction;
+
+ALTER TABLE transaction_detail
+  ADD COLUMN discount double NOT NULL DEFAULT 0;
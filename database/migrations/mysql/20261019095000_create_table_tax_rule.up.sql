@@ -0,0 +1,31 @@
+CREATE TABLE tax_rule  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  name varchar(50) NOT NULL,
+  rate double NOT NULL,
+  inclusive tinyint(1) NOT NULL DEFAULT 0,
+  brandId int(11) NULL DEFAULT NULL,
+  productId int(11) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB;
+
+CREATE TABLE transaction_tax  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  transactionId int(11) NOT NULL,
+  taxRuleId int(11) NOT NULL,
+  name varchar(50) NOT NULL,
+  rate double NOT NULL,
+  inclusive tinyint(1) NOT NULL DEFAULT 0,
+  amount double NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_transaction_tax_transaction (transactionId)
+) ENGINE = InnoDB;
+
+ALTER TABLE transaction
+  ADD COLUMN taxTotal double NOT NULL DEFAULT 0,
+  ADD COLUMN taxExclusive double NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_detail
+  ADD COLUMN tax double NOT NULL DEFAULT 0,
+  ADD COLUMN taxExclusive double NOT NULL DEFAULT 0;
@@ -0,0 +1,12 @@
+ALTER TABLE transaction
+  DROP COLUMN shippingMethod,
+  DROP COLUMN shippingRegion,
+  DROP COLUMN shippingCost;
+
+DROP TABLE IF EXISTS shipping_rate;
+
+ALTER TABLE product
+  DROP COLUMN weight,
+  DROP COLUMN length,
+  DROP COLUMN width,
+  DROP COLUMN height;
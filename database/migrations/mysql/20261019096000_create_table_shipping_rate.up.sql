@@ -0,0 +1,24 @@
+ALTER TABLE product
+  ADD COLUMN weight int(11) NOT NULL DEFAULT 0,
+  ADD COLUMN length int(11) NOT NULL DEFAULT 0,
+  ADD COLUMN width int(11) NOT NULL DEFAULT 0,
+  ADD COLUMN height int(11) NOT NULL DEFAULT 0;
+
+CREATE TABLE shipping_rate  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  region varchar(50) NOT NULL,
+  method varchar(50) NOT NULL,
+  minWeight int(11) NOT NULL DEFAULT 0,
+  maxWeight int(11) NOT NULL DEFAULT 0,
+  cost double NOT NULL,
+  estimatedDays int(11) NOT NULL DEFAULT 0,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_shipping_rate_region (region, method, minWeight)
+) ENGINE = InnoDB;
+
+ALTER TABLE transaction
+  ADD COLUMN shippingMethod varchar(50) NULL DEFAULT NULL,
+  ADD COLUMN shippingRegion varchar(50) NULL DEFAULT NULL,
+  ADD COLUMN shippingCost double NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS shipment_detail;
+DROP TABLE IF EXISTS shipment;
@@ -0,0 +1,24 @@
+CREATE TABLE shipment  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  transactionId int(11) NOT NULL,
+  carrier varchar(50) NOT NULL,
+  trackingNumber varchar(100) NOT NULL,
+  status varchar(30) NOT NULL DEFAULT 'SHIPPED',
+  createdBy varchar(100) NOT NULL,
+  shippedAt datetime(0) NOT NULL,
+  deliveredAt datetime(0) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_shipment_transaction (transactionId)
+) ENGINE = InnoDB;
+
+CREATE TABLE shipment_detail  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  shipmentId int(11) NOT NULL,
+  transactionDetailId int(11) NOT NULL,
+  qty int(11) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_shipment_detail_shipment (shipmentId),
+  INDEX idx_shipment_detail_transaction_detail (transactionDetailId)
+) ENGINE = InnoDB;
@@ -0,0 +1,28 @@
+CREATE TABLE category  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  parentId int(11) NULL DEFAULT NULL,
+  title varchar(100) NOT NULL,
+  slug varchar(100) NOT NULL,
+  sortOrder int(11) NOT NULL DEFAULT 0,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_category_slug (slug),
+  INDEX idx_category_parent (parentId)
+) ENGINE = InnoDB;
+
+-- closure table, one row for every ancestor of a category including itself (depth 0)
+CREATE TABLE category_path  (
+  ancestorId int(11) NOT NULL,
+  descendantId int(11) NOT NULL,
+  depth int(11) NOT NULL,
+  PRIMARY KEY (ancestorId, descendantId),
+  INDEX idx_category_path_descendant (descendantId)
+) ENGINE = InnoDB;
+
+CREATE TABLE product_category  (
+  productId int(11) NOT NULL,
+  categoryId int(11) NOT NULL,
+  PRIMARY KEY (productId, categoryId),
+  INDEX idx_product_category_category (categoryId)
+) ENGINE = InnoDB;
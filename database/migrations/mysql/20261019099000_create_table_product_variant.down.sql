@@ -0,0 +1,7 @@
+ALTER TABLE transaction_detail
+  DROP COLUMN variantId,
+  DROP COLUMN sku;
+
+DROP TABLE IF EXISTS product_variant_option;
+DROP TABLE IF EXISTS product_variant;
+DROP TABLE IF EXISTS product_option;
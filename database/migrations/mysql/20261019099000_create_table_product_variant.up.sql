@@ -0,0 +1,32 @@
+CREATE TABLE product_option  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  productId int(11) NOT NULL,
+  name varchar(30) NOT NULL,
+  sortOrder int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_product_option_name (productId, name)
+) ENGINE = InnoDB;
+
+CREATE TABLE product_variant  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  productId int(11) NOT NULL,
+  sku varchar(64) NOT NULL,
+  price double NULL DEFAULT NULL,
+  stock int(11) NOT NULL DEFAULT 0,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_product_variant_sku (sku),
+  INDEX idx_product_variant_product (productId)
+) ENGINE = InnoDB;
+
+CREATE TABLE product_variant_option  (
+  variantId int(11) NOT NULL,
+  optionId int(11) NOT NULL,
+  value varchar(50) NOT NULL,
+  PRIMARY KEY (variantId, optionId)
+) ENGINE = InnoDB;
+
+ALTER TABLE transaction_detail
+  ADD COLUMN variantId int(11) NULL DEFAULT NULL,
+  ADD COLUMN sku varchar(64) NULL DEFAULT NULL;
@@ -0,0 +1,14 @@
+-- storageKey is the blob store prefix, the original and its thumbnails are stored under it
+CREATE TABLE product_image  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  productId int(11) NOT NULL,
+  storageKey varchar(255) NOT NULL,
+  contentType varchar(50) NOT NULL,
+  size int(11) NOT NULL,
+  width int(11) NOT NULL,
+  height int(11) NOT NULL,
+  sortOrder int(11) NOT NULL DEFAULT 0,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_product_image_product (productId, sortOrder)
+) ENGINE = InnoDB;
@@ -0,0 +1,33 @@
+ALTER TABLE transaction_discount
+  DROP INDEX idx_transaction_discount_transaction;
+
+ALTER TABLE refund_detail
+  DROP INDEX idx_refund_detail_refund,
+  DROP INDEX idx_refund_detail_transaction_detail;
+
+ALTER TABLE refund
+  DROP INDEX idx_refund_transaction;
+
+ALTER TABLE transaction
+  DROP INDEX idx_transaction_status_created,
+  DROP INDEX idx_transaction_created;
+
+ALTER TABLE transaction_detail
+  DROP FOREIGN KEY fk_transaction_detail_transaction,
+  DROP FOREIGN KEY fk_transaction_detail_product;
+
+ALTER TABLE transaction_detail
+  DROP INDEX idx_transaction_detail_transaction,
+  DROP INDEX idx_transaction_detail_product;
+
+ALTER TABLE product
+  DROP FOREIGN KEY fk_product_brand;
+
+ALTER TABLE product
+  DROP INDEX idx_product_brand,
+  DROP INDEX idx_product_title,
+  MODIFY createdAt datetime(0) NOT NULL ON UPDATE CURRENT_TIMESTAMP(0);
+
+ALTER TABLE brand
+  DROP INDEX uq_brand_title,
+  MODIFY createdAt datetime(0) NOT NULL ON UPDATE CURRENT_TIMESTAMP(0);
@@ -0,0 +1,29 @@
+ALTER TABLE brand
+  MODIFY createdAt datetime(0) NOT NULL,
+  ADD UNIQUE INDEX uq_brand_title (title);
+
+ALTER TABLE product
+  MODIFY createdAt datetime(0) NOT NULL,
+  ADD INDEX idx_product_brand (brandId),
+  ADD INDEX idx_product_title (title),
+  ADD CONSTRAINT fk_product_brand FOREIGN KEY (brandId) REFERENCES brand (id) ON DELETE RESTRICT;
+
+ALTER TABLE transaction_detail
+  ADD INDEX idx_transaction_detail_transaction (transactionId),
+  ADD INDEX idx_transaction_detail_product (productId),
+  ADD CONSTRAINT fk_transaction_detail_transaction FOREIGN KEY (transactionId) REFERENCES transaction (id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_transaction_detail_product FOREIGN KEY (productId) REFERENCES product (id) ON DELETE RESTRICT;
+
+ALTER TABLE transaction
+  ADD INDEX idx_transaction_status_created (status, createdAt),
+  ADD INDEX idx_transaction_created (createdAt);
+
+ALTER TABLE refund
+  ADD INDEX idx_refund_transaction (transactionId);
+
+ALTER TABLE refund_detail
+  ADD INDEX idx_refund_detail_refund (refundId),
+  ADD INDEX idx_refund_detail_transaction_detail (transactionDetailId);
+
+ALTER TABLE transaction_discount
+  ADD INDEX idx_transaction_discount_transaction (transactionId);
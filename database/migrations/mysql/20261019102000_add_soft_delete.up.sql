@@ -0,0 +1,5 @@
+ALTER TABLE brand
+  ADD COLUMN deletedAt datetime(0) NULL DEFAULT NULL;
+
+ALTER TABLE product
+  ADD COLUMN deletedAt datetime(0) NULL DEFAULT NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE audit_log  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  actor varchar(100) NOT NULL,
+  action varchar(20) NOT NULL,
+  entityType varchar(30) NOT NULL,
+  entityId int(11) NOT NULL,
+  oldValues text NULL,
+  newValues text NULL,
+  requestId varchar(64) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_audit_log_entity (entityType, entityId, createdAt),
+  INDEX idx_audit_log_created (createdAt)
+) ENGINE = InnoDB;
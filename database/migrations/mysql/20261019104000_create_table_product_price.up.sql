@@ -0,0 +1,17 @@
+-- every price of a product: the price it was created with and the prices scheduled after, an endAt limits a
+-- price to a period like a flash sale
+CREATE TABLE product_price  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  productId int(11) NOT NULL,
+  price double(10, 2) NOT NULL,
+  startAt datetime(0) NOT NULL,
+  endAt datetime(0) NULL DEFAULT NULL,
+  createdBy varchar(100) NOT NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_product_price_product (productId, startAt),
+  CONSTRAINT fk_product_price_product FOREIGN KEY (productId) REFERENCES product (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+INSERT INTO product_price (productId, price, startAt, createdBy, createdAt)
+SELECT id, price, createdAt, 'system', createdAt FROM product;
@@ -0,0 +1,15 @@
+-- domain events written in the transaction of the change they tell about, the relay publishes them and sets
+-- publishedAt. nextAttemptAt is kept in UTC.
+CREATE TABLE outbox_event  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  type varchar(50) NOT NULL,
+  entityId int(11) NOT NULL,
+  payload text NOT NULL,
+  attempts int(11) NOT NULL DEFAULT 0,
+  nextAttemptAt datetime(0) NOT NULL,
+  publishedAt datetime(0) NULL DEFAULT NULL,
+  lastError text NULL,
+  createdAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_outbox_event_pending (publishedAt, nextAttemptAt)
+) ENGINE = InnoDB;
@@ -0,0 +1,32 @@
+-- partner callbacks: a subscription gets the events of its eventTypes (comma separated), every event it gets is a
+-- delivery retried until it goes through or runs out of attempts. nextAttemptAt and deliveredAt are kept in UTC.
+CREATE TABLE webhook_subscription  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  url varchar(500) NOT NULL,
+  secret varchar(100) NOT NULL,
+  eventTypes varchar(255) NOT NULL,
+  active tinyint(1) NOT NULL DEFAULT 1,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB;
+
+CREATE TABLE webhook_delivery  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  subscriptionId int(11) NOT NULL,
+  eventId int(11) NOT NULL,
+  eventType varchar(50) NOT NULL,
+  payload text NOT NULL,
+  status varchar(30) NOT NULL,
+  attempts int(11) NOT NULL DEFAULT 0,
+  responseCode int(11) NULL DEFAULT NULL,
+  lastError varchar(255) NULL DEFAULT NULL,
+  nextAttemptAt datetime(0) NULL DEFAULT NULL,
+  deliveredAt datetime(0) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_webhook_delivery_subscription_event (subscriptionId, eventId),
+  INDEX idx_webhook_delivery_pending (status, nextAttemptAt),
+  CONSTRAINT fk_webhook_delivery_subscription FOREIGN KEY (subscriptionId) REFERENCES webhook_subscription (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,22 @@
+-- background jobs: a worker claims a job until lockedUntil, a job left RUNNING past it, its worker gone, is claimed
+-- again. uniqueKey is cleared once the job is DONE or DEAD, so a key is only held by one live job. Dates are kept
+-- in UTC.
+CREATE TABLE job  (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  name varchar(100) NOT NULL,
+  payload text NOT NULL,
+  uniqueKey varchar(150) NULL DEFAULT NULL,
+  status varchar(20) NOT NULL,
+  attempts int(11) NOT NULL DEFAULT 0,
+  maxAttempts int(11) NOT NULL,
+  runAt datetime(0) NOT NULL,
+  lockedBy varchar(64) NULL DEFAULT NULL,
+  lockedUntil datetime(0) NULL DEFAULT NULL,
+  lastError varchar(255) NULL DEFAULT NULL,
+  finishedAt datetime(0) NULL DEFAULT NULL,
+  createdAt datetime(0) NOT NULL,
+  updatedAt datetime(0) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_job_unique_key (uniqueKey),
+  INDEX idx_job_due (status, runAt)
+) ENGINE = InnoDB;
@@ -0,0 +1,3 @@
+ALTER TABLE payment_webhook_event
+  DROP INDEX uq_payment_webhook_event_processed,
+  DROP COLUMN processedEventId;
@@ -0,0 +1,3 @@
+ALTER TABLE payment_webhook_event
+  ADD COLUMN processedEventId varchar(100) NULL DEFAULT NULL,
+  ADD UNIQUE INDEX uq_payment_webhook_event_processed (provider, processedEventId);
@@ -0,0 +1,6 @@
+CREATE TABLE brand (
+  id SERIAL PRIMARY KEY,
+  title varchar(100) NOT NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
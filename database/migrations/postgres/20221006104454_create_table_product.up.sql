@@ -0,0 +1,9 @@
+CREATE TABLE product (
+  id SERIAL PRIMARY KEY,
+  title varchar(100) NOT NULL,
+  description varchar(255) NULL,
+  brandId integer NOT NULL,
+  price double precision NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE transaction (
+  id SERIAL PRIMARY KEY,
+  transactionNumber varchar(50) NOT NULL DEFAULT '',
+  deliveryAddress varchar(255) NULL,
+  totalTransaction double precision NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NULL,
+  totalQty integer NULL
+);
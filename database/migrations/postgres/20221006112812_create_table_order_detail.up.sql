@@ -0,0 +1,8 @@
+CREATE TABLE transaction_detail (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL DEFAULT 0,
+  productId integer NOT NULL,
+  qty integer NOT NULL,
+  price double precision NOT NULL,
+  total double precision NOT NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE transaction
+  DROP COLUMN status,
+  DROP COLUMN cancelReason,
+  DROP COLUMN cancelledBy,
+  DROP COLUMN cancelledAt;
@@ -0,0 +1,16 @@
+CREATE TABLE refund (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL,
+  amount double precision NOT NULL DEFAULT 0,
+  reason varchar(255) NOT NULL,
+  createdBy varchar(100) NOT NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE TABLE refund_detail (
+  id SERIAL PRIMARY KEY,
+  refundId integer NOT NULL,
+  transactionDetailId integer NOT NULL,
+  qty integer NOT NULL,
+  amount double precision NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE payment (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL,
+  provider varchar(50) NOT NULL,
+  providerReference varchar(100) NULL,
+  amount double precision NOT NULL DEFAULT 0,
+  refundedAmount double precision NOT NULL DEFAULT 0,
+  status varchar(30) NOT NULL,
+  errorMessage varchar(255) NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE TABLE payment_webhook_event (
+  id SERIAL PRIMARY KEY,
+  provider varchar(50) NOT NULL,
+  eventId varchar(100) NULL,
+  payload text NOT NULL,
+  status varchar(30) NOT NULL,
+  errorMessage varchar(255) NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_payment_webhook_event_provider_event ON payment_webhook_event (provider, eventId);
@@ -0,0 +1,40 @@
+CREATE TABLE promotion (
+  id SERIAL PRIMARY KEY,
+  code varchar(50) NOT NULL,
+  title varchar(100) NOT NULL,
+  type varchar(20) NOT NULL,
+  value double precision NOT NULL DEFAULT 0,
+  minOrderValue double precision NOT NULL DEFAULT 0,
+  brandId integer NULL,
+  productId integer NULL,
+  startAt timestamp(0) NOT NULL,
+  endAt timestamp(0) NULL,
+  usageLimit integer NOT NULL DEFAULT 0,
+  usageCount integer NOT NULL DEFAULT 0,
+  perCustomerLimit integer NOT NULL DEFAULT 0,
+  stackable boolean NOT NULL DEFAULT false,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL,
+  CONSTRAINT uq_promotion_code UNIQUE (code)
+);
+
+CREATE TABLE transaction_discount (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL,
+  promotionId integer NOT NULL,
+  code varchar(50) NOT NULL,
+  customer varchar(100) NOT NULL,
+  amount double precision NOT NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_transaction_discount_promotion_customer ON transaction_discount (promotionId, customer);
+
+ALTER TABLE transaction
+  ADD COLUMN subtotal double precision NOT NULL DEFAULT 0,
+  ADD COLUMN discountTotal double precision NOT NULL DEFAULT 0;
+
+UPDATE transaction SET subtotal = totalTransaction;
+
+ALTER TABLE transaction_detail
+  ADD COLUMN discount double precision NOT NULL DEFAULT 0;
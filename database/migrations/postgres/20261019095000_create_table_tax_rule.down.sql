@@ -0,0 +1,11 @@
+ALTER TABLE transaction_detail
+  DROP COLUMN tax,
+  DROP COLUMN taxExclusive;
+
+ALTER TABLE transaction
+  DROP COLUMN taxTotal,
+  DROP COLUMN taxExclusive;
+
+DROP TABLE IF EXISTS transaction_tax;
+
+DROP TABLE IF EXISTS tax_rule;
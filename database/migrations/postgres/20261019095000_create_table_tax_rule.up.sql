@@ -0,0 +1,30 @@
+CREATE TABLE tax_rule (
+  id SERIAL PRIMARY KEY,
+  name varchar(50) NOT NULL,
+  rate double precision NOT NULL,
+  inclusive boolean NOT NULL DEFAULT false,
+  brandId integer NULL,
+  productId integer NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
+
+CREATE TABLE transaction_tax (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL,
+  taxRuleId integer NOT NULL,
+  name varchar(50) NOT NULL,
+  rate double precision NOT NULL,
+  inclusive boolean NOT NULL DEFAULT false,
+  amount double precision NOT NULL
+);
+
+CREATE INDEX idx_transaction_tax_transaction ON transaction_tax (transactionId);
+
+ALTER TABLE transaction
+  ADD COLUMN taxTotal double precision NOT NULL DEFAULT 0,
+  ADD COLUMN taxExclusive double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_detail
+  ADD COLUMN tax double precision NOT NULL DEFAULT 0,
+  ADD COLUMN taxExclusive double precision NOT NULL DEFAULT 0;
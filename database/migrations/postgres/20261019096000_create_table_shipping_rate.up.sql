@@ -0,0 +1,24 @@
+ALTER TABLE product
+  ADD COLUMN weight integer NOT NULL DEFAULT 0,
+  ADD COLUMN length integer NOT NULL DEFAULT 0,
+  ADD COLUMN width integer NOT NULL DEFAULT 0,
+  ADD COLUMN height integer NOT NULL DEFAULT 0;
+
+CREATE TABLE shipping_rate (
+  id SERIAL PRIMARY KEY,
+  region varchar(50) NOT NULL,
+  method varchar(50) NOT NULL,
+  minWeight integer NOT NULL DEFAULT 0,
+  maxWeight integer NOT NULL DEFAULT 0,
+  cost double precision NOT NULL,
+  estimatedDays integer NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_shipping_rate_region ON shipping_rate (region, method, minWeight);
+
+ALTER TABLE transaction
+  ADD COLUMN shippingMethod varchar(50) NULL,
+  ADD COLUMN shippingRegion varchar(50) NULL,
+  ADD COLUMN shippingCost double precision NOT NULL DEFAULT 0;
@@ -0,0 +1,25 @@
+CREATE TABLE shipment (
+  id SERIAL PRIMARY KEY,
+  transactionId integer NOT NULL,
+  carrier varchar(50) NOT NULL,
+  trackingNumber varchar(100) NOT NULL,
+  status varchar(30) NOT NULL DEFAULT 'SHIPPED',
+  createdBy varchar(100) NOT NULL,
+  shippedAt timestamp(0) NOT NULL,
+  deliveredAt timestamp(0) NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_shipment_transaction ON shipment (transactionId);
+
+CREATE TABLE shipment_detail (
+  id SERIAL PRIMARY KEY,
+  shipmentId integer NOT NULL,
+  transactionDetailId integer NOT NULL,
+  qty integer NOT NULL
+);
+
+CREATE INDEX idx_shipment_detail_shipment ON shipment_detail (shipmentId);
+
+CREATE INDEX idx_shipment_detail_transaction_detail ON shipment_detail (transactionDetailId);
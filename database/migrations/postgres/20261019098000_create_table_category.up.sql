@@ -0,0 +1,30 @@
+CREATE TABLE category (
+  id SERIAL PRIMARY KEY,
+  parentId integer NULL,
+  title varchar(100) NOT NULL,
+  slug varchar(100) NOT NULL,
+  sortOrder integer NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL,
+  CONSTRAINT uq_category_slug UNIQUE (slug)
+);
+
+CREATE INDEX idx_category_parent ON category (parentId);
+
+-- closure table, one row for every ancestor of a category including itself (depth 0)
+CREATE TABLE category_path (
+  ancestorId integer NOT NULL,
+  descendantId integer NOT NULL,
+  depth integer NOT NULL,
+  PRIMARY KEY (ancestorId, descendantId)
+);
+
+CREATE INDEX idx_category_path_descendant ON category_path (descendantId);
+
+CREATE TABLE product_category (
+  productId integer NOT NULL,
+  categoryId integer NOT NULL,
+  PRIMARY KEY (productId, categoryId)
+);
+
+CREATE INDEX idx_product_category_category ON product_category (categoryId);
@@ -0,0 +1,31 @@
+CREATE TABLE product_option (
+  id SERIAL PRIMARY KEY,
+  productId integer NOT NULL,
+  name varchar(30) NOT NULL,
+  sortOrder integer NOT NULL DEFAULT 0,
+  CONSTRAINT uq_product_option_name UNIQUE (productId, name)
+);
+
+CREATE TABLE product_variant (
+  id SERIAL PRIMARY KEY,
+  productId integer NOT NULL,
+  sku varchar(64) NOT NULL,
+  price double precision NULL,
+  stock integer NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL,
+  CONSTRAINT uq_product_variant_sku UNIQUE (sku)
+);
+
+CREATE INDEX idx_product_variant_product ON product_variant (productId);
+
+CREATE TABLE product_variant_option (
+  variantId integer NOT NULL,
+  optionId integer NOT NULL,
+  value varchar(50) NOT NULL,
+  PRIMARY KEY (variantId, optionId)
+);
+
+ALTER TABLE transaction_detail
+  ADD COLUMN variantId integer NULL,
+  ADD COLUMN sku varchar(64) NULL;
@@ -0,0 +1,14 @@
+-- storageKey is the blob store prefix, the original and its thumbnails are stored under it
+CREATE TABLE product_image (
+  id SERIAL PRIMARY KEY,
+  productId integer NOT NULL,
+  storageKey varchar(255) NOT NULL,
+  contentType varchar(50) NOT NULL,
+  size integer NOT NULL,
+  width integer NOT NULL,
+  height integer NOT NULL,
+  sortOrder integer NOT NULL DEFAULT 0,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_product_image_product ON product_image (productId, sortOrder);
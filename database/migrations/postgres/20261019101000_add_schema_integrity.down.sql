@@ -0,0 +1,22 @@
+DROP INDEX IF EXISTS idx_transaction_discount_transaction;
+
+DROP INDEX IF EXISTS idx_refund_detail_refund;
+DROP INDEX IF EXISTS idx_refund_detail_transaction_detail;
+
+DROP INDEX IF EXISTS idx_refund_transaction;
+
+DROP INDEX IF EXISTS idx_transaction_status_created;
+DROP INDEX IF EXISTS idx_transaction_created;
+
+ALTER TABLE transaction_detail
+  DROP CONSTRAINT IF EXISTS fk_transaction_detail_transaction,
+  DROP CONSTRAINT IF EXISTS fk_transaction_detail_product;
+DROP INDEX IF EXISTS idx_transaction_detail_transaction;
+DROP INDEX IF EXISTS idx_transaction_detail_product;
+
+ALTER TABLE product
+  DROP CONSTRAINT IF EXISTS fk_product_brand;
+DROP INDEX IF EXISTS idx_product_brand;
+DROP INDEX IF EXISTS idx_product_title;
+
+DROP INDEX IF EXISTS uq_brand_title;
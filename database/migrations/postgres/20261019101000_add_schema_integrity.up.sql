@@ -0,0 +1,22 @@
+CREATE UNIQUE INDEX uq_brand_title ON brand (title);
+
+CREATE INDEX idx_product_brand ON product (brandId);
+CREATE INDEX idx_product_title ON product (title);
+ALTER TABLE product
+  ADD CONSTRAINT fk_product_brand FOREIGN KEY (brandId) REFERENCES brand (id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_transaction_detail_transaction ON transaction_detail (transactionId);
+CREATE INDEX idx_transaction_detail_product ON transaction_detail (productId);
+ALTER TABLE transaction_detail
+  ADD CONSTRAINT fk_transaction_detail_transaction FOREIGN KEY (transactionId) REFERENCES transaction (id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_transaction_detail_product FOREIGN KEY (productId) REFERENCES product (id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_transaction_status_created ON transaction (status, createdAt);
+CREATE INDEX idx_transaction_created ON transaction (createdAt);
+
+CREATE INDEX idx_refund_transaction ON refund (transactionId);
+
+CREATE INDEX idx_refund_detail_refund ON refund_detail (refundId);
+CREATE INDEX idx_refund_detail_transaction_detail ON refund_detail (transactionDetailId);
+
+CREATE INDEX idx_transaction_discount_transaction ON transaction_discount (transactionId);
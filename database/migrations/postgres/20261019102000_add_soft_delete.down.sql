@@ -0,0 +1,5 @@
+ALTER TABLE product
+  DROP COLUMN deletedAt;
+
+ALTER TABLE brand
+  DROP COLUMN deletedAt;
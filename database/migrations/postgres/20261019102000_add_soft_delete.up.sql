@@ -0,0 +1,5 @@
+ALTER TABLE brand
+  ADD COLUMN deletedAt timestamp(0) NULL;
+
+ALTER TABLE product
+  ADD COLUMN deletedAt timestamp(0) NULL;
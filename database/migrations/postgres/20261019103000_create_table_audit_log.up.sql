@@ -0,0 +1,15 @@
+CREATE TABLE audit_log (
+  id SERIAL PRIMARY KEY,
+  actor varchar(100) NOT NULL,
+  action varchar(20) NOT NULL,
+  entityType varchar(30) NOT NULL,
+  entityId integer NOT NULL,
+  oldValues text NULL,
+  newValues text NULL,
+  requestId varchar(64) NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entityType, entityId, createdAt);
+
+CREATE INDEX idx_audit_log_created ON audit_log (createdAt);
@@ -0,0 +1,16 @@
+-- every price of a product: the price it was created with and the prices scheduled after, an endAt limits a
+-- price to a period like a flash sale
+CREATE TABLE product_price (
+  id SERIAL PRIMARY KEY,
+  productId integer NOT NULL REFERENCES product (id) ON DELETE CASCADE,
+  price double precision NOT NULL,
+  startAt timestamp(0) NOT NULL,
+  endAt timestamp(0) NULL,
+  createdBy varchar(100) NOT NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_product_price_product ON product_price (productId, startAt);
+
+INSERT INTO product_price (productId, price, startAt, createdBy, createdAt)
+SELECT id, price, createdAt, 'system', createdAt FROM product;
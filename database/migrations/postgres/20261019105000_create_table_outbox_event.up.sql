@@ -0,0 +1,15 @@
+-- domain events written in the transaction of the change they tell about, the relay publishes them and sets
+-- publishedAt. nextAttemptAt is kept in UTC.
+CREATE TABLE outbox_event (
+  id SERIAL PRIMARY KEY,
+  type varchar(50) NOT NULL,
+  entityId integer NOT NULL,
+  payload text NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  nextAttemptAt timestamp(0) NOT NULL,
+  publishedAt timestamp(0) NULL,
+  lastError text NULL,
+  createdAt timestamp(0) NOT NULL
+);
+
+CREATE INDEX idx_outbox_event_pending ON outbox_event (publishedAt, nextAttemptAt);
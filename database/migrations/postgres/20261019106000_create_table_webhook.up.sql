@@ -0,0 +1,30 @@
+-- partner callbacks: a subscription gets the events of its eventTypes (comma separated), every event it gets is a
+-- delivery retried until it goes through or runs out of attempts. nextAttemptAt and deliveredAt are kept in UTC.
+CREATE TABLE webhook_subscription (
+  id SERIAL PRIMARY KEY,
+  url varchar(500) NOT NULL,
+  secret varchar(100) NOT NULL,
+  eventTypes varchar(255) NOT NULL,
+  active boolean NOT NULL DEFAULT true,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL
+);
+
+CREATE TABLE webhook_delivery (
+  id SERIAL PRIMARY KEY,
+  subscriptionId integer NOT NULL REFERENCES webhook_subscription (id) ON DELETE CASCADE,
+  eventId integer NOT NULL,
+  eventType varchar(50) NOT NULL,
+  payload text NOT NULL,
+  status varchar(30) NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  responseCode integer NULL,
+  lastError varchar(255) NULL,
+  nextAttemptAt timestamp(0) NULL,
+  deliveredAt timestamp(0) NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL,
+  CONSTRAINT uq_webhook_delivery_subscription_event UNIQUE (subscriptionId, eventId)
+);
+
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (status, nextAttemptAt);
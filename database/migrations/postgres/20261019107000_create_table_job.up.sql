@@ -0,0 +1,22 @@
+-- background jobs: a worker claims a job until lockedUntil, a job left RUNNING past it, its worker gone, is claimed
+-- again. uniqueKey is cleared once the job is DONE or DEAD, so a key is only held by one live job. Dates are kept
+-- in UTC.
+CREATE TABLE job (
+  id SERIAL PRIMARY KEY,
+  name varchar(100) NOT NULL,
+  payload text NOT NULL,
+  uniqueKey varchar(150) NULL,
+  status varchar(20) NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  maxAttempts integer NOT NULL,
+  runAt timestamp(0) NOT NULL,
+  lockedBy varchar(64) NULL,
+  lockedUntil timestamp(0) NULL,
+  lastError varchar(255) NULL,
+  finishedAt timestamp(0) NULL,
+  createdAt timestamp(0) NOT NULL,
+  updatedAt timestamp(0) NOT NULL,
+  CONSTRAINT uq_job_unique_key UNIQUE (uniqueKey)
+);
+
+CREATE INDEX idx_job_due ON job (status, runAt);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_payment_webhook_event_processed;
+
+ALTER TABLE payment_webhook_event
+  DROP COLUMN processedEventId;
@@ -0,0 +1,4 @@
+ALTER TABLE payment_webhook_event
+  ADD COLUMN processedEventId varchar(100) NULL;
+
+CREATE UNIQUE INDEX uq_payment_webhook_event_processed ON payment_webhook_event (provider, processedEventId);
@@ -0,0 +1,9 @@
+CREATE TABLE product (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NULL,
+  brandId INTEGER NOT NULL,
+  price REAL NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
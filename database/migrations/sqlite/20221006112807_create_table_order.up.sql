@@ -0,0 +1,8 @@
+CREATE TABLE "transaction" (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionNumber VARCHAR(50) NOT NULL DEFAULT '',
+  deliveryAddress VARCHAR(255) NULL,
+  totalTransaction REAL NOT NULL DEFAULT 0,
+  createdAt DATETIME NULL,
+  totalQty INTEGER NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE transaction_detail (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL DEFAULT 0,
+  productId INTEGER NOT NULL,
+  qty INTEGER NOT NULL,
+  price REAL NOT NULL,
+  total REAL NOT NULL
+);
@@ -0,0 +1,7 @@
+ALTER TABLE "transaction" DROP COLUMN status;
+
+ALTER TABLE "transaction" DROP COLUMN cancelReason;
+
+ALTER TABLE "transaction" DROP COLUMN cancelledBy;
+
+ALTER TABLE "transaction" DROP COLUMN cancelledAt;
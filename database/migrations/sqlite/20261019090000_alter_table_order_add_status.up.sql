@@ -0,0 +1,7 @@
+ALTER TABLE "transaction" ADD COLUMN status VARCHAR(30) NOT NULL DEFAULT 'PENDING';
+
+ALTER TABLE "transaction" ADD COLUMN cancelReason VARCHAR(255) NULL;
+
+ALTER TABLE "transaction" ADD COLUMN cancelledBy VARCHAR(100) NULL;
+
+ALTER TABLE "transaction" ADD COLUMN cancelledAt DATETIME NULL;
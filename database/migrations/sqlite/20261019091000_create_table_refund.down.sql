@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS refund_detail;
+
+DROP TABLE IF EXISTS refund;
@@ -0,0 +1,16 @@
+CREATE TABLE refund (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL,
+  amount REAL NOT NULL DEFAULT 0,
+  reason VARCHAR(255) NOT NULL,
+  createdBy VARCHAR(100) NOT NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE TABLE refund_detail (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  refundId INTEGER NOT NULL,
+  transactionDetailId INTEGER NOT NULL,
+  qty INTEGER NOT NULL,
+  amount REAL NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE payment (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  providerReference VARCHAR(100) NULL,
+  amount REAL NOT NULL DEFAULT 0,
+  refundedAmount REAL NOT NULL DEFAULT 0,
+  status VARCHAR(30) NOT NULL,
+  errorMessage VARCHAR(255) NULL,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE TABLE payment_webhook_event (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  provider VARCHAR(50) NOT NULL,
+  eventId VARCHAR(100) NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(30) NOT NULL,
+  errorMessage VARCHAR(255) NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_payment_webhook_event_provider_event ON payment_webhook_event (provider, eventId);
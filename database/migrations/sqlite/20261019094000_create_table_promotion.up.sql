@@ -0,0 +1,40 @@
+CREATE TABLE promotion (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  code VARCHAR(50) NOT NULL,
+  title VARCHAR(100) NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  value REAL NOT NULL DEFAULT 0,
+  minOrderValue REAL NOT NULL DEFAULT 0,
+  brandId INTEGER NULL,
+  productId INTEGER NULL,
+  startAt DATETIME NOT NULL,
+  endAt DATETIME NULL,
+  usageLimit INTEGER NOT NULL DEFAULT 0,
+  usageCount INTEGER NOT NULL DEFAULT 0,
+  perCustomerLimit INTEGER NOT NULL DEFAULT 0,
+  stackable BOOLEAN NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_promotion_code ON promotion (code);
+
+CREATE TABLE transaction_discount (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL,
+  promotionId INTEGER NOT NULL,
+  code VARCHAR(50) NOT NULL,
+  customer VARCHAR(100) NOT NULL,
+  amount REAL NOT NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_transaction_discount_promotion_customer ON transaction_discount (promotionId, customer);
+
+ALTER TABLE "transaction" ADD COLUMN subtotal REAL NOT NULL DEFAULT 0;
+
+ALTER TABLE "transaction" ADD COLUMN discountTotal REAL NOT NULL DEFAULT 0;
+
+UPDATE "transaction" SET subtotal = totalTransaction;
+
+ALTER TABLE transaction_detail ADD COLUMN discount REAL NOT NULL DEFAULT 0;
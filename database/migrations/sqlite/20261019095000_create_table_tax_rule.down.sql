@@ -0,0 +1,11 @@
+ALTER TABLE transaction_detail DROP COLUMN tax;
+
+ALTER TABLE transaction_detail DROP COLUMN taxExclusive;
+
+ALTER TABLE "transaction" DROP COLUMN taxTotal;
+
+ALTER TABLE "transaction" DROP COLUMN taxExclusive;
+
+DROP TABLE IF EXISTS transaction_tax;
+
+DROP TABLE IF EXISTS tax_rule;
@@ -0,0 +1,30 @@
+CREATE TABLE tax_rule (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(50) NOT NULL,
+  rate REAL NOT NULL,
+  inclusive BOOLEAN NOT NULL DEFAULT 0,
+  brandId INTEGER NULL,
+  productId INTEGER NULL,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE TABLE transaction_tax (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL,
+  taxRuleId INTEGER NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  rate REAL NOT NULL,
+  inclusive BOOLEAN NOT NULL DEFAULT 0,
+  amount REAL NOT NULL
+);
+
+CREATE INDEX idx_transaction_tax_transaction ON transaction_tax (transactionId);
+
+ALTER TABLE "transaction" ADD COLUMN taxTotal REAL NOT NULL DEFAULT 0;
+
+ALTER TABLE "transaction" ADD COLUMN taxExclusive REAL NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_detail ADD COLUMN tax REAL NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_detail ADD COLUMN taxExclusive REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+ALTER TABLE "transaction" DROP COLUMN shippingMethod;
+
+ALTER TABLE "transaction" DROP COLUMN shippingRegion;
+
+ALTER TABLE "transaction" DROP COLUMN shippingCost;
+
+DROP TABLE IF EXISTS shipping_rate;
+
+ALTER TABLE product DROP COLUMN weight;
+
+ALTER TABLE product DROP COLUMN length;
+
+ALTER TABLE product DROP COLUMN width;
+
+ALTER TABLE product DROP COLUMN height;
@@ -0,0 +1,27 @@
+ALTER TABLE product ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE product ADD COLUMN length INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE product ADD COLUMN width INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE product ADD COLUMN height INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE shipping_rate (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  region VARCHAR(50) NOT NULL,
+  method VARCHAR(50) NOT NULL,
+  minWeight INTEGER NOT NULL DEFAULT 0,
+  maxWeight INTEGER NOT NULL DEFAULT 0,
+  cost REAL NOT NULL,
+  estimatedDays INTEGER NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_shipping_rate_region ON shipping_rate (region, method, minWeight);
+
+ALTER TABLE "transaction" ADD COLUMN shippingMethod VARCHAR(50) NULL;
+
+ALTER TABLE "transaction" ADD COLUMN shippingRegion VARCHAR(50) NULL;
+
+ALTER TABLE "transaction" ADD COLUMN shippingCost REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS shipment_detail;
+
+DROP TABLE IF EXISTS shipment;
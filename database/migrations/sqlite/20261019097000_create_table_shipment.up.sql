@@ -0,0 +1,25 @@
+CREATE TABLE shipment (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL,
+  carrier VARCHAR(50) NOT NULL,
+  trackingNumber VARCHAR(100) NOT NULL,
+  status VARCHAR(30) NOT NULL DEFAULT 'SHIPPED',
+  createdBy VARCHAR(100) NOT NULL,
+  shippedAt DATETIME NOT NULL,
+  deliveredAt DATETIME NULL,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_shipment_transaction ON shipment (transactionId);
+
+CREATE TABLE shipment_detail (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  shipmentId INTEGER NOT NULL,
+  transactionDetailId INTEGER NOT NULL,
+  qty INTEGER NOT NULL
+);
+
+CREATE INDEX idx_shipment_detail_shipment ON shipment_detail (shipmentId);
+
+CREATE INDEX idx_shipment_detail_transaction_detail ON shipment_detail (transactionDetailId);
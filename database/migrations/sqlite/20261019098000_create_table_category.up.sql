@@ -0,0 +1,31 @@
+CREATE TABLE category (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  parentId INTEGER NULL,
+  title VARCHAR(100) NOT NULL,
+  slug VARCHAR(100) NOT NULL,
+  sortOrder INTEGER NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_category_slug ON category (slug);
+
+CREATE INDEX idx_category_parent ON category (parentId);
+
+-- closure table, one row for every ancestor of a category including itself (depth 0)
+CREATE TABLE category_path (
+  ancestorId INTEGER NOT NULL,
+  descendantId INTEGER NOT NULL,
+  depth INTEGER NOT NULL,
+  PRIMARY KEY (ancestorId, descendantId)
+);
+
+CREATE INDEX idx_category_path_descendant ON category_path (descendantId);
+
+CREATE TABLE product_category (
+  productId INTEGER NOT NULL,
+  categoryId INTEGER NOT NULL,
+  PRIMARY KEY (productId, categoryId)
+);
+
+CREATE INDEX idx_product_category_category ON product_category (categoryId);
@@ -0,0 +1,9 @@
+ALTER TABLE transaction_detail DROP COLUMN variantId;
+
+ALTER TABLE transaction_detail DROP COLUMN sku;
+
+DROP TABLE IF EXISTS product_variant_option;
+
+DROP TABLE IF EXISTS product_variant;
+
+DROP TABLE IF EXISTS product_option;
@@ -0,0 +1,33 @@
+CREATE TABLE product_option (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  productId INTEGER NOT NULL,
+  name VARCHAR(30) NOT NULL,
+  sortOrder INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX uq_product_option_name ON product_option (productId, name);
+
+CREATE TABLE product_variant (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  productId INTEGER NOT NULL,
+  sku VARCHAR(64) NOT NULL,
+  price REAL NULL,
+  stock INTEGER NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_product_variant_sku ON product_variant (sku);
+
+CREATE INDEX idx_product_variant_product ON product_variant (productId);
+
+CREATE TABLE product_variant_option (
+  variantId INTEGER NOT NULL,
+  optionId INTEGER NOT NULL,
+  value VARCHAR(50) NOT NULL,
+  PRIMARY KEY (variantId, optionId)
+);
+
+ALTER TABLE transaction_detail ADD COLUMN variantId INTEGER NULL;
+
+ALTER TABLE transaction_detail ADD COLUMN sku VARCHAR(64) NULL;
@@ -0,0 +1,14 @@
+-- storageKey is the blob store prefix, the original and its thumbnails are stored under it
+CREATE TABLE product_image (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  productId INTEGER NOT NULL,
+  storageKey VARCHAR(255) NOT NULL,
+  contentType VARCHAR(50) NOT NULL,
+  size INTEGER NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  sortOrder INTEGER NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_product_image_product ON product_image (productId, sortOrder);
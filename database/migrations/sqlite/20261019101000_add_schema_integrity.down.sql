@@ -0,0 +1,54 @@
+DROP INDEX IF EXISTS idx_transaction_discount_transaction;
+
+DROP INDEX IF EXISTS idx_refund_detail_refund;
+DROP INDEX IF EXISTS idx_refund_detail_transaction_detail;
+
+DROP INDEX IF EXISTS idx_refund_transaction;
+
+DROP INDEX IF EXISTS idx_transaction_status_created;
+DROP INDEX IF EXISTS idx_transaction_created;
+
+-- the tables are copied back into tables without foreign keys, transaction_detail first as it references product
+CREATE TABLE transaction_detail_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL DEFAULT 0,
+  productId INTEGER NOT NULL,
+  qty INTEGER NOT NULL,
+  price REAL NOT NULL,
+  total REAL NOT NULL,
+  discount REAL NOT NULL DEFAULT 0,
+  tax REAL NOT NULL DEFAULT 0,
+  taxExclusive REAL NOT NULL DEFAULT 0,
+  variantId INTEGER NULL,
+  sku VARCHAR(64) NULL
+);
+
+INSERT INTO transaction_detail_old (id, transactionId, productId, qty, price, total, discount, tax, taxExclusive, variantId, sku)
+SELECT id, transactionId, productId, qty, price, total, discount, tax, taxExclusive, variantId, sku FROM transaction_detail;
+
+DROP TABLE transaction_detail;
+
+ALTER TABLE transaction_detail_old RENAME TO transaction_detail;
+
+CREATE TABLE product_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NULL,
+  brandId INTEGER NOT NULL,
+  price REAL NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL,
+  weight INTEGER NOT NULL DEFAULT 0,
+  length INTEGER NOT NULL DEFAULT 0,
+  width INTEGER NOT NULL DEFAULT 0,
+  height INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO product_old (id, title, description, brandId, price, createdAt, updatedAt, weight, length, width, height)
+SELECT id, title, description, brandId, price, createdAt, updatedAt, weight, length, width, height FROM product;
+
+DROP TABLE product;
+
+ALTER TABLE product_old RENAME TO product;
+
+DROP INDEX IF EXISTS uq_brand_title;
@@ -0,0 +1,60 @@
+CREATE UNIQUE INDEX uq_brand_title ON brand (title);
+
+-- SQLite adds no foreign key to an existing table, product and transaction_detail are copied into tables having them
+CREATE TABLE product_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NULL,
+  brandId INTEGER NOT NULL REFERENCES brand (id) ON DELETE RESTRICT,
+  price REAL NOT NULL DEFAULT 0,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL,
+  weight INTEGER NOT NULL DEFAULT 0,
+  length INTEGER NOT NULL DEFAULT 0,
+  width INTEGER NOT NULL DEFAULT 0,
+  height INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO product_new (id, title, description, brandId, price, createdAt, updatedAt, weight, length, width, height)
+SELECT id, title, description, brandId, price, createdAt, updatedAt, weight, length, width, height FROM product;
+
+DROP TABLE product;
+
+ALTER TABLE product_new RENAME TO product;
+
+CREATE INDEX idx_product_brand ON product (brandId);
+CREATE INDEX idx_product_title ON product (title);
+
+CREATE TABLE transaction_detail_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  transactionId INTEGER NOT NULL DEFAULT 0 REFERENCES "transaction" (id) ON DELETE CASCADE,
+  productId INTEGER NOT NULL REFERENCES product (id) ON DELETE RESTRICT,
+  qty INTEGER NOT NULL,
+  price REAL NOT NULL,
+  total REAL NOT NULL,
+  discount REAL NOT NULL DEFAULT 0,
+  tax REAL NOT NULL DEFAULT 0,
+  taxExclusive REAL NOT NULL DEFAULT 0,
+  variantId INTEGER NULL,
+  sku VARCHAR(64) NULL
+);
+
+INSERT INTO transaction_detail_new (id, transactionId, productId, qty, price, total, discount, tax, taxExclusive, variantId, sku)
+SELECT id, transactionId, productId, qty, price, total, discount, tax, taxExclusive, variantId, sku FROM transaction_detail;
+
+DROP TABLE transaction_detail;
+
+ALTER TABLE transaction_detail_new RENAME TO transaction_detail;
+
+CREATE INDEX idx_transaction_detail_transaction ON transaction_detail (transactionId);
+CREATE INDEX idx_transaction_detail_product ON transaction_detail (productId);
+
+CREATE INDEX idx_transaction_status_created ON "transaction" (status, createdAt);
+CREATE INDEX idx_transaction_created ON "transaction" (createdAt);
+
+CREATE INDEX idx_refund_transaction ON refund (transactionId);
+
+CREATE INDEX idx_refund_detail_refund ON refund_detail (refundId);
+CREATE INDEX idx_refund_detail_transaction_detail ON refund_detail (transactionDetailId);
+
+CREATE INDEX idx_transaction_discount_transaction ON transaction_discount (transactionId);
@@ -0,0 +1,3 @@
+ALTER TABLE product DROP COLUMN deletedAt;
+
+ALTER TABLE brand DROP COLUMN deletedAt;
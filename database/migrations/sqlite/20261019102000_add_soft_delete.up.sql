@@ -0,0 +1,3 @@
+ALTER TABLE brand ADD COLUMN deletedAt DATETIME NULL;
+
+ALTER TABLE product ADD COLUMN deletedAt DATETIME NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE audit_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  actor VARCHAR(100) NOT NULL,
+  action VARCHAR(20) NOT NULL,
+  entityType VARCHAR(30) NOT NULL,
+  entityId INTEGER NOT NULL,
+  oldValues TEXT NULL,
+  newValues TEXT NULL,
+  requestId VARCHAR(64) NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entityType, entityId, createdAt);
+
+CREATE INDEX idx_audit_log_created ON audit_log (createdAt);
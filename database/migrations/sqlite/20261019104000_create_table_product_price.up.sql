@@ -0,0 +1,16 @@
+-- every price of a product: the price it was created with and the prices scheduled after, an endAt limits a
+-- price to a period like a flash sale
+CREATE TABLE product_price (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  productId INTEGER NOT NULL REFERENCES product (id) ON DELETE CASCADE,
+  price REAL NOT NULL,
+  startAt DATETIME NOT NULL,
+  endAt DATETIME NULL,
+  createdBy VARCHAR(100) NOT NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_product_price_product ON product_price (productId, startAt);
+
+INSERT INTO product_price (productId, price, startAt, createdBy, createdAt)
+SELECT id, price, createdAt, 'system', createdAt FROM product;
@@ -0,0 +1,15 @@
+-- domain events written in the transaction of the change they tell about, the relay publishes them and sets
+-- publishedAt. nextAttemptAt is kept in UTC.
+CREATE TABLE outbox_event (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  type VARCHAR(50) NOT NULL,
+  entityId INTEGER NOT NULL,
+  payload TEXT NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  nextAttemptAt DATETIME NOT NULL,
+  publishedAt DATETIME NULL,
+  lastError TEXT NULL,
+  createdAt DATETIME NOT NULL
+);
+
+CREATE INDEX idx_outbox_event_pending ON outbox_event (publishedAt, nextAttemptAt);
@@ -0,0 +1,30 @@
+-- partner callbacks: a subscription gets the events of its eventTypes (comma separated), every event it gets is a
+-- delivery retried until it goes through or runs out of attempts. nextAttemptAt and deliveredAt are kept in UTC.
+CREATE TABLE webhook_subscription (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  url VARCHAR(500) NOT NULL,
+  secret VARCHAR(100) NOT NULL,
+  eventTypes VARCHAR(255) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT 1,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL
+);
+
+CREATE TABLE webhook_delivery (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  subscriptionId INTEGER NOT NULL REFERENCES webhook_subscription (id) ON DELETE CASCADE,
+  eventId INTEGER NOT NULL,
+  eventType VARCHAR(50) NOT NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(30) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  responseCode INTEGER NULL,
+  lastError VARCHAR(255) NULL,
+  nextAttemptAt DATETIME NULL,
+  deliveredAt DATETIME NULL,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL,
+  UNIQUE (subscriptionId, eventId)
+);
+
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (status, nextAttemptAt);
@@ -0,0 +1,22 @@
+-- background jobs: a worker claims a job until lockedUntil, a job left RUNNING past it, its worker gone, is claimed
+-- again. uniqueKey is cleared once the job is DONE or DEAD, so a key is only held by one live job. Dates are kept
+-- in UTC.
+CREATE TABLE job (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(100) NOT NULL,
+  payload TEXT NOT NULL,
+  uniqueKey VARCHAR(150) NULL,
+  status VARCHAR(20) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  maxAttempts INTEGER NOT NULL,
+  runAt DATETIME NOT NULL,
+  lockedBy VARCHAR(64) NULL,
+  lockedUntil DATETIME NULL,
+  lastError VARCHAR(255) NULL,
+  finishedAt DATETIME NULL,
+  createdAt DATETIME NOT NULL,
+  updatedAt DATETIME NOT NULL,
+  UNIQUE (uniqueKey)
+);
+
+CREATE INDEX idx_job_due ON job (status, runAt);
@@ -0,0 +1,3 @@
+ALTER TABLE payment_webhook_event ADD COLUMN processedEventId VARCHAR(100) NULL;
+
+CREATE UNIQUE INDEX uq_payment_webhook_event_processed ON payment_webhook_event (provider, processedEventId);
@@ -7,6 +7,8 @@ require (
 	github.com/go-faker/faker/v4 v4.0.0-beta.3
 	github.com/go-playground/validator/v10 v10.11.1
 	github.com/go-sql-driver/mysql v1.6.0
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/stretchr/testify v1.8.0
 )
 
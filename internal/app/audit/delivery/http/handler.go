@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type AuditHandler struct {
+	AuditService service.AuditService
+}
+
+func NewAuditHandler(mux *http.ServeMux, service service.AuditService) {
+	handler := AuditHandler{AuditService: service}
+
+	mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.GetAudit(w, r)
+		}
+	})
+}
+
+func (b *AuditHandler) GetAudit(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	query := r.URL.Query()
+	filter := dto.FilterAuditDto{EntityType: query.Get("entityType"), DateFrom: query.Get("dateFrom"), DateTo: query.Get("dateTo")}
+	filter.EntityId, _ = strconv.Atoi(query.Get("entityId"))
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	validate := validator.New()
+	if err := validate.Struct(&filter); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.AuditService.GetAudit(r.Context(), filter)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
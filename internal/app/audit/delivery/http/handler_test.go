@@ -0,0 +1,62 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	auditHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/audit/service"
+)
+
+func TestGetAudit(t *testing.T) {
+	mockService := new(mocks.AuditService)
+
+	t.Run("Test Get Audit Success", func(t *testing.T) {
+		filter := dto.FilterAuditDto{EntityType: "product", EntityId: 2, DateFrom: "2026-10-01", DateTo: "2026-10-19", Limit: 20}
+		mockService.On("GetAudit", context.Background(), filter).Return([]dto.GetAudit{{ID: 1, EntityType: "product", EntityId: 2}}, nil, "SUCCESS").Once()
+		handler := auditHttp.AuditHandler{AuditService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?entityType=product&entityId=2&dateFrom=2026-10-01&dateTo=2026-10-19&limit=20", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetAudit(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Get Audit Entity Id Without Type", func(t *testing.T) {
+		handler := auditHttp.AuditHandler{AuditService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?entityId=2", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetAudit(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Get Audit Invalid Date", func(t *testing.T) {
+		handler := auditHttp.AuditHandler{AuditService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?dateFrom=19-10-2026", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetAudit(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Get Audit Error Database", func(t *testing.T) {
+		mockService.On("GetAudit", context.Background(), dto.FilterAuditDto{}).Return(nil, errors.New("Database Error"), "SYSTEM_ERROR").Once()
+		handler := auditHttp.AuditHandler{AuditService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetAudit(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "encoding/json"
+
+// Change is a change of an entity to record. Before and After are the entity, or the part of it the change
+// touches, before and after the change and nil when it did not exist. Only the fields that differ are kept.
+type Change struct {
+	Action     string
+	EntityType string
+	EntityId   int
+	Before     interface{}
+	After      interface{}
+}
+
+// FilterAuditDto dates are yyyy-mm-dd, DateTo is inclusive
+type FilterAuditDto struct {
+	EntityType string `json:"entityType" validate:"required_with=EntityId"`
+	EntityId   int    `json:"entityId" validate:"gte=0"`
+	DateFrom   string `json:"dateFrom" validate:"omitempty,datetime=2006-01-02"`
+	DateTo     string `json:"dateTo" validate:"omitempty,datetime=2006-01-02"`
+	Limit      int    `json:"limit" validate:"gte=0,lte=1000"`
+}
+
+type GetAudit struct {
+	ID         int             `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityId   int             `json:"entityId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestId  string          `json:"requestId,omitempty"`
+	CreatedAt  string          `json:"createdAt"`
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type AuditRepository interface {
+	// Record writes the change with db, the transaction making the change or nil when there is none, so the
+	// change and its audit log are kept or rolled back together. The actor and request id come from ctx.
+	Record(ctx context.Context, db dialect.Execer, change dto.Change) error
+	GetAudit(ctx context.Context, filter dto.FilterAuditDto) (data []model.AuditLog, err error)
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewAudit(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) Record(ctx context.Context, db dialect.Execer, change dto.Change) error {
+	before, after, err := diff(change.Before, change.After)
+	if err != nil {
+		return err
+	}
+
+	if db == nil {
+		db = r.DB
+	}
+
+	query := `INSERT INTO audit_log (actor, action, entityType, entityId, oldValues, newValues, requestId, createdAt)
+	values(?, ?, ?, ?, ?, ?, NULLIF(?, ''), CURRENT_TIMESTAMP)`
+	_, err = db.ExecContext(ctx, r.Dialect.Rebind(query), util.ActorOf(ctx), change.Action, change.EntityType, change.EntityId,
+		before, after, util.RequestIdOf(ctx))
+	return err
+}
+
+func (r *Repository) GetAudit(ctx context.Context, filter dto.FilterAuditDto) (data []model.AuditLog, err error) {
+
+	var filterValues []interface{}
+	query := `SELECT id, actor, action, entityType, entityId, oldValues, newValues, requestId, createdAt FROM audit_log`
+
+	if filter.EntityType != "" {
+		query += util.FilterHandler(filterValues) + ` entityType = ?`
+		filterValues = append(filterValues, filter.EntityType)
+	}
+
+	if filter.EntityId > 0 {
+		query += util.FilterHandler(filterValues) + ` entityId = ?`
+		filterValues = append(filterValues, filter.EntityId)
+	}
+
+	if filter.DateFrom != "" {
+		query += util.FilterHandler(filterValues) + ` createdAt >= ?`
+		filterValues = append(filterValues, filter.DateFrom)
+	}
+
+	if filter.DateTo != "" {
+		query += util.FilterHandler(filterValues) + ` createdAt < ` + r.Dialect.AddDays("?", 1)
+		filterValues = append(filterValues, filter.DateTo)
+	}
+
+	//newest first, the id keeps the changes of the same second in order
+	query += ` ORDER BY id DESC`
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			log                  model.AuditLog
+			before, after, reqId sql.NullString
+		)
+		err = rows.Scan(&log.ID, &log.Actor, &log.Action, &log.EntityType, &log.EntityId, &before, &after, &reqId, &log.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			log.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			log.After = json.RawMessage(after.String)
+		}
+		log.RequestId = reqId.String
+
+		data = append(data, log)
+	}
+
+	return data, rows.Err()
+}
+
+// diff encodes the fields of before and after as JSON objects, leaving out the fields both have with the same value.
+// A field only one of them has is null in the other. Nil gives NULL, for the side of an entity that did not exist.
+func diff(before interface{}, after interface{}) (interface{}, interface{}, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+		for key := range beforeFields {
+			if _, ok := afterFields[key]; !ok {
+				afterFields[key] = nil
+			}
+		}
+		for key := range afterFields {
+			if _, ok := beforeFields[key]; !ok {
+				beforeFields[key] = nil
+			}
+		}
+	}
+
+	beforeJSON, err := encode(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := encode(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func fields(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// encode returns the JSON of fields as a string, or nil for NULL
+func encode(fields map[string]interface{}) (interface{}, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,140 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// requestContext returns the context a handler gets for a request made by actor with the given request id
+func requestContext(actor string, requestId string) context.Context {
+	var ctx context.Context
+	req := httptest.NewRequest(http.MethodPost, "/brand", nil)
+	req.Header.Set(util.ActorHeader, actor)
+	req.Header.Set(util.RequestIdHeader, requestId)
+	util.WithRequestContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+func TestRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO audit_log"
+
+	t.Run("Test Record Create", func(t *testing.T) {
+		change := dto.Change{
+			Action:     model.AuditActionCreate,
+			EntityType: model.AuditEntityBrand,
+			EntityId:   1,
+			After:      map[string]interface{}{"title": "Nike", "description": "Sport"},
+		}
+		mock.ExpectExec(query).WithArgs("admin", "CREATE", "brand", 1, nil, `{"description":"Sport","title":"Nike"}`, "req-1").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		err := r.Record(requestContext("admin", "req-1"), nil, change)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Record Update Keeps Only Changed Fields", func(t *testing.T) {
+		change := dto.Change{
+			Action:     model.AuditActionUpdate,
+			EntityType: model.AuditEntityShipment,
+			EntityId:   3,
+			Before:     map[string]interface{}{"carrier": "JNE", "status": "SHIPPED"},
+			After:      map[string]interface{}{"carrier": "JNE", "status": "DELIVERED", "deliveredAt": "2026-10-19 10:00:00"},
+		}
+		mock.ExpectExec(query).WithArgs(util.SystemActor, "UPDATE", "shipment", 3,
+			`{"deliveredAt":null,"status":"SHIPPED"}`, `{"deliveredAt":"2026-10-19 10:00:00","status":"DELIVERED"}`, "").
+			WillReturnResult(sqlmock.NewResult(2, 1))
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		err := r.Record(context.TODO(), nil, change)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Record In Transaction", func(t *testing.T) {
+		change := dto.Change{Action: model.AuditActionDelete, EntityType: model.AuditEntityRefund, EntityId: 1, Before: map[string]interface{}{"amount": 5000}}
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(util.SystemActor, "DELETE", "refund", 1, `{"amount":5000}`, nil, "").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		err = r.Record(context.TODO(), tx, change)
+		tx.Rollback()
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Record Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		err := r.Record(context.TODO(), nil, dto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityOrder, EntityId: 1})
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, actor, action, entityType, entityId, oldValues, newValues, requestId, createdAt FROM audit_log"
+	columns := []string{"id", "actor", "action", "entityType", "entityId", "oldValues", "newValues", "requestId", "createdAt"}
+
+	t.Run("Test Get Audit By Entity And Dates", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(2, "admin", "UPDATE", "brand", 1, `{"deletedAt":null}`, `{"deletedAt":"2026-10-19 10:00:00"}`, "req-2", "2026-10-19 10:00:00").
+			AddRow(1, "system", "CREATE", "brand", 1, nil, `{"title":"Nike"}`, nil, "2026-10-18 09:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(query+` WHERE entityType = ? AND entityId = ? AND createdAt >= ? AND createdAt < DATE_ADD(?, INTERVAL 1 DAY) ORDER BY id DESC LIMIT ?`)).
+			WithArgs("brand", 1, "2026-10-01", "2026-10-19", 100).WillReturnRows(rows)
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		result, err := r.GetAudit(context.TODO(), dto.FilterAuditDto{EntityType: "brand", EntityId: 1, DateFrom: "2026-10-01", DateTo: "2026-10-19", Limit: 100})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "req-2", result[0].RequestId)
+		assert.Nil(t, result[1].Before)
+		assert.JSONEq(t, `{"title":"Nike"}`, string(result[1].After))
+		assert.Empty(t, result[1].RequestId)
+	})
+
+	t.Run("Test Get Audit Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewAudit(db, dialect.MySQL)
+		result, err := r.GetAudit(context.TODO(), dto.FilterAuditDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// DefaultLimit is how many changes GetAudit returns when the filter gives no limit
+const DefaultLimit = 100
+
+type AuditService interface {
+	GetAudit(ctx context.Context, filter dto.FilterAuditDto) ([]dto.GetAudit, error, string)
+}
+
+type Service struct {
+	auditRepository repository.AuditRepository
+	contextTimeout  time.Duration
+}
+
+func NewAuditService(r repository.AuditRepository, timeout time.Duration) AuditService {
+	return &Service{
+		auditRepository: r,
+		contextTimeout:  timeout,
+	}
+}
+
+// GetAudit returns the changes matching the filter, newest first
+func (s *Service) GetAudit(ctx context.Context, filter dto.FilterAuditDto) ([]dto.GetAudit, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	logs, err := s.auditRepository.GetAudit(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := []dto.GetAudit{}
+	for _, log := range logs {
+		data = append(data, dto.GetAudit{
+			ID:         log.ID,
+			Actor:      log.Actor,
+			Action:     log.Action,
+			EntityType: log.EntityType,
+			EntityId:   log.EntityId,
+			Before:     log.Before,
+			After:      log.After,
+			RequestId:  log.RequestId,
+			CreatedAt:  log.CreatedAt,
+		})
+	}
+
+	return data, nil, util.SUCCESS
+}
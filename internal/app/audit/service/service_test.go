@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/service"
+	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/audit/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+const contextTimeout = 2 * time.Second
+
+func TestGetAudit(t *testing.T) {
+	mockRepository := new(mockRepositories.AuditRepository)
+
+	reset := func() {
+		mockRepository = new(mockRepositories.AuditRepository)
+	}
+
+	t.Run("Test Get Audit Default Limit", func(t *testing.T) {
+		defer reset()
+
+		logs := []model.AuditLog{{ID: 1, Actor: "admin", Action: "CREATE", EntityType: "brand", EntityId: 1, After: json.RawMessage(`{"title":"Nike"}`)}}
+		mockRepository.On("GetAudit", mock.Anything, dto.FilterAuditDto{EntityType: "brand", Limit: service.DefaultLimit}).Return(logs, nil)
+
+		auditService := service.NewAuditService(mockRepository, contextTimeout)
+		res, err, state := auditService.GetAudit(context.TODO(), dto.FilterAuditDto{EntityType: "brand"})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "admin", res[0].Actor)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Get Audit Empty", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("GetAudit", mock.Anything, dto.FilterAuditDto{Limit: 10}).Return(nil, nil)
+
+		auditService := service.NewAuditService(mockRepository, contextTimeout)
+		res, err, state := auditService.GetAudit(context.TODO(), dto.FilterAuditDto{Limit: 10})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Test Get Audit Database Error", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("GetAudit", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		auditService := service.NewAuditService(mockRepository, contextTimeout)
+		res, err, state := auditService.GetAudit(context.TODO(), dto.FilterAuditDto{})
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
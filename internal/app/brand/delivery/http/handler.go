@@ -1,7 +1,6 @@
 package http
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
@@ -24,29 +23,65 @@ func NewBrandHandlers(mux *http.ServeMux, service service.BrandService) {
 		}
 	})
 
+	mux.HandleFunc("/brand/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/brand/")
+		switch {
+		case action == "" && r.Method == "DELETE":
+			handler.Delete(w, r)
+		case action == "restore" && r.Method == "POST":
+			handler.Restore(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
 }
 
 func (b *BrandHandler) Create(w http.ResponseWriter, r *http.Request) error {
 	var res *util.Response
 
 	var payload dto.InsertBrandDto
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.Decode(r, &payload)
 
 	if err != nil {
-		code := util.GetResCode(util.SYSTEM_ERROR)
-		return res.JSON(w, false, code, err.Error(), nil)
+		code := util.GetResCode(util.GetDecodeState(err))
+		return res.Render(w, r, false, code, err.Error(), nil)
 	}
 
 	var valid bool
 	if valid, err = isRequestValid(&payload); !valid {
-		return res.JSON(w, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
 	}
 
 	result, err, state := b.BrandService.Create(r.Context(), payload)
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, http.StatusOK, "success", result)
+}
+
+// Delete soft deletes the brand of the path, e.g. DELETE /brand/3
+func (b *BrandHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/brand/")
+	result, err, state := b.BrandService.Delete(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, http.StatusOK, "success", result)
+}
+
+// Restore takes back the soft delete of the brand of the path, e.g. POST /brand/3/restore
+func (b *BrandHandler) Restore(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/brand/")
+	result, err, state := b.BrandService.Restore(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
 	}
-	return res.JSON(w, true, http.StatusOK, "success", result)
+	return res.Render(w, r, true, http.StatusOK, "success", result)
 }
 
 func isRequestValid(payload *dto.InsertBrandDto) (bool, error) {
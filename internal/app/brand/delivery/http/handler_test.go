@@ -34,7 +34,7 @@ func TestCreateBrand(t *testing.T) {
 
 	t.Run("Test Create Brand Success", func(t *testing.T) {
 		defer reset()
-		mockService.On("Create", context.TODO(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
 
 		brandHttp.NewBrandHandlers(mux, mockService)
 		handler := brandHttp.BrandHandler{
@@ -52,7 +52,7 @@ func TestCreateBrand(t *testing.T) {
 
 	t.Run("Test Create Brand Duplicate", func(t *testing.T) {
 		defer reset()
-		mockService.On("Create", context.TODO(), payload).Return(nil, errors.New("DUPLICATE"), "DUPLICATE")
+		mockService.On("Create", context.Background(), payload).Return(nil, errors.New("DUPLICATE"), "DUPLICATE")
 
 		brandHttp.NewBrandHandlers(mux, mockService)
 		handler := brandHttp.BrandHandler{
@@ -67,6 +67,40 @@ func TestCreateBrand(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
 	})
+	t.Run("Test Create Brand Xml", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+
+		brandHttp.NewBrandHandlers(mux, mockService)
+		handler := brandHttp.BrandHandler{BrandService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/brand", strings.NewReader("<brand><title>Nike</title></brand>"))
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "<data><id>1</id></data>")
+
+	})
+
+	t.Run("Test Create Brand Unsupported Content Type", func(t *testing.T) {
+		defer reset()
+
+		brandHttp.NewBrandHandlers(mux, mockService)
+		handler := brandHttp.BrandHandler{BrandService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/brand", strings.NewReader("title=Nike"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	})
+
 	t.Run("Test Create Product No Payload", func(t *testing.T) {
 		defer reset()
 
@@ -104,3 +138,50 @@ func TestCreateBrand(t *testing.T) {
 
 	})
 }
+
+func TestDeleteAndRestoreBrand(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.BrandService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.BrandService)
+	}
+
+	t.Run("Test Delete Brand Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("Delete", context.Background(), 3).Return(map[string]interface{}{"id": 3}, nil, "SUCCESS")
+
+		brandHttp.NewBrandHandlers(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodDelete, "/brand/3", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Restore Brand Not Found", func(t *testing.T) {
+		defer reset()
+		mockService.On("Restore", context.Background(), 3).Return(nil, errors.New("Brand Id Doesn't exists"), "NOT_FOUND")
+
+		brandHttp.NewBrandHandlers(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/brand/3/restore", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Test Unknown Brand Action", func(t *testing.T) {
+		defer reset()
+
+		brandHttp.NewBrandHandlers(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/brand/3/archive", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -8,6 +8,8 @@ type FilterBrandDto struct {
 	ID    int    `json:"int"`
 	Title string `json:"title"`
 	Limit int    `json:"limit"`
+	//IncludeDeleted also returns the soft-deleted brands, which are left out otherwise
+	IncludeDeleted bool `json:"includeDeleted"`
 }
 
 type GetBrand struct {
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// Memory is a BrandRepository keeping the brands in memory, for tests and for running without a database.
+// Changes are recorded to the audit log, and their events written to the outbox, right after they are made, there
+// is no transaction to share.
+type Memory struct {
+	mu     sync.RWMutex
+	brands []model.Brand
+	audit  auditRepository.AuditRepository
+	outbox eventRepository.OutboxRepository
+}
+
+func NewMemoryBrand(audit auditRepository.AuditRepository, outbox eventRepository.OutboxRepository) *Memory {
+	return &Memory{audit: audit, outbox: outbox}
+}
+
+func (m *Memory) Create(ctx context.Context, payload dto.InsertBrandDto) (*model.Brand, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	//the title is a unique key, as it is in the database
+	for _, brand := range m.brands {
+		if brand.Title == payload.Title {
+			return nil, fmt.Errorf("brand %s: %w", payload.Title, util.ErrDuplicate)
+		}
+	}
+
+	brand := model.Brand{ID: len(m.brands) + 1, Title: payload.Title}
+	m.brands = append(m.brands, brand)
+
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityBrand, EntityId: brand.ID, After: payload})
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.outbox.Add(ctx, nil, brandCreated(brand.ID, payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Brand{ID: brand.ID}, nil
+}
+
+func (m *Memory) GetBrand(ctx context.Context, filter dto.FilterBrandDto) (data []model.Brand, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	//brands are kept in the order of their id
+	for _, brand := range m.brands {
+		if filter.ID > 0 && brand.ID != filter.ID {
+			continue
+		}
+		if filter.Title != "" && brand.Title != filter.Title {
+			continue
+		}
+		if !filter.IncludeDeleted && brand.DeletedAt != "" {
+			continue
+		}
+		if filter.Limit > 0 && len(data) == filter.Limit {
+			break
+		}
+		data = append(data, brand)
+	}
+
+	return data, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, id int) (bool, error) {
+	//formatted the way the datetime columns read back from the database
+	return m.setDeletedAt(ctx, model.AuditActionDelete, id, time.Now().UTC().Format(time.RFC3339))
+}
+
+func (m *Memory) Restore(ctx context.Context, id int) (bool, error) {
+	return m.setDeletedAt(ctx, model.AuditActionUpdate, id, "")
+}
+
+// setDeletedAt tells whether the brand is there and its deletedAt changed, the change is recorded as action
+func (m *Memory) setDeletedAt(ctx context.Context, action string, id int, deletedAt string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.brands {
+		if m.brands[i].ID != id {
+			continue
+		}
+		previous := m.brands[i].DeletedAt
+		if (previous == "") == (deletedAt == "") {
+			return false, nil
+		}
+		m.brands[i].DeletedAt = deletedAt
+
+		change := auditDto.Change{Action: action, EntityType: model.AuditEntityBrand, EntityId: id,
+			Before: map[string]interface{}{"deletedAt": nullable(previous)}, After: map[string]interface{}{"deletedAt": nullable(deletedAt)}}
+		return true, m.audit.Record(ctx, nil, change)
+	}
+	return false, nil
+}
+
+// nullable is nil for an empty value, the way it is NULL in the database
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
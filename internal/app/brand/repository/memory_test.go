@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	auditMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/audit/repository"
+	eventMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestMemoryBrand(t *testing.T) {
+	audit := new(auditMocks.AuditRepository)
+	audit.On("Record", mock.Anything, nil, mock.AnythingOfType("dto.Change")).Return(nil)
+	outbox := new(eventMocks.OutboxRepository)
+	outbox.On("Add", mock.Anything, nil, mock.AnythingOfType("dto.Event")).Return(nil)
+	r := repository.NewMemoryBrand(audit, outbox)
+	ctx := context.TODO()
+
+	var wg sync.WaitGroup
+	for _, title := range []string{"Nike", "Adidas", "Puma"} {
+		wg.Add(1)
+		go func(title string) {
+			defer wg.Done()
+			_, err := r.Create(ctx, dto.InsertBrandDto{Title: title})
+			assert.Nil(t, err)
+		}(title)
+	}
+	wg.Wait()
+
+	t.Run("Test Brand Without Filter", func(t *testing.T) {
+		result, err := r.GetBrand(ctx, dto.FilterBrandDto{})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 3)
+		for i, brand := range result {
+			assert.Equal(t, i+1, brand.ID)
+		}
+	})
+
+	t.Run("Test Brand With Filter", func(t *testing.T) {
+		result, err := r.GetBrand(ctx, dto.FilterBrandDto{Title: "Puma", Limit: 1})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Puma", result[0].Title)
+
+		result, err = r.GetBrand(ctx, dto.FilterBrandDto{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, []int{result[0].ID, result[1].ID})
+
+		result, err = r.GetBrand(ctx, dto.FilterBrandDto{ID: 4})
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Brand(nil), result)
+	})
+
+	t.Run("Test Brand Duplicate Title", func(t *testing.T) {
+		_, err := r.Create(ctx, dto.InsertBrandDto{Title: "Nike"})
+
+		assert.True(t, util.IsDuplicate(err))
+	})
+
+	var deletedAt string
+	t.Run("Test Brand Soft Delete", func(t *testing.T) {
+		deleted, err := r.Delete(ctx, 1)
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+		deleted, err = r.Delete(ctx, 1)
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+
+		result, err := r.GetBrand(ctx, dto.FilterBrandDto{ID: 1})
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+
+		result, err = r.GetBrand(ctx, dto.FilterBrandDto{ID: 1, IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, result[0].DeletedAt)
+		deletedAt = result[0].DeletedAt
+
+		restored, err := r.Restore(ctx, 1)
+		assert.Nil(t, err)
+		assert.True(t, restored)
+
+		result, err = r.GetBrand(ctx, dto.FilterBrandDto{ID: 1})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+
+	})
+
+	t.Run("Test Brand Changes Are Recorded", func(t *testing.T) {
+		//three brands created, one deleted and restored, the duplicate left out
+		audit.AssertNumberOfCalls(t, "Record", 5)
+		audit.AssertCalled(t, "Record", ctx, nil, mock.MatchedBy(func(change auditDto.Change) bool {
+			return change.Action == model.AuditActionCreate && change.After == dto.InsertBrandDto{Title: "Puma"}
+		}))
+		audit.AssertCalled(t, "Record", ctx, nil, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityBrand, EntityId: 1,
+			Before: map[string]interface{}{"deletedAt": deletedAt}, After: map[string]interface{}{"deletedAt": nil}})
+	})
+
+	t.Run("Test Brand Created Events", func(t *testing.T) {
+		outbox.AssertNumberOfCalls(t, "Add", 3)
+		outbox.AssertCalled(t, "Add", ctx, nil, mock.MatchedBy(func(event eventDto.Event) bool {
+			return event.Type == model.EventBrandCreated && event.Payload.(map[string]interface{})["title"] == "Puma"
+		}))
+	})
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
@@ -12,25 +17,48 @@ import (
 type BrandRepository interface {
 	Create(ctx context.Context, dto dto.InsertBrandDto) (*model.Brand, error)
 	GetBrand(ctx context.Context, dto dto.FilterBrandDto) (data []model.Brand, err error)
+	Delete(ctx context.Context, id int) (bool, error)
+	Restore(ctx context.Context, id int) (bool, error)
 }
 
 type Repository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Dialect dialect.Dialect
+	Audit   auditRepository.AuditRepository
+	Outbox  eventRepository.OutboxRepository
 }
 
-func NewBrand(db *sql.DB) *Repository {
-	return &Repository{db}
+func NewBrand(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect, auditRepository.NewAudit(db, dialect), eventRepository.NewOutbox(db, dialect)}
 }
 
 func (r *Repository) Create(ctx context.Context, dto dto.InsertBrandDto) (*model.Brand, error) {
-	sqlCommand := "INSERT into brand (title, createdAt, updatedAt) values(?, NOW(), NOW())"
-	result, err := r.DB.ExecContext(ctx, sqlCommand, &dto.Title)
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlCommand := "INSERT into brand (title, createdAt, updatedAt) values(?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)"
+	id, err := r.Dialect.Insert(ctx, tx, sqlCommand, &dto.Title)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityBrand, EntityId: int(id), After: dto})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = r.Outbox.Add(ctx, tx, brandCreated(int(id), dto))
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	var id int64
-	id, err = result.LastInsertId()
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +68,7 @@ func (r *Repository) Create(ctx context.Context, dto dto.InsertBrandDto) (*model
 func (r *Repository) GetBrand(ctx context.Context, filter dto.FilterBrandDto) (data []model.Brand, err error) {
 
 	var filterValues []interface{}
-	query := "SELECT id, title from brand"
+	query := "SELECT id, title, deletedAt from brand"
 
 	if filter.ID > 0 {
 		query += util.FilterHandler(filterValues) + ` id = ?`
@@ -52,6 +80,11 @@ func (r *Repository) GetBrand(ctx context.Context, filter dto.FilterBrandDto) (d
 		filterValues = append(filterValues, filter.Title)
 	}
 
+	//last, FilterHandler tells WHERE from AND by the values before it
+	if !filter.IncludeDeleted {
+		query += util.FilterHandler(filterValues) + ` deletedAt IS NULL`
+	}
+
 	query += ` ORDER BY id `
 
 	if filter.Limit > 0 {
@@ -60,7 +93,7 @@ func (r *Repository) GetBrand(ctx context.Context, filter dto.FilterBrandDto) (d
 	}
 
 	var rows *sql.Rows
-	rows, err = r.DB.QueryContext(ctx, query, filterValues...)
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,13 +102,97 @@ func (r *Repository) GetBrand(ctx context.Context, filter dto.FilterBrandDto) (d
 
 	for rows.Next() {
 		brand := model.Brand{}
+		var deletedAt sql.NullString
 		err = rows.Scan(
 			&brand.ID,
 			&brand.Title,
+			&deletedAt,
 		)
+		brand.DeletedAt = deletedAt.String
 
 		data = append(data, brand)
 	}
 
 	return data, nil
 }
+
+// Delete soft deletes the brand, it tells whether the brand was there and not deleted yet
+func (r *Repository) Delete(ctx context.Context, id int) (bool, error) {
+	query := `UPDATE brand SET deletedAt = CURRENT_TIMESTAMP, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND deletedAt IS NULL`
+	return r.setDeletedAt(ctx, model.AuditActionDelete, query, id)
+}
+
+// Restore takes back the soft delete of the brand, it tells whether the brand was deleted
+func (r *Repository) Restore(ctx context.Context, id int) (bool, error) {
+	query := `UPDATE brand SET deletedAt = NULL, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND deletedAt IS NOT NULL`
+	return r.setDeletedAt(ctx, model.AuditActionUpdate, query, id)
+}
+
+// setDeletedAt runs the query changing the deletedAt of the brand and records it as action, it tells whether
+// the brand changed
+func (r *Repository) setDeletedAt(ctx context.Context, action string, query string, id int) (bool, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	before, err := r.deletedAt(ctx, tx, id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind(query), id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	after, err := r.deletedAt(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: action, EntityType: model.AuditEntityBrand, EntityId: id, Before: before, After: after})
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// deletedAt reads the deletedAt of the brand for the audit log, locking the brand until the transaction ends
+func (r *Repository) deletedAt(ctx context.Context, tx *sql.Tx, id int) (map[string]interface{}, error) {
+	var deletedAt sql.NullString
+	err := tx.QueryRowContext(ctx, r.Dialect.Rebind(`SELECT deletedAt FROM brand WHERE id = ?`+r.Dialect.ForUpdate()), id).Scan(&deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{"deletedAt": nil}
+	if deletedAt.Valid {
+		fields["deletedAt"] = deletedAt.String
+	}
+	return fields, nil
+}
+
+func brandCreated(id int, payload dto.InsertBrandDto) eventDto.Event {
+	return eventDto.Event{Type: model.EventBrandCreated, EntityId: id, Payload: map[string]interface{}{"id": id, "title": payload.Title}}
+}
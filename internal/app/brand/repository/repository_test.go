@@ -8,9 +8,11 @@ import (
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
 
 func TestGetBrand(t *testing.T) {
@@ -23,27 +25,27 @@ func TestGetBrand(t *testing.T) {
 	mockBrand = append(mockBrand, model.Brand{ID: 1, Title: "Nike"})
 	mockBrand = append(mockBrand, model.Brand{ID: 2, Title: "Adidas"})
 
-	rows := sqlmock.NewRows([]string{"id", "title"}).
-		AddRow(mockBrand[0].ID, mockBrand[0].Title).
-		AddRow(mockBrand[1].ID, mockBrand[1].Title)
+	rows := sqlmock.NewRows([]string{"id", "title", "deletedAt"}).
+		AddRow(mockBrand[0].ID, mockBrand[0].Title, nil).
+		AddRow(mockBrand[1].ID, mockBrand[1].Title, nil)
 
 	reset := func() {
 		mockBrand := []model.Brand{}
 		mockBrand = append(mockBrand, model.Brand{ID: 1, Title: "Nike"})
 		mockBrand = append(mockBrand, model.Brand{ID: 2, Title: "Adidas"})
 
-		rows = sqlmock.NewRows([]string{"id", "title"}).
-			AddRow(mockBrand[0].ID, mockBrand[0].Title).
-			AddRow(mockBrand[1].ID, mockBrand[1].Title)
+		rows = sqlmock.NewRows([]string{"id", "title", "deletedAt"}).
+			AddRow(mockBrand[0].ID, mockBrand[0].Title, nil).
+			AddRow(mockBrand[1].ID, mockBrand[1].Title, nil)
 	}
 
 	t.Run("Test Brand Without Filter", func(t *testing.T) {
 
 		defer reset()
-		query := "SELECT id, title from brand ORDER BY id"
+		query := "SELECT id, title, deletedAt from brand WHERE deletedAt IS NULL ORDER BY id"
 
 		mock.ExpectQuery(query).WillReturnRows(rows)
-		r := repository.NewBrand(db)
+		r := repository.NewBrand(db, dialect.MySQL)
 		filter := dto.FilterBrandDto{}
 		result, err := r.GetBrand(context.TODO(), filter)
 
@@ -53,24 +55,42 @@ func TestGetBrand(t *testing.T) {
 
 	t.Run("Test Brand With Filter", func(t *testing.T) {
 
-		query := "SELECT id, title from brand"
+		query := "SELECT id, title, deletedAt from brand WHERE id = (.+) AND title = (.+) AND deletedAt IS NULL ORDER BY id"
 
 		filter := dto.FilterBrandDto{ID: 1, Title: "Nike", Limit: 1}
 		mock.ExpectQuery(query).WithArgs(filter.ID, filter.Title, filter.Limit).WillReturnRows(rows)
-		r := repository.NewBrand(db)
+		r := repository.NewBrand(db, dialect.MySQL)
 		result, err := r.GetBrand(context.TODO(), filter)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 	})
 
+	t.Run("Test Brand Including Deleted", func(t *testing.T) {
+
+		defer reset()
+		query := "SELECT id, title, deletedAt from brand ORDER BY id"
+
+		rows := sqlmock.NewRows([]string{"id", "title", "deletedAt"}).
+			AddRow(1, "Nike", nil).
+			AddRow(3, "Puma", "2026-10-19 09:00:00")
+		mock.ExpectQuery(query).WillReturnRows(rows)
+		r := repository.NewBrand(db, dialect.MySQL)
+		result, err := r.GetBrand(context.TODO(), dto.FilterBrandDto{IncludeDeleted: true})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "", result[0].DeletedAt)
+		assert.Equal(t, "2026-10-19 09:00:00", result[1].DeletedAt)
+	})
+
 	t.Run("Test Brand Error", func(t *testing.T) {
 
 		defer reset()
-		query := "SELECT id, title from brand ORDER BY id"
+		query := "SELECT id, title, deletedAt from brand WHERE deletedAt IS NULL ORDER BY id"
 
 		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
-		r := repository.NewBrand(db)
+		r := repository.NewBrand(db, dialect.MySQL)
 		filter := dto.FilterBrandDto{}
 		result, err := r.GetBrand(context.TODO(), filter)
 
@@ -93,12 +113,19 @@ func TestCreateBrand(t *testing.T) {
 		}
 		query := "INSERT into brand"
 
+		mock.ExpectBegin()
 		mock.ExpectExec(query).WithArgs(payload.Title).WillReturnResult(sqlmock.NewResult(1, 1))
-		r := repository.NewBrand(db)
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityBrand, 1, nil, `{"title":"Puma"}`, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventBrandCreated, 1, `{"id":1,"title":"Puma"}`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewBrand(db, dialect.MySQL)
 		result, err := r.Create(context.TODO(), payload)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Test Create Brand Error", func(t *testing.T) {
@@ -108,11 +135,134 @@ func TestCreateBrand(t *testing.T) {
 		}
 		query := "INSERT into brand"
 
+		mock.ExpectBegin()
 		mock.ExpectExec(query).WithArgs(payload.Title).WillReturnError(errors.New("Error From Database"))
-		r := repository.NewBrand(db)
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
 		result, err := r.Create(context.TODO(), payload)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, result)
 	})
+
+	t.Run("Test Create Brand Error Recording Audit", func(t *testing.T) {
+
+		payload := dto.InsertBrandDto{
+			Title: "Puma",
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into brand").WithArgs(payload.Title).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnError(errors.New("Error From Database"))
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Brand Error Writing Event", func(t *testing.T) {
+
+		payload := dto.InsertBrandDto{
+			Title: "Puma",
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into brand").WithArgs(payload.Title).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WillReturnError(errors.New("Error From Database"))
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteBrand(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	selectQuery := "SELECT deletedAt FROM brand WHERE id = (.+) FOR UPDATE"
+
+	t.Run("Test Delete Brand Success", func(t *testing.T) {
+		query := "UPDATE brand SET deletedAt = CURRENT_TIMESTAMP, updatedAt = CURRENT_TIMESTAMP WHERE id = (.+) AND deletedAt IS NULL"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow(nil))
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow("2026-10-19 10:00:00"))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionDelete, model.AuditEntityBrand, 1,
+			`{"deletedAt":null}`, `{"deletedAt":"2026-10-19 10:00:00"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewBrand(db, dialect.MySQL)
+		deleted, err := r.Delete(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Delete Brand Already Deleted", func(t *testing.T) {
+		query := "UPDATE brand SET deletedAt"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow("2026-10-19 10:00:00"))
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
+		deleted, err := r.Delete(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("Test Delete Brand Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}))
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
+		deleted, err := r.Delete(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("Test Restore Brand Success", func(t *testing.T) {
+		query := "UPDATE brand SET deletedAt = NULL, updatedAt = CURRENT_TIMESTAMP WHERE id = (.+) AND deletedAt IS NOT NULL"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow("2026-10-19 10:00:00"))
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow(nil))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityBrand, 1,
+			`{"deletedAt":"2026-10-19 10:00:00"}`, `{"deletedAt":null}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewBrand(db, dialect.MySQL)
+		restored, err := r.Restore(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.True(t, restored)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Restore Brand Error", func(t *testing.T) {
+		query := "UPDATE brand SET deletedAt = NULL"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow("2026-10-19 10:00:00"))
+		mock.ExpectExec(query).WithArgs(1).WillReturnError(errors.New("Error From Database"))
+		mock.ExpectRollback()
+		r := repository.NewBrand(db, dialect.MySQL)
+		restored, err := r.Restore(context.TODO(), 1)
+
+		assert.NotNil(t, err)
+		assert.False(t, restored)
+	})
 }
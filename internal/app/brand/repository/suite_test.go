@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
+	eventPublisher "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	eventService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestBrandSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		r := repository.NewBrand(db, d)
+		ctx := context.TODO()
+
+		nike, err := r.Create(ctx, dto.InsertBrandDto{Title: "Nike"})
+		assert.Nil(t, err)
+		adidas, err := r.Create(ctx, dto.InsertBrandDto{Title: "Adidas"})
+		assert.Nil(t, err)
+		assert.Equal(t, nike.ID+1, adidas.ID)
+
+		t.Run("Test Brand Without Filter", func(t *testing.T) {
+			result, err := r.GetBrand(ctx, dto.FilterBrandDto{})
+
+			assert.Nil(t, err)
+			assert.Equal(t, []model.Brand{{ID: nike.ID, Title: "Nike"}, {ID: adidas.ID, Title: "Adidas"}}, result)
+		})
+
+		t.Run("Test Brand With Filter", func(t *testing.T) {
+			result, err := r.GetBrand(ctx, dto.FilterBrandDto{Title: "Adidas", Limit: 1})
+
+			assert.Nil(t, err)
+			assert.Equal(t, []model.Brand{{ID: adidas.ID, Title: "Adidas"}}, result)
+		})
+
+		t.Run("Test Brand Duplicate Title", func(t *testing.T) {
+			_, err := r.Create(ctx, dto.InsertBrandDto{Title: "Nike"})
+
+			assert.True(t, util.IsDuplicate(err))
+		})
+
+		t.Run("Test Brand Soft Delete", func(t *testing.T) {
+			deleted, err := r.Delete(ctx, nike.ID)
+			assert.Nil(t, err)
+			assert.True(t, deleted)
+
+			result, err := r.GetBrand(ctx, dto.FilterBrandDto{})
+			assert.Nil(t, err)
+			assert.Equal(t, []model.Brand{{ID: adidas.ID, Title: "Adidas"}}, result)
+
+			result, err = r.GetBrand(ctx, dto.FilterBrandDto{ID: nike.ID, IncludeDeleted: true})
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.NotEmpty(t, result[0].DeletedAt)
+
+			//the title stays taken by the deleted brand
+			_, err = r.Create(ctx, dto.InsertBrandDto{Title: "Nike"})
+			assert.True(t, util.IsDuplicate(err))
+
+			restored, err := r.Restore(ctx, nike.ID)
+			assert.Nil(t, err)
+			assert.True(t, restored)
+			restored, err = r.Restore(ctx, nike.ID)
+			assert.Nil(t, err)
+			assert.False(t, restored)
+
+			result, err = r.GetBrand(ctx, dto.FilterBrandDto{})
+			assert.Nil(t, err)
+			assert.Len(t, result, 2)
+		})
+
+		t.Run("Test Brand Changes Are Audited", func(t *testing.T) {
+			logs, err := auditRepository.NewAudit(db, d).GetAudit(ctx, auditDto.FilterAuditDto{EntityType: model.AuditEntityBrand})
+			assert.Nil(t, err)
+
+			//the duplicate creates were rolled back with their audit logs
+			var actions []string
+			for _, log := range logs {
+				if log.EntityId == nike.ID {
+					actions = append(actions, log.Action)
+				}
+			}
+			assert.Equal(t, []string{model.AuditActionUpdate, model.AuditActionDelete, model.AuditActionCreate}, actions)
+			assert.Len(t, logs, 4)
+			assert.Equal(t, util.SystemActor, logs[0].Actor)
+		})
+
+		t.Run("Test Brand Created Events Are Relayed", func(t *testing.T) {
+			published := eventPublisher.NewMemory()
+			relay := eventService.NewRelay(eventRepository.NewOutbox(db, d), published)
+
+			//the duplicate creates were rolled back with their events
+			count, err := relay.RunOnce(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, count)
+			messages := published.Messages()
+			assert.Equal(t, model.EventBrandCreated, messages[0].Type)
+			assert.Equal(t, nike.ID, messages[0].EntityId)
+			assert.JSONEq(t, fmt.Sprintf(`{"id":%d,"title":"Adidas"}`, adidas.ID), string(messages[1].Payload))
+
+			count, err = relay.RunOnce(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, count)
+		})
+	})
+}
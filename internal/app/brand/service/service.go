@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
@@ -14,10 +16,18 @@ import (
 type BrandService interface {
 	Create(ctx context.Context, payload dto.InsertBrandDto) (interface{}, error, string)
 	CheckBrandById(ctx context.Context, id int) (*model.Brand, error, string)
+	CheckBrandByTitle(ctx context.Context, title string) (*model.Brand, error, string)
+	Delete(ctx context.Context, id int) (interface{}, error, string)
+	Restore(ctx context.Context, id int) (interface{}, error, string)
+	RegisterChangeHandler(handler ChangeHandler)
 }
 
+// ChangeHandler is notified after a brand is deleted or restored, which hides or shows its products
+type ChangeHandler func(ctx context.Context, brandId int) error
+
 type Service struct {
 	brandRepository repository.BrandRepository
+	changeHandlers  []ChangeHandler
 	contextTimeout  time.Duration
 }
 
@@ -32,8 +42,8 @@ func (s *Service) Create(ctx context.Context, payload dto.InsertBrandDto) (inter
 	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
 	defer cancel()
 
-	//Check Brand By Title
-	filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1}
+	//Check Brand By Title, a deleted brand keeps its title until it is restored
+	filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1, IncludeDeleted: true}
 
 	brand, err := s.brandRepository.GetBrand(ctx, filter)
 	if err != nil {
@@ -41,6 +51,9 @@ func (s *Service) Create(ctx context.Context, payload dto.InsertBrandDto) (inter
 	}
 
 	//if brand exists
+	if len(brand) > 0 && brand[0].DeletedAt != "" {
+		return nil, fmt.Errorf("Brand title already Exists as deleted brand %d, restore it instead", brand[0].ID), util.DUPLICATE
+	}
 	if len(brand) > 0 {
 		return nil, errors.New("Brand title already Exists"), "DUPLICATE"
 	}
@@ -48,7 +61,7 @@ func (s *Service) Create(ctx context.Context, payload dto.InsertBrandDto) (inter
 	//Create Brand
 	result, err := s.brandRepository.Create(ctx, payload)
 	if err != nil {
-		return nil, err, util.SYSTEM_ERROR
+		return nil, err, util.ErrorState(err)
 	}
 
 	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
@@ -73,3 +86,77 @@ func (s *Service) CheckBrandById(ctx context.Context, id int) (*model.Brand, err
 
 	return &brand[0], nil, util.SUCCESS
 }
+
+func (s *Service) CheckBrandByTitle(ctx context.Context, title string) (*model.Brand, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	filter := dto.FilterBrandDto{Title: title, Limit: 1}
+
+	brand, err := s.brandRepository.GetBrand(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if len(brand) == 0 {
+		return nil, fmt.Errorf("Brand %s Doesn't exists", title), util.NOT_FOUND
+	}
+
+	return &brand[0], nil, util.SUCCESS
+}
+
+func (s *Service) RegisterChangeHandler(handler ChangeHandler) {
+	s.changeHandlers = append(s.changeHandlers, handler)
+}
+
+// notifyChange runs the change handlers, the change is already stored so their errors are only logged
+func (s *Service) notifyChange(ctx context.Context, brandId int) {
+	for _, handler := range s.changeHandlers {
+		if err := handler(ctx, brandId); err != nil {
+			log.Printf("failed to run change handler for brand %d: %s", brandId, err.Error())
+		}
+	}
+}
+
+// Delete soft deletes the brand, its products are left out with it and orders keep referring to both
+func (s *Service) Delete(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	deleted, err := s.brandRepository.Delete(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !deleted {
+		return nil, errors.New("Brand Id Doesn't exists"), util.NOT_FOUND
+	}
+
+	s.notifyChange(ctx, id)
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
+
+// Restore takes back the soft delete of the brand
+func (s *Service) Restore(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	restored, err := s.brandRepository.Restore(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if !restored {
+		//a brand that is not deleted from one that is not there
+		brand, err := s.brandRepository.GetBrand(ctx, dto.FilterBrandDto{ID: id, Limit: 1, IncludeDeleted: true})
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		if len(brand) == 0 {
+			return nil, errors.New("Brand Id Doesn't exists"), util.NOT_FOUND
+		}
+		return nil, errors.New("Brand is not deleted"), util.INVALID_STATE
+	}
+
+	s.notifyChange(ctx, id)
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
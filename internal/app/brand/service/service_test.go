@@ -10,6 +10,8 @@ import (
 	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/brand/repository"
 
 	"github.com/go-faker/faker/v4"
+	"github.com/go-faker/faker/v4/pkg/options"
+	"github.com/go-sql-driver/mysql"
 	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +33,7 @@ func TestBrandCheckById(t *testing.T) {
 	t.Run("Test Brand By Id Success", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockBrand)
+		err := faker.FakeData(&mockBrand, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		brandService := service.NewBrandService(mockRepository, contextTimeout)
@@ -90,7 +92,7 @@ func TestBrandCreate(t *testing.T) {
 		err := faker.FakeData(&payload)
 		assert.NoError(t, err)
 
-		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1}
+		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1, IncludeDeleted: true}
 		mockRepository.On("GetBrand", mock.Anything, filter).Return(mockBrand, nil)
 		mockRepository.On("Create", mock.Anything, payload).Return(&model.Brand{ID: 1}, nil)
 
@@ -104,14 +106,14 @@ func TestBrandCreate(t *testing.T) {
 	t.Run("Test Brand Create Already Exists", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockBrand)
+		err := faker.FakeData(&mockBrand, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		payload := dto.InsertBrandDto{}
 		err = faker.FakeData(&payload)
 		assert.NoError(t, err)
 
-		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1}
+		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1, IncludeDeleted: true}
 		mockRepository.On("GetBrand", mock.Anything, filter).Return(mockBrand, nil)
 		mockRepository.On("Create", mock.Anything, payload).Return(&model.Brand{ID: 1}, nil)
 
@@ -123,4 +125,168 @@ func TestBrandCreate(t *testing.T) {
 		assert.Equal(t, "DUPLICATE", state)
 	})
 
+	t.Run("Test Brand Create Title Of Deleted Brand", func(t *testing.T) {
+		defer reset()
+
+		payload := dto.InsertBrandDto{}
+		err := faker.FakeData(&payload)
+		assert.NoError(t, err)
+
+		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1, IncludeDeleted: true}
+		mockRepository.On("GetBrand", mock.Anything, filter).Return([]model.Brand{{ID: 4, Title: payload.Title, DeletedAt: "2026-10-19 09:00:00"}}, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Create(context.Background(), payload)
+
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), "restore it")
+		assert.Equal(t, "DUPLICATE", state)
+	})
+
+	t.Run("Test Brand Create Concurrently Created", func(t *testing.T) {
+		defer reset()
+
+		payload := dto.InsertBrandDto{}
+		err := faker.FakeData(&payload)
+		assert.NoError(t, err)
+
+		//another request created the brand between the check and the insert
+		filter := dto.FilterBrandDto{Title: payload.Title, Limit: 1, IncludeDeleted: true}
+		mockRepository.On("GetBrand", mock.Anything, filter).Return(mockBrand, nil)
+		mockRepository.On("Create", mock.Anything, payload).Return(nil, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Create(context.Background(), payload)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "DUPLICATE", state)
+	})
+
+}
+
+func TestBrandCheckByTitle(t *testing.T) {
+	mockRepository := new(mockRepositories.BrandRepository)
+
+	reset := func() {
+		mockRepository = new(mockRepositories.BrandRepository)
+	}
+
+	t.Run("Test Brand By Title Success", func(t *testing.T) {
+		defer reset()
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		filter := dto.FilterBrandDto{Title: "Nike", Limit: 1}
+		mockRepository.On("GetBrand", mock.Anything, filter).Return([]model.Brand{{ID: 2, Title: "Nike"}}, nil)
+
+		res, err, state := brandService.CheckBrandByTitle(context.TODO(), "Nike")
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 2, res.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Brand By Title Not Found", func(t *testing.T) {
+		defer reset()
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		mockRepository.On("GetBrand", mock.Anything, mock.Anything).Return(nil, nil)
+
+		res, err, state := brandService.CheckBrandByTitle(context.TODO(), "Nike")
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestBrandDelete(t *testing.T) {
+	mockRepository := new(mockRepositories.BrandRepository)
+
+	reset := func() {
+		mockRepository = new(mockRepositories.BrandRepository)
+	}
+
+	t.Run("Test Brand Delete Success", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Delete", mock.Anything, 1).Return(true, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		var changed []int
+		brandService.RegisterChangeHandler(func(ctx context.Context, brandId int) error {
+			changed = append(changed, brandId)
+			return nil
+		})
+		res, err, state := brandService.Delete(context.Background(), 1)
+
+		assert.Equal(t, map[string]interface{}{"id": 1}, res)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1}, changed)
+	})
+
+	t.Run("Test Brand Delete Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Delete", mock.Anything, 1).Return(false, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Delete(context.Background(), 1)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+	})
+}
+
+func TestBrandRestore(t *testing.T) {
+	mockRepository := new(mockRepositories.BrandRepository)
+
+	reset := func() {
+		mockRepository = new(mockRepositories.BrandRepository)
+	}
+
+	filter := dto.FilterBrandDto{ID: 1, Limit: 1, IncludeDeleted: true}
+
+	t.Run("Test Brand Restore Success", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Restore", mock.Anything, 1).Return(true, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Restore(context.Background(), 1)
+
+		assert.Equal(t, map[string]interface{}{"id": 1}, res)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Brand Restore Not Deleted", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Restore", mock.Anything, 1).Return(false, nil)
+		mockRepository.On("GetBrand", mock.Anything, filter).Return([]model.Brand{{ID: 1, Title: "Nike"}}, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Restore(context.Background(), 1)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "INVALID_STATE", state)
+	})
+
+	t.Run("Test Brand Restore Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Restore", mock.Anything, 1).Return(false, nil)
+		mockRepository.On("GetBrand", mock.Anything, filter).Return([]model.Brand{}, nil)
+
+		brandService := service.NewBrandService(mockRepository, contextTimeout)
+		res, err, state := brandService.Restore(context.Background(), 1)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+	})
 }
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type CategoryHandler struct {
+	CategoryService service.CategoryService
+}
+
+func NewCategoryHandler(mux *http.ServeMux, service service.CategoryService) {
+	handler := CategoryHandler{CategoryService: service}
+
+	mux.HandleFunc("/categories", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.Create(w, r)
+		case "GET":
+			handler.GetCategoryTree(w, r)
+		}
+	})
+}
+
+func (b *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	var payload dto.InsertCategoryDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.CategoryService.Create(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+	result, err, state := b.CategoryService.GetCategoryTree(r.Context())
+
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func isRequestValid(dto *dto.InsertCategoryDto) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(dto)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
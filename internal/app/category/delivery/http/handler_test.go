@@ -0,0 +1,89 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	categoryHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/service"
+)
+
+func TestCreateCategory(t *testing.T) {
+	mux := http.NewServeMux()
+	payload := dto.InsertCategoryDto{Title: "Running", ParentId: 1}
+
+	mockService := new(mocks.CategoryService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.CategoryService)
+	}
+
+	t.Run("Test Create Category Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 2, "slug": "running"}, nil, "SUCCESS")
+
+		categoryHttp.NewCategoryHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/categories", strings.NewReader(`{"title":"Running","parentId":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Category Without Title", func(t *testing.T) {
+		defer reset()
+		handler := categoryHttp.CategoryHandler{CategoryService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/categories", strings.NewReader(`{"parentId":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	mux := http.NewServeMux()
+	mockService := new(mocks.CategoryService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.CategoryService)
+	}
+
+	t.Run("Test Get Category Tree Success", func(t *testing.T) {
+		defer reset()
+		tree := []dto.GetCategory{{ID: 1, Title: "Shoes", Slug: "shoes", Children: []dto.GetCategory{{ID: 2, ParentId: 1, Title: "Running", Slug: "running"}}}}
+		mockService.On("GetCategoryTree", context.Background()).Return(tree, nil, "SUCCESS")
+
+		categoryHttp.NewCategoryHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"children":[{"id":2,"parentId":1`)
+	})
+
+	t.Run("Test Get Category Tree Error System", func(t *testing.T) {
+		defer reset()
+		mockService.On("GetCategoryTree", context.Background()).Return(nil, errors.New("Database Error"), "SYSTEM_ERROR")
+
+		categoryHttp.NewCategoryHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,24 @@
+package dto
+
+type InsertCategoryDto struct {
+	Title     string `json:"title" validate:"required,max=100"`
+	Slug      string `json:"slug" validate:"omitempty,max=100"`
+	ParentId  int    `json:"parentId" validate:"gte=0"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+type FilterCategoryDto struct {
+	ID    int    `json:"id"`
+	IDs   []int  `json:"ids"`
+	Slug  string `json:"slug"`
+	Limit int    `json:"limit"`
+}
+
+type GetCategory struct {
+	ID        int           `json:"id"`
+	ParentId  int           `json:"parentId,omitempty"`
+	Title     string        `json:"title"`
+	Slug      string        `json:"slug"`
+	SortOrder int           `json:"sortOrder"`
+	Children  []GetCategory `json:"children"`
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, payload dto.InsertCategoryDto) (*model.Category, error)
+	GetCategories(ctx context.Context, filter dto.FilterCategoryDto) (data []model.Category, err error)
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewCategory(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) Create(ctx context.Context, payload dto.InsertCategoryDto) (*model.Category, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	//PROCESS CATEGORY
+	query := `INSERT INTO category (parentId, title, slug, sortOrder, createdAt, updatedAt)
+	values(NULLIF(?, 0), ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, tx, query, payload.ParentId, payload.Title, payload.Slug, payload.SortOrder)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS CATEGORY
+
+	//PROCESS CATEGORY PATH
+	//the new category inherits every ancestor of its parent one level deeper, plus itself at depth 0
+	query = `INSERT INTO category_path (ancestorId, descendantId, depth)
+	SELECT category_path.ancestorId, category.id, category_path.depth + 1 FROM category_path
+	JOIN category ON category.id = ? WHERE category_path.descendantId = ?
+	UNION ALL SELECT id, id, 0 FROM category WHERE id = ?`
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), id, payload.ParentId, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS CATEGORY PATH
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Category{ID: int(id), ParentId: payload.ParentId, Title: payload.Title, Slug: payload.Slug, SortOrder: payload.SortOrder}, nil
+}
+
+func (r *Repository) GetCategories(ctx context.Context, filter dto.FilterCategoryDto) (data []model.Category, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, parentId, title, slug, sortOrder FROM category`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if len(filter.IDs) > 0 {
+		placeholders := make([]string, len(filter.IDs))
+		for i := range placeholders {
+			placeholders[i] = "?"
+		}
+		query += util.FilterHandler(filterValues) + fmt.Sprintf(` id IN (%s)`, strings.Join(placeholders, ","))
+		for _, id := range filter.IDs {
+			filterValues = append(filterValues, id)
+		}
+	}
+
+	if filter.Slug != "" {
+		query += util.FilterHandler(filterValues) + ` slug = ?`
+		filterValues = append(filterValues, filter.Slug)
+	}
+
+	query += ` ORDER BY sortOrder, title `
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentId sql.NullInt64
+		category := model.Category{}
+		err = rows.Scan(&category.ID, &parentId, &category.Title, &category.Slug, &category.SortOrder)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentId = int(parentId.Int64)
+
+		data = append(data, category)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/repository"
+)
+
+func TestCreateCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertCategoryDto{Title: "Running Shoes", Slug: "running-shoes", ParentId: 2, SortOrder: 1}
+	query := "INSERT INTO category"
+	queryPath := "INSERT INTO category_path"
+
+	t.Run("Test Create Category Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(payload.ParentId, payload.Title, payload.Slug, payload.SortOrder).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(queryPath).WithArgs(5, payload.ParentId, 5).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		r := repository.NewCategory(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, result.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Category Error Path", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(queryPath).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewCategory(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := `SELECT id, parentId, title, slug, sortOrder FROM category`
+
+	t.Run("Test Get Categories Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "parentId", "title", "slug", "sortOrder"}).
+			AddRow(1, nil, "Shoes", "shoes", 0).
+			AddRow(2, 1, "Running", "running", 0)
+		mock.ExpectQuery(query).WillReturnRows(rows)
+
+		r := repository.NewCategory(db, dialect.MySQL)
+		result, err := r.GetCategories(context.TODO(), dto.FilterCategoryDto{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result[0].ParentId)
+		assert.Equal(t, 1, result[1].ParentId)
+	})
+
+	t.Run("Test Get Categories With Filter", func(t *testing.T) {
+		mock.ExpectQuery(query+regexp.QuoteMeta(` WHERE id IN (?,?) AND slug = ?`)).WithArgs(1, 2, "shoes", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "parentId", "title", "slug", "sortOrder"}))
+
+		r := repository.NewCategory(db, dialect.MySQL)
+		_, err := r.GetCategories(context.TODO(), dto.FilterCategoryDto{IDs: []int{1, 2}, Slug: "shoes", Limit: 1})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Get Categories Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewCategory(db, dialect.MySQL)
+		_, err := r.GetCategories(context.TODO(), dto.FilterCategoryDto{})
+
+		assert.NotNil(t, err)
+	})
+}
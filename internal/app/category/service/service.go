@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, payload dto.InsertCategoryDto) (interface{}, error, string)
+	GetCategoryTree(ctx context.Context) ([]dto.GetCategory, error, string)
+	CheckCategories(ctx context.Context, ids []int) ([]model.Category, error, string)
+}
+
+type Service struct {
+	categoryRepository repository.CategoryRepository
+	contextTimeout     time.Duration
+}
+
+func NewCategoryService(r repository.CategoryRepository, timeout time.Duration) CategoryService {
+	return &Service{
+		categoryRepository: r,
+		contextTimeout:     timeout,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, payload dto.InsertCategoryDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.Slug == "" {
+		payload.Slug = payload.Title
+	}
+	payload.Slug = slugify(payload.Slug)
+	if payload.Slug == "" {
+		return nil, errors.New("Slug must contain letters or digits"), util.VALIDATION_ERROR
+	}
+
+	//Check Category By Slug
+	categories, err := s.categoryRepository.GetCategories(ctx, dto.FilterCategoryDto{Slug: payload.Slug, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(categories) > 0 {
+		return nil, errors.New("Category slug already Exists"), util.DUPLICATE
+	}
+
+	if payload.ParentId > 0 {
+		_, err, state := s.CheckCategories(ctx, []int{payload.ParentId})
+		if err != nil {
+			return nil, err, state
+		}
+	}
+
+	result, err := s.categoryRepository.Create(ctx, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+
+	return map[string]interface{}{"id": result.ID, "slug": result.Slug}, nil, util.SUCCESS
+}
+
+// GetCategoryTree returns the root categories with their descendants nested as children, siblings sorted by sort order
+func (s *Service) GetCategoryTree(ctx context.Context) ([]dto.GetCategory, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	categories, err := s.categoryRepository.GetCategories(ctx, dto.FilterCategoryDto{})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	children := map[int][]model.Category{}
+	for _, category := range categories {
+		children[category.ParentId] = append(children[category.ParentId], category)
+	}
+
+	return buildTree(children, 0), nil, util.SUCCESS
+}
+
+func buildTree(children map[int][]model.Category, parentId int) []dto.GetCategory {
+	tree := []dto.GetCategory{}
+	for _, category := range children[parentId] {
+		tree = append(tree, dto.GetCategory{
+			ID:        category.ID,
+			ParentId:  category.ParentId,
+			Title:     category.Title,
+			Slug:      category.Slug,
+			SortOrder: category.SortOrder,
+			Children:  buildTree(children, category.ID),
+		})
+	}
+	return tree
+}
+
+// CheckCategories returns the categories with the given ids, failing when one of them doesn't exist
+func (s *Service) CheckCategories(ctx context.Context, ids []int) ([]model.Category, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	categories, err := s.categoryRepository.GetCategories(ctx, dto.FilterCategoryDto{IDs: ids})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	found := map[int]bool{}
+	for _, category := range categories {
+		found[category.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("Category Id %d Doesn't exists", id), util.NOT_FOUND
+		}
+	}
+
+	return categories, nil, util.SUCCESS
+}
+
+// slugify lowercases s and joins its runs of letters and digits with dashes
+func slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
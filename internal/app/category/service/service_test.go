@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	CategoryService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/service"
+	mockCategoryRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockCategoryRepository = new(mockCategoryRepositories.CategoryRepository)
+	categoryService        = CategoryService.NewCategoryService(mockCategoryRepository, contextTimeout)
+)
+
+func reset() {
+	mockCategoryRepository = new(mockCategoryRepositories.CategoryRepository)
+	categoryService = CategoryService.NewCategoryService(mockCategoryRepository, contextTimeout)
+}
+
+func TestCreateCategory(t *testing.T) {
+	t.Run("Test Create Category Generates Slug", func(t *testing.T) {
+		defer reset()
+		payload := dto.InsertCategoryDto{Title: "Men's Running Shoes", ParentId: 1}
+		expected := payload
+		expected.Slug = "men-s-running-shoes"
+
+		mockCategoryRepository.On("GetCategories", mock.Anything, dto.FilterCategoryDto{Slug: "men-s-running-shoes", Limit: 1}).Return(nil, nil)
+		mockCategoryRepository.On("GetCategories", mock.Anything, dto.FilterCategoryDto{IDs: []int{1}}).Return([]model.Category{{ID: 1}}, nil)
+		mockCategoryRepository.On("Create", mock.Anything, expected).Return(&model.Category{ID: 2, Slug: expected.Slug}, nil)
+
+		res, err, state := categoryService.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 2, res.(map[string]interface{})["id"])
+	})
+
+	t.Run("Test Create Category Duplicate Slug", func(t *testing.T) {
+		defer reset()
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return([]model.Category{{ID: 1, Slug: "shoes"}}, nil)
+
+		res, err, state := categoryService.Create(context.TODO(), dto.InsertCategoryDto{Title: "Shoes"})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "DUPLICATE", state)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Category Parent Not Found", func(t *testing.T) {
+		defer reset()
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return(nil, nil)
+
+		res, err, state := categoryService.Create(context.TODO(), dto.InsertCategoryDto{Title: "Running", ParentId: 9})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, res)
+		mockCategoryRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Category Empty Slug", func(t *testing.T) {
+		defer reset()
+
+		_, err, state := categoryService.Create(context.TODO(), dto.InsertCategoryDto{Title: "!!!"})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	t.Run("Test Get Category Tree Success", func(t *testing.T) {
+		defer reset()
+		mockCategoryRepository.On("GetCategories", mock.Anything, dto.FilterCategoryDto{}).Return([]model.Category{
+			{ID: 1, Title: "Shoes", Slug: "shoes"},
+			{ID: 4, Title: "Bags", Slug: "bags", SortOrder: 1},
+			{ID: 2, ParentId: 1, Title: "Running", Slug: "running"},
+			{ID: 3, ParentId: 2, Title: "Trail", Slug: "trail"},
+		}, nil)
+
+		res, err, state := categoryService.GetCategoryTree(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "shoes", res[0].Slug)
+		assert.Equal(t, "trail", res[0].Children[0].Children[0].Slug)
+		assert.Empty(t, res[1].Children)
+	})
+
+	t.Run("Test Get Category Tree Error Database", func(t *testing.T) {
+		defer reset()
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := categoryService.GetCategoryTree(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.Nil(t, res)
+	})
+}
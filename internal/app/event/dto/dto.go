@@ -0,0 +1,20 @@
+package dto
+
+import "encoding/json"
+
+// Event is a domain event to write to the outbox, Type is one of the model.Event constants and Payload is
+// encoded as JSON
+type Event struct {
+	Type     string
+	EntityId int
+	Payload  interface{}
+}
+
+// Message is an event as published. Delivery is at least once, consumers tell the copies of an event by ID.
+type Message struct {
+	ID         int             `json:"id"`
+	Type       string          `json:"type"`
+	EntityId   int             `json:"entityId"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt string          `json:"occurredAt"`
+}
@@ -0,0 +1,27 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+)
+
+// Fanout publishes every event to all its publishers. They all get the event even when one fails, the first error
+// is returned and the event is published to all of them again, so each one should take a repeat without harm.
+type Fanout struct {
+	Publishers []EventPublisher
+}
+
+func NewFanout(publishers ...EventPublisher) *Fanout {
+	return &Fanout{Publishers: publishers}
+}
+
+func (f *Fanout) Publish(ctx context.Context, message dto.Message) error {
+	var first error
+	for _, publisher := range f.Publishers {
+		if err := publisher.Publish(ctx, message); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
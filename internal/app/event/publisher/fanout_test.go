@@ -0,0 +1,38 @@
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/publisher"
+)
+
+func TestFanoutPublish(t *testing.T) {
+	message := dto.Message{ID: 7, Type: "BrandCreated", EntityId: 1}
+
+	t.Run("Test Publish To All", func(t *testing.T) {
+		first, second := publisher.NewMemory(), publisher.NewMemory()
+
+		err := publisher.NewFanout(first, second).Publish(context.TODO(), message)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.Message{message}, first.Messages())
+		assert.Equal(t, []dto.Message{message}, second.Messages())
+	})
+
+	t.Run("Test Publish Failed Still Reaches The Others", func(t *testing.T) {
+		failing := new(mocks.EventPublisher)
+		failing.On("Publish", context.TODO(), message).Return(errors.New("Webhook answered 503"))
+		memory := publisher.NewMemory()
+
+		err := publisher.NewFanout(failing, memory).Publish(context.TODO(), message)
+
+		assert.EqualError(t, err, "Webhook answered 503")
+		assert.Equal(t, []dto.Message{message}, memory.Messages())
+	})
+}
@@ -0,0 +1,65 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// EventIdHeader carries the event id, the same on every delivery of the event
+const EventIdHeader = "X-Event-Id"
+
+// HTTP posts every event as JSON to a webhook URL. The body is signed with the secret like the payment webhooks,
+// see util.Sign, and any status but 2xx is a failed delivery.
+type HTTP struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+func NewHTTP(url string, secret string) *HTTP {
+	return &HTTP{URL: url, Secret: secret, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (h *HTTP) Publish(ctx context.Context, message dto.Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Post(ctx, message.ID, body)
+	return err
+}
+
+// Post sends body, the event of eventId, signed with the current time. It returns the status code of the
+// response, 0 when none came back.
+func (h *HTTP) Post(ctx context.Context, eventId int, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIdHeader, strconv.Itoa(eventId))
+	req.Header.Set(util.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(util.SignatureHeader, util.Sign(h.Secret, timestamp, body))
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("Webhook answered %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,58 @@
+package publisher_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestHTTPPublish(t *testing.T) {
+	message := dto.Message{ID: 7, Type: "BrandCreated", EntityId: 1, Payload: json.RawMessage(`{"id":1,"title":"Nike"}`), OccurredAt: "2026-10-19 10:00:00"}
+
+	t.Run("Test Publish Signed", func(t *testing.T) {
+		var received dto.Message
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "7", r.Header.Get(publisher.EventIdHeader))
+			assert.True(t, util.VerifySignature("secret", r.Header.Get(util.TimestampHeader), r.Header.Get(util.SignatureHeader), body, time.Minute, time.Now()))
+			json.Unmarshal(body, &received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		err := publisher.NewHTTP(server.URL, "secret").Publish(context.TODO(), message)
+
+		assert.Nil(t, err)
+		assert.Equal(t, message, received)
+	})
+
+	t.Run("Test Publish Rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := publisher.NewHTTP(server.URL, "secret").Publish(context.TODO(), message)
+
+		assert.EqualError(t, err, "Webhook answered 503")
+	})
+
+	t.Run("Test Publish Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		err := publisher.NewHTTP(server.URL, "secret").Publish(context.TODO(), message)
+
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,22 @@
+package publisher
+
+import (
+	"context"
+	"log"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+)
+
+// Log writes the events to the app log, for running without a consumer
+type Log struct {
+	Printf func(format string, v ...interface{})
+}
+
+func NewLog() *Log {
+	return &Log{Printf: log.Printf}
+}
+
+func (l *Log) Publish(ctx context.Context, message dto.Message) error {
+	l.Printf("Event %d %s of %d: %s", message.ID, message.Type, message.EntityId, message.Payload)
+	return nil
+}
@@ -0,0 +1,34 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+)
+
+// Memory keeps the published events, for tests and local runs
+type Memory struct {
+	mu       sync.Mutex
+	messages []dto.Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(ctx context.Context, message dto.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Messages returns the events published so far, in the order they were published
+func (m *Memory) Messages() []dto.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]dto.Message(nil), m.messages...)
+}
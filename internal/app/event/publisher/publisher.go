@@ -0,0 +1,13 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+)
+
+// EventPublisher is implemented by every backend domain events are published to. An error leaves the event in the
+// outbox to be tried again, so a publisher may get the same event more than once.
+type EventPublisher interface {
+	Publish(ctx context.Context, message dto.Message) error
+}
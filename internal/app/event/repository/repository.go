@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type OutboxRepository interface {
+	// Add writes the event with db, the transaction making the change or nil when there is none, so the event is
+	// only published when the change is kept
+	Add(ctx context.Context, db dialect.Execer, event dto.Event) error
+	// GetPending returns the oldest events not published yet that are due at now
+	GetPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int) error
+	// MarkFailed counts a failed attempt and puts the next one off until nextAttemptAt
+	MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewOutbox(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+// the outbox dates are written by the app in UTC, the relay compares them with its own clock and not the database one
+func utc(t time.Time) string {
+	return t.UTC().Format(util.DateTimeLayout)
+}
+
+func (r *Repository) Add(ctx context.Context, db dialect.Execer, event dto.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+
+	if db == nil {
+		db = r.DB
+	}
+
+	now := utc(time.Now())
+	query := `INSERT INTO outbox_event (type, entityId, payload, attempts, nextAttemptAt, createdAt) values(?, ?, ?, 0, ?, ?)`
+	_, err = db.ExecContext(ctx, r.Dialect.Rebind(query), event.Type, event.EntityId, string(payload), now, now)
+	return err
+}
+
+func (r *Repository) GetPending(ctx context.Context, now time.Time, limit int) (data []model.OutboxEvent, err error) {
+	query := `SELECT id, type, entityId, payload, attempts, nextAttemptAt, createdAt FROM outbox_event
+	WHERE publishedAt IS NULL AND nextAttemptAt <= ? ORDER BY id LIMIT ?`
+
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), utc(now), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			event   model.OutboxEvent
+			payload string
+		)
+		err = rows.Scan(&event.ID, &event.Type, &event.EntityId, &payload, &event.Attempts, &event.NextAttemptAt, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = json.RawMessage(payload)
+
+		data = append(data, event)
+	}
+
+	return data, rows.Err()
+}
+
+func (r *Repository) MarkPublished(ctx context.Context, id int) error {
+	query := `UPDATE outbox_event SET publishedAt = ?, lastError = NULL WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), utc(time.Now()), id)
+	return err
+}
+
+func (r *Repository) MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE outbox_event SET attempts = attempts + 1, nextAttemptAt = ?, lastError = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), utc(nextAttemptAt), lastError, id)
+	return err
+}
@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestAdd(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO outbox_event"
+	event := dto.Event{Type: model.EventBrandCreated, EntityId: 1, Payload: map[string]interface{}{"id": 1, "title": "Nike"}}
+
+	t.Run("Test Add In Transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs("BrandCreated", 1, `{"id":1,"title":"Nike"}`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		err = r.Add(context.TODO(), tx, event)
+		tx.Rollback()
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Add Error Encoding Payload", func(t *testing.T) {
+		r := repository.NewOutbox(db, dialect.MySQL)
+		err := r.Add(context.TODO(), nil, dto.Event{Type: model.EventBrandCreated, EntityId: 1, Payload: make(chan int)})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Add Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		err := r.Add(context.TODO(), nil, event)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta(`SELECT id, type, entityId, payload, attempts, nextAttemptAt, createdAt FROM outbox_event
+	WHERE publishedAt IS NULL AND nextAttemptAt <= ? ORDER BY id LIMIT ?`)
+	columns := []string{"id", "type", "entityId", "payload", "attempts", "nextAttemptAt", "createdAt"}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Test Get Pending Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "OrderCreated", 3, `{"id":3}`, 0, "2026-10-19 09:59:00", "2026-10-19 09:59:00").
+			AddRow(2, "OrderStatusChanged", 3, `{"id":3,"status":"PAID"}`, 2, "2026-10-19 10:00:00", "2026-10-19 09:58:00")
+		mock.ExpectQuery(query).WithArgs("2026-10-19 10:00:00", 100).WillReturnRows(rows)
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		result, err := r.GetPending(context.TODO(), now, 100)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.JSONEq(t, `{"id":3,"status":"PAID"}`, string(result[1].Payload))
+		assert.Equal(t, 2, result[1].Attempts)
+	})
+
+	t.Run("Test Get Pending Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		result, err := r.GetPending(context.TODO(), now, 100)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestMarkEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	t.Run("Test Mark Published", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox_event SET publishedAt = ?, lastError = NULL WHERE id = ?`)).
+			WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		err := r.MarkPublished(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Mark Failed", func(t *testing.T) {
+		next := time.Date(2026, 10, 19, 10, 0, 5, 0, time.UTC)
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox_event SET attempts = attempts + 1, nextAttemptAt = ?, lastError = ? WHERE id = ?`)).
+			WithArgs("2026-10-19 10:00:05", "Webhook answered 500", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewOutbox(db, dialect.MySQL)
+		err := r.MarkFailed(context.TODO(), 1, next, "Webhook answered 500")
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+)
+
+// Relay publishes the events of the outbox. An event is marked published only once the publisher took it, a
+// failed one is tried again after Backoff, doubled on every failure up to MaxBackoff, so every event gets
+// published at least once.
+type Relay struct {
+	BatchSize  int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	outbox    repository.OutboxRepository
+	publisher publisher.EventPublisher
+}
+
+func NewRelay(outbox repository.OutboxRepository, publisher publisher.EventPublisher) *Relay {
+	return &Relay{
+		BatchSize:  100,
+		Backoff:    5 * time.Second,
+		MaxBackoff: time.Hour,
+		outbox:     outbox,
+		publisher:  publisher,
+	}
+}
+
+// RunOnce publishes the events due now, in the order they were written, and returns how many got published
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := r.outbox.GetPending(ctx, now, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		message := dto.Message{ID: event.ID, Type: event.Type, EntityId: event.EntityId, Payload: event.Payload, OccurredAt: event.CreatedAt}
+
+		if err = r.publisher.Publish(ctx, message); err != nil {
+			if err = r.outbox.MarkFailed(ctx, event.ID, now.Add(r.backoff(event.Attempts)), err.Error()); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err = r.outbox.MarkPublished(ctx, event.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// backoff is the wait before the next attempt of an event that failed attempts times before this one
+func (r *Relay) backoff(attempts int) time.Duration {
+	wait := r.Backoff
+	for i := 0; i < attempts && wait < r.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > r.MaxBackoff {
+		wait = r.MaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/service"
+	publisherMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/publisher"
+	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestRelayRunOnce(t *testing.T) {
+	mockOutbox := new(mockRepositories.OutboxRepository)
+	mockPublisher := new(publisherMocks.EventPublisher)
+
+	reset := func() {
+		mockOutbox = new(mockRepositories.OutboxRepository)
+		mockPublisher = new(publisherMocks.EventPublisher)
+	}
+
+	events := []model.OutboxEvent{
+		{ID: 1, Type: model.EventOrderCreated, EntityId: 3, Payload: json.RawMessage(`{"id":3}`), CreatedAt: "2026-10-19 10:00:00"},
+		{ID: 2, Type: model.EventOrderStatusChanged, EntityId: 3, Payload: json.RawMessage(`{"id":3,"status":"PAID"}`), Attempts: 2},
+	}
+
+	t.Run("Test Relay Publishes In Order", func(t *testing.T) {
+		defer reset()
+
+		mockOutbox.On("GetPending", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return(events, nil)
+		mockPublisher.On("Publish", mock.Anything, dto.Message{ID: 1, Type: model.EventOrderCreated, EntityId: 3, Payload: events[0].Payload,
+			OccurredAt: "2026-10-19 10:00:00"}).Return(nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(message dto.Message) bool { return message.ID == 2 })).Return(nil).Once()
+		mockOutbox.On("MarkPublished", mock.Anything, 1).Return(nil).Once()
+		mockOutbox.On("MarkPublished", mock.Anything, 2).Return(nil).Once()
+
+		relay := service.NewRelay(mockOutbox, mockPublisher)
+		published, err := relay.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, published)
+		mockOutbox.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Test Relay Backs Off Failed Events", func(t *testing.T) {
+		defer reset()
+
+		mockOutbox.On("GetPending", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return(events, nil)
+		mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(errors.New("Webhook answered 503"))
+
+		var waits []time.Duration
+		start := time.Now()
+		mockOutbox.On("MarkFailed", mock.Anything, mock.AnythingOfType("int"), mock.AnythingOfType("time.Time"), "Webhook answered 503").
+			Run(func(args mock.Arguments) {
+				waits = append(waits, args.Get(2).(time.Time).Sub(start))
+			}).Return(nil)
+
+		relay := service.NewRelay(mockOutbox, mockPublisher)
+		published, err := relay.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, published)
+		mockOutbox.AssertNotCalled(t, "MarkPublished", mock.Anything, mock.Anything)
+		//5s on the first failure, doubled for each one before it
+		assert.InDelta(t, 5*time.Second, waits[0], float64(time.Second))
+		assert.InDelta(t, 20*time.Second, waits[1], float64(time.Second))
+	})
+
+	t.Run("Test Relay Backoff Is Capped", func(t *testing.T) {
+		defer reset()
+
+		mockOutbox.On("GetPending", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.OutboxEvent{{ID: 3, Attempts: 40}}, nil)
+		mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(errors.New("Connection refused"))
+
+		var next time.Time
+		mockOutbox.On("MarkFailed", mock.Anything, 3, mock.AnythingOfType("time.Time"), "Connection refused").
+			Run(func(args mock.Arguments) { next = args.Get(2).(time.Time) }).Return(nil)
+
+		relay := service.NewRelay(mockOutbox, mockPublisher)
+		_, err := relay.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), next, time.Second)
+	})
+
+	t.Run("Test Relay Error Database", func(t *testing.T) {
+		defer reset()
+
+		mockOutbox.On("GetPending", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		relay := service.NewRelay(mockOutbox, mockPublisher)
+		published, err := relay.RunOnce(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, published)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type JobHandler struct {
+	JobService service.JobService
+}
+
+func NewJobHandler(mux *http.ServeMux, service service.JobService) {
+	handler := JobHandler{JobService: service}
+
+	mux.HandleFunc("/job", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.GetJobs(w, r)
+		}
+	})
+
+	mux.HandleFunc("/job/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/job/")
+		switch {
+		case action == "retry" && r.Method == "POST":
+			handler.Retry(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// GetJobs lists the jobs, newest first, e.g. GET /job?status=DEAD&name=order.expire
+func (b *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	query := r.URL.Query()
+	filter := dto.FilterJobDto{Name: query.Get("name"), Status: query.Get("status")}
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	if valid, err := isRequestValid(&filter); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.JobService.GetJobs(r.Context(), filter)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+// Retry queues the dead job of the path again, e.g. POST /job/12/retry
+func (b *JobHandler) Retry(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/job/")
+	result, err, state := b.JobService.Retry(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func isRequestValid(payload interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(payload)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
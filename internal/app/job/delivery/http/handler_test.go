@@ -0,0 +1,67 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jobHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/job/service"
+)
+
+func TestGetJobs(t *testing.T) {
+	mockService := new(mocks.JobService)
+
+	t.Run("Test Get Jobs Success", func(t *testing.T) {
+		filter := dto.FilterJobDto{Name: "order.expire", Status: "DEAD", Limit: 10}
+		mockService.On("GetJobs", context.Background(), filter).Return([]dto.JobDto{{ID: 2, Name: "order.expire", Status: "DEAD"}}, nil, "SUCCESS").Once()
+		handler := jobHttp.JobHandler{JobService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/job?name=order.expire&status=DEAD&limit=10", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetJobs(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Get Jobs Unknown Status", func(t *testing.T) {
+		handler := jobHttp.JobHandler{JobService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/job?status=LOST", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetJobs(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	mockService := new(mocks.JobService)
+
+	t.Run("Test Retry Success", func(t *testing.T) {
+		mockService.On("Retry", context.Background(), 12).Return(map[string]interface{}{"id": 12, "status": "QUEUED"}, nil, "SUCCESS").Once()
+		handler := jobHttp.JobHandler{JobService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/job/12/retry", nil)
+		w := httptest.NewRecorder()
+		err := handler.Retry(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Retry Not Dead", func(t *testing.T) {
+		mockService.On("Retry", context.Background(), 13).Return(nil, errors.New("Job with status DONE can't be retried"), "INVALID_STATE").Once()
+		handler := jobHttp.JobHandler{JobService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/job/13/retry", nil)
+		w := httptest.NewRecorder()
+		err := handler.Retry(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EnqueueDto is a job to run. Payload is handed to its handler as JSON, a zero RunAt is now and a zero MaxAttempts
+// the default of the runner. A job is not queued while another one with its UniqueKey is queued or running.
+type EnqueueDto struct {
+	Name        string
+	Payload     interface{}
+	UniqueKey   string
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+type FilterJobDto struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name" validate:"max=100"`
+	Status string `json:"status" validate:"omitempty,oneof=QUEUED RUNNING DONE DEAD"`
+	Limit  int    `json:"limit" validate:"gte=0,lte=1000"`
+}
+
+type JobDto struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	RunAt       string          `json:"runAt"`
+	LockedUntil string          `json:"lockedUntil,omitempty"`
+	LastError   string          `json:"lastError,omitempty"`
+	FinishedAt  string          `json:"finishedAt,omitempty"`
+	CreatedAt   string          `json:"createdAt"`
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type JobRepository interface {
+	// Enqueue queues the job, it returns false when another one with its UniqueKey is queued or running
+	Enqueue(ctx context.Context, payload dto.EnqueueDto) (bool, error)
+	GetJobs(ctx context.Context, filter dto.FilterJobDto) ([]model.Job, error)
+	// Claim locks up to limit jobs due at now for visibility and counts an attempt of each: the queued ones and the
+	// running ones whose worker let its lock expire. A job is only ever claimed by one caller at a time, an expired
+	// one without attempts left is marked dead instead.
+	Claim(ctx context.Context, now time.Time, visibility time.Duration, limit int) ([]model.Job, error)
+	// MarkDone, MarkFailed and MarkDead keep the outcome of the job claimed as lockedBy, they leave it alone once
+	// the claim expired and another worker took it
+	MarkDone(ctx context.Context, id int, lockedBy string) error
+	// MarkFailed queues the job again for runAt
+	MarkFailed(ctx context.Context, id int, lockedBy string, runAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id int, lockedBy string, lastError string) error
+	// Requeue queues a dead job again, with all its attempts
+	Requeue(ctx context.Context, id int) (bool, error)
+	// DeleteFinished deletes the jobs that were done before before and returns how many
+	DeleteFinished(ctx context.Context, before time.Time) (int, error)
+}
+
+// workerGone is the last error of a job whose worker stopped without an outcome on its last attempt
+const workerGone = "Ran out of attempts, its worker stopped without an outcome"
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewJob(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+// the job dates are written by the app in UTC, the workers compare them with their own clock and not the database one
+func utc(t time.Time) string {
+	return t.UTC().Format(util.DateTimeLayout)
+}
+
+func (r *Repository) Enqueue(ctx context.Context, payload dto.EnqueueDto) (bool, error) {
+	body, err := json.Marshal(payload.Payload)
+	if err != nil {
+		return false, err
+	}
+
+	now := utc(time.Now())
+	runAt := now
+	if !payload.RunAt.IsZero() {
+		runAt = utc(payload.RunAt)
+	}
+
+	query := `INSERT INTO job (name, payload, uniqueKey, status, attempts, maxAttempts, runAt, createdAt, updatedAt)
+	values(?, ?, NULLIF(?, ''), ?, 0, ?, ?, ?, ?)`
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(r.Dialect.InsertIgnore(query)), payload.Name, string(body), payload.UniqueKey,
+		model.JobQueued, payload.MaxAttempts, runAt, now, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+const jobColumns = `id, name, payload, uniqueKey, status, attempts, maxAttempts, runAt, lockedBy, lockedUntil, lastError, finishedAt,
+	createdAt, updatedAt`
+
+func (r *Repository) GetJobs(ctx context.Context, filter dto.FilterJobDto) ([]model.Job, error) {
+	var filterValues []interface{}
+	query := `SELECT ` + jobColumns + ` FROM job`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.Name != "" {
+		query += util.FilterHandler(filterValues) + ` name = ?`
+		filterValues = append(filterValues, filter.Name)
+	}
+
+	if filter.Status != "" {
+		query += util.FilterHandler(filterValues) + ` status = ?`
+		filterValues = append(filterValues, filter.Status)
+	}
+
+	query += ` ORDER BY id DESC`
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	return r.queryJobs(ctx, query, filterValues...)
+}
+
+func (r *Repository) Claim(ctx context.Context, now time.Time, visibility time.Duration, limit int) ([]model.Job, error) {
+	//a job that keeps crashing or hanging its worker never reports a failure, so it's stopped here
+	query := `UPDATE job SET status = ?, uniqueKey = NULL, lockedBy = NULL, lockedUntil = NULL, lastError = ?, finishedAt = ?,
+	updatedAt = ? WHERE status = ? AND lockedUntil <= ? AND attempts >= maxAttempts`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.JobDead, workerGone, utc(now), utc(now), model.JobRunning, utc(now))
+	if err != nil {
+		return nil, err
+	}
+
+	due := `((status = ? AND runAt <= ?) OR (status = ? AND lockedUntil <= ? AND attempts < maxAttempts))`
+	dueValues := []interface{}{model.JobQueued, utc(now), model.JobRunning, utc(now)}
+
+	candidates, err := r.queryJobs(ctx, `SELECT `+jobColumns+` FROM job WHERE `+due+` ORDER BY runAt, id LIMIT ?`,
+		append(dueValues, limit)...)
+	if err != nil {
+		return nil, err
+	}
+
+	//the update only goes through for the first worker claiming a candidate, the others skip it
+	var claimed []model.Job
+	lockedUntil := utc(now.Add(visibility))
+	query = `UPDATE job SET status = ?, attempts = attempts + 1, lockedBy = ?, lockedUntil = ?, updatedAt = ? WHERE id = ? AND ` + due
+	for _, job := range candidates {
+		lockedBy, err := newClaim()
+		if err != nil {
+			return claimed, err
+		}
+
+		values := append([]interface{}{model.JobRunning, lockedBy, lockedUntil, utc(now), job.ID}, dueValues...)
+		result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), values...)
+		if err != nil {
+			return claimed, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			continue
+		}
+
+		job.Status, job.LockedBy, job.LockedUntil = model.JobRunning, lockedBy, lockedUntil
+		job.Attempts++
+		claimed = append(claimed, job)
+	}
+
+	return claimed, nil
+}
+
+func newClaim() (string, error) {
+	claim := make([]byte, 16)
+	if _, err := rand.Read(claim); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(claim), nil
+}
+
+func (r *Repository) MarkDone(ctx context.Context, id int, lockedBy string) error {
+	now := utc(time.Now())
+	query := `UPDATE job SET status = ?, uniqueKey = NULL, lockedBy = NULL, lockedUntil = NULL, lastError = NULL, finishedAt = ?,
+	updatedAt = ? WHERE id = ? AND lockedBy = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.JobDone, now, now, id, lockedBy)
+	return err
+}
+
+func (r *Repository) MarkFailed(ctx context.Context, id int, lockedBy string, runAt time.Time, lastError string) error {
+	query := `UPDATE job SET status = ?, runAt = ?, lockedBy = NULL, lockedUntil = NULL, lastError = ?, updatedAt = ?
+	WHERE id = ? AND lockedBy = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.JobQueued, utc(runAt), lastError, utc(time.Now()), id, lockedBy)
+	return err
+}
+
+func (r *Repository) MarkDead(ctx context.Context, id int, lockedBy string, lastError string) error {
+	now := utc(time.Now())
+	query := `UPDATE job SET status = ?, uniqueKey = NULL, lockedBy = NULL, lockedUntil = NULL, lastError = ?, finishedAt = ?,
+	updatedAt = ? WHERE id = ? AND lockedBy = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.JobDead, lastError, now, now, id, lockedBy)
+	return err
+}
+
+func (r *Repository) Requeue(ctx context.Context, id int) (bool, error) {
+	now := utc(time.Now())
+	query := `UPDATE job SET status = ?, attempts = 0, runAt = ?, finishedAt = NULL, updatedAt = ? WHERE id = ? AND status = ?`
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.JobQueued, now, now, id, model.JobDead)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) DeleteFinished(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(`DELETE FROM job WHERE status = ? AND finishedAt < ?`), model.JobDone, utc(before))
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r *Repository) queryJobs(ctx context.Context, query string, values ...interface{}) (data []model.Job, err error) {
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), values...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			job                                                     model.Job
+			payload                                                 string
+			uniqueKey, lockedBy, lockedUntil, lastError, finishedAt sql.NullString
+		)
+		err = rows.Scan(&job.ID, &job.Name, &payload, &uniqueKey, &job.Status, &job.Attempts, &job.MaxAttempts, &job.RunAt, &lockedBy,
+			&lockedUntil, &lastError, &finishedAt, &job.CreatedAt, &job.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		job.Payload = json.RawMessage(payload)
+		job.UniqueKey = uniqueKey.String
+		job.LockedBy = lockedBy.String
+		job.LockedUntil = lockedUntil.String
+		job.LastError = lastError.String
+		job.FinishedAt = finishedAt.String
+
+		data = append(data, job)
+	}
+
+	return data, rows.Err()
+}
@@ -0,0 +1,147 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+var jobColumns = []string{"id", "name", "payload", "uniqueKey", "status", "attempts", "maxAttempts", "runAt", "lockedBy", "lockedUntil",
+	"lastError", "finishedAt", "createdAt", "updatedAt"}
+
+func TestEnqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT IGNORE INTO job"
+	runAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Test Enqueue Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("order.expire", `{"id":1}`, "", model.JobQueued, 3, "2026-10-19 10:00:00", sqlmock.AnyArg(),
+			sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		queued, err := r.Enqueue(context.TODO(), dto.EnqueueDto{Name: "order.expire", Payload: map[string]int{"id": 1}, RunAt: runAt, MaxAttempts: 3})
+
+		assert.Nil(t, err)
+		assert.True(t, queued)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Enqueue Skipped For Unique Key", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("event.relay", "null", "schedule:event.relay", model.JobQueued, 5, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		queued, err := r.Enqueue(context.TODO(), dto.EnqueueDto{Name: "event.relay", UniqueKey: "schedule:event.relay", MaxAttempts: 5})
+
+		assert.Nil(t, err)
+		assert.False(t, queued)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Enqueue Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		queued, err := r.Enqueue(context.TODO(), dto.EnqueueDto{Name: "event.relay", MaxAttempts: 5})
+
+		assert.NotNil(t, err)
+		assert.False(t, queued)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestClaim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Test Claim Skips The Jobs Claimed By Another Worker", func(t *testing.T) {
+		rows := sqlmock.NewRows(jobColumns).
+			AddRow(1, "event.relay", "null", nil, model.JobQueued, 0, 5, "2026-10-19 09:59:59", nil, nil, nil, nil, "2026-10-19 09:59:59", "2026-10-19 09:59:59").
+			AddRow(2, "order.expire", "null", nil, model.JobRunning, 1, 5, "2026-10-19 09:50:00", "gone", "2026-10-19 09:55:00", nil, nil, "2026-10-19 09:50:00", "2026-10-19 09:50:00")
+		mock.ExpectExec("UPDATE job SET status (.+) attempts >= maxAttempts").
+			WithArgs(model.JobDead, sqlmock.AnyArg(), "2026-10-19 10:00:00", "2026-10-19 10:00:00", model.JobRunning, "2026-10-19 10:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT (.+) FROM job WHERE").
+			WithArgs(model.JobQueued, "2026-10-19 10:00:00", model.JobRunning, "2026-10-19 10:00:00", 2).WillReturnRows(rows)
+		mock.ExpectExec("UPDATE job SET status").
+			WithArgs(model.JobRunning, sqlmock.AnyArg(), "2026-10-19 10:05:00", "2026-10-19 10:00:00", 1, model.JobQueued, "2026-10-19 10:00:00",
+				model.JobRunning, "2026-10-19 10:00:00").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE job SET status").
+			WithArgs(model.JobRunning, sqlmock.AnyArg(), "2026-10-19 10:05:00", "2026-10-19 10:00:00", 2, model.JobQueued, "2026-10-19 10:00:00",
+				model.JobRunning, "2026-10-19 10:00:00").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		result, err := r.Claim(context.TODO(), now, 5*time.Minute, 2)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, 2, result[0].ID)
+		assert.Equal(t, model.JobRunning, result[0].Status)
+		assert.Equal(t, 2, result[0].Attempts)
+		assert.Len(t, result[0].LockedBy, 32)
+		assert.NotEqual(t, "gone", result[0].LockedBy)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Claim Error Database", func(t *testing.T) {
+		mock.ExpectExec("UPDATE job SET status").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT (.+) FROM job WHERE").WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		result, err := r.Claim(context.TODO(), now, 5*time.Minute, 2)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRequeue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE job SET status"
+
+	t.Run("Test Requeue Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(model.JobQueued, sqlmock.AnyArg(), sqlmock.AnyArg(), 7, model.JobDead).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		requeued, err := r.Requeue(context.TODO(), 7)
+
+		assert.Nil(t, err)
+		assert.True(t, requeued)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Requeue Not Dead", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(model.JobQueued, sqlmock.AnyArg(), sqlmock.AnyArg(), 8, model.JobDead).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		r := repository.NewJob(db, dialect.MySQL)
+		requeued, err := r.Requeue(context.TODO(), 8)
+
+		assert.Nil(t, err)
+		assert.False(t, requeued)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
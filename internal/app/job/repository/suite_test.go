@@ -0,0 +1,196 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestJobSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		r := repository.NewJob(db, d)
+		ctx := context.TODO()
+		now := time.Now()
+
+		t.Run("Test Unique Key Queues One Job At A Time", func(t *testing.T) {
+			job := dto.EnqueueDto{Name: "event.relay", UniqueKey: "schedule:event.relay", MaxAttempts: 5}
+			queued, err := r.Enqueue(ctx, job)
+			assert.Nil(t, err)
+			assert.True(t, queued)
+			queued, err = r.Enqueue(ctx, job)
+			assert.Nil(t, err)
+			assert.False(t, queued)
+
+			//jobs without a key never conflict
+			queued, err = r.Enqueue(ctx, dto.EnqueueDto{Name: "order.expire", Payload: map[string]int{"id": 1}, MaxAttempts: 2})
+			assert.Nil(t, err)
+			assert.True(t, queued)
+			queued, err = r.Enqueue(ctx, dto.EnqueueDto{Name: "order.expire", Payload: map[string]int{"id": 2}, MaxAttempts: 2})
+			assert.Nil(t, err)
+			assert.True(t, queued)
+
+			result, err := r.GetJobs(ctx, dto.FilterJobDto{Status: model.JobQueued})
+			assert.Nil(t, err)
+			assert.Len(t, result, 3)
+			assert.Equal(t, `{"id":2}`, string(result[0].Payload))
+		})
+
+		t.Run("Test Future Jobs Are Not Claimed", func(t *testing.T) {
+			queued, err := r.Enqueue(ctx, dto.EnqueueDto{Name: "job.cleanup", RunAt: now.Add(time.Hour), MaxAttempts: 1})
+			assert.Nil(t, err)
+			assert.True(t, queued)
+
+			result, err := r.Claim(ctx, now.Add(time.Second), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, result, 3)
+			for _, job := range result {
+				assert.NotEqual(t, "job.cleanup", job.Name)
+				assert.Equal(t, model.JobRunning, job.Status)
+				assert.Equal(t, 1, job.Attempts)
+			}
+
+			//all of them are taken
+			again, err := r.Claim(ctx, now.Add(time.Second), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Empty(t, again)
+
+			for _, job := range result {
+				if job.Name == "event.relay" {
+					assert.Nil(t, r.MarkDone(ctx, job.ID, job.LockedBy))
+				}
+			}
+
+			//done, the key is free again
+			queued, err = r.Enqueue(ctx, dto.EnqueueDto{Name: "event.relay", UniqueKey: "schedule:event.relay", MaxAttempts: 5})
+			assert.Nil(t, err)
+			assert.True(t, queued)
+		})
+
+		t.Run("Test Expired Claim Is Claimed Again", func(t *testing.T) {
+			running, err := r.GetJobs(ctx, dto.FilterJobDto{Name: "order.expire", Status: model.JobRunning})
+			assert.Nil(t, err)
+			assert.Len(t, running, 2)
+
+			result, err := r.Claim(ctx, now.Add(2*time.Minute), time.Minute, 10)
+			assert.Nil(t, err)
+			//the new event.relay and both order.expire jobs, their worker gone
+			assert.Len(t, result, 3)
+
+			stale, claimed := running[0], model.Job{}
+			for _, job := range result {
+				if job.ID == stale.ID {
+					claimed = job
+				}
+			}
+			assert.Equal(t, 2, claimed.Attempts)
+			assert.NotEqual(t, stale.LockedBy, claimed.LockedBy)
+
+			//the late worker can't overwrite the outcome of the new one
+			assert.Nil(t, r.MarkDone(ctx, stale.ID, stale.LockedBy))
+			jobs, err := r.GetJobs(ctx, dto.FilterJobDto{ID: stale.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, model.JobRunning, jobs[0].Status)
+
+			assert.Nil(t, r.MarkFailed(ctx, claimed.ID, claimed.LockedBy, now.Add(time.Hour), "Order service unavailable"))
+			jobs, err = r.GetJobs(ctx, dto.FilterJobDto{ID: claimed.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, model.JobQueued, jobs[0].Status)
+			assert.Equal(t, "Order service unavailable", jobs[0].LastError)
+			assert.Empty(t, jobs[0].LockedBy)
+
+			for _, job := range result {
+				if job.ID != claimed.ID {
+					assert.Nil(t, r.MarkDone(ctx, job.ID, job.LockedBy))
+				}
+			}
+		})
+
+		t.Run("Test Dead Job Is Requeued", func(t *testing.T) {
+			result, err := r.Claim(ctx, now.Add(2*time.Hour), time.Minute, 10)
+			assert.Nil(t, err)
+			//the failed order.expire job and job.cleanup
+			assert.Len(t, result, 2)
+
+			var dead model.Job
+			for _, job := range result {
+				if job.Name == "order.expire" {
+					dead = job
+					assert.Nil(t, r.MarkDead(ctx, job.ID, job.LockedBy, "Order service unavailable"))
+				} else {
+					assert.Nil(t, r.MarkDone(ctx, job.ID, job.LockedBy))
+				}
+			}
+
+			requeued, err := r.Requeue(ctx, dead.ID)
+			assert.Nil(t, err)
+			assert.True(t, requeued)
+			requeued, err = r.Requeue(ctx, dead.ID)
+			assert.Nil(t, err)
+			assert.False(t, requeued)
+
+			jobs, err := r.GetJobs(ctx, dto.FilterJobDto{ID: dead.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, model.JobQueued, jobs[0].Status)
+			assert.Equal(t, 0, jobs[0].Attempts)
+			assert.Empty(t, jobs[0].FinishedAt)
+		})
+
+		t.Run("Test Expired Claim Without Attempts Left Is Dead", func(t *testing.T) {
+			queued, err := r.Enqueue(ctx, dto.EnqueueDto{Name: "webhook.deliver", RunAt: now.Add(2 * time.Hour), MaxAttempts: 1})
+			assert.Nil(t, err)
+			assert.True(t, queued)
+
+			result, err := r.Claim(ctx, now.Add(2*time.Hour), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, result, 2)
+			//the requeued order.expire job is put back for later
+			var hanging model.Job
+			for _, job := range result {
+				if job.Name == "webhook.deliver" {
+					hanging = job
+				} else {
+					assert.Nil(t, r.MarkFailed(ctx, job.ID, job.LockedBy, now.Add(4*time.Hour), "Order service unavailable"))
+				}
+			}
+
+			//its worker never reports back, the expired claim isn't taken again
+			again, err := r.Claim(ctx, now.Add(3*time.Hour), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Empty(t, again)
+
+			jobs, err := r.GetJobs(ctx, dto.FilterJobDto{ID: hanging.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, model.JobDead, jobs[0].Status)
+			assert.Equal(t, 1, jobs[0].Attempts)
+			assert.Equal(t, "Ran out of attempts, its worker stopped without an outcome", jobs[0].LastError)
+			assert.NotEmpty(t, jobs[0].FinishedAt)
+			assert.Empty(t, jobs[0].LockedBy)
+		})
+
+		t.Run("Test Delete Finished Keeps The Others", func(t *testing.T) {
+			deleted, err := r.DeleteFinished(ctx, now.Add(-time.Hour))
+			assert.Nil(t, err)
+			assert.Equal(t, 0, deleted)
+
+			deleted, err = r.DeleteFinished(ctx, time.Now().Add(time.Hour))
+			assert.Nil(t, err)
+			assert.Equal(t, 4, deleted)
+
+			//dead jobs are kept to be requeued
+			result, err := r.GetJobs(ctx, dto.FilterJobDto{})
+			assert.Nil(t, err)
+			assert.Len(t, result, 2)
+			assert.Equal(t, model.JobDead, result[0].Status)
+			assert.Equal(t, model.JobQueued, result[1].Status)
+		})
+	})
+}
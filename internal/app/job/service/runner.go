@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+// Handler runs a job with its payload. An error has the job tried again after the backoff until it runs out of
+// attempts. A job may run more than once, a handler should take a repeat without harm.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Runner runs the queued jobs, Workers at a time, and queues the scheduled ones when they are due. A job is claimed
+// for VisibilityTimeout, the time it gets to run, and claimed again once that passed without an outcome, its worker
+// gone, so every job runs at least once. A failed job is tried again after Backoff, doubled on every failure up to
+// MaxBackoff, and left DEAD once it ran out of attempts.
+type Runner struct {
+	Workers           int
+	PollInterval      time.Duration
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	Backoff           time.Duration
+	MaxBackoff        time.Duration
+
+	repository repository.JobRepository
+	handlers   map[string]Handler
+	schedules  []*scheduled
+}
+
+type scheduled struct {
+	name     string
+	schedule Schedule
+	next     time.Time
+}
+
+func NewRunner(r repository.JobRepository) *Runner {
+	return &Runner{
+		Workers:           4,
+		PollInterval:      time.Second,
+		VisibilityTimeout: 5 * time.Minute,
+		MaxAttempts:       DefaultMaxAttempts,
+		Backoff:           10 * time.Second,
+		MaxBackoff:        time.Hour,
+		repository:        r,
+		handlers:          map[string]Handler{},
+	}
+}
+
+// Register has handler run the jobs named name
+func (r *Runner) Register(name string, handler Handler) {
+	r.handlers[name] = handler
+}
+
+// Schedule queues a job named name, without payload, at the times of spec, see ParseSchedule. A time is skipped
+// while the job queued at an earlier one is still queued or running, by this instance of the app or another.
+func (r *Runner) Schedule(name string, spec string) error {
+	if _, ok := r.handlers[name]; !ok {
+		return fmt.Errorf("no handler registered for job %s", name)
+	}
+
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	r.schedules = append(r.schedules, &scheduled{name: name, schedule: schedule, next: schedule.Next(time.Now())})
+	return nil
+}
+
+// Run runs the jobs every PollInterval until ctx is done, then waits for the running ones to finish
+func (r *Runner) Run(ctx context.Context) {
+	slots := make(chan struct{}, r.Workers)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.poll(ctx, slots, &running); err != nil && ctx.Err() == nil {
+			log.Println("Failed to run the jobs:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce queues the scheduled jobs due now, runs up to Workers of the queued ones and waits for them. It returns
+// how many it ran.
+func (r *Runner) RunOnce(ctx context.Context) (int, error) {
+	slots := make(chan struct{}, r.Workers)
+	var running sync.WaitGroup
+
+	ran, err := r.poll(ctx, slots, &running)
+	running.Wait()
+	return ran, err
+}
+
+// poll claims a job for every free slot and runs each one in its own goroutine, holding its slot until it is done
+func (r *Runner) poll(ctx context.Context, slots chan struct{}, running *sync.WaitGroup) (int, error) {
+	now := time.Now()
+	if err := r.enqueueDue(ctx, now); err != nil {
+		return 0, err
+	}
+
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return 0, nil
+	}
+
+	jobs, err := r.repository.Claim(ctx, now, r.VisibilityTimeout, free)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		slots <- struct{}{}
+		running.Add(1)
+		go func(job model.Job) {
+			defer running.Done()
+			defer func() { <-slots }()
+
+			if err := r.run(job); err != nil {
+				log.Printf("Failed to keep the outcome of job %d %s: %s", job.ID, job.Name, err.Error())
+			}
+		}(job)
+	}
+
+	return len(jobs), nil
+}
+
+func (r *Runner) enqueueDue(ctx context.Context, now time.Time) error {
+	for _, entry := range r.schedules {
+		if entry.next.After(now) {
+			continue
+		}
+
+		//runs missed while the app was down are not made up for, the job runs once and waits for its next time
+		job := dto.EnqueueDto{Name: entry.name, UniqueKey: "schedule:" + entry.name, RunAt: entry.next, MaxAttempts: r.MaxAttempts}
+		if _, err := r.repository.Enqueue(ctx, job); err != nil {
+			return err
+		}
+		entry.next = entry.schedule.Next(now)
+	}
+	return nil
+}
+
+// run runs the claimed job and keeps its outcome. The job gets VisibilityTimeout to run whether or not the runner
+// is stopping, the outcome is kept even when it ran out of time.
+func (r *Runner) run(job model.Job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.VisibilityTimeout)
+	err := r.handle(ctx, job)
+	cancel()
+
+	ctx = context.Background()
+	if err == nil {
+		return r.repository.MarkDone(ctx, job.ID, job.LockedBy)
+	}
+
+	log.Printf("Job %d %s failed on attempt %d of %d: %s", job.ID, job.Name, job.Attempts, job.MaxAttempts, err.Error())
+	lastError := truncate(err.Error(), 255)
+	if job.Attempts >= job.MaxAttempts {
+		return r.repository.MarkDead(ctx, job.ID, job.LockedBy, lastError)
+	}
+	return r.repository.MarkFailed(ctx, job.ID, job.LockedBy, time.Now().Add(r.backoff(job.Attempts-1)), lastError)
+}
+
+// handle calls the handler of the job, a panic fails the job like an error
+func (r *Runner) handle(ctx context.Context, job model.Job) (err error) {
+	handler, ok := r.handlers[job.Name]
+	if !ok {
+		return fmt.Errorf("no handler registered for job %s", job.Name)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, job.Payload)
+}
+
+// backoff is the wait before the next attempt of a job that failed attempts times before this one
+func (r *Runner) backoff(attempts int) time.Duration {
+	wait := r.Backoff
+	for i := 0; i < attempts && wait < r.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > r.MaxBackoff {
+		wait = r.MaxBackoff
+	}
+	return wait
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
@@ -0,0 +1,225 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/service"
+	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestRunnerRunOnce(t *testing.T) {
+	mockRepository := new(mockRepositories.JobRepository)
+
+	reset := func() {
+		mockRepository = new(mockRepositories.JobRepository)
+	}
+
+	job := model.Job{ID: 1, Name: "order.expire", Payload: json.RawMessage(`{"id":3}`), Status: model.JobRunning, Attempts: 1, MaxAttempts: 5,
+		LockedBy: "claim"}
+
+	t.Run("Test Runner Runs Claimed Jobs", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return([]model.Job{job}, nil).Once()
+		mockRepository.On("MarkDone", mock.Anything, 1, "claim").Return(nil).Once()
+
+		var got json.RawMessage
+		runner := service.NewRunner(mockRepository)
+		runner.Register("order.expire", func(ctx context.Context, payload json.RawMessage) error {
+			got = payload
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return nil
+		})
+		ran, err := runner.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ran)
+		assert.Equal(t, `{"id":3}`, string(got))
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Backs Off Failed Jobs", func(t *testing.T) {
+		defer reset()
+
+		failed := job
+		failed.Attempts = 3
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return([]model.Job{failed}, nil).Once()
+
+		var wait time.Duration
+		start := time.Now()
+		mockRepository.On("MarkFailed", mock.Anything, 1, "claim", mock.AnythingOfType("time.Time"), "Order service unavailable").
+			Run(func(args mock.Arguments) {
+				wait = args.Get(3).(time.Time).Sub(start)
+			}).Return(nil).Once()
+
+		runner := service.NewRunner(mockRepository)
+		runner.Register("order.expire", func(ctx context.Context, payload json.RawMessage) error {
+			return errors.New("Order service unavailable")
+		})
+		_, err := runner.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		//10s doubled for each of the 2 failures before this one
+		assert.GreaterOrEqual(t, wait, 40*time.Second)
+		assert.Less(t, wait, 41*time.Second)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Leaves Job Dead On Its Last Attempt", func(t *testing.T) {
+		defer reset()
+
+		last := job
+		last.Attempts = 5
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return([]model.Job{last}, nil).Once()
+		mockRepository.On("MarkDead", mock.Anything, 1, "claim", "panic: "+strings.Repeat("x", 248)).Return(nil).Once()
+
+		runner := service.NewRunner(mockRepository)
+		runner.Register("order.expire", func(ctx context.Context, payload json.RawMessage) error {
+			panic(strings.Repeat("x", 300))
+		})
+		_, err := runner.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Fails Jobs Without Handler", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return([]model.Job{job}, nil).Once()
+		mockRepository.On("MarkFailed", mock.Anything, 1, "claim", mock.AnythingOfType("time.Time"), "no handler registered for job order.expire").
+			Return(nil).Once()
+
+		runner := service.NewRunner(mockRepository)
+		_, err := runner.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Runs Up To Workers Jobs At Once", func(t *testing.T) {
+		defer reset()
+
+		jobs := []model.Job{job, job, job}
+		for i := range jobs {
+			jobs[i].ID = i + 1
+		}
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 3).Return(jobs, nil).Once()
+		mockRepository.On("MarkDone", mock.Anything, mock.AnythingOfType("int"), "claim").Return(nil).Times(3)
+
+		var (
+			lock             sync.Mutex
+			running, busiest int
+		)
+		release := make(chan struct{})
+		runner := service.NewRunner(mockRepository)
+		runner.Workers = 3
+		runner.Register("order.expire", func(ctx context.Context, payload json.RawMessage) error {
+			lock.Lock()
+			running++
+			if running > busiest {
+				busiest = running
+			}
+			if running == 3 {
+				close(release)
+			}
+			lock.Unlock()
+
+			<-release
+			return nil
+		})
+		ran, err := runner.RunOnce(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, ran)
+		assert.Equal(t, 3, busiest)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Queues Due Scheduled Jobs", func(t *testing.T) {
+		defer reset()
+
+		runner := service.NewRunner(mockRepository)
+		runner.Register("job.cleanup", func(ctx context.Context, payload json.RawMessage) error { return nil })
+		assert.NotNil(t, runner.Schedule("order.expire", "@every 1m"))
+		assert.NotNil(t, runner.Schedule("job.cleanup", "every minute"))
+		assert.Nil(t, runner.Schedule("job.cleanup", "@every 1s"))
+
+		mockRepository.On("Enqueue", mock.Anything, mock.MatchedBy(func(payload dto.EnqueueDto) bool {
+			return payload.Name == "job.cleanup" && payload.UniqueKey == "schedule:job.cleanup" && payload.MaxAttempts == 5
+		})).Return(true, nil).Once()
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return(nil, nil)
+
+		time.Sleep(1100 * time.Millisecond)
+		_, err := runner.RunOnce(context.TODO())
+		assert.Nil(t, err)
+
+		//not due again yet
+		_, err = runner.RunOnce(context.TODO())
+		assert.Nil(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Runner Error Database", func(t *testing.T) {
+		defer reset()
+
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return(nil, errors.New("Database Error")).Once()
+
+		runner := service.NewRunner(mockRepository)
+		ran, err := runner.RunOnce(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, ran)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestRunnerRun(t *testing.T) {
+	mockRepository := new(mockRepositories.JobRepository)
+
+	t.Run("Test Run Waits For Running Jobs On Shutdown", func(t *testing.T) {
+		job := model.Job{ID: 1, Name: "order.expire", Attempts: 1, MaxAttempts: 5, LockedBy: "claim"}
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, 4).Return([]model.Job{job}, nil).Once()
+		mockRepository.On("Claim", mock.Anything, mock.AnythingOfType("time.Time"), 5*time.Minute, mock.AnythingOfType("int")).Return(nil, nil)
+		mockRepository.On("MarkDone", mock.Anything, 1, "claim").Return(nil).Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		runner := service.NewRunner(mockRepository)
+		runner.PollInterval = 10 * time.Millisecond
+		runner.Register("order.expire", func(jobCtx context.Context, payload json.RawMessage) error {
+			close(started)
+			<-ctx.Done()
+			//the job keeps its own time to finish
+			assert.Nil(t, jobCtx.Err())
+			return nil
+		})
+
+		done := make(chan struct{})
+		go func() {
+			runner.Run(ctx)
+			close(done)
+		}()
+
+		<-started
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("the runner did not stop")
+		}
+		mockRepository.AssertExpectations(t)
+	})
+}
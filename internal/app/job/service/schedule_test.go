@@ -0,0 +1,51 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/service"
+)
+
+func TestParseSchedule(t *testing.T) {
+	//a Monday
+	after := time.Date(2026, 10, 19, 10, 17, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 19, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2026, 10, 20, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		//either the day of month or the day of week
+		{"0 0 25 * 3", time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"@every 1s", time.Date(2026, 10, 19, 10, 17, 31, 0, time.UTC)},
+		{"@every 1m", time.Date(2026, 10, 19, 10, 18, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run("Test Parse Schedule "+c.spec, func(t *testing.T) {
+			schedule, err := service.ParseSchedule(c.spec)
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.next, schedule.Next(after))
+		})
+	}
+
+	t.Run("Test Parse Schedule Invalid", func(t *testing.T) {
+		for _, spec := range []string{"", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "0 0 31 2 *",
+			"@every 10ms", "@every soon", "@yearly"} {
+			_, err := service.ParseSchedule(spec)
+			assert.NotNil(t, err, spec)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const (
+	DefaultLimit = 100
+	// DefaultMaxAttempts a job gets when it is queued without its own
+	DefaultMaxAttempts = 5
+)
+
+type JobService interface {
+	// Enqueue queues work for the runner, see dto.EnqueueDto. It returns false when the job was skipped for its
+	// UniqueKey.
+	Enqueue(ctx context.Context, payload dto.EnqueueDto) (bool, error)
+	GetJobs(ctx context.Context, filter dto.FilterJobDto) ([]dto.JobDto, error, string)
+	// Retry queues a DEAD job again, with all its attempts
+	Retry(ctx context.Context, id int) (interface{}, error, string)
+	// DeleteFinished deletes the jobs done more than olderThan ago and returns how many
+	DeleteFinished(ctx context.Context, olderThan time.Duration) (int, error)
+}
+
+type Service struct {
+	jobRepository  repository.JobRepository
+	contextTimeout time.Duration
+}
+
+func NewJobService(r repository.JobRepository, timeout time.Duration) JobService {
+	return &Service{
+		jobRepository:  r,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *Service) Enqueue(ctx context.Context, payload dto.EnqueueDto) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.Name == "" {
+		return false, errors.New("Job name is required")
+	}
+	if payload.MaxAttempts == 0 {
+		payload.MaxAttempts = DefaultMaxAttempts
+	}
+
+	return s.jobRepository.Enqueue(ctx, payload)
+}
+
+func (s *Service) GetJobs(ctx context.Context, filter dto.FilterJobDto) ([]dto.JobDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	jobs, err := s.jobRepository.GetJobs(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := []dto.JobDto{}
+	for _, job := range jobs {
+		data = append(data, dto.JobDto{
+			ID:          job.ID,
+			Name:        job.Name,
+			Payload:     job.Payload,
+			Status:      job.Status,
+			Attempts:    job.Attempts,
+			MaxAttempts: job.MaxAttempts,
+			RunAt:       job.RunAt,
+			LockedUntil: job.LockedUntil,
+			LastError:   job.LastError,
+			FinishedAt:  job.FinishedAt,
+			CreatedAt:   job.CreatedAt,
+		})
+	}
+
+	return data, nil, util.SUCCESS
+}
+
+func (s *Service) Retry(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	requeued, err := s.jobRepository.Requeue(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if !requeued {
+		jobs, err := s.jobRepository.GetJobs(ctx, dto.FilterJobDto{ID: id, Limit: 1})
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		if len(jobs) == 0 {
+			return nil, errors.New("Job not found"), util.NOT_FOUND
+		}
+		return nil, fmt.Errorf("Job with status %s can't be retried", jobs[0].Status), util.INVALID_STATE
+	}
+
+	return map[string]interface{}{"id": id, "status": model.JobQueued}, nil, util.SUCCESS
+}
+
+func (s *Service) DeleteFinished(ctx context.Context, olderThan time.Duration) (int, error) {
+	return s.jobRepository.DeleteFinished(ctx, time.Now().Add(-olderThan))
+}
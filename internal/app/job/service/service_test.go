@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/dto"
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/service"
+	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/job/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestEnqueue(t *testing.T) {
+	mockRepository := new(mockRepositories.JobRepository)
+
+	t.Run("Test Enqueue Default Max Attempts", func(t *testing.T) {
+		mockRepository.On("Enqueue", mock.Anything, dto.EnqueueDto{Name: "order.expire", MaxAttempts: 5}).Return(true, nil).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		queued, err := s.Enqueue(context.TODO(), dto.EnqueueDto{Name: "order.expire"})
+
+		assert.Nil(t, err)
+		assert.True(t, queued)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Enqueue Without Name", func(t *testing.T) {
+		s := service.NewJobService(mockRepository, time.Second*2)
+		queued, err := s.Enqueue(context.TODO(), dto.EnqueueDto{})
+
+		assert.NotNil(t, err)
+		assert.False(t, queued)
+	})
+}
+
+func TestGetJobs(t *testing.T) {
+	mockRepository := new(mockRepositories.JobRepository)
+
+	t.Run("Test Get Jobs Default Limit", func(t *testing.T) {
+		jobs := []model.Job{{ID: 2, Name: "order.expire", Status: model.JobDead, Attempts: 5, MaxAttempts: 5, LastError: "Database Error"}}
+		mockRepository.On("GetJobs", mock.Anything, dto.FilterJobDto{Status: model.JobDead, Limit: 100}).Return(jobs, nil).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		result, err, state := s.GetJobs(context.TODO(), dto.FilterJobDto{Status: model.JobDead})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Database Error", result[0].LastError)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Get Jobs Error Database", func(t *testing.T) {
+		mockRepository.On("GetJobs", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error")).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		result, err, state := s.GetJobs(context.TODO(), dto.FilterJobDto{})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.Nil(t, result)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	mockRepository := new(mockRepositories.JobRepository)
+
+	t.Run("Test Retry Success", func(t *testing.T) {
+		mockRepository.On("Requeue", mock.Anything, 2).Return(true, nil).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		result, err, state := s.Retry(context.TODO(), 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, map[string]interface{}{"id": 2, "status": model.JobQueued}, result)
+	})
+
+	t.Run("Test Retry Not Dead", func(t *testing.T) {
+		mockRepository.On("Requeue", mock.Anything, 3).Return(false, nil).Once()
+		mockRepository.On("GetJobs", mock.Anything, dto.FilterJobDto{ID: 3, Limit: 1}).Return([]model.Job{{ID: 3, Status: model.JobRunning}}, nil).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		result, err, state := s.Retry(context.TODO(), 3)
+
+		assert.EqualError(t, err, "Job with status RUNNING can't be retried")
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test Retry Not Found", func(t *testing.T) {
+		mockRepository.On("Requeue", mock.Anything, 9).Return(false, nil).Once()
+		mockRepository.On("GetJobs", mock.Anything, dto.FilterJobDto{ID: 9, Limit: 1}).Return(nil, nil).Once()
+
+		s := service.NewJobService(mockRepository, time.Second*2)
+		result, err, state := s.Retry(context.TODO(), 9)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, result)
+		mockRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// ImageField is the multipart form field holding the uploaded image
+const ImageField = "image"
+
+type MediaHandler struct {
+	MediaService service.MediaService
+}
+
+func NewMediaHandler(mux *http.ServeMux, service service.MediaService) {
+	handler := MediaHandler{MediaService: service}
+
+	mux.HandleFunc("/media/products/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/media/products/")
+		switch {
+		case action == "images" && r.Method == "POST":
+			handler.UploadProductImage(w, r)
+		case action == "images/order" && r.Method == "PUT":
+			handler.ReorderProductImages(w, r)
+		case strings.HasPrefix(action, "images/") && r.Method == "DELETE":
+			handler.DeleteProductImage(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func (b *MediaHandler) UploadProductImage(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/media/products/")
+
+	//leave room for the multipart envelope around the image
+	const maxBodySize = service.MaxImageSize + 1<<20
+	if r.ContentLength > maxBodySize {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), fmt.Sprintf("Image must not be larger than %d MB", service.MaxImageSize>>20), nil)
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	file, _, err := r.FormFile(ImageField)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), fmt.Sprintf("Multipart field %s with an image of at most %d MB is required", ImageField, service.MaxImageSize>>20), nil)
+	}
+	defer file.Close()
+
+	result, err, state := b.MediaService.UploadProductImage(r.Context(), id, file)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *MediaHandler) ReorderProductImages(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/media/products/")
+
+	var payload dto.ReorderImagesDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.MediaService.ReorderProductImages(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *MediaHandler) DeleteProductImage(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, action := util.GetPathId(r.URL.Path, "/media/products/")
+	imageId, _ := strconv.Atoi(strings.TrimPrefix(action, "images/"))
+
+	result, err, state := b.MediaService.DeleteProductImage(r.Context(), id, imageId)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
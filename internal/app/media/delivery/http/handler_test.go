@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mediaHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/media/service"
+)
+
+func multipartBody(t *testing.T, field string, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, "shirt.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadProductImage(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.MediaService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.MediaService)
+	}
+
+	t.Run("Test Upload Image Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("UploadProductImage", mock.Anything, 1, mock.MatchedBy(func(file io.Reader) bool {
+			data, _ := io.ReadAll(file)
+			return string(data) == "image bytes"
+		})).Return(map[string]interface{}{"id": 3}, nil, "SUCCESS")
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		body, contentType := multipartBody(t, mediaHttp.ImageField, "image bytes")
+		req := httptest.NewRequest(http.MethodPost, "/media/products/1/images", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Upload Without Image Field", func(t *testing.T) {
+		defer reset()
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		body, contentType := multipartBody(t, "file", "image bytes")
+		req := httptest.NewRequest(http.MethodPost, "/media/products/1/images", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Upload Not Multipart", func(t *testing.T) {
+		defer reset()
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/media/products/1/images", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestReorderProductImages(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.MediaService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.MediaService)
+	}
+
+	t.Run("Test Reorder Images Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("ReorderProductImages", context.Background(), 1, dto.ReorderImagesDto{Ids: []int{2, 1}}).Return(nil, nil, "SUCCESS")
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPut, "/media/products/1/images/order", strings.NewReader(`{"ids":[2,1]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Reorder Images Duplicate Ids", func(t *testing.T) {
+		defer reset()
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPut, "/media/products/1/images/order", strings.NewReader(`{"ids":[2,2]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.MediaService)
+
+	t.Run("Test Delete Image Success", func(t *testing.T) {
+		mockService.On("DeleteProductImage", context.Background(), 1, 3).Return(map[string]interface{}{"id": 3}, nil, "SUCCESS")
+
+		mediaHttp.NewMediaHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodDelete, "/media/products/1/images/3", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
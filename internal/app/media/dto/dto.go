@@ -0,0 +1,20 @@
+package dto
+
+type InsertImageDto struct {
+	ProductId   int
+	StorageKey  string
+	ContentType string
+	Size        int
+	Width       int
+	Height      int
+}
+
+type FilterImageDto struct {
+	ID         int   `json:"id"`
+	ProductIds []int `json:"productIds"`
+}
+
+// ReorderImagesDto lists every image of the product in its new gallery order
+type ReorderImagesDto struct {
+	Ids []int `json:"ids" validate:"required,unique,dive,gt=0"`
+}
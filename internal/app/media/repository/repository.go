@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type MediaRepository interface {
+	CreateImage(ctx context.Context, payload dto.InsertImageDto) (*model.ProductImage, error)
+	GetImages(ctx context.Context, filter dto.FilterImageDto) (data []model.ProductImage, err error)
+	ReorderImages(ctx context.Context, productId int, ids []int) error
+	DeleteImage(ctx context.Context, productId int, id int) (bool, error)
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewMedia(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+// CreateImage appends the image at the end of the product gallery
+func (r *Repository) CreateImage(ctx context.Context, payload dto.InsertImageDto) (*model.ProductImage, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	//PROCESS SORT ORDER
+	var sortOrder int
+	//the last image is locked rather than read with MAX(), which PostgreSQL cannot lock
+	query := `SELECT sortOrder + 1 FROM product_image WHERE productId = ? ORDER BY sortOrder DESC LIMIT 1` + r.Dialect.ForUpdate()
+	err = tx.QueryRowContext(ctx, r.Dialect.Rebind(query), payload.ProductId).Scan(&sortOrder)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS SORT ORDER
+
+	//PROCESS IMAGE
+	query = `INSERT INTO product_image (productId, storageKey, contentType, size, width, height, sortOrder, createdAt)
+	values(?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, tx, query, payload.ProductId, payload.StorageKey, payload.ContentType, payload.Size, payload.Width, payload.Height, sortOrder)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS IMAGE
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductImage{
+		ID:          int(id),
+		ProductId:   payload.ProductId,
+		StorageKey:  payload.StorageKey,
+		ContentType: payload.ContentType,
+		Size:        payload.Size,
+		Width:       payload.Width,
+		Height:      payload.Height,
+		SortOrder:   sortOrder,
+	}, nil
+}
+
+func (r *Repository) GetImages(ctx context.Context, filter dto.FilterImageDto) (data []model.ProductImage, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, productId, storageKey, contentType, size, width, height, sortOrder, createdAt FROM product_image`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if len(filter.ProductIds) > 0 {
+		placeholders := make([]string, len(filter.ProductIds))
+		for i := range placeholders {
+			placeholders[i] = "?"
+		}
+		query += util.FilterHandler(filterValues) + fmt.Sprintf(` productId IN (%s)`, strings.Join(placeholders, ","))
+		for _, id := range filter.ProductIds {
+			filterValues = append(filterValues, id)
+		}
+	}
+
+	query += ` ORDER BY productId, sortOrder, id`
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		image := model.ProductImage{}
+		err = rows.Scan(&image.ID, &image.ProductId, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.SortOrder, &image.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, image)
+	}
+
+	return data, nil
+}
+
+// ReorderImages sets the gallery position of every image to its index in ids
+func (r *Repository) ReorderImages(ctx context.Context, productId int, ids []int) error {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE product_image SET sortOrder = ? WHERE id = ? AND productId = ?`
+	for i, id := range ids {
+		_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), i, id, productId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) DeleteImage(ctx context.Context, productId int, id int) (bool, error) {
+	query := `DELETE FROM product_image WHERE id = ? AND productId = ?`
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), id, productId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
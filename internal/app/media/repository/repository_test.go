@@ -0,0 +1,163 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/repository"
+)
+
+var imageColumns = []string{"id", "productId", "storageKey", "contentType", "size", "width", "height", "sortOrder", "createdAt"}
+
+func TestCreateImage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertImageDto{ProductId: 1, StorageKey: "products/1/abc", ContentType: "image/png", Size: 2048, Width: 800, Height: 600}
+	querySort := regexp.QuoteMeta(`SELECT sortOrder + 1 FROM product_image WHERE productId = ? ORDER BY sortOrder DESC LIMIT 1 FOR UPDATE`)
+	query := "INSERT INTO product_image"
+
+	t.Run("Test Create Image Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(querySort).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"sortOrder"}).AddRow(2))
+		mock.ExpectExec(query).WithArgs(1, "products/1/abc", "image/png", 2048, 800, 600, 2).WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		result, err := r.CreateImage(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 7, result.ID)
+		assert.Equal(t, 2, result.SortOrder)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Image Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(querySort).WillReturnRows(sqlmock.NewRows([]string{"sortOrder"}).AddRow(0))
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		result, err := r.CreateImage(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := `SELECT id, productId, storageKey, contentType, size, width, height, sortOrder, createdAt FROM product_image`
+
+	t.Run("Test Get Images Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(imageColumns).
+			AddRow(1, 1, "products/1/abc", "image/png", 2048, 800, 600, 0, "2026-10-19 10:00:00").
+			AddRow(2, 2, "products/2/def", "image/jpeg", 4096, 1024, 768, 0, "2026-10-19 10:00:00")
+		mock.ExpectQuery(query+regexp.QuoteMeta(` WHERE productId IN (?,?) ORDER BY productId, sortOrder, id`)).WithArgs(1, 2).WillReturnRows(rows)
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		result, err := r.GetImages(context.TODO(), dto.FilterImageDto{ProductIds: []int{1, 2}})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "products/2/def", result[1].StorageKey)
+	})
+
+	t.Run("Test Get Images By Id", func(t *testing.T) {
+		mock.ExpectQuery(query+regexp.QuoteMeta(` WHERE id = ? AND productId IN (?)`)).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows(imageColumns))
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		result, err := r.GetImages(context.TODO(), dto.FilterImageDto{ID: 3, ProductIds: []int{1}})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 0)
+	})
+
+	t.Run("Test Get Images Error", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		result, err := r.GetImages(context.TODO(), dto.FilterImageDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestReorderImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta(`UPDATE product_image SET sortOrder = ? WHERE id = ? AND productId = ?`)
+
+	t.Run("Test Reorder Images Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(0, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(1, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		err := r.ReorderImages(context.TODO(), 1, []int{3, 2})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Reorder Images Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		err := r.ReorderImages(context.TODO(), 1, []int{3, 2})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteImage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta(`DELETE FROM product_image WHERE id = ? AND productId = ?`)
+
+	t.Run("Test Delete Image Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		deleted, err := r.DeleteImage(context.TODO(), 1, 3)
+
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("Test Delete Image Not Found", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(9, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		r := repository.NewMedia(db, dialect.MySQL)
+		deleted, err := r.DeleteImage(context.TODO(), 1, 9)
+
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	})
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/storage"
+	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	productService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const (
+	MaxImageSize   = 5 << 20
+	MaxImagePixels = 40_000_000
+)
+
+// ImageTypes are the accepted image content types, as sniffed from the upload, with their file extension
+var ImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type MediaService interface {
+	UploadProductImage(ctx context.Context, productId int, file io.Reader) (interface{}, error, string)
+	ReorderProductImages(ctx context.Context, productId int, payload dto.ReorderImagesDto) (interface{}, error, string)
+	DeleteProductImage(ctx context.Context, productId int, imageId int) (interface{}, error, string)
+	GetProductImages(ctx context.Context, productIds []int) (map[int][]ProductDto.ImageDto, error)
+}
+
+type Service struct {
+	mediaRepository repository.MediaRepository
+	store           storage.BlobStore
+	productService  productService.ProductService
+	contextTimeout  time.Duration
+}
+
+func NewMediaService(r repository.MediaRepository, store storage.BlobStore, productService productService.ProductService, timeout time.Duration) MediaService {
+	return &Service{
+		mediaRepository: r,
+		store:           store,
+		productService:  productService,
+		contextTimeout:  timeout,
+	}
+}
+
+// UploadProductImage stores the image with its thumbnails and appends it to the product gallery
+func (s *Service) UploadProductImage(ctx context.Context, productId int, file io.Reader) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	_, err, state := s.productService.GetProductById(ctx, productId)
+	if err != nil {
+		return nil, err, state
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxImageSize+1))
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("Image must not be larger than %d MB", MaxImageSize>>20), util.VALIDATION_ERROR
+	}
+
+	//trust the content, not the file name or the declared content type
+	contentType := http.DetectContentType(data)
+	ext, ok := ImageTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("Image type %s is not supported", contentType), util.VALIDATION_ERROR
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("Image can't be decoded"), util.VALIDATION_ERROR
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return nil, errors.New("Image has too many pixels"), util.VALIDATION_ERROR
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("Image can't be decoded"), util.VALIDATION_ERROR
+	}
+
+	prefix, err := newStorageKey(productId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	var stored []string
+	cleanup := func() {
+		for _, key := range stored {
+			if err := s.store.Delete(context.Background(), key); err != nil {
+				log.Printf("failed to delete blob %s: %s", key, err.Error())
+			}
+		}
+	}
+
+	key := prefix + "/original" + ext
+	if err = s.store.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	stored = append(stored, key)
+
+	for _, size := range ThumbnailSizes {
+		var buf bytes.Buffer
+		if err = encodeThumbnail(&buf, resize(img, size.MaxSize), contentType); err != nil {
+			cleanup()
+			return nil, err, util.SYSTEM_ERROR
+		}
+
+		key = thumbnailKey(prefix, size.Name, contentType)
+		if err = s.store.Put(ctx, key, &buf); err != nil {
+			cleanup()
+			return nil, err, util.SYSTEM_ERROR
+		}
+		stored = append(stored, key)
+	}
+
+	result, err := s.mediaRepository.CreateImage(ctx, dto.InsertImageDto{
+		ProductId:   productId,
+		StorageKey:  prefix,
+		ContentType: contentType,
+		Size:        len(data),
+		Width:       config.Width,
+		Height:      config.Height,
+	})
+	if err != nil {
+		cleanup()
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	return s.imageDto(*result), nil, util.SUCCESS
+}
+
+// ReorderProductImages puts the gallery in the given order, the ids must be every image of the product
+func (s *Service) ReorderProductImages(ctx context.Context, productId int, payload dto.ReorderImagesDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	_, err, state := s.productService.GetProductById(ctx, productId)
+	if err != nil {
+		return nil, err, state
+	}
+
+	images, err := s.mediaRepository.GetImages(ctx, dto.FilterImageDto{ProductIds: []int{productId}})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	current := make([]int, len(images))
+	for i, image := range images {
+		current[i] = image.ID
+	}
+	requested := append([]int(nil), payload.Ids...)
+	sort.Ints(current)
+	sort.Ints(requested)
+	if !reflect.DeepEqual(current, requested) {
+		return nil, fmt.Errorf("Order must list every image of the product: %v", current), util.VALIDATION_ERROR
+	}
+
+	err = s.mediaRepository.ReorderImages(ctx, productId, payload.Ids)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	result, err := s.GetProductImages(ctx, []int{productId})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return result[productId], nil, util.SUCCESS
+}
+
+func (s *Service) DeleteProductImage(ctx context.Context, productId int, imageId int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	images, err := s.mediaRepository.GetImages(ctx, dto.FilterImageDto{ID: imageId, ProductIds: []int{productId}})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(images) == 0 {
+		return nil, errors.New("Image Not Found"), util.NOT_FOUND
+	}
+
+	deleted, err := s.mediaRepository.DeleteImage(ctx, productId, imageId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !deleted {
+		return nil, errors.New("Image Not Found"), util.NOT_FOUND
+	}
+
+	//the row is gone, a blob left behind only costs storage
+	image := images[0]
+	keys := []string{image.StorageKey + "/original" + ImageTypes[image.ContentType]}
+	for _, size := range ThumbnailSizes {
+		keys = append(keys, thumbnailKey(image.StorageKey, size.Name, image.ContentType))
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete blob %s: %s", key, err.Error())
+		}
+	}
+
+	return map[string]interface{}{"id": imageId}, nil, util.SUCCESS
+}
+
+// GetProductImages returns the gallery of every product, in gallery order
+func (s *Service) GetProductImages(ctx context.Context, productIds []int) (map[int][]ProductDto.ImageDto, error) {
+	images, err := s.mediaRepository.GetImages(ctx, dto.FilterImageDto{ProductIds: productIds})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[int][]ProductDto.ImageDto{}
+	for _, image := range images {
+		result[image.ProductId] = append(result[image.ProductId], s.imageDto(image))
+	}
+	return result, nil
+}
+
+func (s *Service) imageDto(image model.ProductImage) ProductDto.ImageDto {
+	thumbnails := map[string]string{}
+	for _, size := range ThumbnailSizes {
+		thumbnails[size.Name] = s.store.URL(thumbnailKey(image.StorageKey, size.Name, image.ContentType))
+	}
+
+	return ProductDto.ImageDto{
+		ID:          image.ID,
+		Url:         s.store.URL(image.StorageKey + "/original" + ImageTypes[image.ContentType]),
+		ContentType: image.ContentType,
+		Width:       image.Width,
+		Height:      image.Height,
+		SortOrder:   image.SortOrder,
+		Thumbnails:  thumbnails,
+	}
+}
+
+// newStorageKey is a fresh prefix for an upload, random so a reused name never serves a cached old image
+func newStorageKey(productId int) (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s", productId, hex.EncodeToString(random)), nil
+}
+
+// thumbnails of jpeg images stay jpeg, the others are stored as png
+func thumbnailKey(prefix string, name string, contentType string) string {
+	if contentType == "image/jpeg" {
+		return prefix + "/" + name + ".jpg"
+	}
+	return prefix + "/" + name + ".png"
+}
+
+func encodeThumbnail(w io.Writer, img image.Image, contentType string) error {
+	if contentType == "image/jpeg" {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+	return png.Encode(w, img)
+}
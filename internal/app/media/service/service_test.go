@@ -0,0 +1,271 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/dto"
+	MediaService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/storage"
+	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	mockMediaRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/media/repository"
+	mockProductServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockMediaRepository *mockMediaRepositories.MediaRepository
+	mockProductService  *mockProductServices.ProductService
+	store               *storage.Local
+	mediaService        MediaService.MediaService
+)
+
+// setup gives every test fresh mocks and an empty blob store
+func setup(t *testing.T) {
+	mockMediaRepository = new(mockMediaRepositories.MediaRepository)
+	mockProductService = new(mockProductServices.ProductService)
+	store = storage.NewLocal(t.TempDir(), "/media/files")
+	mediaService = MediaService.NewMediaService(mockMediaRepository, store, mockProductService, contextTimeout)
+}
+
+func encodeImage(t *testing.T, width int, height int, format string) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func storedFiles(t *testing.T) []string {
+	var files []string
+	filepath.Walk(store.Root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(store.Root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+func TestUploadProductImage(t *testing.T) {
+	t.Run("Test Upload Image Success", func(t *testing.T) {
+		setup(t)
+		data := encodeImage(t, 1000, 500, "png")
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+		mockMediaRepository.On("CreateImage", mock.Anything, mock.MatchedBy(func(p dto.InsertImageDto) bool {
+			return p.ProductId == 1 && p.ContentType == "image/png" && p.Width == 1000 && p.Height == 500 && p.Size == len(data) &&
+				strings.HasPrefix(p.StorageKey, "products/1/")
+		})).Return(func(ctx context.Context, p dto.InsertImageDto) *model.ProductImage {
+			return &model.ProductImage{ID: 3, ProductId: 1, StorageKey: p.StorageKey, ContentType: p.ContentType, Width: 1000, Height: 500, SortOrder: 2}
+		}, nil)
+
+		res, err, state := mediaService.UploadProductImage(context.TODO(), 1, bytes.NewReader(data))
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		result := res.(ProductDto.ImageDto)
+		assert.Equal(t, 3, result.ID)
+		assert.True(t, strings.HasSuffix(result.Url, "/original.png"))
+		assert.Len(t, result.Thumbnails, 3)
+		assert.Len(t, storedFiles(t), 4)
+
+		//the small thumbnail fits in 150x150 keeping the aspect ratio
+		file, err := store.Get(context.TODO(), strings.TrimPrefix(result.Thumbnails["small"], "/media/files/"))
+		assert.Nil(t, err)
+		defer file.Close()
+		config, format, err := image.DecodeConfig(file)
+		assert.Nil(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 150, config.Width)
+		assert.Equal(t, 75, config.Height)
+	})
+
+	t.Run("Test Upload Jpeg Keeps Small Image Size", func(t *testing.T) {
+		setup(t)
+		data := encodeImage(t, 100, 120, "jpeg")
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+		mockMediaRepository.On("CreateImage", mock.Anything, mock.Anything).Return(&model.ProductImage{ID: 4, StorageKey: "products/1/abc", ContentType: "image/jpeg"}, nil)
+
+		res, err, state := mediaService.UploadProductImage(context.TODO(), 1, bytes.NewReader(data))
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "/media/files/products/1/abc/large.jpg", res.(ProductDto.ImageDto).Thumbnails["large"])
+	})
+
+	t.Run("Test Upload Unsupported Type", func(t *testing.T) {
+		setup(t)
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+
+		_, err, state := mediaService.UploadProductImage(context.TODO(), 1, strings.NewReader("%PDF-1.4 not an image"))
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Len(t, storedFiles(t), 0)
+	})
+
+	t.Run("Test Upload Too Large", func(t *testing.T) {
+		setup(t)
+		data := append(encodeImage(t, 10, 10, "png"), make([]byte, MediaService.MaxImageSize)...)
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+
+		_, err, state := mediaService.UploadProductImage(context.TODO(), 1, bytes.NewReader(data))
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Upload Corrupt Image", func(t *testing.T) {
+		setup(t)
+		data := encodeImage(t, 10, 10, "png")[:60]
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+
+		_, err, state := mediaService.UploadProductImage(context.TODO(), 1, bytes.NewReader(data))
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Upload Product Not Found", func(t *testing.T) {
+		setup(t)
+
+		mockProductService.On("GetProductById", mock.Anything, 9).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+
+		_, err, state := mediaService.UploadProductImage(context.TODO(), 9, bytes.NewReader(encodeImage(t, 10, 10, "png")))
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Upload Removes Blobs On Database Error", func(t *testing.T) {
+		setup(t)
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+		mockMediaRepository.On("CreateImage", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		_, err, state := mediaService.UploadProductImage(context.TODO(), 1, bytes.NewReader(encodeImage(t, 10, 10, "png")))
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Len(t, storedFiles(t), 0)
+	})
+}
+
+func TestReorderProductImages(t *testing.T) {
+	images := []model.ProductImage{
+		{ID: 1, ProductId: 1, StorageKey: "products/1/a", ContentType: "image/png", SortOrder: 0},
+		{ID: 2, ProductId: 1, StorageKey: "products/1/b", ContentType: "image/png", SortOrder: 1},
+	}
+
+	t.Run("Test Reorder Images Success", func(t *testing.T) {
+		setup(t)
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+		mockMediaRepository.On("GetImages", mock.Anything, dto.FilterImageDto{ProductIds: []int{1}}).Return(images, nil).Once()
+		mockMediaRepository.On("ReorderImages", mock.Anything, 1, []int{2, 1}).Return(nil)
+		mockMediaRepository.On("GetImages", mock.Anything, dto.FilterImageDto{ProductIds: []int{1}}).Return([]model.ProductImage{
+			{ID: 2, ProductId: 1, StorageKey: "products/1/b", ContentType: "image/png", SortOrder: 0},
+			{ID: 1, ProductId: 1, StorageKey: "products/1/a", ContentType: "image/png", SortOrder: 1},
+		}, nil).Once()
+
+		res, err, state := mediaService.ReorderProductImages(context.TODO(), 1, dto.ReorderImagesDto{Ids: []int{2, 1}})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, res.([]ProductDto.ImageDto)[0].ID)
+		mockMediaRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Reorder Images Incomplete", func(t *testing.T) {
+		setup(t)
+
+		mockProductService.On("GetProductById", mock.Anything, 1).Return(&ProductDto.GetProduct{ID: 1}, nil, "SUCCESS")
+		mockMediaRepository.On("GetImages", mock.Anything, mock.Anything).Return(images, nil)
+
+		_, err, state := mediaService.ReorderProductImages(context.TODO(), 1, dto.ReorderImagesDto{Ids: []int{2, 3}})
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		mockMediaRepository.AssertNotCalled(t, "ReorderImages", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	t.Run("Test Delete Image Success", func(t *testing.T) {
+		setup(t)
+		for _, key := range []string{"products/1/a/original.png", "products/1/a/small.png", "products/1/a/medium.png", "products/1/a/large.png"} {
+			store.Put(context.TODO(), key, strings.NewReader("image"))
+		}
+
+		mockMediaRepository.On("GetImages", mock.Anything, dto.FilterImageDto{ID: 3, ProductIds: []int{1}}).
+			Return([]model.ProductImage{{ID: 3, ProductId: 1, StorageKey: "products/1/a", ContentType: "image/png"}}, nil)
+		mockMediaRepository.On("DeleteImage", mock.Anything, 1, 3).Return(true, nil)
+
+		_, err, state := mediaService.DeleteProductImage(context.TODO(), 1, 3)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Len(t, storedFiles(t), 0)
+	})
+
+	t.Run("Test Delete Image Not Found", func(t *testing.T) {
+		setup(t)
+
+		mockMediaRepository.On("GetImages", mock.Anything, mock.Anything).Return(nil, nil)
+
+		_, err, state := mediaService.DeleteProductImage(context.TODO(), 1, 3)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetProductImages(t *testing.T) {
+	setup(t)
+
+	mockMediaRepository.On("GetImages", mock.Anything, dto.FilterImageDto{ProductIds: []int{1, 2}}).Return([]model.ProductImage{
+		{ID: 1, ProductId: 1, StorageKey: "products/1/a", ContentType: "image/jpeg"},
+		{ID: 2, ProductId: 1, StorageKey: "products/1/b", ContentType: "image/gif"},
+	}, nil)
+
+	result, err := mediaService.GetProductImages(context.TODO(), []int{1, 2})
+
+	assert.Nil(t, err)
+	assert.Len(t, result[1], 2)
+	assert.Len(t, result[2], 0)
+	assert.Equal(t, "/media/files/products/1/a/original.jpg", result[1][0].Url)
+	assert.Equal(t, "/media/files/products/1/b/original.gif", result[1][1].Url)
+	assert.Equal(t, "/media/files/products/1/b/small.png", result[1][1].Thumbnails["small"])
+}
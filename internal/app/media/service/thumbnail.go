@@ -0,0 +1,60 @@
+package service
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail is a generated size of every product image, the image is scaled down to fit in MaxSize x MaxSize
+type Thumbnail struct {
+	Name    string
+	MaxSize int
+}
+
+var ThumbnailSizes = []Thumbnail{
+	{Name: "small", MaxSize: 150},
+	{Name: "medium", MaxSize: 400},
+	{Name: "large", MaxSize: 800},
+}
+
+// resize scales src down to fit in maxSize x maxSize keeping its aspect ratio, averaging the source
+// pixels covered by every target pixel. Images that already fit are returned as they are.
+func resize(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return src
+	}
+
+	targetWidth, targetHeight := maxSize, height*maxSize/width
+	if height > width {
+		targetWidth, targetHeight = width*maxSize/height, maxSize
+	}
+	if targetWidth < 1 {
+		targetWidth = 1
+	}
+	if targetHeight < 1 {
+		targetHeight = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0 := bounds.Min.Y + y*height/targetHeight
+		y1 := bounds.Min.Y + (y+1)*height/targetHeight
+		for x := 0; x < targetWidth; x++ {
+			x0 := bounds.Min.X + x*width/targetWidth
+			x1 := bounds.Min.X + (x+1)*width/targetWidth
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / count), G: uint16(g / count), B: uint16(b / count), A: uint16(a / count)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,72 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	t.Run("Test Put Get And Delete", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir(), "/media/files/")
+
+		err := store.Put(context.TODO(), "products/1/abc/original.png", strings.NewReader("image"))
+		assert.Nil(t, err)
+
+		file, err := store.Get(context.TODO(), "products/1/abc/original.png")
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(file)
+		file.Close()
+		assert.Equal(t, "image", string(data))
+
+		assert.Nil(t, store.Delete(context.TODO(), "products/1/abc/original.png"))
+		_, err = store.Get(context.TODO(), "products/1/abc/original.png")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("Test Delete Missing Blob", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir(), "/media/files")
+
+		assert.Nil(t, store.Delete(context.TODO(), "products/1/missing.png"))
+	})
+
+	t.Run("Test Invalid Keys", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir(), "/media/files")
+
+		for _, key := range []string{"", "/etc/passwd", "../secret", "products/../../secret", "products//1"} {
+			err := store.Put(context.TODO(), key, strings.NewReader("image"))
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+		}
+	})
+
+	t.Run("Test URL", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir(), "/media/files/")
+
+		assert.Equal(t, "/media/files/products/1/abc/small.jpg", store.URL("products/1/abc/small.jpg"))
+	})
+
+	t.Run("Test Handler Serves Files Without Listing Directories", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir(), "/media/files")
+		err := store.Put(context.TODO(), "products/1/abc/small.jpg", strings.NewReader("image"))
+		assert.Nil(t, err)
+		handler := http.StripPrefix("/media/files/", store.Handler())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/files/products/1/abc/small.jpg", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image", w.Body.String())
+
+		for _, path := range []string{"/media/files/products/1/abc/", "/media/files/products/1/", "/media/files/"} {
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+			assert.NotContains(t, w.Body.String(), "small.jpg", path)
+		}
+	})
+}
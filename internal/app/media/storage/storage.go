@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("Blob Not Found")
+	ErrInvalidKey = errors.New("Invalid blob key")
+)
+
+// BlobStore is implemented by every place uploaded files can be kept in. Keys are slash separated
+// relative paths, e.g. products/1/ab12cd34/original.jpg.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -1,10 +1,11 @@
 package http
 
 import (
-	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	validator "github.com/go-playground/validator/v10"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
@@ -29,35 +30,91 @@ func NewOrderHandler(mux *http.ServeMux, service service.OrderService) {
 		}
 	})
 
+	mux.HandleFunc("/order/export", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.ExportOrders(w, r)
+		}
+	})
+
+	mux.HandleFunc("/order/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/order/")
+		switch {
+		case action == "cancel" && r.Method == "POST":
+			handler.CancelOrder(w, r)
+		case action == "refunds" && r.Method == "POST":
+			handler.CreateRefund(w, r)
+		case action == "payments" && r.Method == "POST":
+			handler.PayOrder(w, r)
+		case action == "shipments" && r.Method == "POST":
+			handler.CreateShipment(w, r)
+		case strings.HasPrefix(action, "shipments/") && r.Method == "PUT":
+			handler.UpdateShipment(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
 }
 
 func (b *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) error {
 	var res *util.Response
 
 	var payload dto.CreateOrderDto
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.Decode(r, &payload)
 
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(util.SYSTEM_ERROR), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
 	}
 
 	var valid bool
 	if valid, err = isRequestValid(&payload); !valid {
-		return res.JSON(w, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
 	}
 
 	for i, detail := range payload.Details {
 		if valid, err = isDetailsRequestIsValid(&detail); !valid {
 			errMessage := fmt.Sprintf("Error row %s with details %s", strconv.Itoa(i+1), err.Error())
-			return res.JSON(w, false, util.GetResCode(util.VALIDATION_ERROR), errMessage, nil)
+			return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), errMessage, nil)
 		}
 	}
+	payload.Customer = util.GetActor(r)
 	result, err, state := b.OrderService.CreateOrder(r.Context(), payload)
 
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) ExportOrders(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	format, err := util.GetExportFormat(r)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	query := r.URL.Query()
+	filter := dto.FilterOrderDto{Status: query.Get("status"), DateFrom: query.Get("dateFrom"), DateTo: query.Get("dateTo")}
+	validate := validator.New()
+	if err = validate.Struct(&filter); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	export := util.NewExportWriter(w, format, "orders", dto.ExportOrderColumns)
+	err, state := b.OrderService.ExportOrders(r.Context(), filter, func(row dto.ExportOrder) error {
+		return export.Write(row.Values())
+	})
+	if err != nil {
+		if !export.Started() {
+			return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+		}
+		//the rows are already on their way, the client sees a truncated export
+		log.Printf("failed to export orders: %s", err.Error())
+		return err
 	}
-	return res.JSON(w, true, util.GetResCode(state), "success", result)
+	return export.Close()
 }
 
 func (b *OrderHandler) GetOrderDetails(w http.ResponseWriter, r *http.Request) error {
@@ -67,9 +124,128 @@ func (b *OrderHandler) GetOrderDetails(w http.ResponseWriter, r *http.Request) e
 	result, err, state := b.OrderService.GetOrderDetails(r.Context(), id)
 
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), result)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/order/")
+
+	var payload dto.CancelOrderDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+	payload.CancelledBy = util.GetActor(r)
+
+	result, err, state := b.OrderService.CancelOrder(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) CreateRefund(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/order/")
+
+	var payload dto.CreateRefundDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+	for i, detail := range payload.Details {
+		if err = validate.Struct(&detail); err != nil {
+			errMessage := fmt.Sprintf("Error row %s with details %s", strconv.Itoa(i+1), err.Error())
+			return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), errMessage, nil)
+		}
+	}
+	payload.CreatedBy = util.GetActor(r)
+
+	result, err, state := b.OrderService.CreateRefund(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/order/")
+	result, err, state := b.OrderService.PayOrder(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) CreateShipment(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/order/")
+
+	var payload dto.CreateShipmentDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+	for i, detail := range payload.Details {
+		if err = validate.Struct(&detail); err != nil {
+			errMessage := fmt.Sprintf("Error row %s with details %s", strconv.Itoa(i+1), err.Error())
+			return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), errMessage, nil)
+		}
+	}
+	payload.CreatedBy = util.GetActor(r)
+
+	result, err, state := b.OrderService.CreateShipment(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *OrderHandler) UpdateShipment(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, action := util.GetPathId(r.URL.Path, "/order/")
+	shipmentId, _ := util.GetPathId(action, "shipments/")
+
+	var payload dto.UpdateShipmentDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.OrderService.UpdateShipment(r.Context(), id, shipmentId, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
 	}
-	return res.JSON(w, true, util.GetResCode(state), "success", result)
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
 }
 
 func isRequestValid(payload *dto.CreateOrderDto) (bool, error) {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	orderHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/delivery/http"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
@@ -32,6 +33,8 @@ func TestCreateOrder(t *testing.T) {
 
 	j, err := json.Marshal(payload)
 	assert.NoError(t, err)
+	//the handler fills the customer from the request actor
+	payload.Customer = "anonymous"
 
 	mockService := new(mocks.OrderService)
 
@@ -42,7 +45,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("Test Create Order Success", func(t *testing.T) {
 		defer reset()
-		mockService.On("CreateOrder", context.TODO(), payload).Return(map[string]interface{}{"id": 1, "transactionNumber": "TRX-21510002451122"}, nil, "SUCCESS")
+		mockService.On("CreateOrder", context.Background(), payload).Return(map[string]interface{}{"id": 1, "transactionNumber": "TRX-21510002451122"}, nil, "SUCCESS")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -56,9 +59,44 @@ func TestCreateOrder(t *testing.T) {
 
 	})
 
+	t.Run("Test Create Order With Coupons", func(t *testing.T) {
+		defer reset()
+		couponPayload := dto.CreateOrderDto{
+			DeliveryAddress: "Indonesia",
+			Details:         orderDetail,
+			Coupons:         []string{"HEMAT10"},
+			Customer:        "budi",
+		}
+		mockService.On("CreateOrder", context.Background(), couponPayload).Return(map[string]interface{}{"id": 1, "transactionNumber": "TRX-21510002451122"}, nil, "SUCCESS")
+
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"deliveryAddress":"Indonesia","details":[{"productId":1,"qty":2}],"coupons":["HEMAT10"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "budi")
+		w := httptest.NewRecorder()
+		err = handler.CreateOrder(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order Shipping Method Without Region", func(t *testing.T) {
+		defer reset()
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"deliveryAddress":"Indonesia","details":[{"productId":1,"qty":2}],"shippingMethod":"REG"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.CreateOrder(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	})
+
 	t.Run("Test Create Order Failed Product Not Found", func(t *testing.T) {
 		defer reset()
-		mockService.On("CreateOrder", context.TODO(), payload).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+		mockService.On("CreateOrder", context.Background(), payload).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -74,7 +112,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("Test Create Order Failed Error In Database", func(t *testing.T) {
 		defer reset()
-		mockService.On("CreateOrder", context.TODO(), payload).Return(nil, errors.New("DATABASE ERROR"), "SYSTEM_ERROR")
+		mockService.On("CreateOrder", context.Background(), payload).Return(nil, errors.New("DATABASE ERROR"), "SYSTEM_ERROR")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -174,7 +212,7 @@ func TestGetOrderDetails(t *testing.T) {
 	t.Run("Test Get Order Detail Success", func(t *testing.T) {
 		defer reset()
 		err := faker.FakeData(&mockGetOrder)
-		mockService.On("GetOrderDetails", context.TODO(), 1).Return(mockGetOrder, nil, "SUCCESS")
+		mockService.On("GetOrderDetails", context.Background(), 1).Return(mockGetOrder, nil, "SUCCESS")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -191,7 +229,7 @@ func TestGetOrderDetails(t *testing.T) {
 	t.Run("Test Get Order Detail Failed Error Database", func(t *testing.T) {
 		defer reset()
 		err := faker.FakeData(&mockGetOrder)
-		mockService.On("GetOrderDetails", context.TODO(), 1).Return(nil, errors.New("Databaser Error"), "SYSTEM_ERROR")
+		mockService.On("GetOrderDetails", context.Background(), 1).Return(nil, errors.New("Databaser Error"), "SYSTEM_ERROR")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -207,7 +245,7 @@ func TestGetOrderDetails(t *testing.T) {
 	t.Run("Test Get Order Detail Data Not Found", func(t *testing.T) {
 		defer reset()
 		err := faker.FakeData(&mockGetOrder)
-		mockService.On("GetOrderDetails", context.TODO(), 1).Return(nil, errors.New("Order Not Found"), "NOT_FOUND")
+		mockService.On("GetOrderDetails", context.Background(), 1).Return(nil, errors.New("Order Not Found"), "NOT_FOUND")
 
 		orderHttp.NewOrderHandler(mux, mockService)
 		handler := orderHttp.OrderHandler{OrderService: mockService}
@@ -220,3 +258,308 @@ func TestGetOrderDetails(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
+
+func TestCancelOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	payload := dto.CancelOrderDto{Reason: "Changed my mind", CancelledBy: "customer-service"}
+	body := `{"reason":"Changed my mind"}`
+
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Cancel Order Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("CancelOrder", context.Background(), 1, payload).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/cancel", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "customer-service")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Cancel Order Failed Not Cancellable", func(t *testing.T) {
+		defer reset()
+		mockService.On("CancelOrder", context.Background(), 1, payload).Return(nil, errors.New("Order with status CANCELLED can't be cancelled"), "INVALID_STATE")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/cancel", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "customer-service")
+		w := httptest.NewRecorder()
+		err := handler.CancelOrder(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Test Cancel Order Failed Validation Body", func(t *testing.T) {
+		defer reset()
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/cancel", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.CancelOrder(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateRefund(t *testing.T) {
+	mux := http.NewServeMux()
+	payload := dto.CreateRefundDto{
+		Reason:    "Damaged",
+		Details:   []dto.CreateRefundDetails{{DetailId: 1, Qty: 1}},
+		CreatedBy: "customer-service",
+	}
+	body := `{"reason":"Damaged","details":[{"detailId":1,"qty":1}]}`
+
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Create Refund Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("CreateRefund", context.Background(), 1, payload).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/refunds", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "customer-service")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Refund Failed Exceeds Paid", func(t *testing.T) {
+		defer reset()
+		mockService.On("CreateRefund", context.Background(), 1, payload).Return(nil, errors.New("Refund exceeds the remaining paid amount"), "VALIDATION_ERROR")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/refunds", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "customer-service")
+		w := httptest.NewRecorder()
+		err := handler.CreateRefund(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Refund Failed Validation Details", func(t *testing.T) {
+		defer reset()
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/refunds", strings.NewReader(`{"reason":"Damaged","details":[{"detailId":1,"qty":0}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.CreateRefund(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPayOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Pay Order Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("PayOrder", context.Background(), 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/payments", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Pay Order Failed Already Paid", func(t *testing.T) {
+		defer reset()
+		mockService.On("PayOrder", context.Background(), 1).Return(nil, errors.New("Order with status PAID can't be paid"), "INVALID_STATE")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/payments", nil)
+		w := httptest.NewRecorder()
+		err := handler.PayOrder(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestCreateShipment(t *testing.T) {
+	mux := http.NewServeMux()
+	payload := dto.CreateShipmentDto{
+		Carrier:        "JNE",
+		TrackingNumber: "JNE123",
+		Details:        []dto.CreateShipmentDetails{{DetailId: 1, Qty: 1}},
+		CreatedBy:      "warehouse",
+	}
+	body := `{"carrier":"JNE","trackingNumber":"JNE123","details":[{"detailId":1,"qty":1}]}`
+
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Create Shipment Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("CreateShipment", context.Background(), 1, payload).Return(&dto.GetOrderDto{ID: 1, Status: "PARTIALLY_SHIPPED"}, nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/shipments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "warehouse")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Shipment Failed Invalid State", func(t *testing.T) {
+		defer reset()
+		mockService.On("CreateShipment", context.Background(), 1, payload).Return(nil, errors.New("Order with status PENDING can't be shipped"), "INVALID_STATE")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/shipments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "warehouse")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Test Create Shipment Failed Validation Details", func(t *testing.T) {
+		defer reset()
+
+		orderHttp.NewOrderHandler(mux, mockService)
+		handler := orderHttp.OrderHandler{OrderService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/order/1/shipments", strings.NewReader(`{"carrier":"JNE","trackingNumber":"JNE123","details":[{"detailId":1,"qty":0}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.CreateShipment(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateShipment(t *testing.T) {
+	mux := http.NewServeMux()
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Update Shipment Success", func(t *testing.T) {
+		defer reset()
+		payload := dto.UpdateShipmentDto{Status: "DELIVERED"}
+		mockService.On("UpdateShipment", context.Background(), 1, 3, payload).Return(&dto.GetOrderDto{ID: 1, Status: "DELIVERED"}, nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPut, "/order/1/shipments/3", strings.NewReader(`{"status":"DELIVERED"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Update Shipment Failed Unknown Status", func(t *testing.T) {
+		defer reset()
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPut, "/order/1/shipments/3", strings.NewReader(`{"status":"LOST"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "UpdateShipment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestExportOrders(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.OrderService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.OrderService)
+	}
+
+	t.Run("Test Export Orders Ndjson", func(t *testing.T) {
+		defer reset()
+		filter := dto.FilterOrderDto{Status: "PAID", DateFrom: "2026-10-01", DateTo: "2026-10-31"}
+		mockService.On("ExportOrders", context.Background(), filter, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(dto.ExportOrder) error)
+			fn(dto.ExportOrder{ID: 1, TransactionNumber: "TRX-1", Status: "PAID", TotalQty: 2, TotalTransaction: 198000})
+		}).Return(nil, "SUCCESS")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/order/export?format=ndjson&status=PAID&dateFrom=2026-10-01&dateTo=2026-10-31", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="orders.ndjson"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, `{"id":1,"transactionNumber":"TRX-1","createdAt":"","status":"PAID","deliveryAddress":"","totalQty":2,"subtotal":0,`+
+			`"discountTotal":0,"taxTotal":0,"shippingMethod":"","shippingRegion":"","shippingCost":0,"totalTransaction":198000,"totalRefunded":0}`+"\n", w.Body.String())
+	})
+
+	t.Run("Test Export Orders Invalid Date", func(t *testing.T) {
+		defer reset()
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/order/export?dateFrom=19-10-2026", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Export Orders Invalid Range", func(t *testing.T) {
+		defer reset()
+		mockService.On("ExportOrders", context.Background(), mock.Anything, mock.Anything).Return(errors.New("dateFrom must not be after dateTo"), "VALIDATION_ERROR")
+
+		orderHttp.NewOrderHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/order/export?dateFrom=2026-10-31&dateTo=2026-10-01", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,33 +1,207 @@
 package dto
 
+import paymentDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+
 type CreateOrderDto struct {
-	DeliveryAddress  string               `json:"deliveryAddress" validate:"required"`
-	Details          []CreateOrderDetails `json:"details" validate:"required"`
-	TotalTransaction float32
-	TotalQty         int
+	DeliveryAddress  string                `json:"deliveryAddress" validate:"required"`
+	Details          []CreateOrderDetails  `json:"details" validate:"required"`
+	Coupons          []string              `json:"coupons"`
+	ShippingMethod   string                `json:"shippingMethod"`
+	ShippingRegion   string                `json:"shippingRegion" validate:"required_with=ShippingMethod"`
+	ShippingCost     float32               `json:"-"`
+	Customer         string                `json:"-"`
+	Subtotal         float32               `json:"-"`
+	DiscountTotal    float32               `json:"-"`
+	Discounts        []CreateOrderDiscount `json:"-"`
+	TaxTotal         float32               `json:"-"`
+	TaxExclusive     float32               `json:"-"`
+	Taxes            []CreateOrderTax      `json:"-"`
+	TotalTransaction float32               `json:"-"`
+	TotalQty         int                   `json:"-"`
 }
 
 type CreateOrderDetails struct {
-	ProductId int `json:"productId" validate:"required"`
-	Price     float32
-	Qty       int `json:"qty" validate:"required"`
-	Total     float32
+	ProductId    int     `json:"productId" validate:"required"`
+	VariantId    int     `json:"variantId"`
+	Sku          string  `json:"-"`
+	BrandId      int     `json:"-"`
+	Price        float32 `json:"-"`
+	Qty          int     `json:"qty" validate:"required"`
+	Total        float32 `json:"-"`
+	Discount     float32 `json:"-"`
+	Tax          float32 `json:"-"`
+	TaxExclusive float32 `json:"-"`
+}
+
+type CreateOrderDiscount struct {
+	PromotionId      int
+	Code             string
+	PerCustomerLimit int
+	Amount           float32
+}
+
+type CreateOrderTax struct {
+	TaxRuleId int
+	Name      string
+	Rate      float32
+	Inclusive bool
+	Amount    float32
+}
+
+type CancelOrderDto struct {
+	Reason      string `json:"reason" validate:"required"`
+	CancelledBy string `json:"-"`
 }
 
 type GetOrderDto struct {
-	ID                int               `json:"id"`
-	DeliveryAddress   string            `json:"deliveryAddress"`
-	TransactionNumber string            `json:"transactionNumber"`
-	TotalTransaction  float32           `json:"totalTransaction"`
-	TotalQty          float32           `json:"totalQty"`
-	Details           []GetOrderDetails `json:"details"`
+	ID                int                        `json:"id"`
+	DeliveryAddress   string                     `json:"deliveryAddress"`
+	TransactionNumber string                     `json:"transactionNumber"`
+	Subtotal          float32                    `json:"subtotal"`
+	DiscountTotal     float32                    `json:"discountTotal"`
+	TaxTotal          float32                    `json:"taxTotal"`
+	ShippingMethod    string                     `json:"shippingMethod,omitempty"`
+	ShippingRegion    string                     `json:"shippingRegion,omitempty"`
+	ShippingCost      float32                    `json:"shippingCost"`
+	TotalTransaction  float32                    `json:"totalTransaction"`
+	TotalQty          float32                    `json:"totalQty"`
+	Status            string                     `json:"status"`
+	CancelReason      string                     `json:"cancelReason,omitempty"`
+	CancelledBy       string                     `json:"cancelledBy,omitempty"`
+	CancelledAt       string                     `json:"cancelledAt,omitempty"`
+	TotalRefunded     float32                    `json:"totalRefunded"`
+	NetTotal          float32                    `json:"netTotal"`
+	Details           []GetOrderDetails          `json:"details"`
+	Discounts         []GetOrderDiscount         `json:"discounts"`
+	Taxes             []GetOrderTax              `json:"taxes"`
+	Refunds           []GetRefundDto             `json:"refunds"`
+	Shipments         []GetShipmentDto           `json:"shipments"`
+	Payments          []paymentDto.GetPaymentDto `json:"payments"`
 }
 
 type GetOrderDetails struct {
-	ID          int     `json:"id"`
-	ProductName string  `json:"productName"`
-	BrandName   string  `json:"brandName"`
-	Qty         int     `json:"qty"`
-	Price       float32 `json:"price"`
-	Total       float32 `json:"total"`
+	ID           int     `json:"id"`
+	ProductName  string  `json:"productName"`
+	VariantId    int     `json:"variantId,omitempty"`
+	Sku          string  `json:"sku,omitempty"`
+	BrandName    string  `json:"brandName"`
+	Qty          int     `json:"qty"`
+	RefundedQty  int     `json:"refundedQty"`
+	ShippedQty   int     `json:"shippedQty"`
+	Price        float32 `json:"price"`
+	Total        float32 `json:"total"`
+	Discount     float32 `json:"discount"`
+	Tax          float32 `json:"tax"`
+	TaxExclusive float32 `json:"taxExclusive"`
+}
+
+type GetOrderDiscount struct {
+	PromotionId int     `json:"-"`
+	Code        string  `json:"code"`
+	Customer    string  `json:"-"`
+	Amount      float32 `json:"amount"`
+}
+
+type GetOrderTax struct {
+	Name      string  `json:"name"`
+	Rate      float32 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	Amount    float32 `json:"amount"`
+}
+
+type CreateRefundDto struct {
+	Reason      string                `json:"reason" validate:"required"`
+	Amount      float32               `json:"amount" validate:"gte=0"`
+	Details     []CreateRefundDetails `json:"details"`
+	TotalRefund float32
+	CreatedBy   string `json:"-"`
+}
+
+type CreateRefundDetails struct {
+	DetailId int `json:"detailId" validate:"required"`
+	Qty      int `json:"qty" validate:"required,gt=0"`
+	Amount   float32
+}
+
+type GetRefundDto struct {
+	ID        int                `json:"id"`
+	Amount    float32            `json:"amount"`
+	Reason    string             `json:"reason"`
+	CreatedBy string             `json:"createdBy"`
+	CreatedAt string             `json:"createdAt"`
+	Details   []GetRefundDetails `json:"details"`
+}
+
+type GetRefundDetails struct {
+	DetailId int     `json:"detailId"`
+	Qty      int     `json:"qty"`
+	Amount   float32 `json:"amount"`
+}
+
+type CreateShipmentDto struct {
+	Carrier        string                  `json:"carrier" validate:"required,max=50"`
+	TrackingNumber string                  `json:"trackingNumber" validate:"required,max=100"`
+	Details        []CreateShipmentDetails `json:"details" validate:"required,min=1"`
+	CreatedBy      string                  `json:"-"`
+}
+
+type CreateShipmentDetails struct {
+	DetailId int `json:"detailId" validate:"required"`
+	Qty      int `json:"qty" validate:"required,gt=0"`
+}
+
+type UpdateShipmentDto struct {
+	Carrier        string `json:"carrier" validate:"max=50"`
+	TrackingNumber string `json:"trackingNumber" validate:"max=100"`
+	Status         string `json:"status" validate:"omitempty,oneof=IN_TRANSIT DELIVERED"`
+}
+
+type GetShipmentDto struct {
+	ID             int                  `json:"id"`
+	Carrier        string               `json:"carrier"`
+	TrackingNumber string               `json:"trackingNumber"`
+	Status         string               `json:"status"`
+	CreatedBy      string               `json:"createdBy"`
+	ShippedAt      string               `json:"shippedAt"`
+	DeliveredAt    string               `json:"deliveredAt,omitempty"`
+	Details        []GetShipmentDetails `json:"details"`
+}
+
+type GetShipmentDetails struct {
+	DetailId int `json:"detailId"`
+	Qty      int `json:"qty"`
+}
+
+// FilterOrderDto dates are yyyy-mm-dd, DateTo is inclusive
+type FilterOrderDto struct {
+	Status   string `json:"status"`
+	DateFrom string `json:"dateFrom" validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string `json:"dateTo" validate:"omitempty,datetime=2006-01-02"`
+}
+
+var ExportOrderColumns = []string{"id", "transactionNumber", "createdAt", "status", "deliveryAddress", "totalQty", "subtotal",
+	"discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "totalRefunded"}
+
+// ExportOrder is a row of the order export
+type ExportOrder struct {
+	ID                int
+	TransactionNumber string
+	CreatedAt         string
+	Status            string
+	DeliveryAddress   string
+	TotalQty          int
+	Subtotal          float32
+	DiscountTotal     float32
+	TaxTotal          float32
+	ShippingMethod    string
+	ShippingRegion    string
+	ShippingCost      float32
+	TotalTransaction  float32
+	TotalRefunded     float32
+}
+
+// Values are in the order of ExportOrderColumns
+func (o ExportOrder) Values() []interface{} {
+	return []interface{}{o.ID, o.TransactionNumber, o.CreatedAt, o.Status, o.DeliveryAddress, o.TotalQty, o.Subtotal,
+		o.DiscountTotal, o.TaxTotal, o.ShippingMethod, o.ShippingRegion, o.ShippingCost, o.TotalTransaction, o.TotalRefunded}
 }
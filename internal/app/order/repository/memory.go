@@ -0,0 +1,602 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
+	productDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	productRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
+	promotionRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+// Memory is an OrderRepository keeping the orders with their refunds and shipments in memory, for tests and for
+// running without a database. Stock is taken from the product repository and coupon usages are claimed from the
+// promotion repository, both given back when a later step of CreateOrder fails. Changes are recorded to the
+// audit log, and their events written to the outbox, right after they are made, there is no transaction to share.
+type Memory struct {
+	mu           sync.RWMutex
+	orders       []*memoryOrder
+	lastDetail   int
+	lastRefund   int
+	lastShipment int
+	products     productRepository.ProductRepository
+	promotions   promotionRepository.PromotionRepository
+	audit        auditRepository.AuditRepository
+	outbox       eventRepository.OutboxRepository
+}
+
+type memoryOrder struct {
+	dto.GetOrderDto
+	CreatedAt time.Time
+	Lines     []memoryDetail
+}
+
+type memoryDetail struct {
+	dto.CreateOrderDetails
+	ID int
+}
+
+func NewMemoryOrder(products productRepository.ProductRepository, promotions promotionRepository.PromotionRepository, audit auditRepository.AuditRepository,
+	outbox eventRepository.OutboxRepository) *Memory {
+	return &Memory{products: products, promotions: promotions, audit: audit, outbox: outbox}
+}
+
+// now is how the datetime columns read back from the database look
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (m *Memory) CreateOrder(ctx context.Context, payload dto.CreateOrderDto, transactionNumber string) (*model.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stock []productDto.StockAdjustment
+	for _, detail := range payload.Details {
+		if detail.VariantId > 0 {
+			stock = append(stock, productDto.StockAdjustment{VariantId: detail.VariantId, Qty: -detail.Qty})
+		}
+	}
+	if len(stock) > 0 {
+		err := m.products.AdjustStock(ctx, stock)
+		if errors.Is(err, productRepository.ErrOutOfStock) {
+			return nil, ErrOutOfStock
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id := len(m.orders) + 1
+	for i, discount := range payload.Discounts {
+		//orders are created one at a time under the lock, so the count can't go stale before the claim
+		if discount.PerCustomerLimit > 0 {
+			used, err := m.promotions.CountCustomerUsage(ctx, discount.PromotionId, payload.Customer)
+			if err == nil && used >= discount.PerCustomerLimit {
+				err = ErrPromotionCustomerLimit
+			}
+			if err != nil {
+				m.rollback(id, payload.Discounts[:i], stock)
+				return nil, err
+			}
+		}
+
+		err := m.promotions.ClaimUsage(ctx, model.PromotionUsage{
+			PromotionId:   discount.PromotionId,
+			TransactionId: id,
+			Code:          discount.Code,
+			Customer:      payload.Customer,
+			Amount:        discount.Amount,
+		})
+		if err != nil {
+			m.rollback(id, payload.Discounts[:i], stock)
+			if errors.Is(err, promotionRepository.ErrUsageLimitReached) {
+				return nil, ErrPromotionExhausted
+			}
+			return nil, err
+		}
+	}
+
+	order := &memoryOrder{
+		GetOrderDto: dto.GetOrderDto{
+			ID:                id,
+			DeliveryAddress:   payload.DeliveryAddress,
+			TransactionNumber: transactionNumber,
+			Subtotal:          payload.Subtotal,
+			DiscountTotal:     payload.DiscountTotal,
+			TaxTotal:          payload.TaxTotal,
+			ShippingMethod:    payload.ShippingMethod,
+			ShippingRegion:    payload.ShippingRegion,
+			ShippingCost:      payload.ShippingCost,
+			TotalTransaction:  payload.TotalTransaction,
+			TotalQty:          float32(payload.TotalQty),
+			Status:            model.OrderStatusPending,
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, detail := range payload.Details {
+		m.lastDetail++
+		order.Lines = append(order.Lines, memoryDetail{CreateOrderDetails: detail, ID: m.lastDetail})
+	}
+	for _, discount := range payload.Discounts {
+		order.Discounts = append(order.Discounts, dto.GetOrderDiscount{PromotionId: discount.PromotionId, Code: discount.Code,
+			Customer: payload.Customer, Amount: discount.Amount})
+	}
+	for _, tax := range payload.Taxes {
+		order.Taxes = append(order.Taxes, dto.GetOrderTax{Name: tax.Name, Rate: tax.Rate, Inclusive: tax.Inclusive, Amount: tax.Amount})
+	}
+	m.orders = append(m.orders, order)
+
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityOrder, EntityId: id, After: orderFields(payload, transactionNumber)})
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.outbox.Add(ctx, nil, orderCreated(id, payload, transactionNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Transaction{ID: id}, nil
+}
+
+// rollback gives back what CreateOrder took before failing. It runs on a context of its own, the one of the
+// order may be what failed.
+func (m *Memory) rollback(id int, claimed []dto.CreateOrderDiscount, stock []productDto.StockAdjustment) {
+	ctx := context.Background()
+	for _, discount := range claimed {
+		m.promotions.ReleaseUsage(ctx, discount.PromotionId, id)
+	}
+
+	restock := make([]productDto.StockAdjustment, len(stock))
+	for i, adjustment := range stock {
+		restock[i] = productDto.StockAdjustment{VariantId: adjustment.VariantId, Qty: -adjustment.Qty}
+	}
+	if len(restock) > 0 {
+		m.products.AdjustStock(ctx, restock)
+	}
+}
+
+func (m *Memory) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error) {
+	m.mu.RLock()
+	order := m.find(id)
+	if order == nil {
+		m.mu.RUnlock()
+		return nil, nil
+	}
+	data := order.GetOrderDto
+	details := append([]memoryDetail(nil), order.Lines...)
+	data.Discounts = append([]dto.GetOrderDiscount(nil), order.Discounts...)
+	data.Taxes = append([]dto.GetOrderTax(nil), order.Taxes...)
+	data.Refunds = copyRefunds(order.Refunds)
+	data.Shipments = copyShipments(order.Shipments)
+	m.mu.RUnlock()
+
+	//the names are read without the lock and of deleted products too, like the join of Repository, which leaves
+	//out the lines of a missing product
+	for _, detail := range details {
+		products, err := m.products.GetProduct(ctx, productDto.FilterProductDto{ID: detail.ProductId, IncludeDeleted: true})
+		if err != nil {
+			return nil, err
+		}
+		if len(products) == 0 {
+			continue
+		}
+
+		data.Details = append(data.Details, dto.GetOrderDetails{
+			ID:           detail.ID,
+			ProductName:  products[0].Title,
+			VariantId:    detail.VariantId,
+			Sku:          detail.Sku,
+			BrandName:    products[0].Brand.Title,
+			Qty:          detail.Qty,
+			RefundedQty:  refundedQty(data.Refunds, detail.ID),
+			ShippedQty:   shippedQty(data.Shipments, detail.ID),
+			Price:        detail.Price,
+			Total:        detail.Total,
+			Discount:     detail.Discount,
+			Tax:          detail.Tax,
+			TaxExclusive: detail.TaxExclusive,
+		})
+	}
+
+	for _, refund := range data.Refunds {
+		data.TotalRefunded += refund.Amount
+	}
+	data.NetTotal = data.TotalTransaction - data.TotalRefunded
+
+	return &data, nil
+}
+
+func (m *Memory) find(id int) *memoryOrder {
+	if id < 1 || id > len(m.orders) {
+		return nil
+	}
+	return m.orders[id-1]
+}
+
+func (m *Memory) CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	//only cancel when the order is still in a cancellable status, so concurrent updates can't be overwritten
+	order := m.find(id)
+	if order == nil || !containsStatus(model.CancellableOrderStatuses, order.Status) {
+		return false, nil
+	}
+
+	before := memoryOrderState(order)
+	order.Status = model.OrderStatusCancelled
+	order.CancelReason = payload.Reason
+	order.CancelledBy = payload.CancelledBy
+	order.CancelledAt = now()
+
+	return true, m.recordStatus(ctx, id, before, order)
+}
+
+func (m *Memory) RestoreCancelledOrder(ctx context.Context, id int, status string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order := m.find(id)
+	if order == nil || order.Status != model.OrderStatusCancelled {
+		return false, nil
+	}
+
+	before := memoryOrderState(order)
+	order.Status = status
+	order.CancelReason = ""
+	order.CancelledBy = ""
+	order.CancelledAt = ""
+
+	return true, m.recordStatus(ctx, id, before, order)
+}
+
+// recordStatus records the change of the order status from before, like updateOrder
+func (m *Memory) recordStatus(ctx context.Context, id int, before map[string]interface{}, order *memoryOrder) error {
+	after := memoryOrderState(order)
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityOrder, EntityId: id, Before: before, After: after})
+	if err != nil {
+		return err
+	}
+
+	if event, ok := statusChanged(id, before, after); ok {
+		return m.outbox.Add(ctx, nil, event)
+	}
+	return nil
+}
+
+// memoryOrderState is the status of the order as recorded to the audit log, like orderState
+func memoryOrderState(order *memoryOrder) map[string]interface{} {
+	state := map[string]interface{}{"status": order.Status, "cancelReason": nil, "cancelledBy": nil}
+	if order.Status == model.OrderStatusCancelled {
+		state["cancelReason"] = order.CancelReason
+		state["cancelledBy"] = order.CancelledBy
+	}
+	return state
+}
+
+func (m *Memory) CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*model.Refund, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order := m.find(orderId)
+	if order == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	var refunded float32
+	for _, refund := range order.Refunds {
+		refunded += refund.Amount
+	}
+	if refunded+payload.TotalRefund > order.TotalTransaction {
+		return nil, ErrRefundExceedsPaid
+	}
+	refunding := map[int]int{}
+	for _, detail := range payload.Details {
+		var line *memoryDetail
+		for i := range order.Lines {
+			if order.Lines[i].ID == detail.DetailId {
+				line = &order.Lines[i]
+			}
+		}
+		if line == nil {
+			return nil, ErrRefundExceedsOrder
+		}
+
+		refunding[line.ID] += detail.Qty
+		if refunding[line.ID] > line.Qty-refundedQty(order.Refunds, line.ID) {
+			return nil, ErrRefundExceedsOrder
+		}
+	}
+
+	m.lastRefund++
+	refund := dto.GetRefundDto{
+		ID:        m.lastRefund,
+		Amount:    payload.TotalRefund,
+		Reason:    payload.Reason,
+		CreatedBy: payload.CreatedBy,
+		CreatedAt: now(),
+	}
+	for _, detail := range payload.Details {
+		refund.Details = append(refund.Details, dto.GetRefundDetails{DetailId: detail.DetailId, Qty: detail.Qty, Amount: detail.Amount})
+	}
+	order.Refunds = append(order.Refunds, refund)
+
+	after := map[string]interface{}{"transactionId": orderId, "amount": payload.TotalRefund, "reason": payload.Reason, "createdBy": payload.CreatedBy, "details": payload.Details}
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityRefund, EntityId: refund.ID, After: after})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Refund{ID: refund.ID, TransactionId: orderId, Amount: payload.TotalRefund}, nil
+}
+
+func (m *Memory) DeleteRefund(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, order := range m.orders {
+		for i, refund := range order.Refunds {
+			if refund.ID == id {
+				order.Refunds = append(order.Refunds[:i:i], order.Refunds[i+1:]...)
+
+				before := map[string]interface{}{"transactionId": order.ID, "amount": refund.Amount, "reason": refund.Reason, "createdBy": refund.CreatedBy}
+				return m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionDelete, EntityType: model.AuditEntityRefund, EntityId: id, Before: before})
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Memory) CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*model.Shipment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order := m.find(orderId)
+	if order == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	shipping := map[int]int{}
+	for _, detail := range payload.Details {
+		var line *memoryDetail
+		for i := range order.Lines {
+			if order.Lines[i].ID == detail.DetailId {
+				line = &order.Lines[i]
+			}
+		}
+		if line == nil {
+			return nil, ErrShipmentExceedsOrder
+		}
+
+		shipping[line.ID] += detail.Qty
+		remaining := line.Qty - refundedQty(order.Refunds, line.ID) - shippedQty(order.Shipments, line.ID)
+		if shipping[line.ID] > remaining {
+			return nil, ErrShipmentExceedsOrder
+		}
+	}
+
+	m.lastShipment++
+	shipment := dto.GetShipmentDto{
+		ID:             m.lastShipment,
+		Carrier:        payload.Carrier,
+		TrackingNumber: payload.TrackingNumber,
+		Status:         model.ShipmentStatusShipped,
+		CreatedBy:      payload.CreatedBy,
+		ShippedAt:      now(),
+	}
+	for _, detail := range payload.Details {
+		shipment.Details = append(shipment.Details, dto.GetShipmentDetails{DetailId: detail.DetailId, Qty: detail.Qty})
+	}
+	order.Shipments = append(order.Shipments, shipment)
+
+	after := map[string]interface{}{"transactionId": orderId, "carrier": payload.Carrier, "trackingNumber": payload.TrackingNumber,
+		"status": model.ShipmentStatusShipped, "createdBy": payload.CreatedBy, "details": payload.Details}
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityShipment, EntityId: shipment.ID, After: after})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Shipment{
+		ID:             shipment.ID,
+		TransactionId:  orderId,
+		Carrier:        payload.Carrier,
+		TrackingNumber: payload.TrackingNumber,
+		Status:         model.ShipmentStatusShipped,
+		CreatedBy:      payload.CreatedBy,
+	}, nil
+}
+
+func (m *Memory) UpdateShipment(ctx context.Context, orderId int, shipmentId int, from string, payload dto.UpdateShipmentDto) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	//only update when the shipment is still in the status it was validated against
+	order := m.find(orderId)
+	if order == nil {
+		return false, nil
+	}
+	for i := range order.Shipments {
+		shipment := &order.Shipments[i]
+		if shipment.ID != shipmentId || shipment.Status != from {
+			continue
+		}
+
+		before := memoryShipmentState(shipment)
+		if payload.Carrier != "" {
+			shipment.Carrier = payload.Carrier
+		}
+		if payload.TrackingNumber != "" {
+			shipment.TrackingNumber = payload.TrackingNumber
+		}
+		if payload.Status != "" {
+			shipment.Status = payload.Status
+		}
+		if payload.Status == model.ShipmentStatusDelivered {
+			shipment.DeliveredAt = now()
+		}
+
+		err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityShipment, EntityId: shipmentId, Before: before, After: memoryShipmentState(shipment)})
+		return true, err
+	}
+	return false, nil
+}
+
+// memoryShipmentState is the shipment as recorded to the audit log, like shipmentState
+func memoryShipmentState(shipment *dto.GetShipmentDto) map[string]interface{} {
+	state := map[string]interface{}{"carrier": shipment.Carrier, "trackingNumber": shipment.TrackingNumber, "status": shipment.Status, "deliveredAt": nil}
+	if shipment.DeliveredAt != "" {
+		state["deliveredAt"] = shipment.DeliveredAt
+	}
+	return state
+}
+
+func (m *Memory) UpdateOrderStatus(ctx context.Context, id int, from []string, to string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order := m.find(id)
+	if order == nil || !containsStatus(from, order.Status) {
+		return false, nil
+	}
+
+	before := memoryOrderState(order)
+	order.Status = to
+
+	return true, m.recordStatus(ctx, id, before, order)
+}
+
+func (m *Memory) GetUnpaidOrders(ctx context.Context, olderThan time.Duration, limit int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ids []int
+	createdBefore := time.Now().Add(-olderThan)
+	for _, order := range m.orders {
+		if len(ids) == limit {
+			break
+		}
+		if containsStatus(model.PayableOrderStatuses, order.Status) && !order.CreatedAt.After(createdBefore) {
+			ids = append(ids, order.ID)
+		}
+	}
+	return ids, nil
+}
+
+// ExportOrders passes every order matching the filter to fn, the lock is not held while fn runs
+func (m *Memory) ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(row dto.ExportOrder) error) error {
+	var from, to time.Time
+	var err error
+	if filter.DateFrom != "" {
+		if from, err = time.Parse("2006-01-02", filter.DateFrom); err != nil {
+			return err
+		}
+	}
+	if filter.DateTo != "" {
+		if to, err = time.Parse("2006-01-02", filter.DateTo); err != nil {
+			return err
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	var rows []dto.ExportOrder
+	m.mu.RLock()
+	for _, order := range m.orders {
+		if filter.Status != "" && order.Status != filter.Status {
+			continue
+		}
+		if !from.IsZero() && order.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !order.CreatedAt.Before(to) {
+			continue
+		}
+
+		row := dto.ExportOrder{
+			ID:                order.ID,
+			TransactionNumber: order.TransactionNumber,
+			CreatedAt:         order.CreatedAt.Format(time.RFC3339),
+			Status:            order.Status,
+			DeliveryAddress:   order.DeliveryAddress,
+			TotalQty:          int(order.TotalQty),
+			Subtotal:          order.Subtotal,
+			DiscountTotal:     order.DiscountTotal,
+			TaxTotal:          order.TaxTotal,
+			ShippingMethod:    order.ShippingMethod,
+			ShippingRegion:    order.ShippingRegion,
+			ShippingCost:      order.ShippingCost,
+			TotalTransaction:  order.TotalTransaction,
+		}
+		for _, refund := range order.Refunds {
+			row.TotalRefunded += refund.Amount
+		}
+		rows = append(rows, row)
+	}
+	m.mu.RUnlock()
+
+	for _, row := range rows {
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func refundedQty(refunds []dto.GetRefundDto, detailId int) int {
+	qty := 0
+	for _, refund := range refunds {
+		for _, detail := range refund.Details {
+			if detail.DetailId == detailId {
+				qty += detail.Qty
+			}
+		}
+	}
+	return qty
+}
+
+func shippedQty(shipments []dto.GetShipmentDto, detailId int) int {
+	qty := 0
+	for _, shipment := range shipments {
+		for _, detail := range shipment.Details {
+			if detail.DetailId == detailId {
+				qty += detail.Qty
+			}
+		}
+	}
+	return qty
+}
+
+func copyRefunds(refunds []dto.GetRefundDto) []dto.GetRefundDto {
+	var copied []dto.GetRefundDto
+	for _, refund := range refunds {
+		refund.Details = append([]dto.GetRefundDetails(nil), refund.Details...)
+		copied = append(copied, refund)
+	}
+	return copied
+}
+
+func copyShipments(shipments []dto.GetShipmentDto) []dto.GetShipmentDto {
+	var copied []dto.GetShipmentDto
+	for _, shipment := range shipments {
+		shipment.Details = append([]dto.GetShipmentDetails(nil), shipment.Details...)
+		copied = append(copied, shipment)
+	}
+	return copied
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	brandDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	brandRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
+	productDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	productRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
+	promotionRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/repository"
+	auditMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/audit/repository"
+	categoryMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/repository"
+	eventMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/repository"
+	promotionMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/promotion/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestMemoryOrder(t *testing.T) {
+	ctx := context.TODO()
+	audit := new(auditMocks.AuditRepository)
+	audit.On("Record", mock.Anything, nil, mock.AnythingOfType("dto.Change")).Return(nil)
+	outbox := new(eventMocks.OutboxRepository)
+	outbox.On("Add", mock.Anything, nil, mock.AnythingOfType("dto.Event")).Return(nil)
+	brands := brandRepository.NewMemoryBrand(audit, outbox)
+	brands.Create(ctx, brandDto.InsertBrandDto{Title: "Nike"})
+	products := productRepository.NewMemoryProduct(brands, new(categoryMocks.CategoryRepository), audit, outbox)
+	products.Create(ctx, productDto.InsertProductDto{Title: "Pegasus", BrandId: 1, Price: 100, Options: []string{"size"}})
+	products.CreateVariant(ctx, productDto.InsertVariantDto{ProductId: 1, Sku: "PEG-42", Stock: 3, Options: map[string]string{"size": "42"}})
+
+	promotions := new(promotionMocks.PromotionRepository)
+	r := repository.NewMemoryOrder(products, promotions, audit, outbox)
+
+	payload := dto.CreateOrderDto{
+		DeliveryAddress:  "Jl. Merdeka 1",
+		Details:          []dto.CreateOrderDetails{{ProductId: 1, VariantId: 1, Sku: "PEG-42", Price: 100, Qty: 2, Total: 200, Discount: 10}},
+		Customer:         "budi",
+		Subtotal:         200,
+		DiscountTotal:    10,
+		Discounts:        []dto.CreateOrderDiscount{{PromotionId: 1, Code: "TEN", Amount: 10}},
+		TotalTransaction: 190,
+		TotalQty:         2,
+	}
+	usage := model.PromotionUsage{PromotionId: 1, TransactionId: 1, Code: "TEN", Customer: "budi", Amount: 10}
+	promotions.On("ClaimUsage", mock.Anything, usage).Return(nil).Once()
+
+	order, err := r.CreateOrder(ctx, payload, "TRX-1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, order.ID)
+
+	stock := func() int {
+		variants, _ := products.GetVariants(ctx, productDto.FilterVariantDto{ID: 1})
+		return variants[0].Stock
+	}
+
+	t.Run("Test Create Order Is All Or Nothing", func(t *testing.T) {
+		payload.Details[0].Qty = 1
+		usage.TransactionId = 2
+		promotions.On("ClaimUsage", mock.Anything, usage).Return(promotionRepository.ErrUsageLimitReached).Once()
+
+		_, err := r.CreateOrder(ctx, payload, "TRX-2")
+		assert.Equal(t, repository.ErrPromotionExhausted, err)
+		assert.Equal(t, 1, stock())
+
+		payload.Details[0].Qty = 2
+		_, err = r.CreateOrder(ctx, payload, "TRX-2")
+		assert.Equal(t, repository.ErrOutOfStock, err)
+		assert.Equal(t, 1, stock())
+		promotions.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order Coupon Used Up By Customer", func(t *testing.T) {
+		once := payload
+		once.Details = []dto.CreateOrderDetails{{ProductId: 1, VariantId: 1, Sku: "PEG-42", Price: 100, Qty: 1, Total: 100, Discount: 10}}
+		once.Discounts = []dto.CreateOrderDiscount{{PromotionId: 2, Code: "ONCE", PerCustomerLimit: 1, Amount: 10}}
+		promotions.On("CountCustomerUsage", mock.Anything, 2, "budi").Return(1, nil).Once()
+
+		_, err := r.CreateOrder(ctx, once, "TRX-2")
+		assert.Equal(t, repository.ErrPromotionCustomerLimit, err)
+		assert.Equal(t, 1, stock())
+		promotions.AssertNotCalled(t, "ClaimUsage", mock.Anything, mock.MatchedBy(func(usage model.PromotionUsage) bool { return usage.PromotionId == 2 }))
+	})
+
+	t.Run("Test Get Order Details", func(t *testing.T) {
+		result, err := r.GetOrderDetails(ctx, order.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "TRX-1", result.TransactionNumber)
+		assert.Equal(t, model.OrderStatusPending, result.Status)
+		assert.Equal(t, []dto.GetOrderDetails{{ID: 1, ProductName: "Pegasus", VariantId: 1, Sku: "PEG-42", BrandName: "Nike", Qty: 2,
+			Price: 100, Total: 200, Discount: 10}}, result.Details)
+		assert.Equal(t, []dto.GetOrderDiscount{{PromotionId: 1, Code: "TEN", Customer: "budi", Amount: 10}}, result.Discounts)
+
+		missing, err := r.GetOrderDetails(ctx, 2)
+		assert.Nil(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("Test Get Unpaid Orders", func(t *testing.T) {
+		ids, err := r.GetUnpaidOrders(ctx, time.Hour, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, ids)
+
+		ids, err = r.GetUnpaidOrders(ctx, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{order.ID}, ids)
+	})
+
+	t.Run("Test Refund And Shipment", func(t *testing.T) {
+		updated, err := r.UpdateOrderStatus(ctx, order.ID, model.PayableOrderStatuses, model.OrderStatusPaid)
+		assert.Nil(t, err)
+		assert.True(t, updated)
+
+		_, err = r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "too much", TotalRefund: 200})
+		assert.Equal(t, repository.ErrRefundExceedsPaid, err)
+		_, err = r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "too many", TotalRefund: 10,
+			Details: []dto.CreateRefundDetails{{DetailId: 1, Qty: 2, Amount: 5}, {DetailId: 1, Qty: 1, Amount: 5}}})
+		assert.Equal(t, repository.ErrRefundExceedsOrder, err)
+
+		_, err = r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "damaged", TotalRefund: 95,
+			Details: []dto.CreateRefundDetails{{DetailId: 1, Qty: 1, Amount: 95}}})
+		assert.Nil(t, err)
+
+		_, err = r.CreateShipment(ctx, order.ID, dto.CreateShipmentDto{Carrier: "JNE", TrackingNumber: "1", Details: []dto.CreateShipmentDetails{{DetailId: 1, Qty: 2}}})
+		assert.Equal(t, repository.ErrShipmentExceedsOrder, err)
+
+		shipment, err := r.CreateShipment(ctx, order.ID, dto.CreateShipmentDto{Carrier: "JNE", TrackingNumber: "1", Details: []dto.CreateShipmentDetails{{DetailId: 1, Qty: 1}}})
+		assert.Nil(t, err)
+
+		updated, err = r.UpdateShipment(ctx, order.ID, shipment.ID, model.ShipmentStatusInTransit, dto.UpdateShipmentDto{Status: model.ShipmentStatusDelivered})
+		assert.Nil(t, err)
+		assert.False(t, updated)
+		updated, err = r.UpdateShipment(ctx, order.ID, shipment.ID, model.ShipmentStatusShipped, dto.UpdateShipmentDto{Status: model.ShipmentStatusDelivered})
+		assert.Nil(t, err)
+		assert.True(t, updated)
+
+		result, err := r.GetOrderDetails(ctx, order.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Details[0].RefundedQty)
+		assert.Equal(t, 1, result.Details[0].ShippedQty)
+		assert.Equal(t, float32(95), result.NetTotal)
+		assert.NotEmpty(t, result.Shipments[0].DeliveredAt)
+	})
+
+	t.Run("Test Cancel And Export", func(t *testing.T) {
+		cancelled, err := r.CancelOrder(ctx, order.ID, dto.CancelOrderDto{Reason: "late", CancelledBy: "admin"})
+		assert.Nil(t, err)
+		assert.True(t, cancelled)
+
+		var exported []dto.ExportOrder
+		err = r.ExportOrders(ctx, dto.FilterOrderDto{Status: model.OrderStatusCancelled}, func(row dto.ExportOrder) error {
+			exported = append(exported, row)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Len(t, exported, 1)
+		assert.Equal(t, float32(95), exported[0].TotalRefunded)
+
+		exported = nil
+		err = r.ExportOrders(ctx, dto.FilterOrderDto{DateTo: "2000-01-01"}, func(row dto.ExportOrder) error {
+			exported = append(exported, row)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Empty(t, exported)
+	})
+
+	t.Run("Test Order Events", func(t *testing.T) {
+		var events []eventDto.Event
+		for _, call := range outbox.Calls {
+			if event := call.Arguments.Get(2).(eventDto.Event); event.EntityId == order.ID && event.Type != model.EventBrandCreated &&
+				event.Type != model.EventProductPriceChanged {
+				events = append(events, event)
+			}
+		}
+
+		//the orders that failed tell nothing
+		assert.Len(t, events, 3)
+		assert.Equal(t, model.EventOrderCreated, events[0].Type)
+		assert.Equal(t, "TRX-1", events[0].Payload.(map[string]interface{})["transactionNumber"])
+		assert.Equal(t, map[string]interface{}{"id": order.ID, "previousStatus": model.OrderStatusPending, "status": model.OrderStatusPaid, "cancelReason": nil}, events[1].Payload)
+		assert.Equal(t, map[string]interface{}{"id": order.ID, "previousStatus": model.OrderStatusPaid, "status": model.OrderStatusCancelled, "cancelReason": "late"}, events[2].Payload)
+	})
+	t.Run("Test Restore Cancelled Order", func(t *testing.T) {
+		restored, err := r.RestoreCancelledOrder(ctx, order.ID, model.OrderStatusPaid)
+		assert.Nil(t, err)
+		assert.True(t, restored)
+
+		result, err := r.GetOrderDetails(ctx, order.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, model.OrderStatusPaid, result.Status)
+		assert.Empty(t, result.CancelReason)
+
+		restored, err = r.RestoreCancelledOrder(ctx, order.ID, model.OrderStatusPending)
+		assert.Nil(t, err)
+		assert.False(t, restored)
+	})
+}
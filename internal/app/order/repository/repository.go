@@ -3,40 +3,82 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
 
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, dto dto.CreateOrderDto, transactionNumber string) (*model.Transaction, error)
 	GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error)
+	CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (bool, error)
+	// RestoreCancelledOrder puts a cancelled order back in status, for a cancellation that could not be completed
+	RestoreCancelledOrder(ctx context.Context, id int, status string) (bool, error)
+	CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*model.Refund, error)
+	DeleteRefund(ctx context.Context, id int) error
+	UpdateOrderStatus(ctx context.Context, id int, from []string, to string) (bool, error)
+	CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*model.Shipment, error)
+	UpdateShipment(ctx context.Context, orderId int, shipmentId int, from string, payload dto.UpdateShipmentDto) (bool, error)
+	ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(row dto.ExportOrder) error) error
+	// GetUnpaidOrders returns the ids of the oldest orders still waiting for their payment olderThan after they were
+	// created, by the clock of the database
+	GetUnpaidOrders(ctx context.Context, olderThan time.Duration, limit int) ([]int, error)
 }
 
+// ErrPromotionExhausted is returned when a coupon reached its usage limit while the order was being created
+var ErrPromotionExhausted = errors.New("Coupon has reached its usage limit")
+
+// ErrPromotionCustomerLimit is returned when the customer used up a coupon while the order was being created
+var ErrPromotionCustomerLimit = errors.New("Coupon has reached its usage limit for this customer")
+
+// ErrRefundExceedsPaid is returned when a refund would refund more than what was paid for the order
+var ErrRefundExceedsPaid = errors.New("Refund exceeds the remaining paid amount")
+
+// ErrRefundExceedsOrder is returned when a refund would refund more than what is left of an order line
+var ErrRefundExceedsOrder = errors.New("Refund exceeds the remaining qty of the order")
+
+// ErrShipmentExceedsOrder is returned when a shipment would ship more than what is left of an order line
+var ErrShipmentExceedsOrder = errors.New("Shipment exceeds the remaining qty of the order")
+
+// ErrOutOfStock is returned when a variant ran out of stock while the order was being created
+var ErrOutOfStock = errors.New("Variant is out of stock")
+
 type Repository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Dialect dialect.Dialect
+	Audit   auditRepository.AuditRepository
+	Outbox  eventRepository.OutboxRepository
 }
 
-func NewOrder(db *sql.DB) *Repository {
-	return &Repository{db}
+func NewOrder(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect, auditRepository.NewAudit(db, dialect), eventRepository.NewOutbox(db, dialect)}
 }
 
 func (r *Repository) CreateOrder(ctx context.Context, payload dto.CreateOrderDto, transactionNumber string) (*model.Transaction, error) {
 
 	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
-
-	//PROCESS ORDER
-	query := `INSERT into transaction (transactionNumber, deliveryAddress, totalQty, totalTransaction, createdAt) values(?, ?, ?, ?, NOW())`
-	result, err := tx.ExecContext(ctx, query, transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.TotalTransaction)
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
-	var id int64
-	id, err = result.LastInsertId()
+
+	//PROCESS ORDER
+	query := `INSERT into transaction (transactionNumber, deliveryAddress, totalQty, subtotal, discountTotal, taxTotal, taxExclusive,
+	shippingMethod, shippingRegion, shippingCost, totalTransaction, createdAt)
+	values(?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, tx, query, transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.Subtotal, payload.DiscountTotal,
+		payload.TaxTotal, payload.TaxExclusive, payload.ShippingMethod, payload.ShippingRegion, payload.ShippingCost, payload.TotalTransaction)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 	//END OF PROCESS ORDER
@@ -48,18 +90,134 @@ func (r *Repository) CreateOrder(ctx context.Context, payload dto.CreateOrderDto
 	)
 
 	for _, detail := range payload.Details {
-		placeholders = append(placeholders, "(?,?,?,?,?)")
-		details = append(details, id, detail.ProductId, detail.Qty, detail.Price, detail.Total)
+		placeholders = append(placeholders, "(?,?,NULLIF(?, 0),NULLIF(?, ''),?,?,?,?,?,?)")
+		details = append(details, id, detail.ProductId, detail.VariantId, detail.Sku, detail.Qty, detail.Price, detail.Total, detail.Discount, detail.Tax, detail.TaxExclusive)
 	}
 
-	query = fmt.Sprintf("INSERT INTO transaction_detail (transactionId, productId, qty, price, total) VALUES %s", strings.Join(placeholders, ","))
-	_, err = tx.ExecContext(ctx, query, details...)
+	query = fmt.Sprintf("INSERT INTO transaction_detail (transactionId, productId, variantId, sku, qty, price, total, discount, tax, taxExclusive) VALUES %s", strings.Join(placeholders, ","))
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), details...)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 	//END OF PROCESS ORDER DETAIL
 
+	//PROCESS ORDER STOCK
+	var result sql.Result
+	for _, detail := range payload.Details {
+		if detail.VariantId == 0 {
+			continue
+		}
+
+		//take the stock out, the guard keeps concurrent orders from selling more than what is left
+		query = `UPDATE product_variant SET stock = stock - ?, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND stock >= ?`
+		result, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), detail.Qty, detail.VariantId, detail.Qty)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		var affected int64
+		affected, err = result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if affected == 0 {
+			tx.Rollback()
+			return nil, ErrOutOfStock
+		}
+
+		var stock int
+		err = tx.QueryRowContext(ctx, r.Dialect.Rebind(`SELECT stock FROM product_variant WHERE id = ?`), detail.VariantId).Scan(&stock)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityVariant, EntityId: detail.VariantId,
+			Before: map[string]interface{}{"stock": stock + detail.Qty}, After: map[string]interface{}{"stock": stock}})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	//END OF PROCESS ORDER STOCK
+
+	//PROCESS ORDER DISCOUNT
+	for _, discount := range payload.Discounts {
+		//claim one usage of the coupon, the guard keeps concurrent orders from going over the limit
+		query = `UPDATE promotion SET usageCount = usageCount + 1, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND (usageLimit = 0 OR usageCount < usageLimit)`
+		result, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), discount.PromotionId)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		var affected int64
+		affected, err = result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if affected == 0 {
+			tx.Rollback()
+			return nil, ErrPromotionExhausted
+		}
+
+		//the promotion row is locked by the claim, so the usages of the customer are counted one order at a time
+		if discount.PerCustomerLimit > 0 {
+			var used int
+			used, err = r.countCustomerUsage(ctx, tx, discount.PromotionId, payload.Customer)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if used >= discount.PerCustomerLimit {
+				tx.Rollback()
+				return nil, ErrPromotionCustomerLimit
+			}
+		}
+
+		query = `INSERT INTO transaction_discount (transactionId, promotionId, code, customer, amount, createdAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+		_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), id, discount.PromotionId, discount.Code, payload.Customer, discount.Amount)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	//END OF PROCESS ORDER DISCOUNT
+
+	//PROCESS ORDER TAX
+	if len(payload.Taxes) > 0 {
+		placeholders = nil
+		var taxes []interface{}
+		for _, tax := range payload.Taxes {
+			placeholders = append(placeholders, "(?,?,?,?,?,?)")
+			taxes = append(taxes, id, tax.TaxRuleId, tax.Name, tax.Rate, tax.Inclusive, tax.Amount)
+		}
+
+		query = fmt.Sprintf("INSERT INTO transaction_tax (transactionId, taxRuleId, name, rate, inclusive, amount) VALUES %s", strings.Join(placeholders, ","))
+		_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), taxes...)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	//END OF PROCESS ORDER TAX
+
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityOrder, EntityId: int(id), After: orderFields(payload, transactionNumber)})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = r.Outbox.Add(ctx, tx, orderCreated(int(id), payload, transactionNumber))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
@@ -68,18 +226,84 @@ func (r *Repository) CreateOrder(ctx context.Context, payload dto.CreateOrderDto
 	return &model.Transaction{ID: int(id)}, nil
 }
 
+// orderFields is the order created from payload, as recorded to the audit log
+func orderFields(payload dto.CreateOrderDto, transactionNumber string) map[string]interface{} {
+	details := make([]map[string]interface{}, len(payload.Details))
+	for i, detail := range payload.Details {
+		details[i] = map[string]interface{}{"productId": detail.ProductId, "variantId": detail.VariantId, "sku": detail.Sku,
+			"qty": detail.Qty, "price": detail.Price, "total": detail.Total, "discount": detail.Discount, "tax": detail.Tax}
+	}
+
+	return map[string]interface{}{
+		"transactionNumber": transactionNumber,
+		"status":            model.OrderStatusPending,
+		"customer":          payload.Customer,
+		"deliveryAddress":   payload.DeliveryAddress,
+		"coupons":           payload.Coupons,
+		"totalQty":          payload.TotalQty,
+		"subtotal":          payload.Subtotal,
+		"discountTotal":     payload.DiscountTotal,
+		"taxTotal":          payload.TaxTotal,
+		"shippingMethod":    payload.ShippingMethod,
+		"shippingRegion":    payload.ShippingRegion,
+		"shippingCost":      payload.ShippingCost,
+		"totalTransaction":  payload.TotalTransaction,
+		"details":           details,
+	}
+}
+
+// orderCreated tells about the order created from payload, its payload is the order as recorded to the audit log
+func orderCreated(id int, payload dto.CreateOrderDto, transactionNumber string) eventDto.Event {
+	fields := orderFields(payload, transactionNumber)
+	fields["id"] = id
+	return eventDto.Event{Type: model.EventOrderCreated, EntityId: id, Payload: fields}
+}
+
+// statusChanged tells about the order going from the status of before to the one of after, when they differ
+func statusChanged(id int, before map[string]interface{}, after map[string]interface{}) (eventDto.Event, bool) {
+	if before["status"] == after["status"] {
+		return eventDto.Event{}, false
+	}
+	return eventDto.Event{Type: model.EventOrderStatusChanged, EntityId: id, Payload: map[string]interface{}{
+		"id": id, "previousStatus": before["status"], "status": after["status"], "cancelReason": after["cancelReason"]}}, true
+}
+
+// countCustomerUsage reads the usages with a locking read, so it sees the ones committed after the transaction began
+func (r *Repository) countCustomerUsage(ctx context.Context, tx *sql.Tx, promotionId int, customer string) (int, error) {
+	query := `SELECT id FROM transaction_discount WHERE promotionId = ? AND customer = ?` + r.Dialect.ForUpdate()
+	rows, err := tx.QueryContext(ctx, r.Dialect.Rebind(query), promotionId, customer)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	used := 0
+	for rows.Next() {
+		used++
+	}
+	return used, rows.Err()
+}
+
 func (r *Repository) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error) {
 
 	//PROCESS GET ORDER DATA BY ID
-	query := `SELECT id, transactionNumber, deliveryAddress, totalQty, totalTransaction FROM transaction where id = ? LIMIT 1`
-	row, err := r.DB.QueryContext(ctx, query, id)
+	query := `SELECT id, transactionNumber, deliveryAddress, totalQty, subtotal, discountTotal, taxTotal,
+	shippingMethod, shippingRegion, shippingCost, totalTransaction,
+	status, cancelReason, cancelledBy, cancelledAt
+	FROM transaction where id = ? LIMIT 1`
+	row, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), id)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var data dto.GetOrderDto
+	var (
+		data                                   dto.GetOrderDto
+		cancelReason, cancelledBy, cancelledAt sql.NullString
+		shippingMethod, shippingRegion         sql.NullString
+	)
 	if !row.Next() {
+		row.Close()
 		return nil, nil
 	}
 
@@ -88,53 +312,751 @@ func (r *Repository) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrder
 		&data.TransactionNumber,
 		&data.DeliveryAddress,
 		&data.TotalQty,
+		&data.Subtotal,
+		&data.DiscountTotal,
+		&data.TaxTotal,
+		&shippingMethod,
+		&shippingRegion,
+		&data.ShippingCost,
 		&data.TotalTransaction,
+		&data.Status,
+		&cancelReason,
+		&cancelledBy,
+		&cancelledAt,
 	)
+	row.Close()
 	if err != nil {
 		return nil, err
 	}
+	data.CancelReason = cancelReason.String
+	data.CancelledBy = cancelledBy.String
+	data.CancelledAt = cancelledAt.String
+	data.ShippingMethod = shippingMethod.String
+	data.ShippingRegion = shippingRegion.String
 	//END OF PROCESS GET ORDER DATA BY ID
 
 	//PROCESS GET ORDER DETAIL BY ORDER ID
 	queryDetail := `SELECT
 	transaction_detail.id,
 	product.title as productName,
+	transaction_detail.variantId,
+	transaction_detail.sku,
 	brand.title as brandName,
 	transaction_detail.qty,
+	(SELECT COALESCE(SUM(refund_detail.qty), 0) FROM refund_detail WHERE refund_detail.transactionDetailId = transaction_detail.id) as refundedQty,
+	(SELECT COALESCE(SUM(shipment_detail.qty), 0) FROM shipment_detail WHERE shipment_detail.transactionDetailId = transaction_detail.id) as shippedQty,
 	transaction_detail.price,
-	transaction_detail.total
+	transaction_detail.total,
+	transaction_detail.discount,
+	transaction_detail.tax,
+	transaction_detail.taxExclusive
 	FROM transaction_detail
 	JOIN product ON product.id = transaction_detail.productId
 	JOIN brand ON brand.id = product.brandId
 	WHERE transaction_detail.transactionId = ?
 	ORDER BY transaction_detail.id`
 
-	detailRows, err := r.DB.QueryContext(ctx, queryDetail, id)
+	detailRows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(queryDetail), id)
 	if err != nil {
 		return nil, err
 	}
 	for detailRows.Next() {
+		var (
+			variantId sql.NullInt64
+			sku       sql.NullString
+		)
 		transactionDetail := dto.GetOrderDetails{}
 		err = detailRows.Scan(
 			&transactionDetail.ID,
 			&transactionDetail.ProductName,
+			&variantId,
+			&sku,
 			&transactionDetail.BrandName,
 			&transactionDetail.Qty,
+			&transactionDetail.RefundedQty,
+			&transactionDetail.ShippedQty,
 			&transactionDetail.Price,
 			&transactionDetail.Total,
+			&transactionDetail.Discount,
+			&transactionDetail.Tax,
+			&transactionDetail.TaxExclusive,
 		)
+		transactionDetail.VariantId = int(variantId.Int64)
+		transactionDetail.Sku = sku.String
 
 		data.Details = append(data.Details, transactionDetail)
 	}
+	detailRows.Close()
 	if err != nil {
 		return nil, err
 	}
 	//END PROCESS GET ORDER DETAIL BY ORDER ID
 
-	defer func() {
-		row.Close()
-		detailRows.Close()
-	}()
+	data.Discounts, err = r.getDiscounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Taxes, err = r.getTaxes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Refunds, err = r.getRefunds(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, refund := range data.Refunds {
+		data.TotalRefunded += refund.Amount
+	}
+	data.NetTotal = data.TotalTransaction - data.TotalRefunded
+
+	data.Shipments, err = r.getShipments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
 	return &data, nil
 }
+
+func (r *Repository) CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (bool, error) {
+
+	//only cancel when the order is still in a cancellable status, so concurrent updates can't be overwritten
+	var (
+		placeholders []string
+		values       []interface{}
+	)
+	values = append(values, model.OrderStatusCancelled, payload.Reason, payload.CancelledBy, id)
+	for _, status := range model.CancellableOrderStatuses {
+		placeholders = append(placeholders, "?")
+		values = append(values, status)
+	}
+
+	query := fmt.Sprintf(`UPDATE transaction SET status = ?, cancelReason = ?, cancelledBy = ?, cancelledAt = CURRENT_TIMESTAMP
+	WHERE id = ? AND status IN (%s)`, strings.Join(placeholders, ","))
+	return r.updateOrder(ctx, id, query, values...)
+}
+
+func (r *Repository) RestoreCancelledOrder(ctx context.Context, id int, status string) (bool, error) {
+	query := `UPDATE transaction SET status = ?, cancelReason = NULL, cancelledBy = NULL, cancelledAt = NULL WHERE id = ? AND status = ?`
+	return r.updateOrder(ctx, id, query, status, id, model.OrderStatusCancelled)
+}
+
+// updateOrder runs the query updating the order and records the change, it tells whether the order changed
+func (r *Repository) updateOrder(ctx context.Context, id int, query string, values ...interface{}) (bool, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	before, err := r.orderState(ctx, tx, id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind(query), values...)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	after, err := r.orderState(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityOrder, EntityId: id, Before: before, After: after})
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if event, ok := statusChanged(id, before, after); ok {
+		err = r.Outbox.Add(ctx, tx, event)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// orderState reads the status of the order for the audit log, locking the order until the transaction ends
+func (r *Repository) orderState(ctx context.Context, tx *sql.Tx, id int) (map[string]interface{}, error) {
+	var (
+		status                    string
+		cancelReason, cancelledBy sql.NullString
+	)
+	query := `SELECT status, cancelReason, cancelledBy FROM transaction WHERE id = ?` + r.Dialect.ForUpdate()
+	err := tx.QueryRowContext(ctx, r.Dialect.Rebind(query), id).Scan(&status, &cancelReason, &cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"status": status, "cancelReason": nullString(cancelReason), "cancelledBy": nullString(cancelledBy)}, nil
+}
+
+// nullString is nil for NULL, for the audit log
+func nullString(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.String
+}
+
+func (r *Repository) getDiscounts(ctx context.Context, orderId int) ([]dto.GetOrderDiscount, error) {
+
+	query := `SELECT promotionId, code, customer, amount FROM transaction_discount WHERE transactionId = ? ORDER BY id`
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []dto.GetOrderDiscount
+	for rows.Next() {
+		discount := dto.GetOrderDiscount{}
+		err = rows.Scan(&discount.PromotionId, &discount.Code, &discount.Customer, &discount.Amount)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
+	}
+
+	return discounts, nil
+}
+
+func (r *Repository) getTaxes(ctx context.Context, orderId int) ([]dto.GetOrderTax, error) {
+
+	query := `SELECT name, rate, inclusive, amount FROM transaction_tax WHERE transactionId = ? ORDER BY id`
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []dto.GetOrderTax
+	for rows.Next() {
+		tax := dto.GetOrderTax{}
+		err = rows.Scan(&tax.Name, &tax.Rate, &tax.Inclusive, &tax.Amount)
+		if err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, tax)
+	}
+
+	return taxes, nil
+}
+
+func (r *Repository) getRefunds(ctx context.Context, orderId int) ([]dto.GetRefundDto, error) {
+
+	query := `SELECT id, amount, reason, createdBy, createdAt FROM refund WHERE transactionId = ? ORDER BY id`
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []dto.GetRefundDto
+	index := map[int]int{}
+	for rows.Next() {
+		refund := dto.GetRefundDto{}
+		err = rows.Scan(&refund.ID, &refund.Amount, &refund.Reason, &refund.CreatedBy, &refund.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		index[refund.ID] = len(refunds)
+		refunds = append(refunds, refund)
+	}
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	queryDetail := `SELECT refund_detail.refundId, refund_detail.transactionDetailId, refund_detail.qty, refund_detail.amount
+	FROM refund_detail
+	JOIN refund ON refund.id = refund_detail.refundId
+	WHERE refund.transactionId = ?
+	ORDER BY refund_detail.id`
+	detailRows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(queryDetail), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer detailRows.Close()
+
+	for detailRows.Next() {
+		var refundId int
+		detail := dto.GetRefundDetails{}
+		err = detailRows.Scan(&refundId, &detail.DetailId, &detail.Qty, &detail.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[refundId]; ok {
+			refunds[i].Details = append(refunds[i].Details, detail)
+		}
+	}
+
+	return refunds, nil
+}
+
+func (r *Repository) getShipments(ctx context.Context, orderId int) ([]dto.GetShipmentDto, error) {
+
+	query := `SELECT id, carrier, trackingNumber, status, createdBy, shippedAt, deliveredAt FROM shipment WHERE transactionId = ? ORDER BY id`
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []dto.GetShipmentDto
+	index := map[int]int{}
+	for rows.Next() {
+		var deliveredAt sql.NullString
+		shipment := dto.GetShipmentDto{}
+		err = rows.Scan(&shipment.ID, &shipment.Carrier, &shipment.TrackingNumber, &shipment.Status, &shipment.CreatedBy, &shipment.ShippedAt, &deliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		shipment.DeliveredAt = deliveredAt.String
+		index[shipment.ID] = len(shipments)
+		shipments = append(shipments, shipment)
+	}
+	if len(shipments) == 0 {
+		return shipments, nil
+	}
+
+	queryDetail := `SELECT shipment_detail.shipmentId, shipment_detail.transactionDetailId, shipment_detail.qty
+	FROM shipment_detail
+	JOIN shipment ON shipment.id = shipment_detail.shipmentId
+	WHERE shipment.transactionId = ?
+	ORDER BY shipment_detail.id`
+	detailRows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(queryDetail), orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer detailRows.Close()
+
+	for detailRows.Next() {
+		var shipmentId int
+		detail := dto.GetShipmentDetails{}
+		err = detailRows.Scan(&shipmentId, &detail.DetailId, &detail.Qty)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[shipmentId]; ok {
+			shipments[i].Details = append(shipments[i].Details, detail)
+		}
+	}
+
+	return shipments, nil
+}
+
+func (r *Repository) CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*model.Refund, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	//lock the order so concurrent refunds are validated one after another
+	var paid, refunded float32
+	err = tx.QueryRowContext(ctx, r.Dialect.Rebind(`SELECT totalTransaction FROM transaction WHERE id = ?`+r.Dialect.ForUpdate()), orderId).Scan(&paid)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.QueryRowContext(ctx, r.Dialect.Rebind(`SELECT COALESCE(SUM(amount), 0) FROM refund WHERE transactionId = ?`), orderId).Scan(&refunded)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if refunded+payload.TotalRefund > paid {
+		tx.Rollback()
+		return nil, ErrRefundExceedsPaid
+	}
+	var lines []int
+	refunding := map[int]int{}
+	for _, detail := range payload.Details {
+		if _, ok := refunding[detail.DetailId]; !ok {
+			lines = append(lines, detail.DetailId)
+		}
+		refunding[detail.DetailId] += detail.Qty
+	}
+	for _, detailId := range lines {
+		qty := refunding[detailId]
+		var remaining int
+		query := `SELECT transaction_detail.qty
+		- (SELECT COALESCE(SUM(refund_detail.qty), 0) FROM refund_detail WHERE refund_detail.transactionDetailId = transaction_detail.id)
+		FROM transaction_detail WHERE transaction_detail.id = ? AND transaction_detail.transactionId = ?`
+		err = tx.QueryRowContext(ctx, r.Dialect.Rebind(query), detailId, orderId).Scan(&remaining)
+		if err == sql.ErrNoRows || (err == nil && qty > remaining) {
+			tx.Rollback()
+			return nil, ErrRefundExceedsOrder
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	//PROCESS REFUND
+	query := `INSERT INTO refund (transactionId, amount, reason, createdBy, createdAt) values(?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, tx, query, orderId, payload.TotalRefund, payload.Reason, payload.CreatedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS REFUND
+
+	//PROCESS REFUND DETAIL
+	if len(payload.Details) > 0 {
+		var (
+			placeholders []string
+			details      []interface{}
+		)
+		for _, detail := range payload.Details {
+			placeholders = append(placeholders, "(?,?,?,?)")
+			details = append(details, id, detail.DetailId, detail.Qty, detail.Amount)
+		}
+
+		query = fmt.Sprintf("INSERT INTO refund_detail (refundId, transactionDetailId, qty, amount) VALUES %s", strings.Join(placeholders, ","))
+		_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), details...)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	//END OF PROCESS REFUND DETAIL
+
+	after := map[string]interface{}{"transactionId": orderId, "amount": payload.TotalRefund, "reason": payload.Reason, "createdBy": payload.CreatedBy, "details": payload.Details}
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityRefund, EntityId: int(id), After: after})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Refund{ID: int(id), TransactionId: orderId, Amount: payload.TotalRefund}, nil
+}
+
+func (r *Repository) DeleteRefund(ctx context.Context, id int) error {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	var (
+		orderId           int
+		amount            float32
+		reason, createdBy string
+	)
+	query := `SELECT transactionId, amount, reason, createdBy FROM refund WHERE id = ?` + r.Dialect.ForUpdate()
+	err = tx.QueryRowContext(ctx, r.Dialect.Rebind(query), id).Scan(&orderId, &amount, &reason, &createdBy)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(`DELETE FROM refund_detail WHERE refundId = ?`), id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(`DELETE FROM refund WHERE id = ?`), id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	before := map[string]interface{}{"transactionId": orderId, "amount": amount, "reason": reason, "createdBy": createdBy}
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionDelete, EntityType: model.AuditEntityRefund, EntityId: id, Before: before})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*model.Shipment, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	//lock the order so concurrent shipments are validated one after another
+	var locked int
+	err = tx.QueryRowContext(ctx, r.Dialect.Rebind(`SELECT id FROM transaction WHERE id = ?`+r.Dialect.ForUpdate()), orderId).Scan(&locked)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, detail := range payload.Details {
+		var remaining int
+		query := `SELECT transaction_detail.qty
+		- (SELECT COALESCE(SUM(refund_detail.qty), 0) FROM refund_detail WHERE refund_detail.transactionDetailId = transaction_detail.id)
+		- (SELECT COALESCE(SUM(shipment_detail.qty), 0) FROM shipment_detail WHERE shipment_detail.transactionDetailId = transaction_detail.id)
+		FROM transaction_detail WHERE transaction_detail.id = ? AND transaction_detail.transactionId = ?`
+		err = tx.QueryRowContext(ctx, r.Dialect.Rebind(query), detail.DetailId, orderId).Scan(&remaining)
+		if err == sql.ErrNoRows || (err == nil && detail.Qty > remaining) {
+			tx.Rollback()
+			return nil, ErrShipmentExceedsOrder
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	//PROCESS SHIPMENT
+	query := `INSERT INTO shipment (transactionId, carrier, trackingNumber, status, createdBy, shippedAt, createdAt, updatedAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, tx, query, orderId, payload.Carrier, payload.TrackingNumber, model.ShipmentStatusShipped, payload.CreatedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS SHIPMENT
+
+	//PROCESS SHIPMENT DETAIL
+	var (
+		placeholders []string
+		details      []interface{}
+	)
+	for _, detail := range payload.Details {
+		placeholders = append(placeholders, "(?,?,?)")
+		details = append(details, id, detail.DetailId, detail.Qty)
+	}
+
+	query = fmt.Sprintf("INSERT INTO shipment_detail (shipmentId, transactionDetailId, qty) VALUES %s", strings.Join(placeholders, ","))
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), details...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS SHIPMENT DETAIL
+
+	after := map[string]interface{}{"transactionId": orderId, "carrier": payload.Carrier, "trackingNumber": payload.TrackingNumber,
+		"status": model.ShipmentStatusShipped, "createdBy": payload.CreatedBy, "details": payload.Details}
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityShipment, EntityId: int(id), After: after})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Shipment{
+		ID:             int(id),
+		TransactionId:  orderId,
+		Carrier:        payload.Carrier,
+		TrackingNumber: payload.TrackingNumber,
+		Status:         model.ShipmentStatusShipped,
+		CreatedBy:      payload.CreatedBy,
+	}, nil
+}
+
+func (r *Repository) UpdateShipment(ctx context.Context, orderId int, shipmentId int, from string, payload dto.UpdateShipmentDto) (bool, error) {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	before, err := r.shipmentState(ctx, tx, orderId, shipmentId)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	//only update when the shipment is still in the status it was validated against
+	query := `UPDATE shipment SET
+	carrier = COALESCE(NULLIF(?, ''), carrier),
+	trackingNumber = COALESCE(NULLIF(?, ''), trackingNumber),
+	status = COALESCE(NULLIF(?, ''), status),
+	deliveredAt = CASE WHEN ? = 'DELIVERED' THEN CURRENT_TIMESTAMP ELSE deliveredAt END,
+	updatedAt = CURRENT_TIMESTAMP
+	WHERE id = ? AND transactionId = ? AND status = ?`
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind(query), payload.Carrier, payload.TrackingNumber, payload.Status, payload.Status, shipmentId, orderId, from)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	after, err := r.shipmentState(ctx, tx, orderId, shipmentId)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = r.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityShipment, EntityId: shipmentId, Before: before, After: after})
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// shipmentState reads the shipment for the audit log, locking it until the transaction ends
+func (r *Repository) shipmentState(ctx context.Context, tx *sql.Tx, orderId int, shipmentId int) (map[string]interface{}, error) {
+	var (
+		carrier, trackingNumber, status string
+		deliveredAt                     sql.NullString
+	)
+	query := `SELECT carrier, trackingNumber, status, deliveredAt FROM shipment WHERE id = ? AND transactionId = ?` + r.Dialect.ForUpdate()
+	err := tx.QueryRowContext(ctx, r.Dialect.Rebind(query), shipmentId, orderId).Scan(&carrier, &trackingNumber, &status, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"carrier": carrier, "trackingNumber": trackingNumber, "status": status, "deliveredAt": nullString(deliveredAt)}, nil
+}
+
+func (r *Repository) UpdateOrderStatus(ctx context.Context, id int, from []string, to string) (bool, error) {
+
+	var (
+		placeholders []string
+		values       []interface{}
+	)
+	values = append(values, to, id)
+	for _, status := range from {
+		placeholders = append(placeholders, "?")
+		values = append(values, status)
+	}
+
+	query := fmt.Sprintf(`UPDATE transaction SET status = ? WHERE id = ? AND status IN (%s)`, strings.Join(placeholders, ","))
+	return r.updateOrder(ctx, id, query, values...)
+}
+
+func (r *Repository) GetUnpaidOrders(ctx context.Context, olderThan time.Duration, limit int) (data []int, err error) {
+	var (
+		placeholders []string
+		values       []interface{}
+	)
+	for _, status := range model.PayableOrderStatuses {
+		placeholders = append(placeholders, "?")
+		values = append(values, status)
+	}
+	values = append(values, limit)
+
+	query := fmt.Sprintf(`SELECT id FROM transaction WHERE status IN (%s) AND createdAt <= %s ORDER BY id LIMIT ?`,
+		strings.Join(placeholders, ","), r.Dialect.AddSeconds("CURRENT_TIMESTAMP", -int(olderThan/time.Second)))
+
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), values...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		data = append(data, id)
+	}
+
+	return data, rows.Err()
+}
+
+// ExportOrders passes every order matching the filter to fn while reading them, without loading them all
+func (r *Repository) ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(row dto.ExportOrder) error) error {
+	var filterValues []interface{}
+	query := `SELECT id, transactionNumber, createdAt, status, deliveryAddress, totalQty, subtotal, discountTotal, taxTotal,
+	shippingMethod, shippingRegion, shippingCost, totalTransaction,
+	(SELECT COALESCE(SUM(refund.amount), 0) FROM refund WHERE refund.transactionId = transaction.id) as totalRefunded
+	FROM transaction`
+
+	if filter.Status != "" {
+		query += util.FilterHandler(filterValues) + ` status = ?`
+		filterValues = append(filterValues, filter.Status)
+	}
+
+	if filter.DateFrom != "" {
+		query += util.FilterHandler(filterValues) + ` createdAt >= ?`
+		filterValues = append(filterValues, filter.DateFrom)
+	}
+
+	if filter.DateTo != "" {
+		query += util.FilterHandler(filterValues) + ` createdAt < ` + r.Dialect.AddDays("?", 1)
+		filterValues = append(filterValues, filter.DateTo)
+	}
+
+	query += ` ORDER BY id`
+
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row                                                        dto.ExportOrder
+			createdAt, deliveryAddress, shippingMethod, shippingRegion sql.NullString
+			totalQty                                                   sql.NullInt64
+		)
+		err = rows.Scan(&row.ID, &row.TransactionNumber, &createdAt, &row.Status, &deliveryAddress, &totalQty,
+			&row.Subtotal, &row.DiscountTotal, &row.TaxTotal, &shippingMethod, &shippingRegion, &row.ShippingCost,
+			&row.TotalTransaction, &row.TotalRefunded)
+		if err != nil {
+			return err
+		}
+		row.CreatedAt = createdAt.String
+		row.DeliveryAddress = deliveryAddress.String
+		row.ShippingMethod = shippingMethod.String
+		row.ShippingRegion = shippingRegion.String
+		row.TotalQty = int(totalQty.Int64)
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -3,14 +3,18 @@ package repository_test
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
 
 func TestCreateOrder(t *testing.T) {
@@ -30,6 +34,7 @@ func TestCreateOrder(t *testing.T) {
 	payload := dto.CreateOrderDto{
 		DeliveryAddress:  "Indonesia",
 		Details:          detailOrder,
+		Subtotal:         2000000,
 		TotalTransaction: 2000000,
 		TotalQty:         1,
 	}
@@ -38,27 +43,155 @@ func TestCreateOrder(t *testing.T) {
 
 		mock.ExpectBegin()
 		query := "INSERT into transaction"
-		mock.ExpectExec(query).WithArgs(transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.TotalTransaction).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(query).WithArgs(transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.Subtotal, payload.DiscountTotal, payload.TaxTotal, payload.TaxExclusive, payload.ShippingMethod, payload.ShippingRegion, payload.ShippingCost, payload.TotalTransaction).WillReturnResult(sqlmock.NewResult(1, 1))
 
 		query = "INSERT INTO transaction_detail"
-		mock.ExpectExec(query).WithArgs(1, detailOrder[0].ProductId, detailOrder[0].Qty, detailOrder[0].Price, detailOrder[0].Total).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(query).WithArgs(1, detailOrder[0].ProductId, detailOrder[0].VariantId, detailOrder[0].Sku, detailOrder[0].Qty, detailOrder[0].Price, detailOrder[0].Total, detailOrder[0].Discount, detailOrder[0].Tax, detailOrder[0].TaxExclusive).WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityOrder, 1, nil, sqlmock.AnyArg(), "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventOrderCreated, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
-		r := repository.NewOrder(db)
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.CreateOrder(context.TODO(), payload, transactionNumber)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	discountPayload := payload
+	discountPayload.Customer = "budi"
+	discountPayload.DiscountTotal = 200000
+	discountPayload.TotalTransaction = 1800000
+	discountPayload.Discounts = []dto.CreateOrderDiscount{{PromotionId: 7, Code: "HEMAT10", Amount: 200000}}
+
+	t.Run("Test Create Order With Discount Success", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WithArgs(transactionNumber, discountPayload.DeliveryAddress, discountPayload.TotalQty, discountPayload.Subtotal, discountPayload.DiscountTotal, discountPayload.TaxTotal, discountPayload.TaxExclusive, discountPayload.ShippingMethod, discountPayload.ShippingRegion, discountPayload.ShippingCost, discountPayload.TotalTransaction).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE promotion SET usageCount").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO transaction_discount").WithArgs(1, 7, "HEMAT10", "budi", float32(200000)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), discountPayload, transactionNumber)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	taxPayload := payload
+	taxPayload.TaxTotal = 220000
+	taxPayload.TaxExclusive = 220000
+	taxPayload.TotalTransaction = 2220000
+	taxPayload.Taxes = []dto.CreateOrderTax{{TaxRuleId: 1, Name: "PPN", Rate: 11, Amount: 220000}}
+
+	t.Run("Test Create Order With Tax Success", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WithArgs(transactionNumber, taxPayload.DeliveryAddress, taxPayload.TotalQty, taxPayload.Subtotal, taxPayload.DiscountTotal, taxPayload.TaxTotal, taxPayload.TaxExclusive, taxPayload.ShippingMethod, taxPayload.ShippingRegion, taxPayload.ShippingCost, taxPayload.TotalTransaction).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_tax").WithArgs(1, 1, "PPN", float32(11), false, float32(220000)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), taxPayload, transactionNumber)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Order Coupon Used Up By Customer", func(t *testing.T) {
+		payload := discountPayload
+		payload.Discounts = []dto.CreateOrderDiscount{{PromotionId: 7, Code: "ONCE", PerCustomerLimit: 1, Amount: 10}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE promotion SET usageCount").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM transaction_discount WHERE promotionId = \? AND customer = \? FOR UPDATE`).WithArgs(7, payload.Customer).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), payload, transactionNumber)
+
+		assert.Equal(t, repository.ErrPromotionCustomerLimit, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Order Coupon Usage Limit Reached", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE promotion SET usageCount").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), discountPayload, transactionNumber)
+
+		assert.Equal(t, repository.ErrPromotionExhausted, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	variantPayload := payload
+	variantPayload.Details = []dto.CreateOrderDetails{{ProductId: 1, VariantId: 4, Sku: "TSHIRT-M", Qty: 2, Price: 100000, Total: 200000}}
+
+	t.Run("Test Create Order With Variant Success", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WithArgs(1, 1, 4, "TSHIRT-M", 2, float32(100000), float32(200000), float32(0), float32(0), float32(0)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE product_variant SET stock").WithArgs(2, 4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT stock FROM product_variant").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(8))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityVariant, 4,
+			`{"stock":10}`, `{"stock":8}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityOrder, 1, nil, sqlmock.AnyArg(), "").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventOrderCreated, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), variantPayload, transactionNumber)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Order Variant Out Of Stock", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT into transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO transaction_detail").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE product_variant SET stock").WithArgs(2, 4, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateOrder(context.TODO(), variantPayload, transactionNumber)
+
+		assert.Equal(t, repository.ErrOutOfStock, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Test Create Order Error Table Transaction", func(t *testing.T) {
 
 		mock.ExpectBegin()
 		query := "INSERT into transaction"
-		mock.ExpectExec(query).WithArgs(transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.TotalTransaction).WillReturnError(errors.New("Error Database Transaction"))
+		mock.ExpectExec(query).WithArgs(transactionNumber, payload.DeliveryAddress, payload.TotalQty, payload.Subtotal, payload.DiscountTotal, payload.TaxTotal, payload.TaxExclusive, payload.ShippingMethod, payload.ShippingRegion, payload.ShippingCost, payload.TotalTransaction).WillReturnError(errors.New("Error Database Transaction"))
 
 		mock.ExpectCommit()
-		r := repository.NewOrder(db)
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.CreateOrder(context.TODO(), payload, transactionNumber)
 
 		assert.NotNil(t, err)
@@ -84,39 +217,141 @@ func TestGetOrderDetail(t *testing.T) {
 		Total:       2000000,
 	})
 
-	query := `SELECT id, transactionNumber, deliveryAddress, totalQty, totalTransaction FROM transaction`
-	queryDetail := `SELECT
+	query := `SELECT id, transactionNumber, deliveryAddress, totalQty, subtotal, discountTotal, taxTotal,
+	shippingMethod, shippingRegion, shippingCost, totalTransaction,
+	status, cancelReason, cancelledBy, cancelledAt
+	FROM transaction`
+	queryDetail := regexp.QuoteMeta(`SELECT
 	transaction_detail.id,
 	product.title as productName,
+	transaction_detail.variantId,
+	transaction_detail.sku,
 	brand.title as brandName,
 	transaction_detail.qty,
+	(SELECT COALESCE(SUM(refund_detail.qty), 0) FROM refund_detail WHERE refund_detail.transactionDetailId = transaction_detail.id) as refundedQty,
+	(SELECT COALESCE(SUM(shipment_detail.qty), 0) FROM shipment_detail WHERE shipment_detail.transactionDetailId = transaction_detail.id) as shippedQty,
 	transaction_detail.price,
-	transaction_detail.total
+	transaction_detail.total,
+	transaction_detail.discount,
+	transaction_detail.tax,
+	transaction_detail.taxExclusive
 	FROM transaction_detail
 	JOIN product ON product.id = transaction_detail.productId
-	JOIN brand ON brand.id = product.brandId`
+	JOIN brand ON brand.id = product.brandId`)
+	queryDiscount := `SELECT promotionId, code, customer, amount FROM transaction_discount`
+	queryTax := `SELECT name, rate, inclusive, amount FROM transaction_tax`
+	queryRefund := `SELECT id, amount, reason, createdBy, createdAt FROM refund`
+	queryRefundDetail := `SELECT refund_detail.refundId, refund_detail.transactionDetailId, refund_detail.qty, refund_detail.amount`
+	queryShipment := `SELECT id, carrier, trackingNumber, status, createdBy, shippedAt, deliveredAt FROM shipment`
+	queryShipmentDetail := `SELECT shipment_detail.shipmentId, shipment_detail.transactionDetailId, shipment_detail.qty`
 
 	t.Run("Test Get Order Detail Success", func(t *testing.T) {
 		err = faker.FakeData(&mockOrder)
 		assert.NoError(t, err)
-		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "totalTransaction"}).
-			AddRow(mockOrder.ID, mockOrder.TransactionNumber, mockOrder.DeliveryAddress, mockOrder.TotalQty, mockOrder.TotalTransaction)
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"}).
+			AddRow(mockOrder.ID, mockOrder.TransactionNumber, mockOrder.DeliveryAddress, mockOrder.TotalQty, mockOrder.TotalTransaction, 0, 0, nil, nil, 0, mockOrder.TotalTransaction, "PENDING", nil, nil, nil)
 
-		detailRows := sqlmock.NewRows([]string{"id", "productName", "brandName", "qty", "price", "total"}).
-			AddRow(mockDetailOrder[0].ID, mockDetailOrder[0].ProductName, mockDetailOrder[0].BrandName, mockDetailOrder[0].Qty, mockDetailOrder[0].Price, mockDetailOrder[0].Total)
+		detailRows := sqlmock.NewRows([]string{"id", "productName", "variantId", "sku", "brandName", "qty", "refundedQty", "shippedQty", "price", "total", "discount", "tax", "taxExclusive"}).
+			AddRow(mockDetailOrder[0].ID, mockDetailOrder[0].ProductName, nil, nil, mockDetailOrder[0].BrandName, mockDetailOrder[0].Qty, 0, 0, mockDetailOrder[0].Price, mockDetailOrder[0].Total, 0, 0, 0)
+		refundRows := sqlmock.NewRows([]string{"id", "amount", "reason", "createdBy", "createdAt"})
 
 		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
 		mock.ExpectQuery(queryDetail).WithArgs(1).WillReturnRows(detailRows)
-		r := repository.NewOrder(db)
+		mock.ExpectQuery(queryDiscount).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"promotionId", "code", "customer", "amount"}))
+		mock.ExpectQuery(queryTax).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name", "rate", "inclusive", "amount"}))
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(refundRows)
+		mock.ExpectQuery(queryShipment).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "trackingNumber", "status", "createdBy", "shippedAt", "deliveredAt"}))
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.GetOrderDetails(context.TODO(), 1)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 	})
 
+	t.Run("Test Get Order Detail Success With Refunds", func(t *testing.T) {
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"}).
+			AddRow(1, "TRX-1", "Indonesia", 1, 2000000, 0, 0, nil, nil, 0, 2000000, "PAID", nil, nil, nil)
+		detailRows := sqlmock.NewRows([]string{"id", "productName", "variantId", "sku", "brandName", "qty", "refundedQty", "shippedQty", "price", "total", "discount", "tax", "taxExclusive"}).
+			AddRow(mockDetailOrder[0].ID, mockDetailOrder[0].ProductName, nil, nil, mockDetailOrder[0].BrandName, mockDetailOrder[0].Qty, 1, 0, mockDetailOrder[0].Price, mockDetailOrder[0].Total, 0, 0, 0)
+		refundRows := sqlmock.NewRows([]string{"id", "amount", "reason", "createdBy", "createdAt"}).
+			AddRow(1, 2000000, "Damaged", "customer-service", "2026-10-19 10:00:00")
+		refundDetailRows := sqlmock.NewRows([]string{"refundId", "transactionDetailId", "qty", "amount"}).
+			AddRow(1, mockDetailOrder[0].ID, 1, 2000000)
+
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
+		mock.ExpectQuery(queryDetail).WithArgs(1).WillReturnRows(detailRows)
+		mock.ExpectQuery(queryDiscount).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"promotionId", "code", "customer", "amount"}))
+		mock.ExpectQuery(queryTax).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name", "rate", "inclusive", "amount"}))
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(refundRows)
+		mock.ExpectQuery(queryRefundDetail).WithArgs(1).WillReturnRows(refundDetailRows)
+		mock.ExpectQuery(queryShipment).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "trackingNumber", "status", "createdBy", "shippedAt", "deliveredAt"}))
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.GetOrderDetails(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(2000000), result.TotalRefunded)
+		assert.Equal(t, float32(0), result.NetTotal)
+		assert.Equal(t, 1, result.Details[0].RefundedQty)
+		assert.Len(t, result.Refunds[0].Details, 1)
+	})
+
+	t.Run("Test Get Order Detail Success With Discounts", func(t *testing.T) {
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"}).
+			AddRow(1, "TRX-1", "Indonesia", 1, 2000000, 200000, 0, "REG", "JAKARTA", 0, 1800000, "PAID", nil, nil, nil)
+		detailRows := sqlmock.NewRows([]string{"id", "productName", "variantId", "sku", "brandName", "qty", "refundedQty", "shippedQty", "price", "total", "discount", "tax", "taxExclusive"}).
+			AddRow(mockDetailOrder[0].ID, mockDetailOrder[0].ProductName, nil, nil, mockDetailOrder[0].BrandName, mockDetailOrder[0].Qty, 0, 0, mockDetailOrder[0].Price, mockDetailOrder[0].Total, 200000, 0, 0)
+		discountRows := sqlmock.NewRows([]string{"promotionId", "code", "customer", "amount"}).AddRow(3, "HEMAT10", "budi@mail.com", 200000)
+
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
+		mock.ExpectQuery(queryDetail).WithArgs(1).WillReturnRows(detailRows)
+		mock.ExpectQuery(queryDiscount).WithArgs(1).WillReturnRows(discountRows)
+		mock.ExpectQuery(queryTax).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name", "rate", "inclusive", "amount"}))
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "reason", "createdBy", "createdAt"}))
+		mock.ExpectQuery(queryShipment).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "trackingNumber", "status", "createdBy", "shippedAt", "deliveredAt"}))
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.GetOrderDetails(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(2000000), result.Subtotal)
+		assert.Equal(t, float32(200000), result.DiscountTotal)
+		assert.Equal(t, "REG", result.ShippingMethod)
+		assert.Equal(t, "JAKARTA", result.ShippingRegion)
+		assert.Equal(t, float32(200000), result.Details[0].Discount)
+		assert.Equal(t, []dto.GetOrderDiscount{{PromotionId: 3, Code: "HEMAT10", Customer: "budi@mail.com", Amount: 200000}}, result.Discounts)
+		assert.Equal(t, float32(1800000), result.NetTotal)
+	})
+
+	t.Run("Test Get Order Detail Success With Shipments", func(t *testing.T) {
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"}).
+			AddRow(1, "TRX-1", "Indonesia", 1, 2000000, 0, 0, nil, nil, 0, 2000000, "DELIVERED", nil, nil, nil)
+		detailRows := sqlmock.NewRows([]string{"id", "productName", "variantId", "sku", "brandName", "qty", "refundedQty", "shippedQty", "price", "total", "discount", "tax", "taxExclusive"}).
+			AddRow(mockDetailOrder[0].ID, mockDetailOrder[0].ProductName, 4, "TSHIRT-M", mockDetailOrder[0].BrandName, mockDetailOrder[0].Qty, 0, 1, mockDetailOrder[0].Price, mockDetailOrder[0].Total, 0, 0, 0)
+		shipmentRows := sqlmock.NewRows([]string{"id", "carrier", "trackingNumber", "status", "createdBy", "shippedAt", "deliveredAt"}).
+			AddRow(3, "JNE", "JNE123", "DELIVERED", "warehouse", "2026-10-19 10:00:00", "2026-10-20 10:00:00")
+		shipmentDetailRows := sqlmock.NewRows([]string{"shipmentId", "transactionDetailId", "qty"}).
+			AddRow(3, mockDetailOrder[0].ID, 1)
+
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
+		mock.ExpectQuery(queryDetail).WithArgs(1).WillReturnRows(detailRows)
+		mock.ExpectQuery(queryDiscount).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"promotionId", "code", "customer", "amount"}))
+		mock.ExpectQuery(queryTax).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name", "rate", "inclusive", "amount"}))
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "reason", "createdBy", "createdAt"}))
+		mock.ExpectQuery(queryShipment).WithArgs(1).WillReturnRows(shipmentRows)
+		mock.ExpectQuery(queryShipmentDetail).WithArgs(1).WillReturnRows(shipmentDetailRows)
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.GetOrderDetails(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Details[0].ShippedQty)
+		assert.Equal(t, "2026-10-20 10:00:00", result.Shipments[0].DeliveredAt)
+		assert.Equal(t, 4, result.Details[0].VariantId)
+		assert.Equal(t, "TSHIRT-M", result.Details[0].Sku)
+		assert.Equal(t, []dto.GetShipmentDetails{{DetailId: mockDetailOrder[0].ID, Qty: 1}}, result.Shipments[0].Details)
+	})
+
 	t.Run("Test Get Order Detail Error Get Order", func(t *testing.T) {
 		mock.ExpectQuery(query).WithArgs(1).WillReturnError(errors.New("Database Error"))
-		r := repository.NewOrder(db)
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.GetOrderDetails(context.TODO(), 1)
 
 		assert.NotNil(t, err)
@@ -124,9 +359,9 @@ func TestGetOrderDetail(t *testing.T) {
 	})
 
 	t.Run("Test Get Order Detail not found", func(t *testing.T) {
-		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "totalTransaction"})
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"})
 		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
-		r := repository.NewOrder(db)
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.GetOrderDetails(context.TODO(), 1)
 
 		assert.Nil(t, err)
@@ -136,16 +371,443 @@ func TestGetOrderDetail(t *testing.T) {
 	t.Run("Test Get Order Detail Error Get Order Detail", func(t *testing.T) {
 		err = faker.FakeData(&mockOrder)
 		assert.NoError(t, err)
-		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "totalTransaction"}).
-			AddRow(mockOrder.ID, mockOrder.TransactionNumber, mockOrder.DeliveryAddress, mockOrder.TotalQty, mockOrder.TotalTransaction)
+		orderRow := sqlmock.NewRows([]string{"id", "transactionNumber", "deliveryAddres", "totalQty", "subtotal", "discountTotal", "taxTotal", "shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "status", "cancelReason", "cancelledBy", "cancelledAt"}).
+			AddRow(mockOrder.ID, mockOrder.TransactionNumber, mockOrder.DeliveryAddress, mockOrder.TotalQty, mockOrder.TotalTransaction, 0, 0, nil, nil, 0, mockOrder.TotalTransaction, "PENDING", nil, nil, nil)
 
 		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(orderRow)
 		mock.ExpectQuery(queryDetail).WithArgs(1).WillReturnError(errors.New("Database Error"))
 
-		r := repository.NewOrder(db)
+		r := repository.NewOrder(db, dialect.MySQL)
 		result, err := r.GetOrderDetails(context.TODO(), 1)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, result)
 	})
 }
+
+func TestCancelOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.CancelOrderDto{Reason: "Changed my mind", CancelledBy: "customer-service"}
+	query := "UPDATE transaction SET status"
+	queryState := regexp.QuoteMeta(`SELECT status, cancelReason, cancelledBy FROM transaction WHERE id = ? FOR UPDATE`)
+	columns := []string{"status", "cancelReason", "cancelledBy"}
+
+	t.Run("Test Cancel Order Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PAID", nil, nil))
+		mock.ExpectExec(query).WithArgs("CANCELLED", payload.Reason, payload.CancelledBy, 1, "PENDING", "PAID", "PAYMENT_FAILED").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("CANCELLED", payload.Reason, payload.CancelledBy))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityOrder, 1,
+			`{"cancelReason":null,"cancelledBy":null,"status":"PAID"}`,
+			`{"cancelReason":"Changed my mind","cancelledBy":"customer-service","status":"CANCELLED"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventOrderStatusChanged, 1,
+			`{"cancelReason":"Changed my mind","id":1,"previousStatus":"PAID","status":"CANCELLED"}`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.True(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Cancel Order Status Not Cancellable", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("SHIPPED", nil, nil))
+		mock.ExpectExec(query).WithArgs("CANCELLED", payload.Reason, payload.CancelledBy, 1, "PENDING", "PAID", "PAYMENT_FAILED").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.False(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Cancel Order Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.False(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Cancel Order Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PAID", nil, nil))
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CancelOrder(context.TODO(), 1, payload)
+
+		assert.NotNil(t, err)
+		assert.False(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateRefund(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.CreateRefundDto{
+		Reason:      "Damaged",
+		Amount:      5000,
+		Details:     []dto.CreateRefundDetails{{DetailId: 1, Qty: 1, Amount: 2000000}},
+		TotalRefund: 2005000,
+		CreatedBy:   "customer-service",
+	}
+	queryLock := `SELECT totalTransaction FROM transaction WHERE id = \? FOR UPDATE`
+	queryRefunded := `SELECT COALESCE`
+	queryRemaining := `SELECT transaction_detail.qty`
+
+	t.Run("Test Create Refund Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"totalTransaction"}).AddRow(4000000))
+		mock.ExpectQuery(queryRefunded).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow(0))
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO refund ").WithArgs(1, payload.TotalRefund, payload.Reason, payload.CreatedBy).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO refund_detail").WithArgs(1, 1, 1, payload.Details[0].Amount).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityRefund, 1, nil,
+			`{"amount":2005000,"createdBy":"customer-service","details":[{"Amount":2000000,"detailId":1,"qty":1}],"reason":"Damaged","transactionId":1}`, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Refund Exceeds Paid Amount", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"totalTransaction"}).AddRow(4000000))
+		mock.ExpectQuery(queryRefunded).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow(2000000))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateRefund(context.TODO(), 1, payload)
+
+		assert.ErrorIs(t, err, repository.ErrRefundExceedsPaid)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test Create Refund Exceeds Remaining Qty", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"totalTransaction"}).AddRow(4000000))
+		mock.ExpectQuery(queryRefunded).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow(0))
+		//refunded by a concurrent refund after the service checked the line
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateRefund(context.TODO(), 1, payload)
+
+		assert.ErrorIs(t, err, repository.ErrRefundExceedsOrder)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Refund Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"totalTransaction"}).AddRow(4000000))
+		mock.ExpectQuery(queryRefunded).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow(0))
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO refund ").WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateRefund(context.TODO(), 1, payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE transaction SET status"
+	queryState := `SELECT status, cancelReason, cancelledBy FROM transaction`
+	columns := []string{"status", "cancelReason", "cancelledBy"}
+
+	t.Run("Test Update Order Status Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PENDING", nil, nil))
+		mock.ExpectExec(query).WithArgs("PAID", 1, "PENDING", "PAYMENT_FAILED").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PAID", nil, nil))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityOrder, 1,
+			`{"status":"PENDING"}`, `{"status":"PAID"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventOrderStatusChanged, 1,
+			`{"cancelReason":null,"id":1,"previousStatus":"PENDING","status":"PAID"}`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.UpdateOrderStatus(context.TODO(), 1, []string{"PENDING", "PAYMENT_FAILED"}, "PAID")
+
+		assert.Nil(t, err)
+		assert.True(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Order Status Error Writing Event", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PENDING", nil, nil))
+		mock.ExpectExec(query).WithArgs("PAID", 1, "PENDING").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PAID", nil, nil))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.UpdateOrderStatus(context.TODO(), 1, []string{"PENDING"}, "PAID")
+
+		assert.NotNil(t, err)
+		assert.False(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Order Status Error Recording Audit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PENDING", nil, nil))
+		mock.ExpectExec(query).WithArgs("PAID", 1, "PENDING").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PAID", nil, nil))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.UpdateOrderStatus(context.TODO(), 1, []string{"PENDING"}, "PAID")
+
+		assert.NotNil(t, err)
+		assert.False(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Order Status Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow("PENDING", nil, nil))
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.UpdateOrderStatus(context.TODO(), 1, []string{"PENDING"}, "PAID")
+
+		assert.NotNil(t, err)
+		assert.False(t, result)
+	})
+}
+
+func TestDeleteRefund(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	queryRefund := regexp.QuoteMeta(`SELECT transactionId, amount, reason, createdBy FROM refund WHERE id = ? FOR UPDATE`)
+	columns := []string{"transactionId", "amount", "reason", "createdBy"}
+
+	t.Run("Test Delete Refund Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 5000, "Damaged", "customer-service"))
+		mock.ExpectExec("DELETE FROM refund_detail").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM refund WHERE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionDelete, model.AuditEntityRefund, 1,
+			`{"amount":5000,"createdBy":"customer-service","reason":"Damaged","transactionId":2}`, nil, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		err := r.DeleteRefund(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Delete Refund Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryRefund).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 5000, "Damaged", "customer-service"))
+		mock.ExpectExec("DELETE FROM refund_detail").WithArgs(1).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		err := r.DeleteRefund(context.TODO(), 1)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateShipment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.CreateShipmentDto{
+		Carrier:        "JNE",
+		TrackingNumber: "JNE123",
+		CreatedBy:      "warehouse",
+		Details:        []dto.CreateShipmentDetails{{DetailId: 1, Qty: 1}},
+	}
+	queryLock := regexp.QuoteMeta(`SELECT id FROM transaction WHERE id = ? FOR UPDATE`)
+	queryRemaining := `SELECT transaction_detail.qty`
+
+	t.Run("Test Create Shipment Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO shipment").WithArgs(1, "JNE", "JNE123", "SHIPPED", "warehouse").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec("INSERT INTO shipment_detail").WithArgs(3, 1, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityShipment, 3, nil,
+			`{"carrier":"JNE","createdBy":"warehouse","details":[{"detailId":1,"qty":1}],"status":"SHIPPED","trackingNumber":"JNE123","transactionId":1}`, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.ID)
+		assert.Equal(t, "SHIPPED", result.Status)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Shipment Exceeds Order", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateShipment(context.TODO(), 1, payload)
+
+		assert.ErrorIs(t, err, repository.ErrShipmentExceedsOrder)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test Create Shipment Error Insert", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(queryRemaining).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"remaining"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO shipment").WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		result, err := r.CreateShipment(context.TODO(), 1, payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUpdateShipment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.UpdateShipmentDto{Status: "DELIVERED"}
+	queryState := regexp.QuoteMeta(`SELECT carrier, trackingNumber, status, deliveredAt FROM shipment WHERE id = ? AND transactionId = ? FOR UPDATE`)
+	columns := []string{"carrier", "trackingNumber", "status", "deliveredAt"}
+
+	t.Run("Test Update Shipment Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow("JNE", "JNE123", "IN_TRANSIT", nil))
+		mock.ExpectExec("UPDATE shipment SET").WithArgs("", "", "DELIVERED", "DELIVERED", 3, 1, "IN_TRANSIT").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(queryState).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow("JNE", "JNE123", "DELIVERED", "2026-10-19 10:00:00"))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityShipment, 3,
+			`{"deliveredAt":null,"status":"IN_TRANSIT"}`, `{"deliveredAt":"2026-10-19 10:00:00","status":"DELIVERED"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		updated, err := r.UpdateShipment(context.TODO(), 1, 3, "IN_TRANSIT", payload)
+
+		assert.Nil(t, err)
+		assert.True(t, updated)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Shipment Status Changed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(queryState).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow("JNE", "JNE123", "DELIVERED", "2026-10-19 10:00:00"))
+		mock.ExpectExec("UPDATE shipment SET").WithArgs("", "", "DELIVERED", "DELIVERED", 3, 1, "IN_TRANSIT").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewOrder(db, dialect.MySQL)
+		updated, err := r.UpdateShipment(context.TODO(), 1, 3, "IN_TRANSIT", payload)
+
+		assert.Nil(t, err)
+		assert.False(t, updated)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExportOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	columns := []string{"id", "transactionNumber", "createdAt", "status", "deliveryAddress", "totalQty", "subtotal", "discountTotal", "taxTotal",
+		"shippingMethod", "shippingRegion", "shippingCost", "totalTransaction", "totalRefunded"}
+	query := regexp.QuoteMeta(`FROM transaction WHERE status = ? AND createdAt >= ? AND createdAt < DATE_ADD(?, INTERVAL 1 DAY) ORDER BY id`)
+	filter := dto.FilterOrderDto{Status: "PAID", DateFrom: "2026-10-01", DateTo: "2026-10-31"}
+
+	t.Run("Test Export Orders Streams Rows", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "TRX-1", "2026-10-02 10:00:00", "PAID", "Jakarta", 2, 200000, 0, 0, nil, nil, 0, 200000, 50000).
+			AddRow(2, "TRX-2", nil, "PAID", nil, nil, 100000, 10000, 0, "REG", "JAKARTA", 9000, 99000, 0)
+		mock.ExpectQuery(query).WithArgs("PAID", "2026-10-01", "2026-10-31").WillReturnRows(rows)
+
+		var exported []dto.ExportOrder
+		r := repository.NewOrder(db, dialect.MySQL)
+		err := r.ExportOrders(context.TODO(), filter, func(row dto.ExportOrder) error {
+			exported = append(exported, row)
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.Len(t, exported, 2)
+		assert.Equal(t, float32(50000), exported[0].TotalRefunded)
+		assert.Equal(t, "REG", exported[1].ShippingMethod)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Export Orders Stops On Write Error", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "TRX-1", nil, "PAID", nil, 2, 200000, 0, 0, nil, nil, 0, 200000, 0).
+			AddRow(2, "TRX-2", nil, "PAID", nil, 1, 100000, 0, 0, nil, nil, 0, 100000, 0)
+		mock.ExpectQuery(query).WillReturnRows(rows)
+
+		calls := 0
+		r := repository.NewOrder(db, dialect.MySQL)
+		err := r.ExportOrders(context.TODO(), filter, func(row dto.ExportOrder) error {
+			calls++
+			return errors.New("client gone")
+		})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,205 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestOrderSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		seed := []string{
+			`INSERT INTO brand (title, createdAt, updatedAt) VALUES ('Nike', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO product (title, description, brandId, price, createdAt, updatedAt) VALUES ('Pegasus', 'Road', 1, 100, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO product_variant (productId, sku, price, stock, createdAt, updatedAt) VALUES (1, 'PEG-42', NULL, 3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO promotion (code, title, type, value, startAt, usageLimit, createdAt, updatedAt)
+			VALUES ('TEN', 'Ten off', 'FIXED', 10, CURRENT_TIMESTAMP, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO promotion (code, title, type, value, startAt, usageLimit, perCustomerLimit, createdAt, updatedAt)
+			VALUES ('ONCE', 'Once per customer', 'FIXED', 10, CURRENT_TIMESTAMP, 0, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO tax_rule (name, rate, inclusive, createdAt, updatedAt) VALUES ('VAT', 11, false, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		}
+		for _, query := range seed {
+			_, err := db.Exec(d.Rebind(query))
+			assert.Nil(t, err)
+		}
+
+		r := repository.NewOrder(db, d)
+		ctx := context.TODO()
+
+		payload := dto.CreateOrderDto{
+			DeliveryAddress: "Jl. Merdeka 1",
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, VariantId: 1, Sku: "PEG-42", Price: 100, Qty: 2, Total: 200, Discount: 10, Tax: 20.9, TaxExclusive: 20.9}},
+			Customer:        "budi",
+			Subtotal:        200,
+			DiscountTotal:   10,
+			Discounts:       []dto.CreateOrderDiscount{{PromotionId: 1, Code: "TEN", Amount: 10}},
+			TaxTotal:        20.9,
+			TaxExclusive:    20.9,
+			Taxes:           []dto.CreateOrderTax{{TaxRuleId: 1, Name: "VAT", Rate: 11, Amount: 20.9}},
+			TotalQty:        2,
+		}
+		payload.TotalTransaction = 210.9
+
+		order, err := r.CreateOrder(ctx, payload, "TRX-1")
+		assert.Nil(t, err)
+
+		t.Run("Test Create Order Guards", func(t *testing.T) {
+			_, err := r.CreateOrder(ctx, payload, "TRX-2")
+			assert.Equal(t, repository.ErrOutOfStock, err)
+
+			payload.Details[0].Qty = 1
+			_, err = r.CreateOrder(ctx, payload, "TRX-2")
+			assert.Equal(t, repository.ErrPromotionExhausted, err)
+
+			//budi already used ONCE on another order
+			_, err = db.Exec(d.Rebind(`INSERT INTO transaction_discount (transactionId, promotionId, code, customer, amount, createdAt)
+			VALUES (99, 2, 'ONCE', 'budi', 10, CURRENT_TIMESTAMP)`))
+			assert.Nil(t, err)
+			once := payload
+			once.Discounts = []dto.CreateOrderDiscount{{PromotionId: 2, Code: "ONCE", PerCustomerLimit: 1, Amount: 10}}
+			_, err = r.CreateOrder(ctx, once, "TRX-2")
+			assert.Equal(t, repository.ErrPromotionCustomerLimit, err)
+		})
+
+		t.Run("Test Get Order Details", func(t *testing.T) {
+			result, err := r.GetOrderDetails(ctx, order.ID)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "TRX-1", result.TransactionNumber)
+			assert.Equal(t, model.OrderStatusPending, result.Status)
+			assert.Equal(t, []dto.GetOrderDetails{{ID: 1, ProductName: "Pegasus", VariantId: 1, Sku: "PEG-42", BrandName: "Nike", Qty: 2,
+				Price: 100, Total: 200, Discount: 10, Tax: 20.9, TaxExclusive: 20.9}}, result.Details)
+			assert.Equal(t, []dto.GetOrderDiscount{{PromotionId: 1, Code: "TEN", Customer: "budi", Amount: 10}}, result.Discounts)
+			assert.Equal(t, []dto.GetOrderTax{{Name: "VAT", Rate: 11, Amount: 20.9}}, result.Taxes)
+
+			missing, err := r.GetOrderDetails(ctx, order.ID+100)
+			assert.Nil(t, err)
+			assert.Nil(t, missing)
+		})
+
+		t.Run("Test Get Order Details Of Deleted Product", func(t *testing.T) {
+			_, err := db.Exec(d.Rebind(`UPDATE product SET deletedAt = CURRENT_TIMESTAMP WHERE id = ?`), 1)
+			assert.Nil(t, err)
+			defer db.Exec(d.Rebind(`UPDATE product SET deletedAt = NULL WHERE id = ?`), 1)
+
+			result, err := r.GetOrderDetails(ctx, order.ID)
+
+			assert.Nil(t, err)
+			assert.Len(t, result.Details, 1)
+			assert.Equal(t, "Pegasus", result.Details[0].ProductName)
+		})
+
+		t.Run("Test Get Unpaid Orders", func(t *testing.T) {
+			ids, err := r.GetUnpaidOrders(ctx, time.Hour, 10)
+			assert.Nil(t, err)
+			assert.Empty(t, ids)
+
+			_, err = db.Exec(d.Rebind(`UPDATE transaction SET createdAt = `+d.AddSeconds("CURRENT_TIMESTAMP", -7200)+` WHERE id = ?`), order.ID)
+			assert.Nil(t, err)
+			defer db.Exec(d.Rebind(`UPDATE transaction SET createdAt = CURRENT_TIMESTAMP WHERE id = ?`), order.ID)
+
+			ids, err = r.GetUnpaidOrders(ctx, time.Hour, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, []int{order.ID}, ids)
+		})
+
+		t.Run("Test Refund", func(t *testing.T) {
+			updated, err := r.UpdateOrderStatus(ctx, order.ID, model.PayableOrderStatuses, model.OrderStatusPaid)
+			assert.Nil(t, err)
+			assert.True(t, updated)
+
+			_, err = r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "too much", TotalRefund: 300, CreatedBy: "admin"})
+			assert.Equal(t, repository.ErrRefundExceedsPaid, err)
+			_, err = r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "too many", TotalRefund: 10, CreatedBy: "admin",
+				Details: []dto.CreateRefundDetails{{DetailId: 1, Qty: 2, Amount: 5}, {DetailId: 1, Qty: 1, Amount: 5}}})
+			assert.Equal(t, repository.ErrRefundExceedsOrder, err)
+
+			refund, err := r.CreateRefund(ctx, order.ID, dto.CreateRefundDto{Reason: "damaged", TotalRefund: 100, CreatedBy: "admin",
+				Details: []dto.CreateRefundDetails{{DetailId: 1, Qty: 1, Amount: 100}}})
+			assert.Nil(t, err)
+
+			result, err := r.GetOrderDetails(ctx, order.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, result.Details[0].RefundedQty)
+			assert.Equal(t, float32(100), result.TotalRefunded)
+			assert.Len(t, result.Refunds, 1)
+			_, err = time.Parse(time.RFC3339, result.Refunds[0].CreatedAt)
+			assert.Nil(t, err)
+
+			assert.Nil(t, r.DeleteRefund(ctx, refund.ID))
+			result, err = r.GetOrderDetails(ctx, order.ID)
+			assert.Nil(t, err)
+			assert.Empty(t, result.Refunds)
+		})
+
+		t.Run("Test Shipment", func(t *testing.T) {
+			_, err := r.CreateShipment(ctx, order.ID, dto.CreateShipmentDto{Carrier: "JNE", TrackingNumber: "1", Details: []dto.CreateShipmentDetails{{DetailId: 1, Qty: 3}}})
+			assert.Equal(t, repository.ErrShipmentExceedsOrder, err)
+
+			shipment, err := r.CreateShipment(ctx, order.ID, dto.CreateShipmentDto{Carrier: "JNE", TrackingNumber: "1", CreatedBy: "admin",
+				Details: []dto.CreateShipmentDetails{{DetailId: 1, Qty: 2}}})
+			assert.Nil(t, err)
+
+			updated, err := r.UpdateShipment(ctx, order.ID, shipment.ID, model.ShipmentStatusShipped, dto.UpdateShipmentDto{Status: model.ShipmentStatusDelivered})
+			assert.Nil(t, err)
+			assert.True(t, updated)
+
+			result, err := r.GetOrderDetails(ctx, order.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, result.Details[0].ShippedQty)
+			assert.Equal(t, []dto.GetShipmentDetails{{DetailId: 1, Qty: 2}}, result.Shipments[0].Details)
+			assert.Equal(t, model.ShipmentStatusDelivered, result.Shipments[0].Status)
+			assert.NotEmpty(t, result.Shipments[0].DeliveredAt)
+		})
+
+		t.Run("Test Cancel And Export", func(t *testing.T) {
+			cancelled, err := r.CancelOrder(ctx, order.ID, dto.CancelOrderDto{Reason: "late", CancelledBy: "admin"})
+			assert.Nil(t, err)
+			assert.True(t, cancelled)
+
+			today := time.Now().UTC().Format("2006-01-02")
+			var exported []dto.ExportOrder
+			err = r.ExportOrders(ctx, dto.FilterOrderDto{Status: model.OrderStatusCancelled, DateFrom: today, DateTo: today}, func(row dto.ExportOrder) error {
+				exported = append(exported, row)
+				return nil
+			})
+
+			assert.Nil(t, err)
+			assert.Len(t, exported, 1)
+
+			exported = nil
+			err = r.ExportOrders(ctx, dto.FilterOrderDto{DateTo: "2000-01-01"}, func(row dto.ExportOrder) error {
+				exported = append(exported, row)
+				return nil
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, exported)
+		})
+
+		t.Run("Test Restore Cancelled Order", func(t *testing.T) {
+			restored, err := r.RestoreCancelledOrder(ctx, order.ID, model.OrderStatusPaid)
+			assert.Nil(t, err)
+			assert.True(t, restored)
+
+			result, err := r.GetOrderDetails(ctx, order.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, model.OrderStatusPaid, result.Status)
+			assert.Empty(t, result.CancelReason)
+			assert.Empty(t, result.CancelledAt)
+
+			//only a cancelled order is restored
+			restored, err = r.RestoreCancelledOrder(ctx, order.ID, model.OrderStatusPending)
+			assert.Nil(t, err)
+			assert.False(t, restored)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
+	paymentService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/service"
+	productDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	productService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	promotionService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+// ReversalHook undoes a side effect of an order (restock, void payment, ...) when it gets cancelled.
+// Compensate re-applies the side effect when a later step of the cancellation fails.
+type ReversalHook interface {
+	Name() string
+	Reverse(ctx context.Context, order *dto.GetOrderDto) error
+	Compensate(ctx context.Context, order *dto.GetOrderDto) error
+}
+
+func (s *Service) RegisterReversalHook(hook ReversalHook) {
+	s.reversalHooks = append(s.reversalHooks, hook)
+}
+
+// compensate rolls back the applied hooks in reverse order
+func (s *Service) compensate(ctx context.Context, order *dto.GetOrderDto, applied []ReversalHook) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		if err := applied[i].Compensate(ctx, order); err != nil {
+			log.Printf("failed to compensate %s for order %d: %s", applied[i].Name(), order.ID, err.Error())
+		}
+	}
+}
+
+type paymentReversalHook struct {
+	paymentService paymentService.PaymentService
+}
+
+// NewPaymentReversalHook voids or refunds the payment of a cancelled order.
+// A payment reversal can't be compensated, so it should be registered after every other hook.
+func NewPaymentReversalHook(paymentService paymentService.PaymentService) ReversalHook {
+	return &paymentReversalHook{paymentService: paymentService}
+}
+
+func (h *paymentReversalHook) Name() string {
+	return "payment"
+}
+
+func (h *paymentReversalHook) Reverse(ctx context.Context, order *dto.GetOrderDto) error {
+	_, err, _ := h.paymentService.Reverse(ctx, order.ID)
+	return err
+}
+
+func (h *paymentReversalHook) Compensate(ctx context.Context, order *dto.GetOrderDto) error {
+	return errors.New("payment reversal can't be compensated")
+}
+
+type stockReversalHook struct {
+	productService productService.ProductService
+}
+
+// NewStockReversalHook puts the variants of a cancelled order back in stock
+func NewStockReversalHook(productService productService.ProductService) ReversalHook {
+	return &stockReversalHook{productService: productService}
+}
+
+func (h *stockReversalHook) Name() string {
+	return "stock"
+}
+
+func (h *stockReversalHook) Reverse(ctx context.Context, order *dto.GetOrderDto) error {
+	return h.adjust(ctx, order, 1)
+}
+
+func (h *stockReversalHook) Compensate(ctx context.Context, order *dto.GetOrderDto) error {
+	return h.adjust(ctx, order, -1)
+}
+
+func (h *stockReversalHook) adjust(ctx context.Context, order *dto.GetOrderDto, sign int) error {
+	var adjustments []productDto.StockAdjustment
+	for _, detail := range order.Details {
+		if detail.VariantId > 0 {
+			adjustments = append(adjustments, productDto.StockAdjustment{VariantId: detail.VariantId, Qty: sign * detail.Qty})
+		}
+	}
+	if len(adjustments) == 0 {
+		return nil
+	}
+	return h.productService.AdjustStock(ctx, adjustments)
+}
+
+type promotionReversalHook struct {
+	promotionService promotionService.PromotionService
+}
+
+// NewPromotionReversalHook gives the coupon usages of a cancelled order back
+func NewPromotionReversalHook(promotionService promotionService.PromotionService) ReversalHook {
+	return &promotionReversalHook{promotionService: promotionService}
+}
+
+func (h *promotionReversalHook) Name() string {
+	return "promotion"
+}
+
+func (h *promotionReversalHook) Reverse(ctx context.Context, order *dto.GetOrderDto) error {
+	for i, discount := range order.Discounts {
+		err := h.promotionService.ReleaseUsage(ctx, discount.PromotionId, order.ID)
+		if err != nil {
+			h.claim(ctx, order, order.Discounts[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *promotionReversalHook) Compensate(ctx context.Context, order *dto.GetOrderDto) error {
+	return h.claim(ctx, order, order.Discounts)
+}
+
+// claim takes the usages back, a coupon that reached its limit meanwhile stays released
+func (h *promotionReversalHook) claim(ctx context.Context, order *dto.GetOrderDto, discounts []dto.GetOrderDiscount) error {
+	var failed []error
+	for _, discount := range discounts {
+		err := h.promotionService.ClaimUsage(ctx, model.PromotionUsage{PromotionId: discount.PromotionId, TransactionId: order.ID,
+			Code: discount.Code, Customer: discount.Customer, Amount: discount.Amount})
+		if err != nil {
+			log.Printf("failed to claim coupon %s again for order %d: %s", discount.Code, order.ID, err.Error())
+			failed = append(failed, err)
+		}
+	}
+	return errors.Join(failed...)
+}
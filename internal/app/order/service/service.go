@@ -2,14 +2,23 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/helper"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
+	paymentService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/service"
+	productDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	productService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	promotionDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	promotionService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+	shippingDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	shippingService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/service"
+	taxDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	taxService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/service"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
@@ -17,19 +26,42 @@ import (
 type OrderService interface {
 	CreateOrder(ctx context.Context, payload dto.CreateOrderDto) (interface{}, error, string)
 	GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error, string)
+	CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (*dto.GetOrderDto, error, string)
+	CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*dto.GetOrderDto, error, string)
+	CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*dto.GetOrderDto, error, string)
+	UpdateShipment(ctx context.Context, orderId int, shipmentId int, payload dto.UpdateShipmentDto) (*dto.GetOrderDto, error, string)
+	PayOrder(ctx context.Context, id int) (*dto.GetOrderDto, error, string)
+	HandlePaymentStatus(ctx context.Context, orderId int, paymentStatus string) error
+	ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(row dto.ExportOrder) error) (error, string)
+	RegisterReversalHook(hook ReversalHook)
+	// ExpireUnpaidOrders cancels the orders still waiting for their payment olderThan after they were created and
+	// returns how many got cancelled
+	ExpireUnpaidOrders(ctx context.Context, olderThan time.Duration) (int, error)
 }
 
+// ExpireBatchSize is how many unpaid orders ExpireUnpaidOrders cancels at most
+const ExpireBatchSize = 100
+
 type Service struct {
-	orderRepository repository.OrderRepository
-	productService  productService.ProductService
-	contextTimeout  time.Duration
+	orderRepository  repository.OrderRepository
+	productService   productService.ProductService
+	paymentService   paymentService.PaymentService
+	promotionService promotionService.PromotionService
+	taxService       taxService.TaxService
+	shippingService  shippingService.ShippingService
+	reversalHooks    []ReversalHook
+	contextTimeout   time.Duration
 }
 
-func NewOrderService(repository repository.OrderRepository, productService productService.ProductService, timeout time.Duration) OrderService {
+func NewOrderService(repository repository.OrderRepository, productService productService.ProductService, paymentService paymentService.PaymentService, promotionService promotionService.PromotionService, taxService taxService.TaxService, shippingService shippingService.ShippingService, timeout time.Duration) OrderService {
 	return &Service{
-		orderRepository: repository,
-		productService:  productService,
-		contextTimeout:  timeout,
+		orderRepository:  repository,
+		productService:   productService,
+		paymentService:   paymentService,
+		promotionService: promotionService,
+		taxService:       taxService,
+		shippingService:  shippingService,
+		contextTimeout:   timeout,
 	}
 }
 
@@ -37,29 +69,251 @@ func (s *Service) CreateOrder(ctx context.Context, payload dto.CreateOrderDto) (
 	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
 	defer cancel()
 
+	payload.Subtotal = 0
+	payload.TotalQty = 0
+	parcel := shippingDto.ShippingParcelDto{Region: payload.ShippingRegion}
 	for i, detail := range payload.Details {
 		productResult, err, state := s.productService.GetProductById(ctx, detail.ProductId)
 		if err != nil {
 			return nil, err, state
 		}
 
-		byteData, _ := json.Marshal(productResult)
-		receivedProduct := &model.Product{}
-		json.Unmarshal(byteData, receivedProduct)
+		//the product comes at the price in effect now, a scheduled one included. Products with variants are sold
+		//per variant, at the variant price
+		price := productResult.Price
+		payload.Details[i].Sku = ""
+		if detail.VariantId > 0 || len(productResult.Variants) > 0 {
+			variant := findVariant(productResult.Variants, detail.VariantId)
+			if variant == nil {
+				return nil, fmt.Errorf("Product %d requires one of its variants", detail.ProductId), util.VALIDATION_ERROR
+			}
+			if detail.Qty > variant.Stock {
+				return nil, fmt.Errorf("Variant %s only has %d in stock", variant.Sku, variant.Stock), util.VALIDATION_ERROR
+			}
+			price = variant.Price
+			payload.Details[i].Sku = variant.Sku
+		}
 
-		payload.Details[i].Price = receivedProduct.Price
-		payload.Details[i].Total = float32(detail.Qty) * receivedProduct.Price
-		payload.TotalTransaction += payload.Details[i].Total
+		payload.Details[i].BrandId = productResult.Brand.ID
+		payload.Details[i].Price = price
+		payload.Details[i].Total = float32(detail.Qty) * price
+		payload.Subtotal += payload.Details[i].Total
 		payload.TotalQty += detail.Qty
+
+		parcel.Items = append(parcel.Items, shippingDto.ParcelItem{
+			Weight: productResult.Weight,
+			Length: productResult.Length,
+			Width:  productResult.Width,
+			Height: productResult.Height,
+			Qty:    detail.Qty,
+		})
+	}
+
+	//PROCESS APPLY COUPONS
+	promotion := promotionDto.ApplyPromotionDto{Codes: payload.Coupons, Customer: payload.Customer}
+	for _, detail := range payload.Details {
+		promotion.Lines = append(promotion.Lines, promotionDto.PromotionLine{
+			ProductId: detail.ProductId,
+			BrandId:   detail.BrandId,
+			Amount:    detail.Total,
+		})
+	}
+
+	applied, err, state := s.promotionService.Apply(ctx, promotion)
+	if err != nil {
+		return nil, err, state
+	}
+
+	payload.Discounts = nil
+	for i := range payload.Details {
+		payload.Details[i].Discount = applied.LineDiscounts[i]
+	}
+	for _, discount := range applied.Discounts {
+		payload.Discounts = append(payload.Discounts, dto.CreateOrderDiscount{
+			PromotionId:      discount.PromotionId,
+			Code:             discount.Code,
+			PerCustomerLimit: discount.PerCustomerLimit,
+			Amount:           discount.Amount,
+		})
 	}
+	payload.DiscountTotal = applied.Total
+	//END OF PROCESS APPLY COUPONS
+
+	//PROCESS CALCULATE TAX
+	taxes := taxDto.CalculateTaxDto{}
+	for _, detail := range payload.Details {
+		taxes.Lines = append(taxes.Lines, taxDto.TaxLine{
+			ProductId: detail.ProductId,
+			BrandId:   detail.BrandId,
+			Amount:    detail.Total - detail.Discount,
+		})
+	}
+
+	calculated, err, state := s.taxService.Calculate(ctx, taxes)
+	if err != nil {
+		return nil, err, state
+	}
+
+	payload.Taxes = nil
+	for i := range payload.Details {
+		payload.Details[i].Tax = calculated.Lines[i].Tax
+		payload.Details[i].TaxExclusive = calculated.Lines[i].Exclusive
+	}
+	for _, tax := range calculated.Taxes {
+		payload.Taxes = append(payload.Taxes, dto.CreateOrderTax{
+			TaxRuleId: tax.TaxRuleId,
+			Name:      tax.Name,
+			Rate:      tax.Rate,
+			Inclusive: tax.Inclusive,
+			Amount:    tax.Amount,
+		})
+	}
+	payload.TaxTotal = calculated.Total
+	payload.TaxExclusive = calculated.Exclusive
+	//END OF PROCESS CALCULATE TAX
+
+	//PROCESS CALCULATE SHIPPING
+	payload.ShippingCost = 0
+	if payload.ShippingMethod != "" {
+		quote, err, state := s.shippingService.QuoteMethod(ctx, parcel, payload.ShippingMethod)
+		if err != nil {
+			return nil, err, state
+		}
+		payload.ShippingCost = quote.Cost
+	}
+	//END OF PROCESS CALCULATE SHIPPING
+
+	payload.TotalTransaction = util.RoundMoney(payload.Subtotal - payload.DiscountTotal + payload.TaxExclusive + payload.ShippingCost)
 
 	transactionNumber := helper.GenerateTransactionNumber()
 	result, err := s.orderRepository.CreateOrder(ctx, payload, transactionNumber)
+	if errors.Is(err, repository.ErrPromotionExhausted) || errors.Is(err, repository.ErrPromotionCustomerLimit) || errors.Is(err, repository.ErrOutOfStock) {
+		return nil, err, util.VALIDATION_ERROR
+	}
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	//the order is kept when its payment can't be recorded, it waits as PENDING and can be paid again
+	payment, err, _ := s.pay(ctx, result.ID, payload.TotalTransaction)
+	if err != nil {
+		log.Printf("failed to pay order %d: %s", result.ID, err.Error())
+		return map[string]interface{}{
+			"id":                result.ID,
+			"transactionNumber": transactionNumber,
+			"status":            model.OrderStatusPending,
+			"paymentError":      err.Error(),
+		}, nil, util.SUCCESS
+	}
+
+	return map[string]interface{}{
+		"id":                result.ID,
+		"transactionNumber": transactionNumber,
+		"status":            orderStatusForPayment(payment.Status),
+		"paymentStatus":     payment.Status,
+	}, nil, util.SUCCESS
+}
+
+func findVariant(variants []productDto.VariantDto, id int) *productDto.VariantDto {
+	for i := range variants {
+		if variants[i].ID == id {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+func (s *Service) PayOrder(ctx context.Context, id int) (*dto.GetOrderDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepository.GetOrderDetails(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if order == nil {
+		return nil, errors.New("Order Not Found"), util.NOT_FOUND
+	}
+	if !model.IsOrderPayable(order.Status) {
+		return nil, fmt.Errorf("Order with status %s can't be paid", order.Status), util.INVALID_STATE
+	}
+
+	_, err, state := s.pay(ctx, id, order.TotalTransaction)
+	if err != nil {
+		return nil, err, state
+	}
+
+	return s.GetOrderDetails(ctx, id)
+}
+
+// pay charges the order and moves it to the status matching the payment outcome
+func (s *Service) pay(ctx context.Context, orderId int, amount float32) (*model.Payment, error, string) {
+	payment, err, state := s.paymentService.Pay(ctx, orderId, amount)
+	if err != nil {
+		return nil, err, state
+	}
+
+	status := orderStatusForPayment(payment.Status)
+	if status == model.OrderStatusPending {
+		return payment, nil, util.SUCCESS
+	}
+
+	_, err = s.orderRepository.UpdateOrderStatus(ctx, orderId, model.PayableOrderStatuses, status)
 	if err != nil {
 		return nil, err, util.SYSTEM_ERROR
 	}
+	return payment, nil, util.SUCCESS
+}
+
+// HandlePaymentStatus moves a pending order to the status matching a payment confirmed asynchronously.
+// A payment captured for an order cancelled in the meantime is refunded.
+func (s *Service) HandlePaymentStatus(ctx context.Context, orderId int, paymentStatus string) error {
+	status := orderStatusForPayment(paymentStatus)
+	if status == model.OrderStatusPending {
+		return nil
+	}
+
+	updated, err := s.orderRepository.UpdateOrderStatus(ctx, orderId, model.PayableOrderStatuses, status)
+	if err != nil || updated || paymentStatus != model.PaymentStatusCaptured {
+		return err
+	}
 
-	return map[string]interface{}{"id": result.ID, "transactionNumber": transactionNumber}, nil, util.SUCCESS
+	order, err := s.orderRepository.GetOrderDetails(ctx, orderId)
+	if err != nil {
+		return err
+	}
+	if order == nil || order.Status != model.OrderStatusCancelled {
+		return nil
+	}
+	_, err, _ = s.paymentService.Reverse(ctx, orderId)
+	if err != nil {
+		log.Printf("failed to refund the payment captured for cancelled order %d: %s", orderId, err.Error())
+	}
+	return err
+}
+
+func orderStatusForPayment(paymentStatus string) string {
+	switch paymentStatus {
+	case model.PaymentStatusCaptured:
+		return model.OrderStatusPaid
+	case model.PaymentStatusDeclined, model.PaymentStatusFailed:
+		return model.OrderStatusPaymentFailed
+	}
+	return model.OrderStatusPending
+}
+
+// ExportOrders streams the orders to fn. It runs without the service timeout as an export lasts as long
+// as the client keeps reading it.
+func (s *Service) ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(row dto.ExportOrder) error) (error, string) {
+	if filter.DateFrom != "" && filter.DateTo != "" && filter.DateFrom > filter.DateTo {
+		return errors.New("dateFrom must not be after dateTo"), util.VALIDATION_ERROR
+	}
+
+	err := s.orderRepository.ExportOrders(ctx, filter, fn)
+	if err != nil {
+		return err, util.SYSTEM_ERROR
+	}
+	return nil, util.SUCCESS
 }
 
 func (s *Service) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error, string) {
@@ -75,5 +329,285 @@ func (s *Service) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto
 	if result == nil {
 		return nil, errors.New("Order Not Found"), util.NOT_FOUND
 	}
+
+	payments, err, state := s.paymentService.GetPayments(ctx, id)
+	if err != nil {
+		return nil, err, state
+	}
+	result.Payments = payments
+
 	return result, nil, util.SUCCESS
 }
+
+func (s *Service) CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (*dto.GetOrderDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepository.GetOrderDetails(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if order == nil {
+		return nil, errors.New("Order Not Found"), util.NOT_FOUND
+	}
+	if !model.IsOrderCancellable(order.Status) {
+		return nil, fmt.Errorf("Order with status %s can't be cancelled", order.Status), util.INVALID_STATE
+	}
+
+	//claim the cancellation first, so a payment is never reversed for an order that moved on meanwhile
+	cancelled, err := s.orderRepository.CancelOrder(ctx, id, payload)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !cancelled {
+		return nil, errors.New("Order status has changed and can't be cancelled"), util.INVALID_STATE
+	}
+
+	//run reversal hooks, compensating the applied ones and putting the status back when a later step fails
+	var applied []ReversalHook
+	for _, hook := range s.reversalHooks {
+		err = hook.Reverse(ctx, order)
+		if err != nil {
+			s.compensate(ctx, order, applied)
+			if _, restoreErr := s.orderRepository.RestoreCancelledOrder(ctx, id, order.Status); restoreErr != nil {
+				log.Printf("failed to restore the status of order %d: %s", id, restoreErr.Error())
+			}
+			return nil, fmt.Errorf("Failed to reverse %s: %s", hook.Name(), err.Error()), util.SYSTEM_ERROR
+		}
+		applied = append(applied, hook)
+	}
+
+	return s.GetOrderDetails(ctx, id)
+}
+
+// ExpireUnpaidOrders cancels like CancelOrder, giving the stock and the coupons of the orders back. An order paid
+// in the meantime is left as it is, an order that fails to cancel doesn't stop the others.
+func (s *Service) ExpireUnpaidOrders(ctx context.Context, olderThan time.Duration) (int, error) {
+	ids, err := s.orderRepository.GetUnpaidOrders(ctx, olderThan, ExpireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var failed []error
+	payload := dto.CancelOrderDto{Reason: "Payment not received in time", CancelledBy: util.SystemActor}
+	for _, id := range ids {
+		_, err, state := s.CancelOrder(ctx, id, payload)
+		if state == util.INVALID_STATE {
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to expire order %d: %s", id, err.Error())
+			failed = append(failed, fmt.Errorf("order %d: %w", id, err))
+			continue
+		}
+		expired++
+	}
+
+	return expired, errors.Join(failed...)
+}
+
+func (s *Service) CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*dto.GetOrderDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepository.GetOrderDetails(ctx, orderId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if order == nil {
+		return nil, errors.New("Order Not Found"), util.NOT_FOUND
+	}
+	if !model.IsOrderRefundable(order.Status) {
+		return nil, fmt.Errorf("Order with status %s can't be refunded", order.Status), util.INVALID_STATE
+	}
+
+	//remaining qty that can still be refunded per order line
+	remaining := map[int]int{}
+	prices := map[int]float32{}
+	for _, detail := range order.Details {
+		remaining[detail.ID] = detail.Qty - detail.RefundedQty
+		//refund what was actually paid per unit, after the line discount and with the tax added on top
+		prices[detail.ID] = detail.Price
+		if detail.Qty > 0 {
+			prices[detail.ID] = (detail.Total - detail.Discount + detail.TaxExclusive) / float32(detail.Qty)
+		}
+	}
+
+	payload.TotalRefund = 0
+	for i, detail := range payload.Details {
+		left, ok := remaining[detail.DetailId]
+		if !ok {
+			return nil, fmt.Errorf("Order line %d doesn't exist", detail.DetailId), util.VALIDATION_ERROR
+		}
+		if detail.Qty > left {
+			return nil, fmt.Errorf("Order line %d only has %d qty left to refund", detail.DetailId, left), util.VALIDATION_ERROR
+		}
+		remaining[detail.DetailId] -= detail.Qty
+
+		payload.Details[i].Amount = util.RoundMoney(float32(detail.Qty) * prices[detail.DetailId])
+		payload.TotalRefund += payload.Details[i].Amount
+	}
+	payload.TotalRefund += payload.Amount
+
+	if payload.TotalRefund <= 0 {
+		return nil, errors.New("Refund amount must be greater than zero"), util.VALIDATION_ERROR
+	}
+	if payload.TotalRefund > order.NetTotal {
+		return nil, fmt.Errorf("Refund amount exceeds the remaining paid amount %.2f", order.NetTotal), util.VALIDATION_ERROR
+	}
+
+	refund, err := s.orderRepository.CreateRefund(ctx, orderId, payload)
+	if errors.Is(err, repository.ErrRefundExceedsPaid) || errors.Is(err, repository.ErrRefundExceedsOrder) {
+		return nil, err, util.VALIDATION_ERROR
+	}
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	//give the money back, removing the refund record again when the provider refuses
+	_, err, state := s.paymentService.Refund(ctx, orderId, payload.TotalRefund)
+	if err != nil {
+		if deleteErr := s.orderRepository.DeleteRefund(ctx, refund.ID); deleteErr != nil {
+			log.Printf("failed to remove refund %d of order %d: %s", refund.ID, orderId, deleteErr.Error())
+		}
+		return nil, err, state
+	}
+
+	return s.GetOrderDetails(ctx, orderId)
+}
+
+func (s *Service) CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*dto.GetOrderDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepository.GetOrderDetails(ctx, orderId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if order == nil {
+		return nil, errors.New("Order Not Found"), util.NOT_FOUND
+	}
+	if !model.IsOrderShippable(order.Status) {
+		return nil, fmt.Errorf("Order with status %s can't be shipped", order.Status), util.INVALID_STATE
+	}
+
+	//remaining qty that can still be shipped per order line, refunded units are never shipped
+	remaining := map[int]int{}
+	for _, detail := range order.Details {
+		remaining[detail.ID] = detail.Qty - detail.RefundedQty - detail.ShippedQty
+	}
+	for _, detail := range payload.Details {
+		left, ok := remaining[detail.DetailId]
+		if !ok {
+			return nil, fmt.Errorf("Order line %d doesn't exist", detail.DetailId), util.VALIDATION_ERROR
+		}
+		if detail.Qty > left {
+			return nil, fmt.Errorf("Order line %d only has %d qty left to ship", detail.DetailId, left), util.VALIDATION_ERROR
+		}
+		remaining[detail.DetailId] -= detail.Qty
+	}
+
+	_, err = s.orderRepository.CreateShipment(ctx, orderId, payload)
+	if errors.Is(err, repository.ErrShipmentExceedsOrder) {
+		return nil, err, util.VALIDATION_ERROR
+	}
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	return s.advanceFulfilment(ctx, orderId)
+}
+
+func (s *Service) UpdateShipment(ctx context.Context, orderId int, shipmentId int, payload dto.UpdateShipmentDto) (*dto.GetOrderDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepository.GetOrderDetails(ctx, orderId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if order == nil {
+		return nil, errors.New("Order Not Found"), util.NOT_FOUND
+	}
+
+	var shipment *dto.GetShipmentDto
+	for i := range order.Shipments {
+		if order.Shipments[i].ID == shipmentId {
+			shipment = &order.Shipments[i]
+		}
+	}
+	if shipment == nil {
+		return nil, errors.New("Shipment Not Found"), util.NOT_FOUND
+	}
+	if payload.Status != "" && !model.CanShipmentMoveTo(shipment.Status, payload.Status) {
+		return nil, fmt.Errorf("Shipment with status %s can't be moved to %s", shipment.Status, payload.Status), util.INVALID_STATE
+	}
+
+	updated, err := s.orderRepository.UpdateShipment(ctx, orderId, shipmentId, shipment.Status, payload)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !updated {
+		return nil, errors.New("Shipment status has changed and can't be updated"), util.INVALID_STATE
+	}
+
+	return s.advanceFulfilment(ctx, orderId)
+}
+
+// advanceFulfilment moves the order to the status matching what has been shipped and delivered so far
+func (s *Service) advanceFulfilment(ctx context.Context, orderId int) (*dto.GetOrderDto, error, string) {
+	order, err, state := s.GetOrderDetails(ctx, orderId)
+	if err != nil {
+		return nil, err, state
+	}
+
+	status := fulfilmentStatus(order)
+	if status == "" || status == order.Status {
+		return order, nil, util.SUCCESS
+	}
+
+	_, err = s.orderRepository.UpdateOrderStatus(ctx, orderId, model.ShippableOrderStatuses, status)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	return s.GetOrderDetails(ctx, orderId)
+}
+
+// fulfilmentStatus returns the order status for its shipments, or an empty string when nothing was shipped yet
+func fulfilmentStatus(order *dto.GetOrderDto) string {
+	delivered := map[int]int{}
+	for _, shipment := range order.Shipments {
+		if shipment.Status != model.ShipmentStatusDelivered {
+			continue
+		}
+		for _, detail := range shipment.Details {
+			delivered[detail.DetailId] += detail.Qty
+		}
+	}
+
+	allShipped, allDelivered, anyShipped := true, true, false
+	for _, detail := range order.Details {
+		toShip := detail.Qty - detail.RefundedQty
+		if detail.ShippedQty > 0 {
+			anyShipped = true
+		}
+		if detail.ShippedQty < toShip {
+			allShipped = false
+		}
+		if delivered[detail.ID] < toShip {
+			allDelivered = false
+		}
+	}
+
+	switch {
+	case !anyShipped:
+		return ""
+	case allDelivered:
+		return model.OrderStatusDelivered
+	case allShipped:
+		return model.OrderStatusShipped
+	}
+	return model.OrderStatusPartiallyShipped
+}
@@ -9,12 +9,21 @@ import (
 	"github.com/go-faker/faker/v4"
 	BrandService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
+	OrderRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
 	OrderService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/service"
 	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	ProductService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	PromotionService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+	ShippingDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	TaxService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/service"
 	mockBrandRepositores "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/brand/repository"
+	mockCategoryServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/service"
 	mockOrderRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/order/repository"
+	mockPaymentServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/payment/service"
 	mockProductRepositores "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/product/repository"
+	mockPromotionRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/promotion/repository"
+	mockShippingServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/shipping/service"
+	mockTaxRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/tax/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,14 +32,20 @@ import (
 const contextTimeout = 2 * time.Second
 
 var (
-	mockProduct           []ProductDto.GetProduct
-	mockGetOrder          *dto.GetOrderDto
-	mockOrderRepository   = new(mockOrderRepositories.OrderRepository)
-	mockProductRepository = new(mockProductRepositores.ProductRepository)
-	mockBrandRepository   = new(mockBrandRepositores.BrandRepository)
-	brandService          = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
-	productService        = ProductService.NewProductService(mockProductRepository, brandService, contextTimeout)
-	orderService          = OrderService.NewOrderService(mockOrderRepository, productService, contextTimeout)
+	mockProduct             []ProductDto.GetProduct
+	mockGetOrder            *dto.GetOrderDto
+	mockOrderRepository     = new(mockOrderRepositories.OrderRepository)
+	mockProductRepository   = new(mockProductRepositores.ProductRepository)
+	mockBrandRepository     = new(mockBrandRepositores.BrandRepository)
+	mockPaymentService      = new(mockPaymentServices.PaymentService)
+	mockPromotionRepository = new(mockPromotionRepositories.PromotionRepository)
+	mockTaxRepository       = new(mockTaxRepositories.TaxRepository)
+	mockShippingService     = new(mockShippingServices.ShippingService)
+	brandService            = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
+	productService          = ProductService.NewProductService(mockProductRepository, brandService, new(mockCategoryServices.CategoryService), contextTimeout)
+	promotionService        = PromotionService.NewPromotionService(mockPromotionRepository, contextTimeout)
+	taxService              = TaxService.NewTaxService(mockTaxRepository, contextTimeout)
+	orderService            = OrderService.NewOrderService(mockOrderRepository, productService, mockPaymentService, promotionService, taxService, mockShippingService, contextTimeout)
 )
 
 func reset() {
@@ -39,10 +54,16 @@ func reset() {
 	mockOrderRepository = new(mockOrderRepositories.OrderRepository)
 	mockProductRepository = new(mockProductRepositores.ProductRepository)
 	mockBrandRepository = new(mockBrandRepositores.BrandRepository)
+	mockPaymentService = new(mockPaymentServices.PaymentService)
+	mockPromotionRepository = new(mockPromotionRepositories.PromotionRepository)
+	mockTaxRepository = new(mockTaxRepositories.TaxRepository)
+	mockShippingService = new(mockShippingServices.ShippingService)
 
 	brandService = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
-	productService = ProductService.NewProductService(mockProductRepository, brandService, contextTimeout)
-	orderService = OrderService.NewOrderService(mockOrderRepository, productService, contextTimeout)
+	productService = ProductService.NewProductService(mockProductRepository, brandService, new(mockCategoryServices.CategoryService), contextTimeout)
+	promotionService = PromotionService.NewPromotionService(mockPromotionRepository, contextTimeout)
+	taxService = TaxService.NewTaxService(mockTaxRepository, contextTimeout)
+	orderService = OrderService.NewOrderService(mockOrderRepository, productService, mockPaymentService, promotionService, taxService, mockShippingService, contextTimeout)
 
 }
 
@@ -68,13 +89,279 @@ func TestCreateOrder(t *testing.T) {
 		}
 
 		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
 		mockOrderRepository.On("CreateOrder", mock.Anything, payload, mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, mock.Anything).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, "PAID", res.(map[string]interface{})["status"])
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Create Order Payment Declined", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 25000000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(25000000)).Return(&model.Payment{ID: 1, Status: "DECLINED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAYMENT_FAILED").Return(true, nil)
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, "PAYMENT_FAILED", res.(map[string]interface{})["status"])
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Create Order Payment Error Keeps The Order", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 25000000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(25000000)).Return(nil, errors.New("Database Error"), "SYSTEM_ERROR")
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, res.(map[string]interface{})["id"])
+		assert.Equal(t, "PENDING", res.(map[string]interface{})["status"])
+		assert.Equal(t, "Database Error", res.(map[string]interface{})["paymentError"])
+		mockOrderRepository.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Order With Coupon", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Brand: ProductDto.BrandDto{ID: 1}, Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 2}},
+			DeliveryAddress: "Indonesia",
+			Coupons:         []string{"HEMAT10"},
+			Customer:        "budi",
+		}
+		promotion := model.Promotion{ID: 7, Code: "HEMAT10", Type: "PERCENTAGE", Value: 10, StartAt: "2020-01-01 00:00:00"}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{promotion}, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order dto.CreateOrderDto) bool {
+			return order.Subtotal == 200000 && order.DiscountTotal == 20000 && order.TotalTransaction == 180000 &&
+				order.Details[0].Discount == 20000 && len(order.Discounts) == 1 && order.Discounts[0].PromotionId == 7
+		}), mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(180000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
 
 		res, err, state := orderService.CreateOrder(context.TODO(), payload)
 
 		assert.Equal(t, "SUCCESS", state)
 		assert.NotNil(t, res)
 		assert.Nil(t, err)
+		mockOrderRepository.AssertExpectations(t)
+		mockPaymentService.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order With Tax", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Brand: ProductDto.BrandDto{ID: 1}, Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 2}},
+			DeliveryAddress: "Indonesia",
+		}
+		rules := []model.TaxRule{
+			{ID: 1, Name: "PPN", Rate: 11},
+			{ID: 2, Name: "PPN", Rate: 5, BrandId: 2},
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(rules, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order dto.CreateOrderDto) bool {
+			return order.Subtotal == 200000 && order.TaxTotal == 22000 && order.TaxExclusive == 22000 && order.TotalTransaction == 222000 &&
+				order.Details[0].Tax == 22000 && len(order.Taxes) == 1 && order.Taxes[0].TaxRuleId == 1
+		}), mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(222000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		mockOrderRepository.AssertExpectations(t)
+		mockPaymentService.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order With Shipping", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000, Weight: 800, Length: 30, Width: 20, Height: 12})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 2}},
+			DeliveryAddress: "Jl. Sudirman, Jakarta",
+			ShippingMethod:  "REG",
+			ShippingRegion:  "JAKARTA",
+		}
+		parcel := ShippingDto.ShippingParcelDto{
+			Region: "JAKARTA",
+			Items:  []ShippingDto.ParcelItem{{Weight: 800, Length: 30, Width: 20, Height: 12, Qty: 2}},
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockShippingService.On("QuoteMethod", mock.Anything, parcel, "REG").Return(&ShippingDto.GetShippingQuote{Method: "REG", Cost: 18000}, nil, "SUCCESS")
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order dto.CreateOrderDto) bool {
+			return order.ShippingCost == 18000 && order.TotalTransaction == 218000
+		}), mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(218000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		mockShippingService.AssertExpectations(t)
+		mockPaymentService.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order Shipping Method Not Available", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Jayapura",
+			ShippingMethod:  "SAMEDAY",
+			ShippingRegion:  "PAPUA",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockShippingService.On("QuoteMethod", mock.Anything, mock.Anything, "SAMEDAY").Return(nil, errors.New("Shipping method SAMEDAY is not available to PAPUA"), "VALIDATION_ERROR")
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockOrderRepository.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Order Error Get Tax Rules", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Order Invalid Coupon", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+			Coupons:         []string{"UNKNOWN"},
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{}, nil)
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockOrderRepository.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Order Coupon Exhausted While Ordering", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+			Coupons:         []string{"HEMAT10"},
+		}
+		promotion := model.Promotion{ID: 7, Code: "HEMAT10", Type: "FIXED", Value: 10000, StartAt: "2020-01-01 00:00:00", UsageLimit: 1}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{promotion}, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(nil, OrderRepository.ErrPromotionExhausted)
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Equal(t, OrderRepository.ErrPromotionExhausted, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Order Coupon Used Up By Customer While Ordering", func(t *testing.T) {
+		defer reset()
+
+		mockProduct = append(mockProduct, ProductDto.GetProduct{ID: 1, Title: "Nike", Price: 100000})
+
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+			Coupons:         []string{"HEMAT10"},
+			Customer:        "budi",
+		}
+		promotion := model.Promotion{ID: 7, Code: "HEMAT10", Type: "FIXED", Value: 10000, StartAt: "2020-01-01 00:00:00", PerCustomerLimit: 1}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{promotion}, nil)
+		mockPromotionRepository.On("CountCustomerUsage", mock.Anything, 7, "budi").Return(0, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		//the limit travels with the discount so the repository can guard it
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.MatchedBy(func(payload dto.CreateOrderDto) bool {
+			return len(payload.Discounts) == 1 && payload.Discounts[0].PerCustomerLimit == 1
+		}), mock.Anything).Return(nil, OrderRepository.ErrPromotionCustomerLimit)
+
+		res, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Equal(t, OrderRepository.ErrPromotionCustomerLimit, err)
+		assert.Nil(t, res)
 	})
 
 	t.Run("Test Create Order Product Id Doesn't Exist", func(t *testing.T) {
@@ -138,6 +425,7 @@ func TestCreateOrder(t *testing.T) {
 		}
 
 		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
 		mockOrderRepository.On("CreateOrder", mock.Anything, payload, mock.Anything).Return(nil, errors.New("Database Error"))
 
 		res, err, state := orderService.CreateOrder(context.TODO(), payload)
@@ -156,6 +444,7 @@ func TestGetOrderDetails(t *testing.T) {
 		assert.NoError(t, err)
 
 		mockOrderRepository.On("GetOrderDetails", mock.Anything, mockGetOrder.ID).Return(mockGetOrder, nil)
+		mockPaymentService.On("GetPayments", mock.Anything, mockGetOrder.ID).Return(nil, nil, "SUCCESS")
 
 		res, err, state := orderService.GetOrderDetails(context.TODO(), mockGetOrder.ID)
 
@@ -184,3 +473,879 @@ func TestGetOrderDetails(t *testing.T) {
 		assert.Nil(t, res)
 	})
 }
+
+type fakeReversalHook struct {
+	name        string
+	err         error
+	reversed    int
+	compensated int
+}
+
+func (h *fakeReversalHook) Name() string {
+	return h.name
+}
+
+func (h *fakeReversalHook) Reverse(ctx context.Context, order *dto.GetOrderDto) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.reversed++
+	return nil
+}
+
+func (h *fakeReversalHook) Compensate(ctx context.Context, order *dto.GetOrderDto) error {
+	h.compensated++
+	return nil
+}
+
+func TestCancelOrder(t *testing.T) {
+	payload := dto.CancelOrderDto{Reason: "Changed my mind", CancelledBy: "customer-service"}
+
+	t.Run("Test Cancel Order Success", func(t *testing.T) {
+		defer reset()
+		hook := &fakeReversalHook{name: "restock"}
+		orderService.RegisterReversalHook(hook)
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PENDING"}, nil).Once()
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(true, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil).Once()
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "CANCELLED", res.Status)
+		assert.Equal(t, 1, hook.reversed)
+		assert.Equal(t, 0, hook.compensated)
+	})
+
+	t.Run("Test Cancel Order Reverses Payment", func(t *testing.T) {
+		defer reset()
+		orderService.RegisterReversalHook(OrderService.NewPaymentReversalHook(mockPaymentService))
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil).Once()
+		mockPaymentService.On("Reverse", mock.Anything, 1).Return(&model.Payment{ID: 1, Status: "REFUNDED"}, nil, "SUCCESS")
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(true, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil).Once()
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "CANCELLED", res.Status)
+		mockPaymentService.AssertCalled(t, "Reverse", mock.Anything, 1)
+	})
+
+	t.Run("Test Cancel Order Not Found", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(nil, nil)
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Cancel Order Status Not Cancellable", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil)
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockOrderRepository.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Cancel Order Hook Failed Compensates Applied Hooks", func(t *testing.T) {
+		defer reset()
+		restock := &fakeReversalHook{name: "restock"}
+		voidPayment := &fakeReversalHook{name: "void payment", err: errors.New("Gateway Error")}
+		orderService.RegisterReversalHook(restock)
+		orderService.RegisterReversalHook(voidPayment)
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil)
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(true, nil).Once()
+		mockOrderRepository.On("RestoreCancelledOrder", mock.Anything, 1, "PAID").Return(true, nil).Once()
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, 1, restock.compensated)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Cancel Order Status Changed Concurrently", func(t *testing.T) {
+		defer reset()
+		hook := &fakeReversalHook{name: "restock"}
+		orderService.RegisterReversalHook(hook)
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PENDING"}, nil)
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(false, nil)
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		//nothing is reversed for an order that moved on
+		assert.Equal(t, 0, hook.reversed)
+		assert.Equal(t, 0, hook.compensated)
+	})
+
+	t.Run("Test Cancel Order Error Database", func(t *testing.T) {
+		defer reset()
+		hook := &fakeReversalHook{name: "restock"}
+		orderService.RegisterReversalHook(hook)
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PENDING"}, nil)
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(false, errors.New("Database Error"))
+
+		res, err, state := orderService.CancelOrder(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, 0, hook.reversed)
+	})
+}
+
+func TestExpireUnpaidOrders(t *testing.T) {
+	payload := dto.CancelOrderDto{Reason: "Payment not received in time", CancelledBy: "system"}
+
+	t.Run("Test Expire Unpaid Orders Skips Paid Meanwhile", func(t *testing.T) {
+		defer reset()
+
+		mockOrderRepository.On("GetUnpaidOrders", mock.Anything, 24*time.Hour, 100).Return([]int{1, 2}, nil).Once()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PENDING"}, nil).Once()
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(true, nil).Once()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil).Once()
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 2).Return(&dto.GetOrderDto{ID: 2, Status: "PENDING"}, nil).Once()
+		mockOrderRepository.On("CancelOrder", mock.Anything, 2, payload).Return(false, nil).Once()
+
+		expired, err := orderService.ExpireUnpaidOrders(context.TODO(), 24*time.Hour)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, expired)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Expire Unpaid Orders Failed Order Doesn't Stop The Others", func(t *testing.T) {
+		defer reset()
+
+		mockOrderRepository.On("GetUnpaidOrders", mock.Anything, 24*time.Hour, 100).Return([]int{1, 2}, nil).Once()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PENDING"}, nil).Once()
+		mockOrderRepository.On("CancelOrder", mock.Anything, 1, payload).Return(false, errors.New("Database Error")).Once()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 2).Return(&dto.GetOrderDto{ID: 2, Status: "PENDING"}, nil).Once()
+		mockOrderRepository.On("CancelOrder", mock.Anything, 2, payload).Return(true, nil).Once()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 2).Return(&dto.GetOrderDto{ID: 2, Status: "CANCELLED"}, nil).Once()
+		mockPaymentService.On("GetPayments", mock.Anything, 2).Return(nil, nil, "SUCCESS")
+
+		expired, err := orderService.ExpireUnpaidOrders(context.TODO(), 24*time.Hour)
+
+		assert.EqualError(t, err, "order 1: Database Error")
+		assert.Equal(t, 1, expired)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Expire Unpaid Orders Error Database", func(t *testing.T) {
+		defer reset()
+
+		mockOrderRepository.On("GetUnpaidOrders", mock.Anything, time.Hour, 100).Return(nil, errors.New("Database Error")).Once()
+
+		expired, err := orderService.ExpireUnpaidOrders(context.TODO(), time.Hour)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, expired)
+	})
+}
+
+func TestCreateRefund(t *testing.T) {
+	paidOrder := func() *dto.GetOrderDto {
+		return &dto.GetOrderDto{
+			ID:               1,
+			Status:           "PAID",
+			TotalTransaction: 300000,
+			NetTotal:         300000,
+			Details: []dto.GetOrderDetails{
+				{ID: 10, Qty: 2, RefundedQty: 1, Price: 100000, Total: 200000},
+				{ID: 11, Qty: 1, Price: 100000, Total: 100000},
+			},
+		}
+	}
+
+	t.Run("Test Create Refund Success", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateRefundDto{
+			Reason:  "Damaged",
+			Amount:  5000,
+			Details: []dto.CreateRefundDetails{{DetailId: 10, Qty: 1}},
+		}
+		expected := payload
+		expected.Details = []dto.CreateRefundDetails{{DetailId: 10, Qty: 1, Amount: 100000}}
+		expected.TotalRefund = 105000
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+		mockOrderRepository.On("CreateRefund", mock.Anything, 1, expected).Return(&model.Refund{ID: 1}, nil)
+		mockPaymentService.On("Refund", mock.Anything, 1, float32(105000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+	})
+
+	t.Run("Test Create Refund Discounted Line Refunds Paid Amount", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.Details[1].Discount = 25000
+		payload := dto.CreateRefundDto{
+			Reason:  "Damaged",
+			Details: []dto.CreateRefundDetails{{DetailId: 11, Qty: 1}},
+		}
+		expected := payload
+		expected.Details = []dto.CreateRefundDetails{{DetailId: 11, Qty: 1, Amount: 75000}}
+		expected.TotalRefund = 75000
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil)
+		mockOrderRepository.On("CreateRefund", mock.Anything, 1, expected).Return(&model.Refund{ID: 1}, nil)
+		mockPaymentService.On("Refund", mock.Anything, 1, float32(75000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Refund Provider Failed Removes Refund", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateRefundDto{Reason: "Goodwill", Amount: 1000}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+		mockOrderRepository.On("CreateRefund", mock.Anything, 1, mock.Anything).Return(&model.Refund{ID: 7}, nil)
+		mockPaymentService.On("Refund", mock.Anything, 1, float32(1000)).Return(nil, errors.New("Gateway Error"), "SYSTEM_ERROR")
+		mockOrderRepository.On("DeleteRefund", mock.Anything, 7).Return(nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockOrderRepository.AssertCalled(t, "DeleteRefund", mock.Anything, 7)
+	})
+
+	t.Run("Test Create Refund Qty Exceeds Remaining", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateRefundDto{
+			Reason:  "Damaged",
+			Details: []dto.CreateRefundDetails{{DetailId: 10, Qty: 2}},
+		}
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Unknown Order Line", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateRefundDto{
+			Reason:  "Damaged",
+			Details: []dto.CreateRefundDetails{{DetailId: 99, Qty: 1}},
+		}
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Amount Exceeds Net Total", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.TotalRefunded = 250000
+		order.NetTotal = 50000
+		payload := dto.CreateRefundDto{Reason: "Goodwill", Amount: 60000}
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Empty Amount", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, dto.CreateRefundDto{Reason: "Goodwill"})
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Order Not Paid", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.Status = "PENDING"
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, dto.CreateRefundDto{Reason: "Goodwill", Amount: 1000})
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Concurrent Refund Exceeds Paid", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+		mockOrderRepository.On("CreateRefund", mock.Anything, 1, mock.Anything).Return(nil, OrderRepository.ErrRefundExceedsPaid)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, dto.CreateRefundDto{Reason: "Goodwill", Amount: 1000})
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Refund Concurrent Refund Exceeds Remaining Qty", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+		mockOrderRepository.On("CreateRefund", mock.Anything, 1, mock.Anything).Return(nil, OrderRepository.ErrRefundExceedsOrder)
+
+		res, err, state := orderService.CreateRefund(context.TODO(), 1, dto.CreateRefundDto{Reason: "Goodwill", Amount: 1000})
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Equal(t, OrderRepository.ErrRefundExceedsOrder, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestPayOrder(t *testing.T) {
+	t.Run("Test Pay Order Success", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAYMENT_FAILED", TotalTransaction: 50000}, nil).Once()
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(50000)).Return(&model.Payment{ID: 2, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID", TotalTransaction: 50000}, nil).Once()
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.PayOrder(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "PAID", res.Status)
+	})
+
+	t.Run("Test Pay Order Already Paid", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil)
+
+		res, err, state := orderService.PayOrder(context.TODO(), 1)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Pay Order Not Found", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(nil, nil)
+
+		res, err, state := orderService.PayOrder(context.TODO(), 1)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestHandlePaymentStatus(t *testing.T) {
+	t.Run("Test Handle Payment Captured", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+
+		err := orderService.HandlePaymentStatus(context.TODO(), 1, "CAPTURED")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Handle Payment Captured For Cancelled Order Refunds It", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(false, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "CANCELLED"}, nil)
+		mockPaymentService.On("Reverse", mock.Anything, 1).Return(&model.Payment{ID: 1, Status: "REFUNDED"}, nil, "SUCCESS")
+
+		err := orderService.HandlePaymentStatus(context.TODO(), 1, "CAPTURED")
+
+		assert.Nil(t, err)
+		mockPaymentService.AssertCalled(t, "Reverse", mock.Anything, 1)
+	})
+
+	t.Run("Test Handle Payment Captured For Paid Order", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(false, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(&dto.GetOrderDto{ID: 1, Status: "PAID"}, nil)
+
+		err := orderService.HandlePaymentStatus(context.TODO(), 1, "CAPTURED")
+
+		assert.Nil(t, err)
+		mockPaymentService.AssertNotCalled(t, "Reverse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Handle Payment Still Pending", func(t *testing.T) {
+		defer reset()
+
+		err := orderService.HandlePaymentStatus(context.TODO(), 1, "PENDING")
+
+		assert.Nil(t, err)
+		mockOrderRepository.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Handle Payment Error Database", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAYMENT_FAILED").Return(false, errors.New("Database Error"))
+
+		err := orderService.HandlePaymentStatus(context.TODO(), 1, "DECLINED")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCreateShipment(t *testing.T) {
+	paidOrder := func() *dto.GetOrderDto {
+		return &dto.GetOrderDto{
+			ID:     1,
+			Status: "PAID",
+			Details: []dto.GetOrderDetails{
+				{ID: 10, Qty: 2},
+				{ID: 11, Qty: 1},
+			},
+		}
+	}
+	payload := dto.CreateShipmentDto{
+		Carrier:        "JNE",
+		TrackingNumber: "JNE123",
+		Details:        []dto.CreateShipmentDetails{{DetailId: 10, Qty: 2}},
+	}
+
+	t.Run("Test Create Shipment Partially Shipped", func(t *testing.T) {
+		defer reset()
+		shipped := paidOrder()
+		shipped.Details[0].ShippedQty = 2
+		shipped.Shipments = []dto.GetShipmentDto{{ID: 3, Status: "SHIPPED", Details: []dto.GetShipmentDetails{{DetailId: 10, Qty: 2}}}}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil).Once()
+		mockOrderRepository.On("CreateShipment", mock.Anything, 1, payload).Return(&model.Shipment{ID: 3}, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shipped, nil)
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.ShippableOrderStatuses, "PARTIALLY_SHIPPED").Return(true, nil)
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Shipment Refunded Units Count As Shipped", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.Details[1].RefundedQty = 1
+		shipped := paidOrder()
+		shipped.Details[0].ShippedQty = 2
+		shipped.Details[1].RefundedQty = 1
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil).Once()
+		mockOrderRepository.On("CreateShipment", mock.Anything, 1, payload).Return(&model.Shipment{ID: 3}, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shipped, nil)
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.ShippableOrderStatuses, "SHIPPED").Return(true, nil)
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		_, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		mockOrderRepository.AssertCalled(t, "UpdateOrderStatus", mock.Anything, 1, model.ShippableOrderStatuses, "SHIPPED")
+	})
+
+	t.Run("Test Create Shipment Qty Exceeds Remaining", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.Details[0].ShippedQty = 1
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil)
+
+		res, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockOrderRepository.AssertNotCalled(t, "CreateShipment", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Shipment Unknown Line", func(t *testing.T) {
+		defer reset()
+		unknown := dto.CreateShipmentDto{Carrier: "JNE", TrackingNumber: "JNE123", Details: []dto.CreateShipmentDetails{{DetailId: 99, Qty: 1}}}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+
+		_, err, state := orderService.CreateShipment(context.TODO(), 1, unknown)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Shipment Unpaid Order", func(t *testing.T) {
+		defer reset()
+		order := paidOrder()
+		order.Status = "PENDING"
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(order, nil)
+
+		_, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Shipment Not Found", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(nil, nil)
+
+		_, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Shipment Concurrently Exceeded", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(paidOrder(), nil)
+		mockOrderRepository.On("CreateShipment", mock.Anything, 1, payload).Return(nil, OrderRepository.ErrShipmentExceedsOrder)
+
+		_, err, state := orderService.CreateShipment(context.TODO(), 1, payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.ErrorIs(t, err, OrderRepository.ErrShipmentExceedsOrder)
+	})
+}
+
+func TestUpdateShipment(t *testing.T) {
+	shippedOrder := func(shipmentStatus string) *dto.GetOrderDto {
+		return &dto.GetOrderDto{
+			ID:      1,
+			Status:  "SHIPPED",
+			Details: []dto.GetOrderDetails{{ID: 10, Qty: 2, ShippedQty: 2}},
+			Shipments: []dto.GetShipmentDto{
+				{ID: 3, Status: shipmentStatus, Details: []dto.GetShipmentDetails{{DetailId: 10, Qty: 2}}},
+			},
+		}
+	}
+
+	t.Run("Test Update Shipment Delivered", func(t *testing.T) {
+		defer reset()
+		payload := dto.UpdateShipmentDto{Status: "DELIVERED"}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("IN_TRANSIT"), nil).Once()
+		mockOrderRepository.On("UpdateShipment", mock.Anything, 1, 3, "IN_TRANSIT", payload).Return(true, nil)
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("DELIVERED"), nil)
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.ShippableOrderStatuses, "DELIVERED").Return(true, nil)
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		res, err, state := orderService.UpdateShipment(context.TODO(), 1, 3, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Update Shipment Tracking Keeps Order Status", func(t *testing.T) {
+		defer reset()
+		payload := dto.UpdateShipmentDto{TrackingNumber: "JNE456"}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("SHIPPED"), nil)
+		mockOrderRepository.On("UpdateShipment", mock.Anything, 1, 3, "SHIPPED", payload).Return(true, nil)
+		mockPaymentService.On("GetPayments", mock.Anything, 1).Return(nil, nil, "SUCCESS")
+
+		_, err, state := orderService.UpdateShipment(context.TODO(), 1, 3, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		mockOrderRepository.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Update Shipment Backward Transition", func(t *testing.T) {
+		defer reset()
+		payload := dto.UpdateShipmentDto{Status: "IN_TRANSIT"}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("DELIVERED"), nil)
+
+		_, err, state := orderService.UpdateShipment(context.TODO(), 1, 3, payload)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Update Shipment Status Changed", func(t *testing.T) {
+		defer reset()
+		payload := dto.UpdateShipmentDto{Status: "DELIVERED"}
+
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("SHIPPED"), nil)
+		mockOrderRepository.On("UpdateShipment", mock.Anything, 1, 3, "SHIPPED", payload).Return(false, nil)
+
+		_, err, state := orderService.UpdateShipment(context.TODO(), 1, 3, payload)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Update Shipment Not Found", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("GetOrderDetails", mock.Anything, 1).Return(shippedOrder("SHIPPED"), nil)
+
+		_, err, state := orderService.UpdateShipment(context.TODO(), 1, 9, dto.UpdateShipmentDto{Status: "DELIVERED"})
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCreateOrderWithVariant(t *testing.T) {
+	variantProduct := func() []ProductDto.GetProduct {
+		return []ProductDto.GetProduct{{
+			ID:      1,
+			Title:   "Basic T-Shirt",
+			Brand:   ProductDto.BrandDto{ID: 1},
+			Price:   100000,
+			Options: []string{"size"},
+			Variants: []ProductDto.VariantDto{
+				{ID: 4, Sku: "TSHIRT-M", Price: 100000, Stock: 5, Options: map[string]string{"size": "M"}},
+				{ID: 5, Sku: "TSHIRT-XL", Price: 120000, Stock: 1, Options: map[string]string{"size": "XL"}},
+			},
+		}}
+	}
+
+	t.Run("Test Create Order Variant Price And Sku", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, VariantId: 5, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(variantProduct(), nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.MatchedBy(func(p dto.CreateOrderDto) bool {
+			return p.Details[0].Sku == "TSHIRT-XL" && p.Details[0].Price == 120000 && p.TotalTransaction == 120000
+		}), mock.Anything).Return(&model.Transaction{ID: 1}, nil)
+		mockPaymentService.On("Pay", mock.Anything, 1, float32(120000)).Return(&model.Payment{ID: 1, Status: "CAPTURED"}, nil, "SUCCESS")
+		mockOrderRepository.On("UpdateOrderStatus", mock.Anything, 1, model.PayableOrderStatuses, "PAID").Return(true, nil)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		mockOrderRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Create Order Variant Required", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(variantProduct(), nil)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Order Variant Not Enough Stock", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, VariantId: 5, Qty: 2}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(variantProduct(), nil)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		mockOrderRepository.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Order Variant Sold Out Concurrently", func(t *testing.T) {
+		defer reset()
+		payload := dto.CreateOrderDto{
+			Details:         []dto.CreateOrderDetails{{ProductId: 1, VariantId: 4, Qty: 1}},
+			DeliveryAddress: "Indonesia",
+		}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(variantProduct(), nil)
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+		mockOrderRepository.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(nil, OrderRepository.ErrOutOfStock)
+
+		_, err, state := orderService.CreateOrder(context.TODO(), payload)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.ErrorIs(t, err, OrderRepository.ErrOutOfStock)
+	})
+}
+
+func TestStockReversalHook(t *testing.T) {
+	order := &dto.GetOrderDto{ID: 1, Details: []dto.GetOrderDetails{
+		{ID: 10, VariantId: 4, Qty: 2},
+		{ID: 11, Qty: 1},
+	}}
+
+	t.Run("Test Stock Reversal Restocks Variants", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewStockReversalHook(productService)
+
+		mockProductRepository.On("AdjustStock", mock.Anything, []ProductDto.StockAdjustment{{VariantId: 4, Qty: 2}}).Return(nil)
+
+		err := hook.Reverse(context.TODO(), order)
+
+		assert.Nil(t, err)
+		mockProductRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Stock Reversal Compensate Takes Stock Out Again", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewStockReversalHook(productService)
+
+		mockProductRepository.On("AdjustStock", mock.Anything, []ProductDto.StockAdjustment{{VariantId: 4, Qty: -2}}).Return(nil)
+
+		err := hook.Compensate(context.TODO(), order)
+
+		assert.Nil(t, err)
+		mockProductRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Stock Reversal Without Variants", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewStockReversalHook(productService)
+
+		err := hook.Reverse(context.TODO(), &dto.GetOrderDto{ID: 2, Details: []dto.GetOrderDetails{{ID: 12, Qty: 1}}})
+
+		assert.Nil(t, err)
+		mockProductRepository.AssertNotCalled(t, "AdjustStock", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPromotionReversalHook(t *testing.T) {
+	order := &dto.GetOrderDto{ID: 1, Discounts: []dto.GetOrderDiscount{
+		{PromotionId: 3, Code: "TEN", Customer: "budi", Amount: 10},
+		{PromotionId: 4, Code: "SHIP", Customer: "budi", Amount: 5},
+	}}
+
+	t.Run("Test Promotion Reversal Releases Usages", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewPromotionReversalHook(promotionService)
+
+		mockPromotionRepository.On("ReleaseUsage", mock.Anything, 3, 1).Return(nil).Once()
+		mockPromotionRepository.On("ReleaseUsage", mock.Anything, 4, 1).Return(nil).Once()
+
+		err := hook.Reverse(context.TODO(), order)
+
+		assert.Nil(t, err)
+		mockPromotionRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Promotion Reversal Failed Claims Released Usages Again", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewPromotionReversalHook(promotionService)
+
+		mockPromotionRepository.On("ReleaseUsage", mock.Anything, 3, 1).Return(nil).Once()
+		mockPromotionRepository.On("ReleaseUsage", mock.Anything, 4, 1).Return(errors.New("Database Error")).Once()
+		mockPromotionRepository.On("ClaimUsage", mock.Anything, model.PromotionUsage{PromotionId: 3, TransactionId: 1, Code: "TEN", Customer: "budi", Amount: 10}).Return(nil).Once()
+
+		err := hook.Reverse(context.TODO(), order)
+
+		assert.NotNil(t, err)
+		mockPromotionRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Promotion Reversal Compensate Claims Usages", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewPromotionReversalHook(promotionService)
+
+		mockPromotionRepository.On("ClaimUsage", mock.Anything, mock.Anything).Return(nil).Twice()
+
+		err := hook.Compensate(context.TODO(), order)
+
+		assert.Nil(t, err)
+		mockPromotionRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Promotion Reversal Compensate Keeps Claiming After A Failure", func(t *testing.T) {
+		defer reset()
+		hook := OrderService.NewPromotionReversalHook(promotionService)
+
+		mockPromotionRepository.On("ClaimUsage", mock.Anything, mock.Anything).Return(errors.New("Database Error")).Twice()
+
+		err := hook.Compensate(context.TODO(), order)
+
+		assert.NotNil(t, err)
+		mockPromotionRepository.AssertNumberOfCalls(t, "ClaimUsage", 2)
+	})
+}
+
+func TestExportOrders(t *testing.T) {
+	t.Run("Test Export Orders Success", func(t *testing.T) {
+		defer reset()
+		filter := dto.FilterOrderDto{Status: "PAID", DateFrom: "2026-10-01", DateTo: "2026-10-31"}
+		mockOrderRepository.On("ExportOrders", mock.Anything, filter, mock.Anything).Return(nil)
+
+		err, state := orderService.ExportOrders(context.TODO(), filter, func(row dto.ExportOrder) error { return nil })
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Export Orders Invalid Range", func(t *testing.T) {
+		defer reset()
+
+		err, state := orderService.ExportOrders(context.TODO(), dto.FilterOrderDto{DateFrom: "2026-10-31", DateTo: "2026-10-01"}, func(row dto.ExportOrder) error { return nil })
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		mockOrderRepository.AssertNotCalled(t, "ExportOrders", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Export Orders Error", func(t *testing.T) {
+		defer reset()
+		mockOrderRepository.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Database Error"))
+
+		err, state := orderService.ExportOrders(context.TODO(), dto.FilterOrderDto{}, func(row dto.ExportOrder) error { return nil })
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// maxWebhookSize limits the payload a provider can post to the webhook endpoint
+const maxWebhookSize = 1 << 20
+
+type PaymentHandler struct {
+	PaymentService service.PaymentService
+}
+
+func NewPaymentHandler(mux *http.ServeMux, service service.PaymentService) {
+	handler := PaymentHandler{PaymentService: service}
+
+	mux.HandleFunc("/webhooks/payments/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.HandleWebhook(w, r)
+		}
+	})
+}
+
+func (b *PaymentHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	providerName := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhooks/payments/"), "/")
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.PaymentService.HandleWebhook(r.Context(), providerName, payload, r.Header)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
@@ -0,0 +1,55 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	paymentHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/delivery/http"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/payment/service"
+)
+
+func TestHandleWebhook(t *testing.T) {
+	mux := http.NewServeMux()
+	body := `{"id":"evt_1","reference":"fake_pi_1","status":"CAPTURED"}`
+
+	mockService := new(mocks.PaymentService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.PaymentService)
+	}
+
+	t.Run("Test Handle Webhook Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("HandleWebhook", context.Background(), "fake", []byte(body), mock.Anything).Return(map[string]interface{}{"eventId": "evt_1", "status": "PROCESSED"}, nil, "SUCCESS")
+
+		paymentHttp.NewPaymentHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payments/fake", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Handle Webhook Invalid Signature", func(t *testing.T) {
+		defer reset()
+		mockService.On("HandleWebhook", context.Background(), "fake", []byte(body), mock.Anything).Return(nil, errors.New("Invalid or expired webhook signature"), "UNAUTHORIZED")
+
+		paymentHttp.NewPaymentHandler(mux, mockService)
+		handler := paymentHttp.PaymentHandler{PaymentService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payments/fake", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		err := handler.HandleWebhook(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,48 @@
+package dto
+
+type CreatePaymentDto struct {
+	TransactionId int
+	Provider      string
+	Amount        float32
+	Status        string
+}
+
+type UpdatePaymentDto struct {
+	ProviderReference string
+	RefundedAmount    float32
+	Status            string
+	ErrorMessage      string
+}
+
+type FilterPaymentDto struct {
+	TransactionId     int    `json:"transactionId"`
+	Provider          string `json:"provider"`
+	ProviderReference string `json:"providerReference"`
+	Limit             int    `json:"limit"`
+}
+
+type GetPaymentDto struct {
+	ID                int     `json:"id"`
+	Provider          string  `json:"provider"`
+	ProviderReference string  `json:"providerReference"`
+	Amount            float32 `json:"amount"`
+	RefundedAmount    float32 `json:"refundedAmount"`
+	Status            string  `json:"status"`
+	ErrorMessage      string  `json:"errorMessage,omitempty"`
+	CreatedAt         string  `json:"createdAt"`
+}
+
+type CreateWebhookEventDto struct {
+	Provider     string
+	EventId      string
+	Payload      string
+	Status       string
+	ErrorMessage string
+}
+
+type FilterWebhookEventDto struct {
+	Provider string `json:"provider"`
+	EventId  string `json:"eventId"`
+	Status   string `json:"status"`
+	Limit    int    `json:"limit"`
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const (
+	FakeModeSucceed = "succeed"
+	FakeModeDecline = "decline"
+	FakeModeTimeout = "timeout"
+	FakeModeAsync   = "async"
+)
+
+type fakeIntent struct {
+	amount   float32
+	refunded float32
+	status   string
+}
+
+// Fake is an in-memory provider used to exercise the payment flow offline.
+// Mode decides whether new intents succeed, get declined, time out or stay pending
+// until a webhook reports the outcome.
+type Fake struct {
+	Mode string
+
+	mu      sync.Mutex
+	seq     int
+	intents map[string]*fakeIntent
+}
+
+func NewFake(mode string) *Fake {
+	if mode == "" {
+		mode = FakeModeSucceed
+	}
+	return &Fake{Mode: mode, intents: map[string]*fakeIntent{}}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) CreateIntent(ctx context.Context, request IntentRequest) (*Result, error) {
+	switch f.Mode {
+	case FakeModeDecline:
+		return nil, ErrDeclined
+	case FakeModeTimeout:
+		<-ctx.Done()
+		return nil, ErrTimeout
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := model.PaymentStatusAuthorized
+	if f.Mode == FakeModeAsync {
+		status = model.PaymentStatusPending
+	}
+
+	f.seq++
+	reference := fmt.Sprintf("fake_pi_%d", f.seq)
+	f.intents[reference] = &fakeIntent{amount: request.Amount, status: status}
+
+	return &Result{Reference: reference, Status: status}, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, reference string, amount float32) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[reference]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if intent.status != model.PaymentStatusAuthorized || amount > intent.amount {
+		return nil, ErrInvalidState
+	}
+	intent.amount = amount
+	intent.status = model.PaymentStatusCaptured
+
+	return &Result{Reference: reference, Status: intent.status}, nil
+}
+
+func (f *Fake) Void(ctx context.Context, reference string) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[reference]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	//a pending intent is cancelled, its outcome can't be captured anymore
+	if intent.status != model.PaymentStatusAuthorized && intent.status != model.PaymentStatusPending {
+		return nil, ErrInvalidState
+	}
+	intent.status = model.PaymentStatusVoided
+
+	return &Result{Reference: reference, Status: intent.status}, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, reference string, amount float32) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[reference]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	refunded := util.RoundMoney(intent.refunded + amount)
+	if intent.status != model.PaymentStatusCaptured || refunded > util.RoundMoney(intent.amount) {
+		return nil, ErrInvalidState
+	}
+	intent.refunded = refunded
+
+	status := model.PaymentStatusCaptured
+	if refunded == util.RoundMoney(intent.amount) {
+		status = model.PaymentStatusRefunded
+	}
+	return &Result{Reference: reference, Status: status}, nil
+}
+
+func (f *Fake) ParseWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	var event WebhookEvent
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	//keep the fake in sync with the outcome it reported
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if intent, ok := f.intents[event.Reference]; ok && intent.status == model.PaymentStatusPending {
+		intent.status = event.Status
+	}
+
+	return &event, nil
+}
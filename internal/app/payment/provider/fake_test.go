@@ -0,0 +1,108 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/provider"
+)
+
+func TestFakeProvider(t *testing.T) {
+	t.Run("Test Fake Succeed Flow", func(t *testing.T) {
+		fake := provider.NewFake("")
+
+		intent, err := fake.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		assert.Nil(t, err)
+		assert.Equal(t, "AUTHORIZED", intent.Status)
+
+		captured, err := fake.Capture(context.TODO(), intent.Reference, 50000)
+		assert.Nil(t, err)
+		assert.Equal(t, "CAPTURED", captured.Status)
+
+		_, err = fake.Void(context.TODO(), intent.Reference)
+		assert.ErrorIs(t, err, provider.ErrInvalidState)
+
+		refunded, err := fake.Refund(context.TODO(), intent.Reference, 50000)
+		assert.Nil(t, err)
+		assert.Equal(t, "REFUNDED", refunded.Status)
+
+		_, err = fake.Refund(context.TODO(), intent.Reference, 1)
+		assert.ErrorIs(t, err, provider.ErrInvalidState)
+	})
+
+	t.Run("Test Fake Refund In Parts", func(t *testing.T) {
+		fake := provider.NewFake("")
+
+		intent, _ := fake.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 99.99})
+		fake.Capture(context.TODO(), intent.Reference, 99.99)
+
+		for i := 0; i < 2; i++ {
+			partial, err := fake.Refund(context.TODO(), intent.Reference, 33.33)
+			assert.Nil(t, err)
+			assert.Equal(t, "CAPTURED", partial.Status)
+		}
+		refunded, err := fake.Refund(context.TODO(), intent.Reference, 33.33)
+		assert.Nil(t, err)
+		assert.Equal(t, "REFUNDED", refunded.Status)
+	})
+
+	t.Run("Test Fake Decline", func(t *testing.T) {
+		fake := provider.NewFake(provider.FakeModeDecline)
+
+		intent, err := fake.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		assert.ErrorIs(t, err, provider.ErrDeclined)
+		assert.Nil(t, intent)
+	})
+
+	t.Run("Test Fake Timeout", func(t *testing.T) {
+		fake := provider.NewFake(provider.FakeModeTimeout)
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		intent, err := fake.CreateIntent(ctx, provider.IntentRequest{OrderId: 1, Amount: 50000})
+		assert.ErrorIs(t, err, provider.ErrTimeout)
+		assert.Nil(t, intent)
+	})
+
+	t.Run("Test Fake Async Settled By Webhook", func(t *testing.T) {
+		fake := provider.NewFake(provider.FakeModeAsync)
+
+		intent, err := fake.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		assert.Nil(t, err)
+		assert.Equal(t, "PENDING", intent.Status)
+
+		event, err := fake.ParseWebhook([]byte(`{"id":"evt_1","reference":"`+intent.Reference+`","status":"CAPTURED"}`), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "evt_1", event.ID)
+
+		_, err = fake.Refund(context.TODO(), intent.Reference, 50000)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Fake Async Voided Before Webhook", func(t *testing.T) {
+		fake := provider.NewFake(provider.FakeModeAsync)
+
+		intent, err := fake.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		assert.Nil(t, err)
+
+		voided, err := fake.Void(context.TODO(), intent.Reference)
+		assert.Nil(t, err)
+		assert.Equal(t, "VOIDED", voided.Status)
+
+		//the late outcome doesn't capture the cancelled intent
+		_, err = fake.ParseWebhook([]byte(`{"id":"evt_1","reference":"`+intent.Reference+`","status":"CAPTURED"}`), nil)
+		assert.Nil(t, err)
+		_, err = fake.Refund(context.TODO(), intent.Reference, 50000)
+		assert.ErrorIs(t, err, provider.ErrInvalidState)
+	})
+
+	t.Run("Test Fake Unknown Reference", func(t *testing.T) {
+		fake := provider.NewFake(provider.FakeModeSucceed)
+
+		_, err := fake.Capture(context.TODO(), "fake_pi_404", 1)
+		assert.ErrorIs(t, err, provider.ErrNotFound)
+	})
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrDeclined     = errors.New("Payment declined by provider")
+	ErrTimeout      = errors.New("Payment provider timed out")
+	ErrInvalidState = errors.New("Payment is not in a valid state for this operation")
+	ErrNotFound     = errors.New("Payment reference not found")
+)
+
+// PaymentProvider is implemented by every payment gateway integration.
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, request IntentRequest) (*Result, error)
+	Capture(ctx context.Context, reference string, amount float32) (*Result, error)
+	Void(ctx context.Context, reference string) (*Result, error)
+	Refund(ctx context.Context, reference string, amount float32) (*Result, error)
+	ParseWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+}
+
+type IntentRequest struct {
+	OrderId        int
+	Amount         float32
+	IdempotencyKey string
+}
+
+// Result holds the provider reference and the payment status after an operation.
+// Status is one of the model.PaymentStatus constants.
+type Result struct {
+	Reference string
+	Status    string
+}
+
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Reference string    `json:"reference"`
+	Status    string    `json:"status"`
+	Amount    float32   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payload dto.CreatePaymentDto) (*model.Payment, error)
+	Update(ctx context.Context, id int, payload dto.UpdatePaymentDto) error
+	GetPayments(ctx context.Context, filter dto.FilterPaymentDto) (data []model.Payment, err error)
+	CreateWebhookEvent(ctx context.Context, payload dto.CreateWebhookEventDto) (*model.PaymentWebhookEvent, error)
+	GetWebhookEvents(ctx context.Context, filter dto.FilterWebhookEventDto) (data []model.PaymentWebhookEvent, err error)
+	// ClaimWebhookEvent records the event as processed before it is handled, it returns false when another
+	// delivery of the event claimed it already
+	ClaimWebhookEvent(ctx context.Context, payload dto.CreateWebhookEventDto) (bool, error)
+	// ReleaseWebhookEvent gives up the claim of an event that couldn't be handled, so a redelivery can claim it
+	ReleaseWebhookEvent(ctx context.Context, provider string, eventId string, errorMessage string) error
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewPayment(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) Create(ctx context.Context, payload dto.CreatePaymentDto) (*model.Payment, error) {
+	query := `INSERT INTO payment (transactionId, provider, amount, status, createdAt, updatedAt) values(?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query, payload.TransactionId, payload.Provider, payload.Amount, payload.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Payment{
+		ID:            int(id),
+		TransactionId: payload.TransactionId,
+		Provider:      payload.Provider,
+		Amount:        payload.Amount,
+		Status:        payload.Status,
+	}, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id int, payload dto.UpdatePaymentDto) error {
+	query := `UPDATE payment SET providerReference = ?, refundedAmount = ?, status = ?, errorMessage = ?, updatedAt = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), payload.ProviderReference, payload.RefundedAmount, payload.Status, payload.ErrorMessage, id)
+	return err
+}
+
+func (r *Repository) GetPayments(ctx context.Context, filter dto.FilterPaymentDto) (data []model.Payment, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, transactionId, provider, providerReference, amount, refundedAmount, status, errorMessage, createdAt, updatedAt FROM payment`
+
+	if filter.TransactionId > 0 {
+		query += util.FilterHandler(filterValues) + ` transactionId = ?`
+		filterValues = append(filterValues, filter.TransactionId)
+	}
+
+	if filter.Provider != "" {
+		query += util.FilterHandler(filterValues) + ` provider = ?`
+		filterValues = append(filterValues, filter.Provider)
+	}
+
+	if filter.ProviderReference != "" {
+		query += util.FilterHandler(filterValues) + ` providerReference = ?`
+		filterValues = append(filterValues, filter.ProviderReference)
+	}
+
+	query += ` ORDER BY id DESC`
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var reference, errorMessage sql.NullString
+		payment := model.Payment{}
+		err = rows.Scan(
+			&payment.ID,
+			&payment.TransactionId,
+			&payment.Provider,
+			&reference,
+			&payment.Amount,
+			&payment.RefundedAmount,
+			&payment.Status,
+			&errorMessage,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payment.ProviderReference = reference.String
+		payment.ErrorMessage = errorMessage.String
+
+		data = append(data, payment)
+	}
+
+	return data, nil
+}
+
+func (r *Repository) CreateWebhookEvent(ctx context.Context, payload dto.CreateWebhookEventDto) (*model.PaymentWebhookEvent, error) {
+	query := `INSERT INTO payment_webhook_event (provider, eventId, payload, status, errorMessage, createdAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query, payload.Provider, payload.EventId, payload.Payload, payload.Status, payload.ErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PaymentWebhookEvent{ID: int(id), Provider: payload.Provider, EventId: payload.EventId, Status: payload.Status}, nil
+}
+
+func (r *Repository) ClaimWebhookEvent(ctx context.Context, payload dto.CreateWebhookEventDto) (bool, error) {
+	query := `INSERT INTO payment_webhook_event (provider, eventId, processedEventId, payload, status, createdAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(r.Dialect.InsertIgnore(query)), payload.Provider, payload.EventId, payload.EventId,
+		payload.Payload, model.WebhookEventProcessed)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) ReleaseWebhookEvent(ctx context.Context, provider string, eventId string, errorMessage string) error {
+	query := `UPDATE payment_webhook_event SET status = ?, errorMessage = ?, processedEventId = NULL WHERE provider = ? AND processedEventId = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), model.WebhookEventFailed, errorMessage, provider, eventId)
+	return err
+}
+
+func (r *Repository) GetWebhookEvents(ctx context.Context, filter dto.FilterWebhookEventDto) (data []model.PaymentWebhookEvent, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, provider, eventId, payload, status, errorMessage, createdAt FROM payment_webhook_event`
+
+	if filter.Provider != "" {
+		query += util.FilterHandler(filterValues) + ` provider = ?`
+		filterValues = append(filterValues, filter.Provider)
+	}
+
+	if filter.EventId != "" {
+		query += util.FilterHandler(filterValues) + ` eventId = ?`
+		filterValues = append(filterValues, filter.EventId)
+	}
+
+	if filter.Status != "" {
+		query += util.FilterHandler(filterValues) + ` status = ?`
+		filterValues = append(filterValues, filter.Status)
+	}
+
+	query += ` ORDER BY id DESC`
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventId, errorMessage sql.NullString
+		event := model.PaymentWebhookEvent{}
+		err = rows.Scan(
+			&event.ID,
+			&event.Provider,
+			&eventId,
+			&event.Payload,
+			&event.Status,
+			&errorMessage,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.EventId = eventId.String
+		event.ErrorMessage = errorMessage.String
+
+		data = append(data, event)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,214 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/repository"
+)
+
+func TestCreatePayment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.CreatePaymentDto{TransactionId: 1, Provider: "fake", Amount: 50000, Status: "PENDING"}
+	query := "INSERT INTO payment"
+
+	t.Run("Test Create Payment Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.TransactionId, payload.Provider, payload.Amount, payload.Status).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+		assert.Equal(t, "PENDING", result.Status)
+	})
+
+	t.Run("Test Create Payment Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUpdatePayment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.UpdatePaymentDto{ProviderReference: "fake_pi_1", Status: "CAPTURED"}
+	query := "UPDATE payment SET"
+
+	t.Run("Test Update Payment Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.ProviderReference, payload.RefundedAmount, payload.Status, payload.ErrorMessage, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		err := r.Update(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Update Payment Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		err := r.Update(context.TODO(), 1, payload)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetPayments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, transactionId, provider, providerReference, amount, refundedAmount, status, errorMessage, createdAt, updatedAt FROM payment"
+	columns := []string{"id", "transactionId", "provider", "providerReference", "amount", "refundedAmount", "status", "errorMessage", "createdAt", "updatedAt"}
+
+	t.Run("Test Get Payments Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(2, 1, "fake", "fake_pi_2", 50000, 0, "CAPTURED", nil, "2026-10-19 10:00:00", "2026-10-19 10:00:00").
+			AddRow(1, 1, "fake", nil, 50000, 0, "DECLINED", "Payment declined by provider", "2026-10-19 09:00:00", "2026-10-19 09:00:00")
+		mock.ExpectQuery(query).WithArgs(1, 2).WillReturnRows(rows)
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.GetPayments(context.TODO(), dto.FilterPaymentDto{TransactionId: 1, Limit: 2})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "fake_pi_2", result[0].ProviderReference)
+		assert.Equal(t, "Payment declined by provider", result[1].ErrorMessage)
+	})
+
+	t.Run("Test Get Payments Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.GetPayments(context.TODO(), dto.FilterPaymentDto{ProviderReference: "fake_pi_2"})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestCreateWebhookEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: `{"id":"evt_1"}`, Status: "PROCESSED"}
+	query := "INSERT INTO payment_webhook_event"
+
+	t.Run("Test Create Webhook Event Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.Provider, payload.EventId, payload.Payload, payload.Status, payload.ErrorMessage).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.CreateWebhookEvent(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+	})
+
+	t.Run("Test Create Webhook Event Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.CreateWebhookEvent(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetWebhookEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, provider, eventId, payload, status, errorMessage, createdAt FROM payment_webhook_event"
+	filter := dto.FilterWebhookEventDto{Provider: "fake", EventId: "evt_1", Status: "PROCESSED", Limit: 1}
+
+	t.Run("Test Get Webhook Events Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "provider", "eventId", "payload", "status", "errorMessage", "createdAt"}).
+			AddRow(1, "fake", "evt_1", `{"id":"evt_1"}`, "PROCESSED", nil, "2026-10-19 10:00:00")
+		mock.ExpectQuery(query).WithArgs("fake", "evt_1", "PROCESSED", 1).WillReturnRows(rows)
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.GetWebhookEvents(context.TODO(), filter)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "evt_1", result[0].EventId)
+	})
+
+	t.Run("Test Get Webhook Events Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		result, err := r.GetWebhookEvents(context.TODO(), filter)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestClaimWebhookEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := `INSERT IGNORE INTO payment_webhook_event \(provider, eventId, processedEventId, payload, status, createdAt\)`
+	payload := dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: `{"id":"evt_1"}`}
+
+	t.Run("Test Claim Webhook Event Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("fake", "evt_1", "evt_1", `{"id":"evt_1"}`, "PROCESSED").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		claimed, err := r.ClaimWebhookEvent(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Claim Webhook Event Claimed Already", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		claimed, err := r.ClaimWebhookEvent(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Release Webhook Event", func(t *testing.T) {
+		mock.ExpectExec("UPDATE payment_webhook_event SET status").WithArgs("FAILED", "Database Error", "fake", "evt_1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewPayment(db, dialect.MySQL)
+		err := r.ReleaseWebhookEvent(context.TODO(), "fake", "evt_1", "Database Error")
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestPaymentSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		r := repository.NewPayment(db, d)
+		ctx := context.TODO()
+		event := dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: `{"id":"evt_1"}`}
+
+		t.Run("Test Webhook Event Is Claimed Once", func(t *testing.T) {
+			claimed, err := r.ClaimWebhookEvent(ctx, event)
+			assert.Nil(t, err)
+			assert.True(t, claimed)
+
+			claimed, err = r.ClaimWebhookEvent(ctx, event)
+			assert.Nil(t, err)
+			assert.False(t, claimed)
+
+			//the deliveries that didn't claim it are still logged
+			_, err = r.CreateWebhookEvent(ctx, dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: event.Payload, Status: model.WebhookEventDuplicate})
+			assert.Nil(t, err)
+			_, err = r.CreateWebhookEvent(ctx, dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: event.Payload, Status: model.WebhookEventDuplicate})
+			assert.Nil(t, err)
+		})
+
+		t.Run("Test Released Webhook Event Is Claimed Again", func(t *testing.T) {
+			assert.Nil(t, r.ReleaseWebhookEvent(ctx, "fake", "evt_1", "Database Error"))
+
+			failed, err := r.GetWebhookEvents(ctx, dto.FilterWebhookEventDto{Provider: "fake", EventId: "evt_1", Status: model.WebhookEventFailed})
+			assert.Nil(t, err)
+			assert.Len(t, failed, 1)
+			assert.Equal(t, "Database Error", failed[0].ErrorMessage)
+
+			claimed, err := r.ClaimWebhookEvent(ctx, event)
+			assert.Nil(t, err)
+			assert.True(t, claimed)
+
+			processed, err := r.GetWebhookEvents(ctx, dto.FilterWebhookEventDto{Provider: "fake", EventId: "evt_1", Status: model.WebhookEventProcessed})
+			assert.Nil(t, err)
+			assert.Len(t, processed, 1)
+		})
+	})
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/provider"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type PaymentService interface {
+	Pay(ctx context.Context, orderId int, amount float32) (*model.Payment, error, string)
+	Reverse(ctx context.Context, orderId int) (*model.Payment, error, string)
+	Refund(ctx context.Context, orderId int, amount float32) (*model.Payment, error, string)
+	GetPayments(ctx context.Context, orderId int) ([]dto.GetPaymentDto, error, string)
+	HandleWebhook(ctx context.Context, providerName string, payload []byte, header http.Header) (interface{}, error, string)
+	RegisterStatusHandler(handler StatusHandler)
+}
+
+// StatusHandler is notified when a webhook changes the status of a payment
+type StatusHandler func(ctx context.Context, orderId int, paymentStatus string) error
+
+// webhookTolerance is how old a signed webhook may be before it's treated as a replay
+const webhookTolerance = 5 * time.Minute
+
+type Service struct {
+	paymentRepository repository.PaymentRepository
+	provider          provider.PaymentProvider
+	webhookSecret     string
+	statusHandlers    []StatusHandler
+	contextTimeout    time.Duration
+}
+
+func NewPaymentService(r repository.PaymentRepository, p provider.PaymentProvider, webhookSecret string, timeout time.Duration) PaymentService {
+	return &Service{
+		paymentRepository: r,
+		provider:          p,
+		webhookSecret:     webhookSecret,
+		contextTimeout:    timeout,
+	}
+}
+
+func (s *Service) RegisterStatusHandler(handler StatusHandler) {
+	s.statusHandlers = append(s.statusHandlers, handler)
+}
+
+// providerContext gives the provider half of the time budget, so a provider timeout
+// still leaves time to record the outcome of the payment
+func (s *Service) providerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.contextTimeout/2)
+}
+
+func (s *Service) Pay(ctx context.Context, orderId int, amount float32) (*model.Payment, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	//record the attempt before calling the provider
+	payment, err := s.paymentRepository.Create(ctx, dto.CreatePaymentDto{
+		TransactionId: orderId,
+		Provider:      s.provider.Name(),
+		Amount:        amount,
+		Status:        model.PaymentStatusPending,
+	})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	providerCtx, providerCancel := s.providerContext(ctx)
+	defer providerCancel()
+
+	result, err := s.provider.CreateIntent(providerCtx, provider.IntentRequest{
+		OrderId:        orderId,
+		Amount:         amount,
+		IdempotencyKey: fmt.Sprintf("payment-%d", payment.ID),
+	})
+	if err == nil {
+		payment.ProviderReference = result.Reference
+		payment.Status = result.Status
+	}
+	if err == nil && result.Status == model.PaymentStatusAuthorized {
+		result, err = s.provider.Capture(providerCtx, result.Reference, amount)
+		if err == nil {
+			payment.Status = result.Status
+		}
+	}
+	if err != nil {
+		payment.Status = model.PaymentStatusFailed
+		if errors.Is(err, provider.ErrDeclined) {
+			payment.Status = model.PaymentStatusDeclined
+		}
+		payment.ErrorMessage = err.Error()
+	}
+
+	err = s.save(ctx, payment)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return payment, nil, util.SUCCESS
+}
+
+// Reverse voids the pending or authorized payment of an order or refunds what is left of a captured one
+func (s *Service) Reverse(ctx context.Context, orderId int) (*model.Payment, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	payment, err := s.latestPayment(ctx, orderId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if payment == nil {
+		return nil, nil, util.SUCCESS
+	}
+
+	providerCtx, providerCancel := s.providerContext(ctx)
+	defer providerCancel()
+
+	var result *provider.Result
+	switch payment.Status {
+	case model.PaymentStatusPending, model.PaymentStatusAuthorized:
+		//a pending payment is cancelled at the provider, so a late outcome can't charge the customer
+		if payment.ProviderReference == "" {
+			return payment, nil, util.SUCCESS
+		}
+		result, err = s.provider.Void(providerCtx, payment.ProviderReference)
+	case model.PaymentStatusCaptured:
+		remaining := payment.Amount - payment.RefundedAmount
+		result, err = s.provider.Refund(providerCtx, payment.ProviderReference, remaining)
+		payment.RefundedAmount = payment.Amount
+	default:
+		return payment, nil, util.SUCCESS
+	}
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	payment.Status = result.Status
+
+	err = s.save(ctx, payment)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return payment, nil, util.SUCCESS
+}
+
+func (s *Service) Refund(ctx context.Context, orderId int, amount float32) (*model.Payment, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	payment, err := s.latestPayment(ctx, orderId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if payment == nil || payment.Status != model.PaymentStatusCaptured {
+		return nil, errors.New("Order doesn't have a captured payment to refund"), util.INVALID_STATE
+	}
+
+	providerCtx, providerCancel := s.providerContext(ctx)
+	defer providerCancel()
+
+	result, err := s.provider.Refund(providerCtx, payment.ProviderReference, amount)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	payment.Status = result.Status
+	payment.RefundedAmount += amount
+
+	err = s.save(ctx, payment)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return payment, nil, util.SUCCESS
+}
+
+func (s *Service) GetPayments(ctx context.Context, orderId int) ([]dto.GetPaymentDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	payments, err := s.paymentRepository.GetPayments(ctx, dto.FilterPaymentDto{TransactionId: orderId})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	var data []dto.GetPaymentDto
+	for _, payment := range payments {
+		data = append(data, dto.GetPaymentDto{
+			ID:                payment.ID,
+			Provider:          payment.Provider,
+			ProviderReference: payment.ProviderReference,
+			Amount:            payment.Amount,
+			RefundedAmount:    payment.RefundedAmount,
+			Status:            payment.Status,
+			ErrorMessage:      payment.ErrorMessage,
+			CreatedAt:         payment.CreatedAt,
+		})
+	}
+	return data, nil, util.SUCCESS
+}
+
+func (s *Service) HandleWebhook(ctx context.Context, providerName string, payload []byte, header http.Header) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if providerName != s.provider.Name() {
+		return nil, errors.New("Payment provider not found"), util.NOT_FOUND
+	}
+
+	//every delivery is stored with its raw payload for audit
+	event := dto.CreateWebhookEventDto{Provider: providerName, Payload: string(payload)}
+
+	//without a secret anyone could sign a webhook, so none is taken
+	if s.webhookSecret == "" {
+		return s.rejectWebhook(ctx, event, errors.New("Payment webhooks are disabled, no webhook secret is configured"), util.UNAUTHORIZED)
+	}
+
+	valid := util.VerifySignature(s.webhookSecret, header.Get(util.TimestampHeader), header.Get(util.SignatureHeader), payload, webhookTolerance, time.Now())
+	if !valid {
+		return s.rejectWebhook(ctx, event, errors.New("Invalid or expired webhook signature"), util.UNAUTHORIZED)
+	}
+
+	parsed, err := s.provider.ParseWebhook(payload, header)
+	if err != nil {
+		return s.rejectWebhook(ctx, event, err, util.VALIDATION_ERROR)
+	}
+	if parsed.ID == "" {
+		return s.rejectWebhook(ctx, event, errors.New("Webhook event id is required"), util.VALIDATION_ERROR)
+	}
+	event.EventId = parsed.ID
+
+	payments, err := s.paymentRepository.GetPayments(ctx, dto.FilterPaymentDto{Provider: providerName, ProviderReference: parsed.Reference, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(payments) == 0 {
+		return s.rejectWebhook(ctx, event, errors.New("Payment reference not found"), util.NOT_FOUND)
+	}
+
+	//claim the event before handling it, so concurrent deliveries of it are handled once
+	claimed, err := s.paymentRepository.ClaimWebhookEvent(ctx, event)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !claimed {
+		event.Status = model.WebhookEventDuplicate
+		_, err = s.paymentRepository.CreateWebhookEvent(ctx, event)
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		return map[string]interface{}{"eventId": parsed.ID, "status": event.Status}, nil, util.SUCCESS
+	}
+
+	payment := &payments[0]
+	if isOpenPayment(payment.Status) && isFinalPayment(parsed.Status) {
+		payment.Status = parsed.Status
+		if parsed.Status != model.PaymentStatusCaptured {
+			payment.ErrorMessage = fmt.Sprintf("Payment %s by provider", parsed.Status)
+		}
+		err = s.save(ctx, payment)
+		if err != nil {
+			return s.releaseWebhook(ctx, event, err)
+		}
+
+		//the claim is given up when a handler fails, so the provider redelivers the event
+		for _, handler := range s.statusHandlers {
+			err = handler(ctx, payment.TransactionId, payment.Status)
+			if err != nil {
+				return s.releaseWebhook(ctx, event, err)
+			}
+		}
+	}
+
+	return map[string]interface{}{"eventId": parsed.ID, "status": event.Status, "paymentStatus": payment.Status}, nil, util.SUCCESS
+}
+
+func (s *Service) rejectWebhook(ctx context.Context, event dto.CreateWebhookEventDto, reason error, state string) (interface{}, error, string) {
+	event.Status = model.WebhookEventRejected
+	event.ErrorMessage = reason.Error()
+	_, err := s.paymentRepository.CreateWebhookEvent(ctx, event)
+	if err != nil {
+		log.Printf("failed to store rejected %s webhook: %s", event.Provider, err.Error())
+	}
+	return nil, reason, state
+}
+
+func (s *Service) releaseWebhook(ctx context.Context, event dto.CreateWebhookEventDto, reason error) (interface{}, error, string) {
+	err := s.paymentRepository.ReleaseWebhookEvent(ctx, event.Provider, event.EventId, reason.Error())
+	if err != nil {
+		log.Printf("failed to release %s webhook %s: %s", event.Provider, event.EventId, err.Error())
+	}
+	return nil, reason, util.SYSTEM_ERROR
+}
+
+func isOpenPayment(status string) bool {
+	return status == model.PaymentStatusPending || status == model.PaymentStatusAuthorized
+}
+
+func isFinalPayment(status string) bool {
+	switch status {
+	case model.PaymentStatusCaptured, model.PaymentStatusDeclined, model.PaymentStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (s *Service) latestPayment(ctx context.Context, orderId int) (*model.Payment, error) {
+	payments, err := s.paymentRepository.GetPayments(ctx, dto.FilterPaymentDto{TransactionId: orderId, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, nil
+	}
+	return &payments[0], nil
+}
+
+func (s *Service) save(ctx context.Context, payment *model.Payment) error {
+	return s.paymentRepository.Update(ctx, payment.ID, dto.UpdatePaymentDto{
+		ProviderReference: payment.ProviderReference,
+		RefundedAmount:    payment.RefundedAmount,
+		Status:            payment.Status,
+		ErrorMessage:      payment.ErrorMessage,
+	})
+}
@@ -0,0 +1,344 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/provider"
+	PaymentService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/service"
+	mockPaymentRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/payment/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const (
+	contextTimeout = 200 * time.Millisecond
+	webhookSecret  = "whsec_test"
+)
+
+var (
+	mockPaymentRepository = new(mockPaymentRepositories.PaymentRepository)
+	fakeProvider          = provider.NewFake(provider.FakeModeSucceed)
+	paymentService        = PaymentService.NewPaymentService(mockPaymentRepository, fakeProvider, webhookSecret, contextTimeout)
+)
+
+func reset() {
+	mockPaymentRepository = new(mockPaymentRepositories.PaymentRepository)
+	fakeProvider = provider.NewFake(provider.FakeModeSucceed)
+	paymentService = PaymentService.NewPaymentService(mockPaymentRepository, fakeProvider, webhookSecret, contextTimeout)
+}
+
+func TestPay(t *testing.T) {
+	pending := &model.Payment{ID: 1, TransactionId: 1, Provider: "fake", Amount: 50000, Status: "PENDING"}
+
+	t.Run("Test Pay Success", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("Create", mock.Anything, mock.Anything).Return(pending, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, dto.UpdatePaymentDto{ProviderReference: "fake_pi_1", Status: "CAPTURED"}).Return(nil)
+
+		res, err, state := paymentService.Pay(context.TODO(), 1, 50000)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "CAPTURED", res.Status)
+	})
+
+	t.Run("Test Pay Declined", func(t *testing.T) {
+		defer reset()
+		fakeProvider.Mode = provider.FakeModeDecline
+		mockPaymentRepository.On("Create", mock.Anything, mock.Anything).Return(&model.Payment{ID: 1}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, mock.Anything).Return(nil)
+
+		res, err, state := paymentService.Pay(context.TODO(), 1, 50000)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "DECLINED", res.Status)
+		assert.NotEmpty(t, res.ErrorMessage)
+	})
+
+	t.Run("Test Pay Provider Timeout", func(t *testing.T) {
+		defer reset()
+		fakeProvider.Mode = provider.FakeModeTimeout
+		mockPaymentRepository.On("Create", mock.Anything, mock.Anything).Return(&model.Payment{ID: 1}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, mock.Anything).Return(nil)
+
+		res, err, state := paymentService.Pay(context.TODO(), 1, 50000)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "FAILED", res.Status)
+	})
+
+	t.Run("Test Pay Error Database", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := paymentService.Pay(context.TODO(), 1, 50000)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestReverse(t *testing.T) {
+	t.Run("Test Reverse Voids Authorized Payment", func(t *testing.T) {
+		defer reset()
+		intent, _ := fakeProvider.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		payment := model.Payment{ID: 1, ProviderReference: intent.Reference, Amount: 50000, Status: "AUTHORIZED"}
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, dto.FilterPaymentDto{TransactionId: 1, Limit: 1}).Return([]model.Payment{payment}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, mock.Anything).Return(nil)
+
+		res, err, state := paymentService.Reverse(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "VOIDED", res.Status)
+	})
+
+	t.Run("Test Reverse Voids Pending Payment", func(t *testing.T) {
+		defer reset()
+		asyncProvider := provider.NewFake(provider.FakeModeAsync)
+		paymentService = PaymentService.NewPaymentService(mockPaymentRepository, asyncProvider, webhookSecret, contextTimeout)
+		intent, _ := asyncProvider.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		payment := model.Payment{ID: 1, ProviderReference: intent.Reference, Amount: 50000, Status: "PENDING"}
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, dto.FilterPaymentDto{TransactionId: 1, Limit: 1}).Return([]model.Payment{payment}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, dto.UpdatePaymentDto{ProviderReference: intent.Reference, Status: "VOIDED"}).Return(nil)
+
+		res, err, state := paymentService.Reverse(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "VOIDED", res.Status)
+		mockPaymentRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Reverse Refunds Captured Payment", func(t *testing.T) {
+		defer reset()
+		intent, _ := fakeProvider.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		fakeProvider.Capture(context.TODO(), intent.Reference, 50000)
+		fakeProvider.Refund(context.TODO(), intent.Reference, 10000)
+		payment := model.Payment{ID: 1, ProviderReference: intent.Reference, Amount: 50000, RefundedAmount: 10000, Status: "CAPTURED"}
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, mock.Anything).Return([]model.Payment{payment}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, dto.UpdatePaymentDto{ProviderReference: intent.Reference, RefundedAmount: 50000, Status: "REFUNDED"}).Return(nil)
+
+		res, err, state := paymentService.Reverse(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "REFUNDED", res.Status)
+	})
+
+	t.Run("Test Reverse Without Payment", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("GetPayments", mock.Anything, mock.Anything).Return(nil, nil)
+
+		res, err, state := paymentService.Reverse(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRefund(t *testing.T) {
+	t.Run("Test Refund Success", func(t *testing.T) {
+		defer reset()
+		intent, _ := fakeProvider.CreateIntent(context.TODO(), provider.IntentRequest{OrderId: 1, Amount: 50000})
+		fakeProvider.Capture(context.TODO(), intent.Reference, 50000)
+		payment := model.Payment{ID: 1, ProviderReference: intent.Reference, Amount: 50000, Status: "CAPTURED"}
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, mock.Anything).Return([]model.Payment{payment}, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, dto.UpdatePaymentDto{ProviderReference: intent.Reference, RefundedAmount: 20000, Status: "CAPTURED"}).Return(nil)
+
+		res, err, state := paymentService.Refund(context.TODO(), 1, 20000)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, float32(20000), res.RefundedAmount)
+	})
+
+	t.Run("Test Refund Without Captured Payment", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("GetPayments", mock.Anything, mock.Anything).Return([]model.Payment{{ID: 1, Status: "DECLINED"}}, nil)
+
+		res, err, state := paymentService.Refund(context.TODO(), 1, 20000)
+
+		assert.Equal(t, "INVALID_STATE", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGetPayments(t *testing.T) {
+	t.Run("Test Get Payments Success", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("GetPayments", mock.Anything, dto.FilterPaymentDto{TransactionId: 1}).Return([]model.Payment{{ID: 1, Status: "CAPTURED"}}, nil)
+
+		res, err, state := paymentService.GetPayments(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("Test Get Payments Error Database", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("GetPayments", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := paymentService.GetPayments(context.TODO(), 1)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func signedHeader(body []byte, timestamp time.Time) http.Header {
+	header := http.Header{}
+	header.Set(util.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(util.SignatureHeader, util.Sign(webhookSecret, timestamp.Unix(), body))
+	return header
+}
+
+func TestHandleWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1","reference":"fake_pi_1","status":"CAPTURED","amount":50000}`)
+	event := dto.CreateWebhookEventDto{Provider: "fake", EventId: "evt_1", Payload: string(body)}
+	paymentFilter := dto.FilterPaymentDto{Provider: "fake", ProviderReference: "fake_pi_1", Limit: 1}
+
+	t.Run("Test Handle Webhook Captures Pending Payment", func(t *testing.T) {
+		defer reset()
+		var notified []string
+		paymentService.RegisterStatusHandler(func(ctx context.Context, orderId int, paymentStatus string) error {
+			notified = append(notified, paymentStatus)
+			return nil
+		})
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, paymentFilter).Return([]model.Payment{{ID: 1, TransactionId: 3, ProviderReference: "fake_pi_1", Status: "PENDING"}}, nil)
+		mockPaymentRepository.On("ClaimWebhookEvent", mock.Anything, event).Return(true, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, dto.UpdatePaymentDto{ProviderReference: "fake_pi_1", Status: "CAPTURED"}).Return(nil)
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, signedHeader(body, time.Now()))
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "CAPTURED", res.(map[string]interface{})["paymentStatus"])
+		assert.Equal(t, []string{"CAPTURED"}, notified)
+	})
+
+	t.Run("Test Handle Webhook Duplicate Event", func(t *testing.T) {
+		defer reset()
+		//another delivery of the event claimed it first
+		mockPaymentRepository.On("GetPayments", mock.Anything, paymentFilter).Return([]model.Payment{{ID: 1, TransactionId: 3, ProviderReference: "fake_pi_1", Status: "PENDING"}}, nil)
+		mockPaymentRepository.On("ClaimWebhookEvent", mock.Anything, event).Return(false, nil)
+		mockPaymentRepository.On("CreateWebhookEvent", mock.Anything, mock.MatchedBy(func(event dto.CreateWebhookEventDto) bool {
+			return event.Status == "DUPLICATE"
+		})).Return(&model.PaymentWebhookEvent{ID: 2}, nil)
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, signedHeader(body, time.Now()))
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, "DUPLICATE", res.(map[string]interface{})["status"])
+		mockPaymentRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Handle Webhook Invalid Signature", func(t *testing.T) {
+		defer reset()
+		header := signedHeader(body, time.Now())
+		header.Set(util.SignatureHeader, "forged")
+		mockPaymentRepository.On("CreateWebhookEvent", mock.Anything, mock.MatchedBy(func(event dto.CreateWebhookEventDto) bool {
+			return event.Status == "REJECTED" && event.Payload == string(body)
+		})).Return(&model.PaymentWebhookEvent{ID: 1}, nil)
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, header)
+
+		assert.Equal(t, "UNAUTHORIZED", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Handle Webhook Without Secret", func(t *testing.T) {
+		defer reset()
+		paymentService = PaymentService.NewPaymentService(mockPaymentRepository, fakeProvider, "", contextTimeout)
+		mockPaymentRepository.On("CreateWebhookEvent", mock.Anything, mock.MatchedBy(func(event dto.CreateWebhookEventDto) bool {
+			return event.Status == "REJECTED"
+		})).Return(&model.PaymentWebhookEvent{ID: 1}, nil)
+
+		//signed with the empty secret like a forger would
+		timestamp := time.Now()
+		header := http.Header{}
+		header.Set(util.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		header.Set(util.SignatureHeader, util.Sign("", timestamp.Unix(), body))
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, header)
+
+		assert.Equal(t, "UNAUTHORIZED", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockPaymentRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Handle Webhook Replayed Timestamp", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("CreateWebhookEvent", mock.Anything, mock.Anything).Return(&model.PaymentWebhookEvent{ID: 1}, nil)
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, signedHeader(body, time.Now().Add(-time.Hour)))
+
+		assert.Equal(t, "UNAUTHORIZED", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Handle Webhook Unknown Provider", func(t *testing.T) {
+		defer reset()
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "stripe", body, signedHeader(body, time.Now()))
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Handle Webhook Unknown Reference", func(t *testing.T) {
+		defer reset()
+		mockPaymentRepository.On("GetPayments", mock.Anything, paymentFilter).Return(nil, nil)
+		mockPaymentRepository.On("CreateWebhookEvent", mock.Anything, mock.Anything).Return(&model.PaymentWebhookEvent{ID: 1}, nil)
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, signedHeader(body, time.Now()))
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Handle Webhook Status Handler Failed", func(t *testing.T) {
+		defer reset()
+		paymentService.RegisterStatusHandler(func(ctx context.Context, orderId int, paymentStatus string) error {
+			return errors.New("Database Error")
+		})
+
+		mockPaymentRepository.On("GetPayments", mock.Anything, paymentFilter).Return([]model.Payment{{ID: 1, TransactionId: 3, ProviderReference: "fake_pi_1", Status: "PENDING"}}, nil)
+		mockPaymentRepository.On("ClaimWebhookEvent", mock.Anything, event).Return(true, nil)
+		mockPaymentRepository.On("Update", mock.Anything, 1, mock.Anything).Return(nil)
+		mockPaymentRepository.On("ReleaseWebhookEvent", mock.Anything, "fake", "evt_1", "Database Error").Return(nil).Once()
+
+		res, err, state := paymentService.HandleWebhook(context.TODO(), "fake", body, signedHeader(body, time.Now()))
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockPaymentRepository.AssertExpectations(t)
+	})
+}
@@ -1,7 +1,8 @@
 package http
 
 import (
-	"encoding/json"
+	"log"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -34,52 +35,295 @@ func NewProductHandler(mux *http.ServeMux, service service.ProductService) {
 		}
 	})
 
+	mux.HandleFunc("/product/category", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.GetProductByCategory(w, r)
+		}
+	})
+
+	mux.HandleFunc("/product/export", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.ExportProducts(w, r)
+		}
+	})
+
+	mux.HandleFunc("/product/import", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.Import(w, r)
+		}
+	})
+
+	mux.HandleFunc("/product/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/product/")
+		switch {
+		case action == "variants" && r.Method == "POST":
+			handler.CreateVariant(w, r)
+		case action == "" && r.Method == "DELETE":
+			handler.Delete(w, r)
+		case action == "restore" && r.Method == "POST":
+			handler.Restore(w, r)
+		case action == "prices" && r.Method == "POST":
+			handler.CreatePrice(w, r)
+		case action == "prices" && r.Method == "GET":
+			handler.GetPrices(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
 }
 
 func (b *ProductHandler) Create(w http.ResponseWriter, r *http.Request) error {
 	var res *util.Response
 
 	var payload dto.InsertProductDto
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.Decode(r, &payload)
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode("SYSTEM_ERROR"), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
 	}
 
 	var valid bool
 	if valid, err = isRequestValid(&payload); !valid {
-		return res.JSON(w, false, util.GetResCode("VALIDATION_ERROR"), err.Error(), nil)
+		return res.Render(w, r, false, util.GetResCode("VALIDATION_ERROR"), err.Error(), nil)
 
 	}
 
 	result, err, state := b.ProductService.Create(r.Context(), payload)
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), result)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
 	}
-	return res.JSON(w, true, util.GetResCode(state), "Success", result)
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
 }
 
 func (b *ProductHandler) GetProductById(w http.ResponseWriter, r *http.Request) error {
 	var res *util.Response
 	ParamId := r.URL.Query().Get("id")
 	id, _ := strconv.Atoi(ParamId)
+	if includeDeleted(r) {
+		return b.getProducts(w, r, dto.FilterProductDto{ID: id, Limit: 1, IncludeDeleted: true})
+	}
 	result, err, state := b.ProductService.GetProductById(r.Context(), id)
 
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), result)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
 	}
-	return res.JSON(w, true, util.GetResCode(state), "Success", result)
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
 }
 
 func (b *ProductHandler) GetProductByBrand(w http.ResponseWriter, r *http.Request) error {
 	var res *util.Response
 	ParamId := r.URL.Query().Get("id")
 	id, _ := strconv.Atoi(ParamId)
+	if includeDeleted(r) {
+		return b.getProducts(w, r, dto.FilterProductDto{BrandId: id, IncludeDeleted: true})
+	}
 	result, err, state := b.ProductService.GetProductByBrand(r.Context(), id)
 
 	if err != nil {
-		return res.JSON(w, false, util.GetResCode(state), err.Error(), result)
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, false, util.GetResCode(state), "Success", result)
+}
+
+func (b *ProductHandler) GetProductByCategory(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+	ParamId := r.URL.Query().Get("id")
+	id, _ := strconv.Atoi(ParamId)
+	if includeDeleted(r) {
+		return b.getProducts(w, r, dto.FilterProductDto{CategoryId: id, IncludeDeleted: true})
+	}
+	result, err, state := b.ProductService.GetProductByCategory(r.Context(), id)
+
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/product/")
+
+	var payload dto.InsertVariantDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.ProductService.CreateVariant(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+// Delete soft deletes the product of the path, e.g. DELETE /product/3
+func (b *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/product/")
+	result, err, state := b.ProductService.Delete(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+// Restore takes back the soft delete of the product of the path, e.g. POST /product/3/restore
+func (b *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/product/")
+	result, err, state := b.ProductService.Restore(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+// CreatePrice schedules a price of the product of the path, e.g. POST /product/3/prices
+func (b *ProductHandler) CreatePrice(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/product/")
+
+	var payload dto.InsertPriceDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+	payload.CreatedBy = util.GetActor(r)
+
+	result, err, state := b.ProductService.CreatePrice(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+// GetPrices returns the price history of the product of the path, e.g. GET /product/3/prices
+func (b *ProductHandler) GetPrices(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/product/")
+	result, err, state := b.ProductService.GetPrices(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+// includeDeleted tells whether the request asks for the soft-deleted products too, with ?includeDeleted=true
+func includeDeleted(r *http.Request) bool {
+	include, _ := strconv.ParseBool(r.URL.Query().Get("includeDeleted"))
+	return include
+}
+
+// getProducts answers the lookups including the soft-deleted products, a lookup by id with the product alone
+func (b *ProductHandler) getProducts(w http.ResponseWriter, r *http.Request, filter dto.FilterProductDto) error {
+	var res *util.Response
+
+	result, err, state := b.ProductService.GetProducts(r.Context(), filter)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	if len(result) == 0 {
+		return res.Render(w, r, false, util.GetResCode(util.NOT_FOUND), "Product Not Found", nil)
+	}
+	if filter.ID > 0 {
+		return res.Render(w, r, true, util.GetResCode(state), "Success", result[0])
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *ProductHandler) ExportProducts(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	format, err := util.GetExportFormat(r)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	query := r.URL.Query()
+	var filter dto.FilterProductDto
+	filter.BrandId, _ = strconv.Atoi(query.Get("brandId"))
+	filter.CategoryId, _ = strconv.Atoi(query.Get("categoryId"))
+	filter.IncludeDeleted = includeDeleted(r)
+
+	export := util.NewExportWriter(w, format, "products", dto.ExportProductColumns)
+	err, state := b.ProductService.ExportProducts(r.Context(), filter, func(row dto.ExportProduct) error {
+		return export.Write(row.Values())
+	})
+	if err != nil {
+		if !export.Started() {
+			return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+		}
+		//the rows are already on their way, the client sees a truncated export
+		log.Printf("failed to export products: %s", err.Error())
+		return err
+	}
+	return export.Close()
+}
+
+// MaxImportSize is the largest import body accepted
+const MaxImportSize = 10 << 20
+
+// Import reads the options from the query, the format defaults to the one of the Content-Type
+func (b *ProductHandler) Import(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	query := r.URL.Query()
+	options := dto.ImportOptions{Format: query.Get("format"), Mode: query.Get("mode")}
+	if options.Format == "" {
+		options.Format = importFormat(r.Header.Get("Content-Type"))
+	}
+	if options.Mode == "" {
+		options.Mode = dto.ImportModeAtomic
+	}
+	if value := query.Get("dryRun"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), "dryRun must be true or false", nil)
+		}
+		options.DryRun = dryRun
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&options); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImportSize)
+	result, err, state := b.ProductService.Import(r.Context(), r.Body, options)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func importFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return dto.ImportFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return dto.ImportFormatNDJSON
 	}
-	return res.JSON(w, false, util.GetResCode(state), "Success", result)
+	return ""
 }
 
 func isRequestValid(dto *dto.InsertProductDto) (bool, error) {
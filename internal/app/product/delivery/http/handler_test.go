@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	productHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/delivery/http"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
@@ -40,7 +41,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Test Create Product success", func(t *testing.T) {
 		defer reset()
 
-		mockService.On("Create", context.TODO(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -57,7 +58,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Test Create Product Failed Brand Id Not Found", func(t *testing.T) {
 		defer reset()
 
-		mockService.On("Create", context.TODO(), payload).Return(nil, errors.New("BrandId Doesn't exist"), "NOT_FOUND")
+		mockService.On("Create", context.Background(), payload).Return(nil, errors.New("BrandId Doesn't exist"), "NOT_FOUND")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -74,7 +75,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Test Create Product Error from Database", func(t *testing.T) {
 		defer reset()
 
-		mockService.On("Create", context.TODO(), payload).Return(nil, errors.New("BrandId Doesn't exist"), "SYSTEM_ERROR")
+		mockService.On("Create", context.Background(), payload).Return(nil, errors.New("BrandId Doesn't exist"), "SYSTEM_ERROR")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -144,7 +145,7 @@ func TestGetProductById(t *testing.T) {
 	t.Run("Test Get Product By Id Success", func(t *testing.T) {
 		defer reset()
 		err := faker.FakeData(&mockGetProduct)
-		mockService.On("GetProductById", context.TODO(), 1).Return(mockGetProduct, nil, "SUCCESS")
+		mockService.On("GetProductById", context.Background(), 1).Return(mockGetProduct, nil, "SUCCESS")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -160,7 +161,7 @@ func TestGetProductById(t *testing.T) {
 
 	t.Run("Test Get Product By Id Not Found ", func(t *testing.T) {
 		defer reset()
-		mockService.On("GetProductById", context.TODO(), 1).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+		mockService.On("GetProductById", context.Background(), 1).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -176,7 +177,7 @@ func TestGetProductById(t *testing.T) {
 
 	t.Run("Test Get Product By Id Error System ", func(t *testing.T) {
 		defer reset()
-		mockService.On("GetProductById", context.TODO(), 1).Return(nil, errors.New("System Error"), "SYSTEM_ERROR")
+		mockService.On("GetProductById", context.Background(), 1).Return(nil, errors.New("System Error"), "SYSTEM_ERROR")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -206,7 +207,7 @@ func TestGetProductByBrand(t *testing.T) {
 	t.Run("Test Get Product By Brand Success", func(t *testing.T) {
 		defer reset()
 		err := faker.FakeData(&mockGetProduct)
-		mockService.On("GetProductByBrand", context.TODO(), 1).Return(mockGetProduct, nil, "SUCCESS")
+		mockService.On("GetProductByBrand", context.Background(), 1).Return(mockGetProduct, nil, "SUCCESS")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -222,7 +223,7 @@ func TestGetProductByBrand(t *testing.T) {
 
 	t.Run("Test Get Product By Brand Not Found ", func(t *testing.T) {
 		defer reset()
-		mockService.On("GetProductByBrand", context.TODO(), 1).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+		mockService.On("GetProductByBrand", context.Background(), 1).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -238,7 +239,7 @@ func TestGetProductByBrand(t *testing.T) {
 
 	t.Run("Test Get Product By Brand Error System ", func(t *testing.T) {
 		defer reset()
-		mockService.On("GetProductByBrand", context.TODO(), 1).Return(nil, errors.New("System Error"), "SYSTEM_ERROR")
+		mockService.On("GetProductByBrand", context.Background(), 1).Return(nil, errors.New("System Error"), "SYSTEM_ERROR")
 
 		productHttp.NewProductHandler(mux, mockService)
 		handler := productHttp.ProductHandler{ProductService: mockService}
@@ -253,3 +254,358 @@ func TestGetProductByBrand(t *testing.T) {
 	})
 
 }
+
+func TestGetProductByCategory(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	t.Run("Test Get Product By Category Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("GetProductByCategory", context.Background(), 5).Return([]dto.GetProduct{{ID: 1}}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/category?id=5", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Get Product By Category Not Found", func(t *testing.T) {
+		defer reset()
+		mockService.On("GetProductByCategory", context.Background(), 5).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/category?id=5", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCreateVariant(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	t.Run("Test Create Variant Success", func(t *testing.T) {
+		defer reset()
+		payload := dto.InsertVariantDto{Sku: "TSHIRT-M", Price: 120000, Stock: 5, Options: map[string]string{"size": "M"}}
+		mockService.On("CreateVariant", context.Background(), 1, payload).Return(map[string]interface{}{"id": 4}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/1/variants", strings.NewReader(`{"sku":"TSHIRT-M","price":120000,"stock":5,"options":{"size":"M"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Variant Without Options", func(t *testing.T) {
+		defer reset()
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/1/variants", strings.NewReader(`{"sku":"TSHIRT-M","stock":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Variant Unknown Route", func(t *testing.T) {
+		defer reset()
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/1/variants", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestImport(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	t.Run("Test Import Csv Dry Run", func(t *testing.T) {
+		defer reset()
+		options := dto.ImportOptions{Format: dto.ImportFormatCSV, Mode: dto.ImportModeBestEffort, DryRun: true}
+		mockService.On("Import", context.Background(), mock.Anything, options).Return(&dto.ImportReport{Total: 1, Valid: 1}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/import?mode=best-effort&dryRun=true", strings.NewReader("title,brandId,price\nShirt,1,99000\n"))
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Import Invalid Rows", func(t *testing.T) {
+		defer reset()
+		options := dto.ImportOptions{Format: dto.ImportFormatNDJSON, Mode: dto.ImportModeAtomic}
+		mockService.On("Import", context.Background(), mock.Anything, options).Return(&dto.ImportReport{Total: 1}, errors.New("1 of 1 rows are invalid, nothing was imported"), "VALIDATION_ERROR")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/import?format=ndjson", strings.NewReader(`{"title":"Shirt"}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":1`)
+	})
+
+	t.Run("Test Import Unknown Format", func(t *testing.T) {
+		defer reset()
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/import", strings.NewReader("title\nShirt\n"))
+		req.Header.Set("Content-Type", "text/plain")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestExportProducts(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	rows := []dto.ExportProduct{
+		{ID: 1, Title: `Shirt, "Basic"`, Description: "Soft\ncotton", BrandId: 1, Brand: "Uniqlo", Price: 99000.5, CreatedAt: "2026-10-19 10:00:00"},
+		{ID: 2, Title: "=HYPERLINK(1)", BrandId: 2, Brand: "Nike", Price: 1200000, CreatedAt: "2026-10-19 11:00:00"},
+	}
+	stream := func(args mock.Arguments) {
+		fn := args.Get(2).(func(dto.ExportProduct) error)
+		for _, row := range rows {
+			fn(row)
+		}
+	}
+
+	t.Run("Test Export Products Csv", func(t *testing.T) {
+		defer reset()
+		mockService.On("ExportProducts", context.Background(), dto.FilterProductDto{BrandId: 1}, mock.Anything).Run(stream).Return(nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/export?brandId=1", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="products.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,title,description,brandId,brand,price,weight,length,width,height,createdAt\n"+
+			"1,\"Shirt, \"\"Basic\"\"\",\"Soft\ncotton\",1,Uniqlo,99000.5,0,0,0,0,2026-10-19 10:00:00\n"+
+			"2,'=HYPERLINK(1),,2,Nike,1200000,0,0,0,0,2026-10-19 11:00:00\n", w.Body.String())
+	})
+
+	t.Run("Test Export Products Ndjson From Accept", func(t *testing.T) {
+		defer reset()
+		mockService.On("ExportProducts", context.Background(), dto.FilterProductDto{}, mock.Anything).Run(stream).Return(nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/export", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], `{"id":1,"title":"Shirt, \"Basic\"","description":"Soft\ncotton","brandId":1,`))
+	})
+
+	t.Run("Test Export Products Empty", func(t *testing.T) {
+		defer reset()
+		mockService.On("ExportProducts", context.Background(), mock.Anything, mock.Anything).Return(nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/export?format=csv", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,title,description,brandId,brand,price,weight,length,width,height,createdAt\n", w.Body.String())
+	})
+
+	t.Run("Test Export Products Error Before First Row", func(t *testing.T) {
+		defer reset()
+		mockService.On("ExportProducts", context.Background(), mock.Anything, mock.Anything).Return(errors.New("Database Error"), "SYSTEM_ERROR")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/export", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("Test Export Products Unknown Format", func(t *testing.T) {
+		defer reset()
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/export?format=xlsx", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteAndRestoreProduct(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	t.Run("Test Delete Product Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("Delete", context.Background(), 1).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodDelete, "/product/1", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Restore Product Not Deleted", func(t *testing.T) {
+		defer reset()
+		mockService.On("Restore", context.Background(), 1).Return(nil, errors.New("Product is not deleted"), "INVALID_STATE")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/1/restore", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Test Get Product Including Deleted", func(t *testing.T) {
+		defer reset()
+		filter := dto.FilterProductDto{ID: 1, Limit: 1, IncludeDeleted: true}
+		mockService.On("GetProducts", context.Background(), filter).Return([]dto.GetProduct{{ID: 1, DeletedAt: "2026-10-19 09:00:00"}}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product?id=1&includeDeleted=true", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"deletedAt":"2026-10-19 09:00:00"`)
+	})
+
+	t.Run("Test Get Product Including Deleted Not Found", func(t *testing.T) {
+		defer reset()
+		filter := dto.FilterProductDto{BrandId: 2, IncludeDeleted: true}
+		mockService.On("GetProducts", context.Background(), filter).Return([]dto.GetProduct{}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/brand?id=2&includeDeleted=true", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestProductPrices(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.ProductService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ProductService)
+	}
+
+	t.Run("Test Create Price Success", func(t *testing.T) {
+		defer reset()
+		payload := dto.InsertPriceDto{Price: 99000, StartAt: "2026-11-11 00:00:00", EndAt: "2026-11-12 00:00:00", CreatedBy: "merchandiser"}
+		mockService.On("CreatePrice", context.Background(), 1, payload).Return(map[string]interface{}{"id": 3}, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/1/prices", strings.NewReader(`{"price":99000,"startAt":"2026-11-11 00:00:00","endAt":"2026-11-12 00:00:00"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "merchandiser")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Price Invalid Date", func(t *testing.T) {
+		defer reset()
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/product/1/prices", strings.NewReader(`{"price":99000,"startAt":"11-11-2026"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Get Prices Success", func(t *testing.T) {
+		defer reset()
+		prices := []dto.PriceDto{{ID: 1, Price: 120000, StartAt: "2026-10-01 00:00:00", Active: true, CreatedBy: "system"}}
+		mockService.On("GetPrices", context.Background(), 1).Return(prices, nil, "SUCCESS")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/1/prices", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"active":true`)
+	})
+
+	t.Run("Test Get Prices Not Found", func(t *testing.T) {
+		defer reset()
+		mockService.On("GetPrices", context.Background(), 9).Return(nil, errors.New("Product Not Found"), "NOT_FOUND")
+
+		productHttp.NewProductHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/product/9/prices", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,10 +1,16 @@
 package dto
 
 type InsertProductDto struct {
-	Title       string  `json:"title" validate:"required"`
-	Description string  `json:"description"`
-	BrandId     int     `json:"brandId" validate:"required"`
-	Price       float32 `json:"price" validate:"required"`
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description"`
+	BrandId     int      `json:"brandId" validate:"required"`
+	Price       float32  `json:"price" validate:"required"`
+	Weight      int      `json:"weight" validate:"gte=0"`
+	Length      int      `json:"length" validate:"gte=0"`
+	Width       int      `json:"width" validate:"gte=0"`
+	Height      int      `json:"height" validate:"gte=0"`
+	CategoryIds []int    `json:"categoryIds" validate:"dive,gt=0"`
+	Options     []string `json:"options" validate:"unique,dive,required,max=30"`
 }
 
 type FilterProductDto struct {
@@ -12,18 +18,161 @@ type FilterProductDto struct {
 	BrandId     int    `json:"brandId" validate:"required"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	CategoryId  int    `json:"categoryId"`
 	Limit       int    `json:"limit"`
+	//IncludeDeleted also returns the soft-deleted products and the products of soft-deleted brands, which are
+	//left out otherwise
+	IncludeDeleted bool `json:"includeDeleted"`
 }
 
 type GetProduct struct {
-	ID          int      `json:"id"`
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Brand       BrandDto `json:"brand"`
-	Price       float32  `json:"price"`
+	ID          int           `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Brand       BrandDto      `json:"brand"`
+	Price       float32       `json:"price"`
+	Weight      int           `json:"weight"`
+	Length      int           `json:"length"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	Categories  []CategoryDto `json:"categories"`
+	Options     []string      `json:"options"`
+	Variants    []VariantDto  `json:"variants"`
+	Images      []ImageDto    `json:"images"`
+	DeletedAt   string        `json:"deletedAt,omitempty"`
 }
 
 type BrandDto struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 }
+
+type CategoryDto struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type InsertVariantDto struct {
+	ProductId int               `json:"-"`
+	Sku       string            `json:"sku" validate:"required,max=64"`
+	Price     float32           `json:"price" validate:"gte=0"`
+	Stock     int               `json:"stock" validate:"gte=0"`
+	Options   map[string]string `json:"options" validate:"required,dive,keys,required,endkeys,required,max=50"`
+}
+
+type FilterVariantDto struct {
+	ID         int    `json:"id"`
+	Sku        string `json:"sku"`
+	ProductIds []int  `json:"productIds"`
+}
+
+// VariantDto is a variant as sold, Price is the variant price or the product price when it has no override
+type VariantDto struct {
+	ID      int               `json:"id"`
+	Sku     string            `json:"sku"`
+	Price   float32           `json:"price"`
+	Stock   int               `json:"stock"`
+	Options map[string]string `json:"options"`
+}
+
+// InsertPriceDto schedules a price of the product from StartAt, right away when empty, until EndAt or for good
+// when empty. Dates are yyyy-mm-dd hh:mm:ss.
+type InsertPriceDto struct {
+	ProductId int     `json:"-"`
+	Price     float32 `json:"price" validate:"required,gt=0"`
+	StartAt   string  `json:"startAt" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	EndAt     string  `json:"endAt" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	CreatedBy string  `json:"-"`
+}
+
+// PriceDto is a price of the product history, Active tells whether the product sells at it now
+type PriceDto struct {
+	ID        int     `json:"id"`
+	Price     float32 `json:"price"`
+	StartAt   string  `json:"startAt"`
+	EndAt     string  `json:"endAt,omitempty"`
+	Active    bool    `json:"active"`
+	CreatedBy string  `json:"createdBy"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+// StockAdjustment adds Qty to the stock of a variant, a negative Qty takes stock out
+type StockAdjustment struct {
+	VariantId int
+	Qty       int
+}
+
+// ImageDto is a product image with the URL of the original and of every thumbnail size
+type ImageDto struct {
+	ID          int               `json:"id"`
+	Url         string            `json:"url"`
+	ContentType string            `json:"contentType"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	SortOrder   int               `json:"sortOrder"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+}
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	ImportModeAtomic     = "atomic"
+	ImportModeBestEffort = "best-effort"
+)
+
+// ImportOptions of a bulk import: atomic creates every product or none, best-effort creates the valid rows.
+// A dry run validates without creating anything.
+type ImportOptions struct {
+	Format string `json:"format" validate:"required,oneof=csv ndjson"`
+	Mode   string `json:"mode" validate:"required,oneof=atomic best-effort"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// ImportRow is a product of the import, the brand is given by BrandId or by its title in Brand
+type ImportRow struct {
+	InsertProductDto
+	Brand string `json:"brand"`
+}
+
+type ImportReport struct {
+	Mode    string            `json:"mode"`
+	DryRun  bool              `json:"dryRun"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of a row: valid (dry run or not created), created, invalid or failed
+type ImportRowResult struct {
+	Line   int      `json:"line"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	ID     int      `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+var ExportProductColumns = []string{"id", "title", "description", "brandId", "brand", "price", "weight", "length", "width", "height", "createdAt"}
+
+// ExportProduct is a row of the catalogue export
+type ExportProduct struct {
+	ID          int
+	Title       string
+	Description string
+	BrandId     int
+	Brand       string
+	Price       float32
+	Weight      int
+	Length      int
+	Width       int
+	Height      int
+	CreatedAt   string
+}
+
+// Values are in the order of ExportProductColumns
+func (p ExportProduct) Values() []interface{} {
+	return []interface{}{p.ID, p.Title, p.Description, p.BrandId, p.Brand, p.Price, p.Weight, p.Length, p.Width, p.Height, p.CreatedAt}
+}
@@ -0,0 +1,530 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	brandDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	brandRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
+	categoryDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/dto"
+	categoryRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/repository"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// Memory is a ProductRepository keeping the products and their variants in memory, for tests and for running
+// without a database. Brands and categories are read from their repositories, like the joins of Repository.
+// Changes are recorded to the audit log, and their events written to the outbox, right after they are made, there
+// is no transaction to share.
+type Memory struct {
+	mu         sync.RWMutex
+	products   []memoryProduct
+	variants   []model.ProductVariant
+	prices     []model.ProductPrice
+	brands     brandRepository.BrandRepository
+	categories categoryRepository.CategoryRepository
+	audit      auditRepository.AuditRepository
+	outbox     eventRepository.OutboxRepository
+}
+
+type memoryProduct struct {
+	dto.InsertProductDto
+	ID        int
+	CreatedAt string
+	DeletedAt string
+}
+
+func NewMemoryProduct(brands brandRepository.BrandRepository, categories categoryRepository.CategoryRepository, audit auditRepository.AuditRepository,
+	outbox eventRepository.OutboxRepository) *Memory {
+	return &Memory{brands: brands, categories: categories, audit: audit, outbox: outbox}
+}
+
+func (m *Memory) Create(ctx context.Context, payload dto.InsertProductDto) (*model.Product, error) {
+	products, err := m.CreateMany(ctx, []dto.InsertProductDto{payload})
+	if err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// CreateMany creates every product or none of them
+func (m *Memory) CreateMany(ctx context.Context, payloads []dto.InsertProductDto) ([]model.Product, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, payload := range payloads {
+		if err := uniqueOptions(payload.Options); err != nil {
+			return nil, err
+		}
+	}
+
+	//formatted the way the datetime columns read back from the database
+	createdAt := time.Now().UTC().Format(time.RFC3339)
+	products := make([]model.Product, len(payloads))
+	for i, payload := range payloads {
+		product := memoryProduct{InsertProductDto: payload, ID: len(m.products) + 1, CreatedAt: createdAt}
+		product.CategoryIds = uniqueIds(payload.CategoryIds)
+		product.Options = append([]string(nil), payload.Options...)
+
+		m.products = append(m.products, product)
+		products[i].ID = product.ID
+
+		//the price history starts with the price of the product
+		m.prices = append(m.prices, model.ProductPrice{ID: len(m.prices) + 1, ProductId: product.ID, Price: payload.Price,
+			StartAt: time.Now().Format(util.DateTimeLayout), CreatedBy: util.ActorOf(ctx), CreatedAt: createdAt})
+	}
+
+	for i, payload := range payloads {
+		err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityProduct, EntityId: products[i].ID, After: payload})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return products, nil
+}
+
+func (m *Memory) GetProduct(ctx context.Context, filter dto.FilterProductDto) (data []dto.GetProduct, err error) {
+	products, brands, err := m.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Limit > 0 && len(products) > filter.Limit {
+		products = products[:filter.Limit]
+	}
+
+	categories, err := m.getCategories(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	for _, product := range products {
+		//the scheduled price in effect, which the variants inherit
+		prices := m.productPrices(product.ID)
+		if active := ActivePrice(prices, now); active >= 0 {
+			product.Price = prices[active].Price
+		}
+
+		result := dto.GetProduct{
+			ID:          product.ID,
+			Title:       product.Title,
+			Description: product.Description,
+			Brand:       dto.BrandDto{ID: product.BrandId, Title: brands[product.BrandId]},
+			Price:       product.Price,
+			Weight:      product.Weight,
+			Length:      product.Length,
+			Width:       product.Width,
+			Height:      product.Height,
+			Options:     append([]string(nil), product.Options...),
+			DeletedAt:   product.DeletedAt,
+		}
+
+		for _, category := range categories {
+			if containsId(product.CategoryIds, category.ID) {
+				result.Categories = append(result.Categories, dto.CategoryDto{ID: category.ID, Title: category.Title, Slug: category.Slug})
+			}
+		}
+
+		for _, variant := range m.variants {
+			if variant.ProductId != product.ID {
+				continue
+			}
+			price := variant.Price
+			if price == 0 {
+				price = product.Price
+			}
+			result.Variants = append(result.Variants, dto.VariantDto{
+				ID:      variant.ID,
+				Sku:     variant.Sku,
+				Price:   price,
+				Stock:   variant.Stock,
+				Options: copyOptions(variant.Options),
+			})
+		}
+
+		data = append(data, result)
+	}
+
+	return data, nil
+}
+
+// find returns the products matching the filter in the order of their id, without the limit, and the titles of
+// their brands. Products whose brand is gone, or deleted unless the filter includes them, are left out like the
+// join of Repository leaves them out.
+func (m *Memory) find(ctx context.Context, filter dto.FilterProductDto) ([]memoryProduct, map[int]string, error) {
+	brands := map[int]string{}
+	found, err := m.brands.GetBrand(ctx, brandDto.FilterBrandDto{ID: filter.BrandId, IncludeDeleted: filter.IncludeDeleted})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, brand := range found {
+		brands[brand.ID] = brand.Title
+	}
+
+	var categoryIds []int
+	if filter.CategoryId > 0 {
+		categoryIds, err = m.descendants(ctx, filter.CategoryId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var products []memoryProduct
+	for _, product := range m.products {
+		if _, ok := brands[product.BrandId]; !ok {
+			continue
+		}
+		if filter.ID > 0 && product.ID != filter.ID {
+			continue
+		}
+		if filter.Title != "" && product.Title != filter.Title {
+			continue
+		}
+		if !filter.IncludeDeleted && product.DeletedAt != "" {
+			continue
+		}
+		if filter.CategoryId > 0 && !containsAny(product.CategoryIds, categoryIds) {
+			continue
+		}
+		products = append(products, product)
+	}
+
+	return products, brands, nil
+}
+
+// descendants returns the category and every category below it
+func (m *Memory) descendants(ctx context.Context, categoryId int) ([]int, error) {
+	categories, err := m.categories.GetCategories(ctx, categoryDto.FilterCategoryDto{})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{categoryId}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range categories {
+			if category.ParentId == ids[i] {
+				ids = append(ids, category.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// getCategories returns the categories of the products ordered like Repository orders them
+func (m *Memory) getCategories(ctx context.Context, products []memoryProduct) ([]model.Category, error) {
+	var ids []int
+	for _, product := range products {
+		ids = append(ids, product.CategoryIds...)
+	}
+	ids = uniqueIds(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	categories, err := m.categories.GetCategories(ctx, categoryDto.FilterCategoryDto{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Title < categories[j].Title
+	})
+	return categories, nil
+}
+
+func (m *Memory) CreateVariant(ctx context.Context, payload dto.InsertVariantDto) (*model.ProductVariant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var product *memoryProduct
+	for i := range m.products {
+		if m.products[i].ID == payload.ProductId {
+			product = &m.products[i]
+		}
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %d does not exist", payload.ProductId)
+	}
+	for _, variant := range m.variants {
+		if variant.Sku == payload.Sku {
+			return nil, fmt.Errorf("sku %s: %w", payload.Sku, util.ErrDuplicate)
+		}
+	}
+	for name := range payload.Options {
+		if !containsString(product.Options, name) {
+			return nil, fmt.Errorf("product %d has no option %s", payload.ProductId, name)
+		}
+	}
+
+	variant := model.ProductVariant{
+		ID:        len(m.variants) + 1,
+		ProductId: payload.ProductId,
+		Sku:       payload.Sku,
+		Price:     payload.Price,
+		Stock:     payload.Stock,
+		Options:   copyOptions(payload.Options),
+	}
+	m.variants = append(m.variants, variant)
+
+	after := map[string]interface{}{"productId": payload.ProductId, "sku": payload.Sku, "price": payload.Price, "stock": payload.Stock, "options": payload.Options}
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityVariant, EntityId: variant.ID, After: after})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductVariant{ID: variant.ID, ProductId: payload.ProductId, Sku: payload.Sku, Price: payload.Price, Stock: payload.Stock, Options: payload.Options}, nil
+}
+
+func (m *Memory) GetVariants(ctx context.Context, filter dto.FilterVariantDto) (data []model.ProductVariant, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, variant := range m.variants {
+		if filter.ID > 0 && variant.ID != filter.ID {
+			continue
+		}
+		if filter.Sku != "" && variant.Sku != filter.Sku {
+			continue
+		}
+		if len(filter.ProductIds) > 0 && !containsId(filter.ProductIds, variant.ProductId) {
+			continue
+		}
+		variant.Options = copyOptions(variant.Options)
+		data = append(data, variant)
+	}
+
+	return data, nil
+}
+
+// AdjustStock applies the adjustments at once, failing with ErrOutOfStock when a variant would go below zero
+func (m *Memory) AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stock := map[int]int{}
+	for _, adjustment := range adjustments {
+		i := m.variantIndex(adjustment.VariantId)
+		if i < 0 {
+			return ErrOutOfStock
+		}
+		if _, ok := stock[i]; !ok {
+			stock[i] = m.variants[i].Stock
+		}
+		stock[i] += adjustment.Qty
+		if stock[i] < 0 {
+			return ErrOutOfStock
+		}
+	}
+
+	for _, adjustment := range adjustments {
+		i := m.variantIndex(adjustment.VariantId)
+		m.variants[i].Stock += adjustment.Qty
+
+		err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityVariant, EntityId: adjustment.VariantId,
+			Before: map[string]interface{}{"stock": m.variants[i].Stock - adjustment.Qty}, After: map[string]interface{}{"stock": m.variants[i].Stock}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) variantIndex(id int) int {
+	for i, variant := range m.variants {
+		if variant.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ExportProducts passes every product matching the filter to fn, the lock is not held while fn runs
+func (m *Memory) ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(row dto.ExportProduct) error) error {
+	products, brands, err := m.find(ctx, dto.FilterProductDto{ID: filter.ID, BrandId: filter.BrandId, CategoryId: filter.CategoryId, IncludeDeleted: filter.IncludeDeleted})
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		row := dto.ExportProduct{
+			ID:          product.ID,
+			Title:       product.Title,
+			Description: product.Description,
+			BrandId:     product.BrandId,
+			Brand:       brands[product.BrandId],
+			Price:       product.Price,
+			Weight:      product.Weight,
+			Length:      product.Length,
+			Width:       product.Width,
+			Height:      product.Height,
+			CreatedAt:   product.CreatedAt,
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, id int) (bool, error) {
+	//formatted the way the datetime columns read back from the database
+	return m.setDeletedAt(ctx, model.AuditActionDelete, id, time.Now().UTC().Format(time.RFC3339))
+}
+
+func (m *Memory) Restore(ctx context.Context, id int) (bool, error) {
+	return m.setDeletedAt(ctx, model.AuditActionUpdate, id, "")
+}
+
+// setDeletedAt tells whether the product is there and its deletedAt changed, the change is recorded as action
+func (m *Memory) setDeletedAt(ctx context.Context, action string, id int, deletedAt string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.products {
+		if m.products[i].ID != id {
+			continue
+		}
+		previous := m.products[i].DeletedAt
+		if (previous == "") == (deletedAt == "") {
+			return false, nil
+		}
+		m.products[i].DeletedAt = deletedAt
+
+		change := auditDto.Change{Action: action, EntityType: model.AuditEntityProduct, EntityId: id,
+			Before: map[string]interface{}{"deletedAt": nullable(previous)}, After: map[string]interface{}{"deletedAt": nullable(deletedAt)}}
+		return true, m.audit.Record(ctx, nil, change)
+	}
+	return false, nil
+}
+
+// CreatePrice schedules a price of the product, the caller checks the product and the dates
+func (m *Memory) CreatePrice(ctx context.Context, payload dto.InsertPriceDto) (*model.ProductPrice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	price := model.ProductPrice{
+		ID:        len(m.prices) + 1,
+		ProductId: payload.ProductId,
+		Price:     payload.Price,
+		StartAt:   payload.StartAt,
+		EndAt:     payload.EndAt,
+		CreatedBy: payload.CreatedBy,
+		//formatted the way the datetime columns read back from the database
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	m.prices = append(m.prices, price)
+
+	after := map[string]interface{}{"productId": payload.ProductId, "price": payload.Price, "startAt": payload.StartAt, "endAt": nullable(payload.EndAt)}
+	err := m.audit.Record(ctx, nil, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityPrice, EntityId: price.ID, After: after})
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.outbox.Add(ctx, nil, priceChanged(price.ID, payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// GetPrices returns every price of the product in the order they start
+func (m *Memory) GetPrices(ctx context.Context, productId int) ([]model.ProductPrice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.productPrices(productId), nil
+}
+
+// productPrices returns a copy of the prices of the product ordered by start and id, the lock is held by the caller
+func (m *Memory) productPrices(productId int) []model.ProductPrice {
+	var prices []model.ProductPrice
+	for _, price := range m.prices {
+		if price.ProductId == productId {
+			prices = append(prices, price)
+		}
+	}
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].StartAt < prices[j].StartAt
+	})
+	return prices
+}
+
+// nullable is nil for an empty value, the way it is NULL in the database
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func uniqueOptions(options []string) error {
+	for i, name := range options {
+		if containsString(options[:i], name) {
+			return fmt.Errorf("option %s is given twice", name)
+		}
+	}
+	return nil
+}
+
+func uniqueIds(ids []int) []int {
+	var unique []int
+	for _, id := range ids {
+		if !containsId(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func containsId(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(ids []int, candidates []int) bool {
+	for _, id := range candidates {
+		if containsId(ids, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func copyOptions(options map[string]string) map[string]string {
+	copied := make(map[string]string, len(options))
+	for name, value := range options {
+		copied[name] = value
+	}
+	return copied
+}
@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	brandDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	brandRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
+	auditMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/audit/repository"
+	categoryMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/repository"
+	eventMocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/event/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestMemoryProduct(t *testing.T) {
+	ctx := context.TODO()
+	audit := new(auditMocks.AuditRepository)
+	audit.On("Record", mock.Anything, nil, mock.AnythingOfType("dto.Change")).Return(nil)
+	outbox := new(eventMocks.OutboxRepository)
+	outbox.On("Add", mock.Anything, nil, mock.AnythingOfType("dto.Event")).Return(nil)
+	brands := brandRepository.NewMemoryBrand(audit, outbox)
+	brands.Create(ctx, brandDto.InsertBrandDto{Title: "Nike"})
+	brands.Create(ctx, brandDto.InsertBrandDto{Title: "Adidas"})
+
+	shoes := model.Category{ID: 1, Title: "Shoes", Slug: "shoes", SortOrder: 1}
+	running := model.Category{ID: 2, ParentId: 1, Title: "Running", Slug: "running"}
+	categories := new(categoryMocks.CategoryRepository)
+	categories.On("GetCategories", mock.Anything, mock.AnythingOfType("dto.FilterCategoryDto")).Return([]model.Category{shoes, running}, nil)
+
+	r := repository.NewMemoryProduct(brands, categories, audit, outbox)
+
+	products, err := r.CreateMany(ctx, []dto.InsertProductDto{
+		{Title: "Pegasus", BrandId: 1, Price: 100, CategoryIds: []int{2, 1, 2}, Options: []string{"size"}},
+		{Title: "Ultraboost", BrandId: 2, Price: 180},
+		{Title: "Vomero", BrandId: 1, Price: 150},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, []int{products[0].ID, products[1].ID, products[2].ID})
+
+	t.Run("Test Create Many Is All Or Nothing", func(t *testing.T) {
+		_, err := r.CreateMany(ctx, []dto.InsertProductDto{{Title: "Invincible", BrandId: 1}, {Title: "Zoom", BrandId: 1, Options: []string{"size", "size"}}})
+		assert.NotNil(t, err)
+
+		result, err := r.GetProduct(ctx, dto.FilterProductDto{Title: "Invincible"})
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("Test Get Product With Filter", func(t *testing.T) {
+		result, err := r.GetProduct(ctx, dto.FilterProductDto{BrandId: 1, Limit: 1})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Pegasus", result[0].Title)
+		assert.Equal(t, dto.BrandDto{ID: 1, Title: "Nike"}, result[0].Brand)
+		assert.Equal(t, []dto.CategoryDto{{ID: 2, Title: "Running", Slug: "running"}, {ID: 1, Title: "Shoes", Slug: "shoes"}}, result[0].Categories)
+
+		result, err = r.GetProduct(ctx, dto.FilterProductDto{CategoryId: 1})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("Test Variants And Stock", func(t *testing.T) {
+		_, err := r.CreateVariant(ctx, dto.InsertVariantDto{ProductId: 1, Sku: "PEG-42", Stock: 10, Options: map[string]string{"color": "red"}})
+		assert.NotNil(t, err)
+
+		variant, err := r.CreateVariant(ctx, dto.InsertVariantDto{ProductId: 1, Sku: "PEG-42", Stock: 10, Options: map[string]string{"size": "42"}})
+		assert.Nil(t, err)
+		_, err = r.CreateVariant(ctx, dto.InsertVariantDto{ProductId: 1, Sku: "PEG-42", Options: map[string]string{"size": "43"}})
+		assert.NotNil(t, err)
+
+		//only as many as there is stock for get through
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		sold := 0
+		for i := 0; i < 15; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if r.AdjustStock(ctx, []dto.StockAdjustment{{VariantId: variant.ID, Qty: -1}}) == nil {
+					mu.Lock()
+					sold++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 10, sold)
+
+		err = r.AdjustStock(ctx, []dto.StockAdjustment{{VariantId: variant.ID, Qty: 2}, {VariantId: variant.ID + 1, Qty: -1}})
+		assert.Equal(t, repository.ErrOutOfStock, err)
+
+		result, err := r.GetProduct(ctx, dto.FilterProductDto{ID: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.VariantDto{{ID: variant.ID, Sku: "PEG-42", Price: 100, Stock: 0, Options: map[string]string{"size": "42"}}}, result[0].Variants)
+	})
+
+	t.Run("Test Export Products", func(t *testing.T) {
+		var titles []string
+		err := r.ExportProducts(ctx, dto.FilterProductDto{BrandId: 1}, func(row dto.ExportProduct) error {
+			assert.Equal(t, "Nike", row.Brand)
+			titles = append(titles, row.Title)
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Pegasus", "Vomero"}, titles)
+	})
+
+	t.Run("Test Scheduled Prices", func(t *testing.T) {
+		now := time.Now()
+		_, err := r.CreatePrice(ctx, dto.InsertPriceDto{ProductId: 2, Price: 200, StartAt: now.Add(time.Hour).Format(util.DateTimeLayout)})
+		assert.Nil(t, err)
+		_, err = r.CreatePrice(ctx, dto.InsertPriceDto{ProductId: 2, Price: 150, StartAt: now.Format(util.DateTimeLayout), EndAt: now.Add(time.Hour).Format(util.DateTimeLayout)})
+		assert.Nil(t, err)
+
+		result, err := r.GetProduct(ctx, dto.FilterProductDto{ID: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, float32(150), result[0].Price)
+
+		prices, err := r.GetPrices(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []float32{180, 150, 200}, []float32{prices[0].Price, prices[1].Price, prices[2].Price})
+		outbox.AssertCalled(t, "Add", ctx, nil, mock.MatchedBy(func(event eventDto.Event) bool {
+			return event.Type == model.EventProductPriceChanged && event.EntityId == 2 && event.Payload.(map[string]interface{})["price"] == float32(200)
+		}))
+	})
+
+	t.Run("Test Soft Delete", func(t *testing.T) {
+		deleted, err := r.Delete(ctx, 3)
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+
+		result, err := r.GetProduct(ctx, dto.FilterProductDto{BrandId: 1})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+
+		result, err = r.GetProduct(ctx, dto.FilterProductDto{ID: 3, IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.NotEmpty(t, result[0].DeletedAt)
+
+		restored, err := r.Restore(ctx, 3)
+		assert.Nil(t, err)
+		assert.True(t, restored)
+
+		//the products of a deleted brand are left out with it
+		brands.Delete(ctx, 2)
+		defer brands.Restore(ctx, 2)
+
+		result, err = r.GetProduct(ctx, dto.FilterProductDto{Title: "Ultraboost"})
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+
+		result, err = r.GetProduct(ctx, dto.FilterProductDto{Title: "Ultraboost", IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+	})
+}
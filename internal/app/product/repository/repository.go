@@ -3,7 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	auditDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/dto"
+	auditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	eventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
@@ -11,38 +21,150 @@ import (
 
 type ProductRepository interface {
 	Create(ctx context.Context, payload dto.InsertProductDto) (*model.Product, error)
+	CreateMany(ctx context.Context, payloads []dto.InsertProductDto) ([]model.Product, error)
 	GetProduct(ctx context.Context, filter dto.FilterProductDto) (data []dto.GetProduct, err error)
+	CreateVariant(ctx context.Context, payload dto.InsertVariantDto) (*model.ProductVariant, error)
+	GetVariants(ctx context.Context, filter dto.FilterVariantDto) (data []model.ProductVariant, err error)
+	AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error
+	ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(row dto.ExportProduct) error) error
+	Delete(ctx context.Context, id int) (bool, error)
+	Restore(ctx context.Context, id int) (bool, error)
+	CreatePrice(ctx context.Context, payload dto.InsertPriceDto) (*model.ProductPrice, error)
+	GetPrices(ctx context.Context, productId int) (data []model.ProductPrice, err error)
 }
 
+// ErrOutOfStock is returned when a stock adjustment would take a variant below zero
+var ErrOutOfStock = errors.New("Variant is out of stock")
+
 type Repository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Dialect dialect.Dialect
+	Audit   auditRepository.AuditRepository
+	Outbox  eventRepository.OutboxRepository
 }
 
-func NewProduct(db *sql.DB) *Repository {
-	return &Repository{db}
+func NewProduct(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect, auditRepository.NewAudit(db, dialect), eventRepository.NewOutbox(db, dialect)}
 }
 
 func (p *Repository) Create(ctx context.Context, payload dto.InsertProductDto) (*model.Product, error) {
 
-	query := `INSERT INTO product (title, description, brandId, price, createdAt, updatedAt) values(?, ?, ?, ?, NOW(), NOW())`
-	result, err := p.DB.ExecContext(ctx, query, payload.Title, payload.Description, payload.BrandId, payload.Price)
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := p.insertProduct(ctx, tx, payload)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
 
-	var id int64
-	id, err = result.LastInsertId()
+	return &model.Product{ID: id}, nil
+}
+
+// CreateMany creates every product or none of them
+func (p *Repository) CreateMany(ctx context.Context, payloads []dto.InsertProductDto) ([]model.Product, error) {
+
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]model.Product, len(payloads))
+	for i, payload := range payloads {
+		products[i].ID, err = p.insertProduct(ctx, tx, payload)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.Product{ID: int(id)}, nil
+	return products, nil
 }
+
+// insertProduct writes the product with its categories and options and records it, the caller owns the transaction
+func (p *Repository) insertProduct(ctx context.Context, tx *sql.Tx, payload dto.InsertProductDto) (int, error) {
+
+	//PROCESS PRODUCT
+	query := `INSERT INTO product (title, description, brandId, price, weight, length, width, height, createdAt, updatedAt)
+	values(?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := p.Dialect.Insert(ctx, tx, query, payload.Title, payload.Description, payload.BrandId, payload.Price,
+		payload.Weight, payload.Length, payload.Width, payload.Height)
+	if err != nil {
+		return 0, err
+	}
+	//END OF PROCESS PRODUCT
+
+	//PROCESS PRODUCT PRICE, the price history starts with the price of the product
+	query = `INSERT INTO product_price (productId, price, startAt, createdBy, createdAt) values(?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	_, err = tx.ExecContext(ctx, p.Dialect.Rebind(query), id, payload.Price, time.Now().Format(util.DateTimeLayout), util.ActorOf(ctx))
+	if err != nil {
+		return 0, err
+	}
+	//END OF PROCESS PRODUCT PRICE
+
+	//PROCESS PRODUCT CATEGORY
+	if len(payload.CategoryIds) > 0 {
+		var (
+			placeholders []string
+			categories   []interface{}
+		)
+		for _, categoryId := range payload.CategoryIds {
+			placeholders = append(placeholders, "(?,?)")
+			categories = append(categories, id, categoryId)
+		}
+
+		query = fmt.Sprintf("INSERT INTO product_category (productId, categoryId) VALUES %s", strings.Join(placeholders, ","))
+		_, err = tx.ExecContext(ctx, p.Dialect.Rebind(p.Dialect.InsertIgnore(query)), categories...)
+		if err != nil {
+			return 0, err
+		}
+	}
+	//END OF PROCESS PRODUCT CATEGORY
+
+	//PROCESS PRODUCT OPTION
+	if len(payload.Options) > 0 {
+		var (
+			placeholders []string
+			options      []interface{}
+		)
+		for i, name := range payload.Options {
+			placeholders = append(placeholders, "(?,?,?)")
+			options = append(options, id, name, i)
+		}
+
+		query = fmt.Sprintf("INSERT INTO product_option (productId, name, sortOrder) VALUES %s", strings.Join(placeholders, ","))
+		_, err = tx.ExecContext(ctx, p.Dialect.Rebind(query), options...)
+		if err != nil {
+			return 0, err
+		}
+	}
+	//END OF PROCESS PRODUCT OPTION
+
+	err = p.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityProduct, EntityId: int(id), After: payload})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
 func (p *Repository) GetProduct(ctx context.Context, filter dto.FilterProductDto) (data []dto.GetProduct, err error) {
 	var filterValues []interface{}
 	query := `SELECT product.id, product.title, product.description, 
 	product.brandId, brand.title as brandTitle,
-	product.price 
+	product.price, product.weight, product.length, product.width, product.height, product.deletedAt
 	FROM product
 	JOIN brand ON product.brandId = brand.id
 	`
@@ -53,7 +175,7 @@ func (p *Repository) GetProduct(ctx context.Context, filter dto.FilterProductDto
 	}
 
 	if filter.Title != "" {
-		query += util.FilterHandler(filterValues) + ` title = ?`
+		query += util.FilterHandler(filterValues) + ` product.title = ?`
 		filterValues = append(filterValues, filter.Title)
 	}
 
@@ -62,7 +184,17 @@ func (p *Repository) GetProduct(ctx context.Context, filter dto.FilterProductDto
 		filterValues = append(filterValues, filter.BrandId)
 	}
 
-	query += ` ORDER BY id `
+	//products of the category or any of its descendants
+	if filter.CategoryId > 0 {
+		query += util.FilterHandler(filterValues) + ` product.id IN (SELECT product_category.productId FROM product_category
+		JOIN category_path ON category_path.descendantId = product_category.categoryId
+		WHERE category_path.ancestorId = ?)`
+		filterValues = append(filterValues, filter.CategoryId)
+	}
+
+	query += notDeleted(filter, filterValues)
+
+	query += ` ORDER BY product.id `
 
 	if filter.Limit > 0 {
 		filterValues = append(filterValues, filter.Limit)
@@ -70,15 +202,14 @@ func (p *Repository) GetProduct(ctx context.Context, filter dto.FilterProductDto
 	}
 
 	var rows *sql.Rows
-	rows, err = p.DB.QueryContext(ctx, query, filterValues...)
+	rows, err = p.DB.QueryContext(ctx, p.Dialect.Rebind(query), filterValues...)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
-
 	for rows.Next() {
 		Repository := dto.GetProduct{}
+		var deletedAt sql.NullString
 		err = rows.Scan(
 			&Repository.ID,
 			&Repository.Title,
@@ -86,10 +217,568 @@ func (p *Repository) GetProduct(ctx context.Context, filter dto.FilterProductDto
 			&Repository.Brand.ID,
 			&Repository.Brand.Title,
 			&Repository.Price,
+			&Repository.Weight,
+			&Repository.Length,
+			&Repository.Width,
+			&Repository.Height,
+			&deletedAt,
 		)
+		Repository.DeletedAt = deletedAt.String
 
 		data = append(data, Repository)
 	}
+	//closed before the categories and variants are read, open rows keep their connection busy
+	rows.Close()
+
+	err = p.loadCategories(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	//before the variants, which inherit the price
+	err = p.loadPrices(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.loadVariants(ctx, data)
+	if err != nil {
+		return nil, err
+	}
 
 	return data, nil
 }
+
+// notDeleted leaves out the soft-deleted products and brands unless the filter includes them. It comes after
+// the other filters, FilterHandler tells WHERE from AND by the values before it.
+func notDeleted(filter dto.FilterProductDto, filterValues []interface{}) string {
+	if filter.IncludeDeleted {
+		return ""
+	}
+	return util.FilterHandler(filterValues) + ` product.deletedAt IS NULL AND brand.deletedAt IS NULL`
+}
+
+// productIndex returns the placeholders and values to query the given products by id, and the positions of every product id
+func productIndex(products []dto.GetProduct) (string, []interface{}, map[int][]int) {
+	var (
+		placeholders []string
+		values       []interface{}
+	)
+	index := map[int][]int{}
+	for i, product := range products {
+		if _, ok := index[product.ID]; !ok {
+			placeholders = append(placeholders, "?")
+			values = append(values, product.ID)
+		}
+		index[product.ID] = append(index[product.ID], i)
+	}
+	return strings.Join(placeholders, ","), values, index
+}
+
+func (p *Repository) loadCategories(ctx context.Context, products []dto.GetProduct) error {
+	if len(products) == 0 {
+		return nil
+	}
+	placeholders, values, index := productIndex(products)
+
+	query := fmt.Sprintf(`SELECT product_category.productId, category.id, category.title, category.slug
+	FROM product_category
+	JOIN category ON category.id = product_category.categoryId
+	WHERE product_category.productId IN (%s)
+	ORDER BY category.sortOrder, category.title`, placeholders)
+	rows, err := p.DB.QueryContext(ctx, p.Dialect.Rebind(query), values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productId int
+		category := dto.CategoryDto{}
+		err = rows.Scan(&productId, &category.ID, &category.Title, &category.Slug)
+		if err != nil {
+			return err
+		}
+		for _, i := range index[productId] {
+			products[i].Categories = append(products[i].Categories, category)
+		}
+	}
+
+	return nil
+}
+
+// loadPrices sets the price of the products to the scheduled price in effect now, the one started last like
+// ActivePrice chooses it. Products without one keep their own price.
+func (p *Repository) loadPrices(ctx context.Context, products []dto.GetProduct) error {
+	if len(products) == 0 {
+		return nil
+	}
+	placeholders, values, index := productIndex(products)
+	now := time.Now().Format(util.DateTimeLayout)
+	values = append(values, now, now)
+
+	query := fmt.Sprintf(`SELECT productId, price FROM product_price
+	WHERE productId IN (%s) AND startAt <= ? AND (endAt IS NULL OR endAt > ?)
+	ORDER BY startAt, id`, placeholders)
+	rows, err := p.DB.QueryContext(ctx, p.Dialect.Rebind(query), values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			productId int
+			price     float32
+		)
+		err = rows.Scan(&productId, &price)
+		if err != nil {
+			return err
+		}
+		for _, i := range index[productId] {
+			products[i].Price = price
+		}
+	}
+
+	return rows.Err()
+}
+
+func (p *Repository) loadVariants(ctx context.Context, products []dto.GetProduct) error {
+	if len(products) == 0 {
+		return nil
+	}
+	placeholders, values, index := productIndex(products)
+
+	query := fmt.Sprintf(`SELECT productId, name FROM product_option WHERE productId IN (%s) ORDER BY sortOrder, id`, placeholders)
+	rows, err := p.DB.QueryContext(ctx, p.Dialect.Rebind(query), values...)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var (
+			productId int
+			name      string
+		)
+		err = rows.Scan(&productId, &name)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		for _, i := range index[productId] {
+			products[i].Options = append(products[i].Options, name)
+		}
+	}
+	rows.Close()
+
+	var productIds []int
+	for id := range index {
+		productIds = append(productIds, id)
+	}
+	sort.Ints(productIds)
+
+	variants, err := p.GetVariants(ctx, dto.FilterVariantDto{ProductIds: productIds})
+	if err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		for _, i := range index[variant.ProductId] {
+			price := variant.Price
+			if price == 0 {
+				price = products[i].Price
+			}
+			products[i].Variants = append(products[i].Variants, dto.VariantDto{
+				ID:      variant.ID,
+				Sku:     variant.Sku,
+				Price:   price,
+				Stock:   variant.Stock,
+				Options: variant.Options,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *Repository) CreateVariant(ctx context.Context, payload dto.InsertVariantDto) (*model.ProductVariant, error) {
+
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	//PROCESS VARIANT
+	query := `INSERT INTO product_variant (productId, sku, price, stock, createdAt, updatedAt)
+	values(?, ?, NULLIF(?, 0.0), ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := p.Dialect.Insert(ctx, tx, query, payload.ProductId, payload.Sku, payload.Price, payload.Stock)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	//END OF PROCESS VARIANT
+
+	//PROCESS VARIANT OPTION
+	names := make([]string, 0, len(payload.Options))
+	for name := range payload.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		query = `INSERT INTO product_variant_option (variantId, value, optionId)
+		VALUES (?, ?, (SELECT id FROM product_option WHERE productId = ? AND name = ?))`
+		_, err = tx.ExecContext(ctx, p.Dialect.Rebind(query), id, payload.Options[name], payload.ProductId, name)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	//END OF PROCESS VARIANT OPTION
+
+	after := map[string]interface{}{"productId": payload.ProductId, "sku": payload.Sku, "price": payload.Price, "stock": payload.Stock, "options": payload.Options}
+	err = p.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityVariant, EntityId: int(id), After: after})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductVariant{ID: int(id), ProductId: payload.ProductId, Sku: payload.Sku, Price: payload.Price, Stock: payload.Stock, Options: payload.Options}, nil
+}
+
+func (p *Repository) GetVariants(ctx context.Context, filter dto.FilterVariantDto) (data []model.ProductVariant, err error) {
+	var filterValues []interface{}
+	query := `SELECT product_variant.id, product_variant.productId, product_variant.sku, product_variant.price, product_variant.stock,
+	product_option.name, product_variant_option.value
+	FROM product_variant
+	LEFT JOIN product_variant_option ON product_variant_option.variantId = product_variant.id
+	LEFT JOIN product_option ON product_option.id = product_variant_option.optionId`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` product_variant.id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.Sku != "" {
+		query += util.FilterHandler(filterValues) + ` product_variant.sku = ?`
+		filterValues = append(filterValues, filter.Sku)
+	}
+
+	if len(filter.ProductIds) > 0 {
+		placeholders := make([]string, len(filter.ProductIds))
+		for i := range placeholders {
+			placeholders[i] = "?"
+		}
+		query += util.FilterHandler(filterValues) + fmt.Sprintf(` product_variant.productId IN (%s)`, strings.Join(placeholders, ","))
+		for _, id := range filter.ProductIds {
+			filterValues = append(filterValues, id)
+		}
+	}
+
+	query += ` ORDER BY product_variant.id, product_option.sortOrder`
+
+	var rows *sql.Rows
+	rows, err = p.DB.QueryContext(ctx, p.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	//a variant spans one row per option value
+	for rows.Next() {
+		var (
+			variant     model.ProductVariant
+			price       sql.NullFloat64
+			name, value sql.NullString
+		)
+		err = rows.Scan(&variant.ID, &variant.ProductId, &variant.Sku, &price, &variant.Stock, &name, &value)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 || data[len(data)-1].ID != variant.ID {
+			variant.Price = float32(price.Float64)
+			variant.Options = map[string]string{}
+			data = append(data, variant)
+		}
+		if name.Valid {
+			data[len(data)-1].Options[name.String] = value.String
+		}
+	}
+
+	return data, nil
+}
+
+// AdjustStock applies the adjustments at once, failing with ErrOutOfStock when a variant would go below zero
+func (p *Repository) AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error {
+
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, adjustment := range adjustments {
+		query := `UPDATE product_variant SET stock = stock + ?, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND stock + ? >= 0`
+		result, err := tx.ExecContext(ctx, p.Dialect.Rebind(query), adjustment.Qty, adjustment.VariantId, adjustment.Qty)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if affected == 0 {
+			tx.Rollback()
+			return ErrOutOfStock
+		}
+
+		var stock int
+		err = tx.QueryRowContext(ctx, p.Dialect.Rebind(`SELECT stock FROM product_variant WHERE id = ?`), adjustment.VariantId).Scan(&stock)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = p.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionUpdate, EntityType: model.AuditEntityVariant, EntityId: adjustment.VariantId,
+			Before: map[string]interface{}{"stock": stock - adjustment.Qty}, After: map[string]interface{}{"stock": stock}})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ExportProducts passes every product matching the filter to fn while reading them, without loading them all
+func (p *Repository) ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(row dto.ExportProduct) error) error {
+	var filterValues []interface{}
+	query := `SELECT product.id, product.title, product.description, product.brandId, brand.title as brandTitle,
+	product.price, product.weight, product.length, product.width, product.height, product.createdAt
+	FROM product
+	JOIN brand ON product.brandId = brand.id`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` product.id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.BrandId > 0 {
+		query += util.FilterHandler(filterValues) + ` brand.id = ?`
+		filterValues = append(filterValues, filter.BrandId)
+	}
+
+	if filter.CategoryId > 0 {
+		query += util.FilterHandler(filterValues) + ` product.id IN (SELECT product_category.productId FROM product_category
+		JOIN category_path ON category_path.descendantId = product_category.categoryId
+		WHERE category_path.ancestorId = ?)`
+		filterValues = append(filterValues, filter.CategoryId)
+	}
+
+	query += notDeleted(filter, filterValues)
+
+	query += ` ORDER BY product.id`
+
+	rows, err := p.DB.QueryContext(ctx, p.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row         dto.ExportProduct
+			description sql.NullString
+		)
+		err = rows.Scan(&row.ID, &row.Title, &description, &row.BrandId, &row.Brand, &row.Price,
+			&row.Weight, &row.Length, &row.Width, &row.Height, &row.CreatedAt)
+		if err != nil {
+			return err
+		}
+		row.Description = description.String
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Delete soft deletes the product, it tells whether the product was there and not deleted yet
+func (p *Repository) Delete(ctx context.Context, id int) (bool, error) {
+	query := `UPDATE product SET deletedAt = CURRENT_TIMESTAMP, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND deletedAt IS NULL`
+	return p.setDeletedAt(ctx, model.AuditActionDelete, query, id)
+}
+
+// Restore takes back the soft delete of the product, it tells whether the product was deleted
+func (p *Repository) Restore(ctx context.Context, id int) (bool, error) {
+	query := `UPDATE product SET deletedAt = NULL, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND deletedAt IS NOT NULL`
+	return p.setDeletedAt(ctx, model.AuditActionUpdate, query, id)
+}
+
+// setDeletedAt runs the query changing the deletedAt of the product and records it as action, it tells whether
+// the product changed
+func (p *Repository) setDeletedAt(ctx context.Context, action string, query string, id int) (bool, error) {
+
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	before, err := p.deletedAt(ctx, tx, id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, p.Dialect.Rebind(query), id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	after, err := p.deletedAt(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = p.Audit.Record(ctx, tx, auditDto.Change{Action: action, EntityType: model.AuditEntityProduct, EntityId: id, Before: before, After: after})
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// deletedAt reads the deletedAt of the product for the audit log, locking the product until the transaction ends
+func (p *Repository) deletedAt(ctx context.Context, tx *sql.Tx, id int) (map[string]interface{}, error) {
+	var deletedAt sql.NullString
+	err := tx.QueryRowContext(ctx, p.Dialect.Rebind(`SELECT deletedAt FROM product WHERE id = ?`+p.Dialect.ForUpdate()), id).Scan(&deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{"deletedAt": nil}
+	if deletedAt.Valid {
+		fields["deletedAt"] = deletedAt.String
+	}
+	return fields, nil
+}
+
+// CreatePrice schedules a price of the product, the caller checks the product and the dates
+func (p *Repository) CreatePrice(ctx context.Context, payload dto.InsertPriceDto) (*model.ProductPrice, error) {
+
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO product_price (productId, price, startAt, endAt, createdBy, createdAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	id, err := p.Dialect.Insert(ctx, tx, query, payload.ProductId, payload.Price, payload.StartAt,
+		sql.NullString{String: payload.EndAt, Valid: payload.EndAt != ""}, payload.CreatedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	after := map[string]interface{}{"productId": payload.ProductId, "price": payload.Price, "startAt": payload.StartAt, "endAt": nullable(payload.EndAt)}
+	err = p.Audit.Record(ctx, tx, auditDto.Change{Action: model.AuditActionCreate, EntityType: model.AuditEntityPrice, EntityId: int(id), After: after})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = p.Outbox.Add(ctx, tx, priceChanged(int(id), payload))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductPrice{ID: int(id), ProductId: payload.ProductId, Price: payload.Price, StartAt: payload.StartAt, EndAt: payload.EndAt, CreatedBy: payload.CreatedBy}, nil
+}
+
+// priceChanged tells about a price scheduled for the product, it takes effect from startAt
+func priceChanged(id int, payload dto.InsertPriceDto) eventDto.Event {
+	return eventDto.Event{Type: model.EventProductPriceChanged, EntityId: payload.ProductId, Payload: map[string]interface{}{
+		"productId": payload.ProductId, "priceId": id, "price": payload.Price, "startAt": payload.StartAt, "endAt": nullable(payload.EndAt)}}
+}
+
+// GetPrices returns every price of the product in the order they start
+func (p *Repository) GetPrices(ctx context.Context, productId int) (data []model.ProductPrice, err error) {
+	query := `SELECT id, productId, price, startAt, endAt, createdBy, createdAt FROM product_price WHERE productId = ? ORDER BY startAt, id`
+	rows, err := p.DB.QueryContext(ctx, p.Dialect.Rebind(query), productId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			price model.ProductPrice
+			endAt sql.NullString
+		)
+		err = rows.Scan(&price.ID, &price.ProductId, &price.Price, &price.StartAt, &endAt, &price.CreatedBy, &price.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		price.EndAt = endAt.String
+
+		data = append(data, price)
+	}
+
+	return data, rows.Err()
+}
+
+// ActivePrice returns the index of the price in effect at now, the one started last or of these the last one,
+// and -1 when none is and the product sells at its own price. prices are in the order of GetPrices.
+func ActivePrice(prices []model.ProductPrice, now time.Time) int {
+	active := -1
+	var activeStart time.Time
+	for i, price := range prices {
+		startAt, err := util.ParseDateTime(price.StartAt)
+		if err != nil || now.Before(startAt) {
+			continue
+		}
+		if price.EndAt != "" {
+			endAt, err := util.ParseDateTime(price.EndAt)
+			if err != nil || !now.Before(endAt) {
+				continue
+			}
+		}
+		if active < 0 || !startAt.Before(activeStart) {
+			active, activeStart = i, startAt
+		}
+	}
+	return active
+}
@@ -3,13 +3,18 @@ package repository_test
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
 
 func TestGetProduct(t *testing.T) {
@@ -20,27 +25,33 @@ func TestGetProduct(t *testing.T) {
 
 	query := `SELECT product.id, product.title, product.description, 
 		product.brandId, brand.title as brandTitle,
-		product.price 
+		product.price, product.weight, product.length, product.width, product.height, product.deletedAt
 		FROM product
 		JOIN brand ON product.brandId = brand.id
 		`
 
+	queryCategory := `SELECT product_category.productId, category.id, category.title, category.slug`
+	queryPrice := regexp.QuoteMeta(`SELECT productId, price FROM product_price
+	WHERE productId IN (?) AND startAt <= ? AND (endAt IS NULL OR endAt > ?)`)
+	queryOption := `SELECT productId, name FROM product_option`
+	queryVariant := `SELECT product_variant.id, product_variant.productId, product_variant.sku, product_variant.price, product_variant.stock`
+
 	mockProduct := []dto.GetProduct{}
 	mockProduct = append(mockProduct, dto.GetProduct{ID: 1, Title: "Nike Airmax", Description: "Sepatu Nike", Brand: dto.BrandDto{ID: 1, Title: "Nike"}, Price: 2000000})
 	mockProduct = append(mockProduct, dto.GetProduct{ID: 1, Title: "Adidas Duramo", Description: "Sepatu Adidas", Brand: dto.BrandDto{ID: 2, Title: "Adidas"}, Price: 1500000})
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "brandId", "brandTitle", "price"}).
-		AddRow(mockProduct[0].ID, mockProduct[0].Title, mockProduct[0].Description, mockProduct[0].Brand.ID, mockProduct[0].Brand.Title, mockProduct[0].Price).
-		AddRow(mockProduct[1].ID, mockProduct[1].Title, mockProduct[1].Description, mockProduct[1].Brand.ID, mockProduct[1].Brand.Title, mockProduct[0].Price)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "brandId", "brandTitle", "price", "weight", "length", "width", "height", "deletedAt"}).
+		AddRow(mockProduct[0].ID, mockProduct[0].Title, mockProduct[0].Description, mockProduct[0].Brand.ID, mockProduct[0].Brand.Title, mockProduct[0].Price, 1000, 30, 20, 12, nil).
+		AddRow(mockProduct[1].ID, mockProduct[1].Title, mockProduct[1].Description, mockProduct[1].Brand.ID, mockProduct[1].Brand.Title, mockProduct[0].Price, 1000, 30, 20, 12, nil)
 
 	reset := func() {
 		mockProduct = []dto.GetProduct{}
 		mockProduct = append(mockProduct, dto.GetProduct{ID: 1, Title: "Nike Airmax", Description: "Sepatu Nike", Brand: dto.BrandDto{ID: 1, Title: "Nike"}, Price: 2000000})
 		mockProduct = append(mockProduct, dto.GetProduct{ID: 1, Title: "Adidas Duramo", Description: "Sepatu Adidas", Brand: dto.BrandDto{ID: 2, Title: "Adidas"}, Price: 1500000})
 
-		sqlmock.NewRows([]string{"id", "title", "description", "brandId", "brandTitle", "price"}).
-			AddRow(mockProduct[0].ID, mockProduct[0].Title, mockProduct[0].Description, mockProduct[0].Brand.ID, mockProduct[0].Brand.Title, mockProduct[0].Price).
-			AddRow(mockProduct[1].ID, mockProduct[1].Title, mockProduct[1].Description, mockProduct[1].Brand.ID, mockProduct[1].Brand.Title, mockProduct[0].Price)
+		sqlmock.NewRows([]string{"id", "title", "description", "brandId", "brandTitle", "price", "weight", "length", "width", "height", "deletedAt"}).
+			AddRow(mockProduct[0].ID, mockProduct[0].Title, mockProduct[0].Description, mockProduct[0].Brand.ID, mockProduct[0].Brand.Title, mockProduct[0].Price, 1000, 30, 20, 12, nil).
+			AddRow(mockProduct[1].ID, mockProduct[1].Title, mockProduct[1].Description, mockProduct[1].Brand.ID, mockProduct[1].Brand.Title, mockProduct[0].Price, 1000, 30, 20, 12, nil)
 	}
 
 	t.Run("Test Product Without Filter", func(t *testing.T) {
@@ -48,12 +59,51 @@ func TestGetProduct(t *testing.T) {
 		defer reset()
 
 		mock.ExpectQuery(query).WillReturnRows(rows)
-		r := repository.NewProduct(db)
+		mock.ExpectQuery(queryCategory).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"productId", "id", "title", "slug"}).AddRow(1, 3, "Sneakers", "sneakers"))
+		mock.ExpectQuery(queryPrice).WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"productId", "price"}).
+			AddRow(1, 2000000).
+			AddRow(1, 1800000))
+		mock.ExpectQuery(queryOption).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"productId", "name"}).AddRow(1, "size"))
+		mock.ExpectQuery(queryVariant).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "productId", "sku", "price", "stock", "name", "value"}).
+			AddRow(4, 1, "AIRMAX-42", nil, 3, "size", "42").
+			AddRow(5, 1, "AIRMAX-43", 2100000, 0, "size", "43"))
+		r := repository.NewProduct(db, dialect.MySQL)
 		filter := dto.FilterProductDto{}
 		result, err := r.GetProduct(context.TODO(), filter)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
+		assert.Equal(t, []dto.CategoryDto{{ID: 3, Title: "Sneakers", Slug: "sneakers"}}, result[0].Categories)
+		assert.Equal(t, result[0].Categories, result[1].Categories)
+		assert.Equal(t, []string{"size"}, result[0].Options)
+		//the price started last is in effect, the variant without a price inherits it
+		assert.Equal(t, float32(1800000), result[0].Price)
+		assert.Equal(t, []dto.VariantDto{
+			{ID: 4, Sku: "AIRMAX-42", Price: 1800000, Stock: 3, Options: map[string]string{"size": "42"}},
+			{ID: 5, Sku: "AIRMAX-43", Price: 2100000, Stock: 0, Options: map[string]string{"size": "43"}},
+		}, result[0].Variants)
+	})
+
+	t.Run("Test Product By Category Includes Descendants", func(t *testing.T) {
+
+		defer reset()
+
+		categoryRows := sqlmock.NewRows([]string{"id", "title", "description", "brandId", "brandTitle", "price", "weight", "length", "width", "height", "deletedAt"}).
+			AddRow(2, "Nike Pegasus", "Sepatu Lari", 1, "Nike", 1800000, 800, 30, 20, 12, nil)
+
+		mock.ExpectQuery(query + regexp.QuoteMeta(` WHERE product.id IN (SELECT product_category.productId FROM product_category
+		JOIN category_path ON category_path.descendantId = product_category.categoryId
+		WHERE category_path.ancestorId = ?)`)).WithArgs(5).WillReturnRows(categoryRows)
+		mock.ExpectQuery(queryCategory).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"productId", "id", "title", "slug"}))
+		mock.ExpectQuery(queryPrice).WithArgs(2, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"productId", "price"}))
+		mock.ExpectQuery(queryOption).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"productId", "name"}))
+		mock.ExpectQuery(queryVariant).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "productId", "sku", "price", "stock", "name", "value"}))
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.GetProduct(context.TODO(), dto.FilterProductDto{CategoryId: 5})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Nil(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Test Product With Filter", func(t *testing.T) {
@@ -67,8 +117,9 @@ func TestGetProduct(t *testing.T) {
 			Limit:   1,
 		}
 
-		mock.ExpectQuery(query).WithArgs(filter.ID, filter.Title, filter.BrandId, filter.Limit).WillReturnRows(rows)
-		r := repository.NewProduct(db)
+		mock.ExpectQuery(query+`WHERE product.id = (.+) AND product.title = (.+) AND brand.id = (.+) AND product.deletedAt IS NULL AND brand.deletedAt IS NULL`).
+			WithArgs(filter.ID, filter.Title, filter.BrandId, filter.Limit).WillReturnRows(rows)
+		r := repository.NewProduct(db, dialect.MySQL)
 
 		_, err := r.GetProduct(context.TODO(), filter)
 
@@ -80,7 +131,7 @@ func TestGetProduct(t *testing.T) {
 		defer reset()
 
 		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
-		r := repository.NewProduct(db)
+		r := repository.NewProduct(db, dialect.MySQL)
 		filter := dto.FilterProductDto{}
 		_, err := r.GetProduct(context.TODO(), filter)
 
@@ -106,21 +157,409 @@ func TestCreateProduct(t *testing.T) {
 
 	t.Run("Test Create Product Success", func(t *testing.T) {
 
-		mock.ExpectExec(query).WithArgs(payload.Title, payload.Description, payload.BrandId, payload.Price).WillReturnResult(sqlmock.NewResult(1, 1))
-		r := repository.NewProduct(db)
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(payload.Title, payload.Description, payload.BrandId, payload.Price, payload.Weight, payload.Length, payload.Width, payload.Height).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_price").WithArgs(1, payload.Price, sqlmock.AnyArg(), util.SystemActor).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityProduct, 1, nil,
+			`{"brandId":1,"categoryIds":null,"description":"Sepatu Nike","height":0,"length":0,"options":null,"price":1250000,"title":"Nike Airmax","weight":0,"width":0}`, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
 		result, err := r.Create(context.TODO(), payload)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Product With Categories", func(t *testing.T) {
+
+		categorized := payload
+		categorized.CategoryIds = []int{3, 5}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec("INSERT INTO product_price").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec("INSERT IGNORE INTO product_category").WithArgs(2, 3, 2, 5).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), categorized)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.ID)
+	})
+
+	t.Run("Test Create Product With Options", func(t *testing.T) {
+
+		withOptions := payload
+		withOptions.Options = []string{"size", "colour"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec("INSERT INTO product_price").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec("INSERT INTO product_option").WithArgs(3, "size", 0, 3, "colour", 1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), withOptions)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.ID)
 	})
 
 	t.Run("Test Create Product Error Database", func(t *testing.T) {
 
-		mock.ExpectExec(query).WithArgs(payload.Title, payload.Description, payload.BrandId, payload.Price).WillReturnError(errors.New("Database Error"))
-		r := repository.NewProduct(db)
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(payload.Title, payload.Description, payload.BrandId, payload.Price, payload.Weight, payload.Length, payload.Width, payload.Height).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+		r := repository.NewProduct(db, dialect.MySQL)
 		result, err := r.Create(context.TODO(), payload)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, result)
 	})
 }
+
+func TestCreateManyProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO product"
+
+	payloads := []dto.InsertProductDto{
+		{Title: "Nike Airmax", BrandId: 1, Price: 1250000},
+		{Title: "Nike Pegasus", BrandId: 1, Price: 1500000, CategoryIds: []int{3}},
+	}
+
+	t.Run("Test Create Many Products Success", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs("Nike Airmax", "", 1, float32(1250000), 0, 0, 0, 0).WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("INSERT INTO product_price").WithArgs(4, float32(1250000), sqlmock.AnyArg(), util.SystemActor).WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec(query).WithArgs("Nike Pegasus", "", 1, float32(1500000), 0, 0, 0, 0).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec("INSERT INTO product_price").WithArgs(5, float32(1500000), sqlmock.AnyArg(), util.SystemActor).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec("INSERT IGNORE INTO product_category").WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreateMany(context.TODO(), payloads)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Product{{ID: 4}, {ID: 5}}, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Many Products Rolls Back Everything", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("INSERT INTO product_price").WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreateMany(context.TODO(), payloads)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateVariant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertVariantDto{ProductId: 1, Sku: "TSHIRT-M-RED", Stock: 10, Options: map[string]string{"size": "M", "colour": "Red"}}
+
+	t.Run("Test Create Variant Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO product_variant").WithArgs(1, "TSHIRT-M-RED", float32(0), 10).WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("INSERT INTO product_variant_option").WithArgs(4, "Red", 1, "colour").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO product_variant_option").WithArgs(4, "M", 1, "size").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityVariant, 4, nil,
+			`{"options":{"colour":"Red","size":"M"},"price":0,"productId":1,"sku":"TSHIRT-M-RED","stock":10}`, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreateVariant(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 4, result.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Variant Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO product_variant").WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreateVariant(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetVariants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := `SELECT product_variant.id, product_variant.productId, product_variant.sku, product_variant.price, product_variant.stock`
+
+	t.Run("Test Get Variants By Sku", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "productId", "sku", "price", "stock", "name", "value"}).
+			AddRow(4, 1, "TSHIRT-M-RED", 120000, 10, "size", "M").
+			AddRow(4, 1, "TSHIRT-M-RED", 120000, 10, "colour", "Red")
+		mock.ExpectQuery(query).WithArgs("TSHIRT-M-RED").WillReturnRows(rows)
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.GetVariants(context.TODO(), dto.FilterVariantDto{Sku: "TSHIRT-M-RED"})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, float32(120000), result[0].Price)
+		assert.Equal(t, map[string]string{"size": "M", "colour": "Red"}, result[0].Options)
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE product_variant SET stock"
+	adjustments := []dto.StockAdjustment{{VariantId: 4, Qty: 2}, {VariantId: 5, Qty: -1}}
+
+	t.Run("Test Adjust Stock Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(2, 4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT stock FROM product_variant").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(12))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityVariant, 4,
+			`{"stock":10}`, `{"stock":12}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(query).WithArgs(-1, 5, -1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT stock FROM product_variant").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(0))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionUpdate, model.AuditEntityVariant, 5,
+			`{"stock":1}`, `{"stock":0}`, "").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		err := r.AdjustStock(context.TODO(), adjustments)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Adjust Stock Below Zero", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(2, 4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT stock FROM product_variant").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(12))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(query).WithArgs(-1, 5, -1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		err := r.AdjustStock(context.TODO(), adjustments)
+
+		assert.Equal(t, repository.ErrOutOfStock, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExportProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	columns := []string{"id", "title", "description", "brandId", "brandTitle", "price", "weight", "length", "width", "height", "createdAt"}
+
+	t.Run("Test Export Products Streams Rows", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "Nike Airmax", nil, 1, "Nike", 1250000, 800, 30, 20, 12, "2026-10-19 10:00:00").
+			AddRow(2, "Nike Pegasus", "Running", 1, "Nike", 1500000, 700, 30, 20, 12, "2026-10-19 11:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM product JOIN brand ON product.brandId = brand.id WHERE brand.id = ? AND product.deletedAt IS NULL AND brand.deletedAt IS NULL ORDER BY product.id`)).WithArgs(1).WillReturnRows(rows)
+
+		var exported []dto.ExportProduct
+		r := repository.NewProduct(db, dialect.MySQL)
+		err := r.ExportProducts(context.TODO(), dto.FilterProductDto{BrandId: 1}, func(row dto.ExportProduct) error {
+			exported = append(exported, row)
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.Len(t, exported, 2)
+		assert.Equal(t, "", exported[0].Description)
+		assert.Equal(t, "Nike", exported[1].Brand)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Export Products Error", func(t *testing.T) {
+		mock.ExpectQuery("FROM product").WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewProduct(db, dialect.MySQL)
+		err := r.ExportProducts(context.TODO(), dto.FilterProductDto{}, func(row dto.ExportProduct) error { return nil })
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	selectQuery := "SELECT deletedAt FROM product WHERE id = (.+) FOR UPDATE"
+
+	t.Run("Test Delete Product Success", func(t *testing.T) {
+		query := "UPDATE product SET deletedAt = CURRENT_TIMESTAMP, updatedAt = CURRENT_TIMESTAMP WHERE id = (.+) AND deletedAt IS NULL"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow(nil))
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow("2026-10-19 10:00:00"))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionDelete, model.AuditEntityProduct, 1,
+			`{"deletedAt":null}`, `{"deletedAt":"2026-10-19 10:00:00"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		deleted, err := r.Delete(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Restore Product Not Deleted", func(t *testing.T) {
+		query := "UPDATE product SET deletedAt = NULL, updatedAt = CURRENT_TIMESTAMP WHERE id = (.+) AND deletedAt IS NOT NULL"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"deletedAt"}).AddRow(nil))
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		r := repository.NewProduct(db, dialect.MySQL)
+		restored, err := r.Restore(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.False(t, restored)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreatePrice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertPriceDto{ProductId: 1, Price: 99000, StartAt: "2026-11-11 00:00:00", EndAt: "2026-11-12 00:00:00", CreatedBy: "merchandiser"}
+	query := "INSERT INTO product_price"
+
+	t.Run("Test Create Price Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(1, payload.Price, payload.StartAt, payload.EndAt, payload.CreatedBy).WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityPrice, 7, nil,
+			`{"endAt":"2026-11-12 00:00:00","price":99000,"productId":1,"startAt":"2026-11-11 00:00:00"}`, "").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WithArgs(model.EventProductPriceChanged, 1,
+			`{"endAt":"2026-11-12 00:00:00","price":99000,"priceId":7,"productId":1,"startAt":"2026-11-11 00:00:00"}`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreatePrice(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 7, result.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Price Without End", func(t *testing.T) {
+		permanent := payload
+		permanent.EndAt = ""
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(1, payload.Price, payload.StartAt, nil, payload.CreatedBy).WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(util.SystemActor, model.AuditActionCreate, model.AuditEntityPrice, 8, nil,
+			`{"endAt":null,"price":99000,"productId":1,"startAt":"2026-11-11 00:00:00"}`, "").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec("INSERT INTO outbox_event").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreatePrice(context.TODO(), permanent)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 8, result.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Price Error Database", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+		mock.ExpectRollback()
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.CreatePrice(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPrices(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta("SELECT id, productId, price, startAt, endAt, createdBy, createdAt FROM product_price WHERE productId = ? ORDER BY startAt, id")
+
+	t.Run("Test Get Prices Success", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "productId", "price", "startAt", "endAt", "createdBy", "createdAt"}).
+			AddRow(1, 1, 120000, "2026-10-01 00:00:00", nil, "system", "2026-10-01 00:00:00").
+			AddRow(2, 1, 99000, "2026-11-11 00:00:00", "2026-11-12 00:00:00", "merchandiser", "2026-10-19 10:00:00"))
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.GetPrices(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "", result[0].EndAt)
+		assert.Equal(t, "2026-11-12 00:00:00", result[1].EndAt)
+	})
+
+	t.Run("Test Get Prices Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+		r := repository.NewProduct(db, dialect.MySQL)
+		result, err := r.GetPrices(context.TODO(), 1)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestActivePrice(t *testing.T) {
+	prices := []model.ProductPrice{
+		{ID: 1, Price: 120000, StartAt: "2026-10-01 00:00:00"},
+		{ID: 2, Price: 130000, StartAt: "2026-12-01 00:00:00"},
+		{ID: 3, Price: 99000, StartAt: "2026-11-11 00:00:00", EndAt: "2026-11-12 00:00:00"},
+	}
+	at := func(value string) time.Time {
+		t, _ := util.ParseDateTime(value)
+		return t
+	}
+
+	assert.Equal(t, -1, repository.ActivePrice(prices, at("2026-09-30 23:59:59")))
+	assert.Equal(t, 0, repository.ActivePrice(prices, at("2026-10-01 00:00:00")))
+	assert.Equal(t, 2, repository.ActivePrice(prices, at("2026-11-11 12:00:00")))
+	//the end is not part of the period
+	assert.Equal(t, 0, repository.ActivePrice(prices, at("2026-11-12 00:00:00")))
+	assert.Equal(t, 1, repository.ActivePrice(prices, at("2026-12-24 00:00:00")))
+}
@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestProductSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		seed := []string{
+			`INSERT INTO brand (title, createdAt, updatedAt) VALUES ('Nike', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO category (parentId, title, slug, sortOrder, createdAt, updatedAt) VALUES (NULL, 'Shoes', 'shoes', 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO category (parentId, title, slug, sortOrder, createdAt, updatedAt) VALUES (1, 'Running', 'running', 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			`INSERT INTO category_path (ancestorId, descendantId, depth) VALUES (1, 1, 0), (2, 2, 0), (1, 2, 1)`,
+		}
+		for _, query := range seed {
+			_, err := db.Exec(d.Rebind(query))
+			assert.Nil(t, err)
+		}
+
+		r := repository.NewProduct(db, d)
+		ctx := context.TODO()
+
+		product, err := r.Create(ctx, dto.InsertProductDto{Title: "Pegasus", Description: "Road", BrandId: 1, Price: 120,
+			Weight: 300, CategoryIds: []int{2, 2}, Options: []string{"size", "color"}})
+		assert.Nil(t, err)
+
+		t.Run("Test Get Product By Ancestor Category", func(t *testing.T) {
+			result, err := r.GetProduct(ctx, dto.FilterProductDto{CategoryId: 1})
+
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, product.ID, result[0].ID)
+			assert.Equal(t, dto.BrandDto{ID: 1, Title: "Nike"}, result[0].Brand)
+			assert.Equal(t, []dto.CategoryDto{{ID: 2, Title: "Running", Slug: "running"}}, result[0].Categories)
+			assert.Equal(t, []string{"size", "color"}, result[0].Options)
+		})
+
+		t.Run("Test Create Variant", func(t *testing.T) {
+			variant, err := r.CreateVariant(ctx, dto.InsertVariantDto{ProductId: product.ID, Sku: "PEG-42-RED", Stock: 2,
+				Options: map[string]string{"size": "42", "color": "red"}})
+			assert.Nil(t, err)
+			_, err = r.CreateVariant(ctx, dto.InsertVariantDto{ProductId: product.ID, Sku: "PEG-43-RED", Price: 130, Stock: 1,
+				Options: map[string]string{"size": "43", "color": "red"}})
+			assert.Nil(t, err)
+
+			result, err := r.GetProduct(ctx, dto.FilterProductDto{Title: "Pegasus"})
+
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, []dto.VariantDto{
+				{ID: variant.ID, Sku: "PEG-42-RED", Price: 120, Stock: 2, Options: map[string]string{"size": "42", "color": "red"}},
+				{ID: variant.ID + 1, Sku: "PEG-43-RED", Price: 130, Stock: 1, Options: map[string]string{"size": "43", "color": "red"}},
+			}, result[0].Variants)
+		})
+
+		t.Run("Test Adjust Stock", func(t *testing.T) {
+			err := r.AdjustStock(ctx, []dto.StockAdjustment{{VariantId: 1, Qty: -1}, {VariantId: 2, Qty: -2}})
+			assert.Equal(t, repository.ErrOutOfStock, err)
+
+			err = r.AdjustStock(ctx, []dto.StockAdjustment{{VariantId: 1, Qty: -2}, {VariantId: 2, Qty: 3}})
+			assert.Nil(t, err)
+
+			variants, err := r.GetVariants(ctx, dto.FilterVariantDto{ProductIds: []int{product.ID}})
+			assert.Nil(t, err)
+			assert.Equal(t, 0, variants[0].Stock)
+			assert.Equal(t, 4, variants[1].Stock)
+		})
+
+		t.Run("Test Create Many And Export", func(t *testing.T) {
+			products, err := r.CreateMany(ctx, []dto.InsertProductDto{{Title: "Vomero", BrandId: 1, Price: 150}, {Title: "Invincible", BrandId: 1, Price: 180}})
+			assert.Nil(t, err)
+			assert.Len(t, products, 2)
+
+			var titles []string
+			err = r.ExportProducts(ctx, dto.FilterProductDto{BrandId: 1}, func(row dto.ExportProduct) error {
+				assert.NotEmpty(t, row.CreatedAt)
+				titles = append(titles, row.Title)
+				return nil
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"Pegasus", "Vomero", "Invincible"}, titles)
+		})
+
+		t.Run("Test Scheduled Prices", func(t *testing.T) {
+			now := time.Now()
+			schedule := []dto.InsertPriceDto{
+				{Price: 50, StartAt: now.Add(-48 * time.Hour).Format(util.DateTimeLayout), EndAt: now.Add(-24 * time.Hour).Format(util.DateTimeLayout)},
+				{Price: 100, StartAt: now.Format(util.DateTimeLayout), EndAt: now.Add(time.Hour).Format(util.DateTimeLayout)},
+				{Price: 150, StartAt: now.Add(24 * time.Hour).Format(util.DateTimeLayout)},
+			}
+			for _, price := range schedule {
+				price.ProductId = product.ID
+				price.CreatedBy = "merchandiser"
+				_, err := r.CreatePrice(ctx, price)
+				assert.Nil(t, err)
+			}
+
+			//the sale is in effect, over the price the product was created with
+			result, err := r.GetProduct(ctx, dto.FilterProductDto{ID: product.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, float32(100), result[0].Price)
+			assert.Equal(t, float32(100), result[0].Variants[0].Price)
+			assert.Equal(t, float32(130), result[0].Variants[1].Price)
+
+			prices, err := r.GetPrices(ctx, product.ID)
+			assert.Nil(t, err)
+			assert.Len(t, prices, 4)
+			assert.Equal(t, float32(50), prices[0].Price)
+			assert.Equal(t, float32(120), prices[1].Price)
+			assert.Equal(t, 2, repository.ActivePrice(prices, now))
+		})
+
+		t.Run("Test Soft Delete", func(t *testing.T) {
+			deleted, err := r.Delete(ctx, product.ID)
+			assert.Nil(t, err)
+			assert.True(t, deleted)
+			deleted, err = r.Delete(ctx, product.ID)
+			assert.Nil(t, err)
+			assert.False(t, deleted)
+
+			result, err := r.GetProduct(ctx, dto.FilterProductDto{ID: product.ID})
+			assert.Nil(t, err)
+			assert.Len(t, result, 0)
+
+			result, err = r.GetProduct(ctx, dto.FilterProductDto{ID: product.ID, IncludeDeleted: true})
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.NotEmpty(t, result[0].DeletedAt)
+
+			restored, err := r.Restore(ctx, product.ID)
+			assert.Nil(t, err)
+			assert.True(t, restored)
+
+			//the products of a deleted brand are left out with it
+			_, err = db.Exec(d.Rebind(`UPDATE brand SET deletedAt = CURRENT_TIMESTAMP WHERE id = ?`), 1)
+			assert.Nil(t, err)
+
+			result, err = r.GetProduct(ctx, dto.FilterProductDto{})
+			assert.Nil(t, err)
+			assert.Len(t, result, 0)
+
+			result, err = r.GetProduct(ctx, dto.FilterProductDto{IncludeDeleted: true})
+			assert.Nil(t, err)
+			assert.Len(t, result, 3)
+			assert.Empty(t, result[0].DeletedAt)
+		})
+	})
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const MaxImportRows = 1000
+
+// ImportRowTimeout is added to the context timeout for every row of an atomic import, which creates
+// all of them in one transaction
+const ImportRowTimeout = 50 * time.Millisecond
+
+// ImportColumns are the accepted CSV header names, categoryIds and options hold values separated by |
+var ImportColumns = []string{"title", "description", "brandId", "brand", "price", "weight", "length", "width", "height", "categoryIds", "options"}
+
+const (
+	importValid   = "valid"
+	importInvalid = "invalid"
+	importCreated = "created"
+	importFailed  = "failed"
+)
+
+// importRow is a parsed row, Err is set when the row itself could not be read
+type importRow struct {
+	Line int
+	Row  dto.ImportRow
+	Err  error
+}
+
+// Import validates every row like a single create, resolving brands by id or title, then creates the
+// products according to the mode. Lookups and writes get their own time budget as imports can be large.
+func (s *Service) Import(ctx context.Context, data io.Reader, options dto.ImportOptions) (*dto.ImportReport, error, string) {
+	var (
+		rows []importRow
+		err  error
+	)
+	switch options.Format {
+	case dto.ImportFormatCSV:
+		rows, err = parseImportCSV(data)
+	case dto.ImportFormatNDJSON:
+		rows, err = parseImportNDJSON(data)
+	default:
+		err = fmt.Errorf("Import format %s is not supported", options.Format)
+	}
+	if err != nil {
+		return nil, err, util.VALIDATION_ERROR
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("Import has no rows"), util.VALIDATION_ERROR
+	}
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("Import must not have more than %d rows", MaxImportRows), util.VALIDATION_ERROR
+	}
+
+	report := &dto.ImportReport{Mode: options.Mode, DryRun: options.DryRun, Total: len(rows), Rows: make([]dto.ImportRowResult, len(rows))}
+	validate := validator.New()
+	brands := map[string]brandLookup{}
+
+	//PROCESS VALIDATION
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Line, result.Title, result.Status = row.Line, row.Row.Title, importValid
+
+		if row.Err != nil {
+			result.Status, result.Errors = importInvalid, []string{row.Err.Error()}
+			continue
+		}
+
+		brandId, err, state := s.importBrand(ctx, row.Row, brands)
+		if state == util.SYSTEM_ERROR {
+			return nil, err, state
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		rows[i].Row.BrandId = brandId
+
+		if err = validate.Struct(&rows[i].Row.InsertProductDto); err != nil {
+			var fields validator.ValidationErrors
+			if !errors.As(err, &fields) {
+				return nil, err, util.SYSTEM_ERROR
+			}
+			for _, field := range fields {
+				//a missing brand is already reported by the lookup
+				if field.Field() != "BrandId" || brandId > 0 {
+					result.Errors = append(result.Errors, field.Error())
+				}
+			}
+		}
+
+		if len(row.Row.CategoryIds) > 0 {
+			_, err, state = s.categoryService.CheckCategories(ctx, row.Row.CategoryIds)
+			if state == util.SYSTEM_ERROR {
+				return nil, err, state
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			result.Status = importInvalid
+		} else {
+			report.Valid++
+		}
+	}
+	//END OF PROCESS VALIDATION
+
+	if options.Mode == dto.ImportModeAtomic && report.Valid < report.Total {
+		return report, fmt.Errorf("%d of %d rows are invalid, nothing was imported", report.Total-report.Valid, report.Total), util.VALIDATION_ERROR
+	}
+	if options.DryRun || report.Valid == 0 {
+		return report, nil, util.SUCCESS
+	}
+
+	//PROCESS IMPORT
+	if options.Mode == dto.ImportModeAtomic {
+		payloads := make([]dto.InsertProductDto, len(rows))
+		for i, row := range rows {
+			payloads[i] = row.Row.InsertProductDto
+		}
+
+		createCtx, cancel := context.WithTimeout(ctx, s.contextTimeout+time.Duration(len(payloads))*ImportRowTimeout)
+		products, err := s.productRepository.CreateMany(createCtx, payloads)
+		cancel()
+		if err != nil {
+			for i := range report.Rows {
+				report.Rows[i].Status = importFailed
+			}
+			report.Failed = report.Total
+			return report, err, util.SYSTEM_ERROR
+		}
+
+		for i, product := range products {
+			report.Rows[i].Status, report.Rows[i].ID = importCreated, product.ID
+			s.notifyChange(ctx, product.ID)
+		}
+		report.Created = len(products)
+		return report, nil, util.SUCCESS
+	}
+
+	for i, row := range rows {
+		result := &report.Rows[i]
+		if result.Status != importValid {
+			continue
+		}
+
+		createCtx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+		product, err := s.productRepository.Create(createCtx, row.Row.InsertProductDto)
+		cancel()
+		if err != nil {
+			result.Status, result.Errors = importFailed, []string{err.Error()}
+			report.Failed++
+			continue
+		}
+
+		result.Status, result.ID = importCreated, product.ID
+		report.Created++
+		s.notifyChange(ctx, product.ID)
+	}
+	//END OF PROCESS IMPORT
+
+	return report, nil, util.SUCCESS
+}
+
+// brandLookup is a cached brand resolution, err is set when the brand doesn't exist
+type brandLookup struct {
+	id  int
+	err error
+}
+
+// importBrand resolves the brand of a row by id, or by title when no id is given, caching the lookups
+func (s *Service) importBrand(ctx context.Context, row dto.ImportRow, brands map[string]brandLookup) (int, error, string) {
+	title := strings.TrimSpace(row.Brand)
+	key := "id:" + strconv.Itoa(row.BrandId)
+	if row.BrandId == 0 {
+		if title == "" {
+			return 0, errors.New("brandId or brand is required"), util.VALIDATION_ERROR
+		}
+		key = "title:" + strings.ToLower(title)
+	}
+	if lookup, ok := brands[key]; ok {
+		if lookup.err != nil {
+			return 0, lookup.err, util.NOT_FOUND
+		}
+		return lookup.id, nil, util.SUCCESS
+	}
+
+	var (
+		brand *model.Brand
+		err   error
+		state string
+	)
+	if row.BrandId > 0 {
+		brand, err, state = s.brandService.CheckBrandById(ctx, row.BrandId)
+	} else {
+		brand, err, state = s.brandService.CheckBrandByTitle(ctx, title)
+	}
+	if err != nil {
+		if state == util.NOT_FOUND {
+			brands[key] = brandLookup{err: err}
+		}
+		return 0, err, state
+	}
+
+	brands[key] = brandLookup{id: brand.ID}
+	return brand.ID, nil, util.SUCCESS
+}
+
+func parseImportCSV(data io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(data)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]string{}
+	for _, column := range ImportColumns {
+		known[strings.ToLower(column)] = column
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		column, ok := known[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("Unknown column %q, the columns are %s", name, strings.Join(ImportColumns, ", "))
+		}
+		columns[i] = column
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			rows = append(rows, importRow{Line: line, Err: fmt.Errorf("Row has %d columns instead of %d", len(record), len(columns))})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := importRow{Line: line}
+		row.Err = parseCSVRecord(columns, record, &row.Row)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseCSVRecord(columns []string, record []string, row *dto.ImportRow) error {
+	for i, column := range columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch column {
+		case "title":
+			row.Title = value
+		case "description":
+			row.Description = value
+		case "brand":
+			row.Brand = value
+		case "brandId":
+			row.BrandId, err = strconv.Atoi(value)
+		case "price":
+			var price float64
+			price, err = strconv.ParseFloat(value, 32)
+			row.Price = float32(price)
+		case "weight":
+			row.Weight, err = strconv.Atoi(value)
+		case "length":
+			row.Length, err = strconv.Atoi(value)
+		case "width":
+			row.Width, err = strconv.Atoi(value)
+		case "height":
+			row.Height, err = strconv.Atoi(value)
+		case "categoryIds":
+			for _, item := range splitList(value) {
+				var id int
+				if id, err = strconv.Atoi(item); err != nil {
+					break
+				}
+				row.CategoryIds = append(row.CategoryIds, id)
+			}
+		case "options":
+			row.Options = splitList(value)
+		}
+		if err != nil {
+			return fmt.Errorf("%s must be a number", column)
+		}
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseImportNDJSON reads one product object per line, blank lines are skipped
+func parseImportNDJSON(data io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []importRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := importRow{Line: line}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.Row); err != nil {
+			row.Err = fmt.Errorf("Invalid JSON: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	BrandDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	ProductService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const importCSV = `title,brand,brandId,price,categoryIds,options
+Nike Airmax,Nike,,1250000,,size|colour
+Nike Pegasus,nike,,1500000,,
+Basic T-Shirt,,1,99000,,
+`
+
+func TestImport(t *testing.T) {
+	atomic := dto.ImportOptions{Format: dto.ImportFormatCSV, Mode: dto.ImportModeAtomic}
+	bestEffort := dto.ImportOptions{Format: dto.ImportFormatCSV, Mode: dto.ImportModeBestEffort}
+
+	brands := func() {
+		mockBrandRepository.On("GetBrand", mock.Anything, BrandDto.FilterBrandDto{Title: "Nike", Limit: 1}).Return([]model.Brand{{ID: 2, Title: "Nike"}}, nil).Once()
+		mockBrandRepository.On("GetBrand", mock.Anything, BrandDto.FilterBrandDto{ID: 1, Limit: 1}).Return([]model.Brand{{ID: 1, Title: "Uniqlo"}}, nil).Once()
+	}
+
+	t.Run("Test Import Atomic Success", func(t *testing.T) {
+		defer reset()
+		brands()
+		mockProductRepository.On("CreateMany", mock.Anything, []dto.InsertProductDto{
+			{Title: "Nike Airmax", BrandId: 2, Price: 1250000, Options: []string{"size", "colour"}},
+			{Title: "Nike Pegasus", BrandId: 2, Price: 1500000},
+			{Title: "Basic T-Shirt", BrandId: 1, Price: 99000},
+		}).Run(func(args mock.Arguments) {
+			//the transaction gets more time than a single create
+			deadline, ok := args.Get(0).(context.Context).Deadline()
+			assert.True(t, ok)
+			assert.Greater(t, time.Until(deadline), contextTimeout+2*ProductService.ImportRowTimeout)
+		}).Return([]model.Product{{ID: 10}, {ID: 11}, {ID: 12}}, nil)
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(importCSV), atomic)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, res.Created)
+		assert.Equal(t, dto.ImportRowResult{Line: 3, Title: "Nike Pegasus", Status: "created", ID: 11}, res.Rows[1])
+		//the brand title is looked up once
+		mockBrandRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Import Atomic Invalid Row Imports Nothing", func(t *testing.T) {
+		defer reset()
+		brands()
+		data := importCSV + "Mystery Shoes,Unknown,,100000,,\n,,1,abc,,\n"
+		mockBrandRepository.On("GetBrand", mock.Anything, BrandDto.FilterBrandDto{Title: "Unknown", Limit: 1}).Return(nil, nil)
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(data), atomic)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Equal(t, 5, res.Total)
+		assert.Equal(t, 3, res.Valid)
+		assert.Equal(t, "invalid", res.Rows[3].Status)
+		assert.Equal(t, []string{"Brand Unknown Doesn't exists"}, res.Rows[3].Errors)
+		assert.Equal(t, []string{"price must be a number"}, res.Rows[4].Errors)
+		mockProductRepository.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Import Dry Run", func(t *testing.T) {
+		defer reset()
+		brands()
+		options := atomic
+		options.DryRun = true
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(importCSV), options)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, res.Valid)
+		assert.Equal(t, 0, res.Created)
+		assert.Equal(t, "valid", res.Rows[0].Status)
+		mockProductRepository.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Import Best Effort", func(t *testing.T) {
+		defer reset()
+		brands()
+		data := importCSV + ",,1,,,\n"
+		mockProductRepository.On("Create", mock.Anything, mock.MatchedBy(func(p dto.InsertProductDto) bool { return p.Title == "Nike Airmax" })).Return(&model.Product{ID: 10}, nil)
+		mockProductRepository.On("Create", mock.Anything, mock.MatchedBy(func(p dto.InsertProductDto) bool { return p.Title == "Nike Pegasus" })).Return(nil, errors.New("Database Error"))
+		mockProductRepository.On("Create", mock.Anything, mock.MatchedBy(func(p dto.InsertProductDto) bool { return p.Title == "Basic T-Shirt" })).Return(&model.Product{ID: 12}, nil)
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(data), bestEffort)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, res.Created)
+		assert.Equal(t, 1, res.Failed)
+		assert.Equal(t, "failed", res.Rows[1].Status)
+		assert.Equal(t, "invalid", res.Rows[3].Status)
+		assert.Len(t, res.Rows[3].Errors, 2)
+	})
+
+	t.Run("Test Import NDJSON", func(t *testing.T) {
+		defer reset()
+		data := `{"title":"Basic T-Shirt","brandId":1,"price":99000,"categoryIds":[4]}
+
+{"title":"Polo","brand":"Nike","price":"cheap"}
+{"title":"Polo","brandName":"Nike","price":150000}
+`
+		mockBrandRepository.On("GetBrand", mock.Anything, BrandDto.FilterBrandDto{ID: 1, Limit: 1}).Return([]model.Brand{{ID: 1}}, nil)
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return([]model.Category{{ID: 4}}, nil)
+		options := dto.ImportOptions{Format: dto.ImportFormatNDJSON, Mode: dto.ImportModeBestEffort, DryRun: true}
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(data), options)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, res.Total)
+		assert.Equal(t, 1, res.Valid)
+		assert.Equal(t, 3, res.Rows[1].Line)
+		assert.Equal(t, "invalid", res.Rows[2].Status)
+	})
+
+	t.Run("Test Import Unknown Column", func(t *testing.T) {
+		defer reset()
+
+		_, err, state := productService.Import(context.TODO(), strings.NewReader("title,colour\nShirt,Red\n"), atomic)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Import Empty", func(t *testing.T) {
+		defer reset()
+
+		_, err, state := productService.Import(context.TODO(), strings.NewReader("title,brandId,price\n"), atomic)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Import Brand Lookup Error", func(t *testing.T) {
+		defer reset()
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := productService.Import(context.TODO(), strings.NewReader(importCSV), bestEffort)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
@@ -3,9 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
 	"time"
 
 	brandService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
+	categoryService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/service"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
@@ -15,22 +20,81 @@ type ProductService interface {
 	Create(ctx context.Context, dto dto.InsertProductDto) (interface{}, error, string)
 	GetProductById(ctx context.Context, id int) (*dto.GetProduct, error, string)
 	GetProductByBrand(ctx context.Context, brandId int) (interface{}, error, string)
+	GetProductByCategory(ctx context.Context, categoryId int) (interface{}, error, string)
+	CreateVariant(ctx context.Context, productId int, payload dto.InsertVariantDto) (interface{}, error, string)
+	AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error
+	Import(ctx context.Context, data io.Reader, options dto.ImportOptions) (*dto.ImportReport, error, string)
+	ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(row dto.ExportProduct) error) (error, string)
+	GetProducts(ctx context.Context, filter dto.FilterProductDto) ([]dto.GetProduct, error, string)
+	Delete(ctx context.Context, id int) (interface{}, error, string)
+	Restore(ctx context.Context, id int) (interface{}, error, string)
+	CreatePrice(ctx context.Context, productId int, payload dto.InsertPriceDto) (interface{}, error, string)
+	GetPrices(ctx context.Context, productId int) ([]dto.PriceDto, error, string)
+	RegisterImageLoader(loader ImageLoader)
+	RegisterChangeHandler(handler ChangeHandler)
 }
 
+// ImageLoader returns the images of every product, keyed by product id
+type ImageLoader func(ctx context.Context, productIds []int) (map[int][]dto.ImageDto, error)
+
+// ChangeHandler is notified after a product is created or changed
+type ChangeHandler func(ctx context.Context, productId int) error
+
 type Service struct {
 	productRepository repository.ProductRepository
 	brandService      brandService.BrandService
+	categoryService   categoryService.CategoryService
+	imageLoader       ImageLoader
+	changeHandlers    []ChangeHandler
 	contextTimeout    time.Duration
 }
 
-func NewProductService(repository repository.ProductRepository, brandService brandService.BrandService, timeout time.Duration) ProductService {
+func NewProductService(repository repository.ProductRepository, brandService brandService.BrandService, categoryService categoryService.CategoryService, timeout time.Duration) ProductService {
 	return &Service{
 		productRepository: repository,
 		brandService:      brandService,
+		categoryService:   categoryService,
 		contextTimeout:    timeout,
 	}
 }
 
+func (s *Service) RegisterImageLoader(loader ImageLoader) {
+	s.imageLoader = loader
+}
+
+func (s *Service) RegisterChangeHandler(handler ChangeHandler) {
+	s.changeHandlers = append(s.changeHandlers, handler)
+}
+
+// notifyChange runs the change handlers, the change is already stored so their errors are only logged
+func (s *Service) notifyChange(ctx context.Context, productId int) {
+	for _, handler := range s.changeHandlers {
+		if err := handler(ctx, productId); err != nil {
+			log.Printf("failed to run change handler for product %d: %s", productId, err.Error())
+		}
+	}
+}
+
+// attachImages fills the images of the products when an image loader is registered
+func (s *Service) attachImages(ctx context.Context, products []dto.GetProduct) error {
+	if s.imageLoader == nil || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	images, err := s.imageLoader(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+	}
+	return nil
+}
+
 func (s *Service) Create(ctx context.Context, payload dto.InsertProductDto) (interface{}, error, string) {
 	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
 	defer cancel()
@@ -41,10 +105,20 @@ func (s *Service) Create(ctx context.Context, payload dto.InsertProductDto) (int
 		return nil, err, state
 	}
 
+	//check categories exist
+	if len(payload.CategoryIds) > 0 {
+		_, err, state = s.categoryService.CheckCategories(ctx, payload.CategoryIds)
+		if err != nil {
+			return nil, err, state
+		}
+	}
+
 	result, err := s.productRepository.Create(ctx, payload)
 	if err != nil {
 		return nil, err, "SYSTEM_ERROR"
 	}
+
+	s.notifyChange(ctx, result.ID)
 	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
 }
 
@@ -65,6 +139,10 @@ func (s *Service) GetProductById(ctx context.Context, id int) (*dto.GetProduct,
 
 	}
 
+	if err = s.attachImages(ctx, result[:1]); err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
 	data = &result[0]
 	return data, nil, util.SUCCESS
 }
@@ -84,5 +162,227 @@ func (s *Service) GetProductByBrand(ctx context.Context, brandId int) (interface
 		return nil, errors.New("Product Not Found"), util.NOT_FOUND
 	}
 
+	if err = s.attachImages(ctx, result); err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return result, nil, util.SUCCESS
+}
+
+// GetProducts returns every product matching the filter, without the images
+func (s *Service) GetProducts(ctx context.Context, filter dto.FilterProductDto) ([]dto.GetProduct, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	result, err := s.productRepository.GetProduct(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	return result, nil, util.SUCCESS
+}
+
+// ExportProducts streams the products to fn. It runs without the service timeout as an export lasts as long
+// as the client keeps reading it.
+func (s *Service) ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(row dto.ExportProduct) error) (error, string) {
+	err := s.productRepository.ExportProducts(ctx, filter, fn)
+	if err != nil {
+		return err, util.SYSTEM_ERROR
+	}
+	return nil, util.SUCCESS
+}
+
+// GetProductByCategory returns the products of the category and of all its descendants
+func (s *Service) GetProductByCategory(ctx context.Context, categoryId int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	filter := dto.FilterProductDto{CategoryId: categoryId}
+
+	result, err := s.productRepository.GetProduct(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("Product Not Found"), util.NOT_FOUND
+	}
+
+	if err = s.attachImages(ctx, result); err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
 	return result, nil, util.SUCCESS
 }
+
+// Delete soft deletes the product, it can no longer be ordered and orders keep referring to it
+func (s *Service) Delete(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	deleted, err := s.productRepository.Delete(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !deleted {
+		return nil, errors.New("Product Not Found"), util.NOT_FOUND
+	}
+
+	s.notifyChange(ctx, id)
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
+
+// Restore takes back the soft delete of the product, the brand of the product has to be restored first
+func (s *Service) Restore(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	products, err := s.productRepository.GetProduct(ctx, dto.FilterProductDto{ID: id, Limit: 1, IncludeDeleted: true})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(products) == 0 {
+		return nil, errors.New("Product Not Found"), util.NOT_FOUND
+	}
+	if products[0].DeletedAt == "" {
+		return nil, errors.New("Product is not deleted"), util.INVALID_STATE
+	}
+
+	_, err, state := s.brandService.CheckBrandById(ctx, products[0].Brand.ID)
+	if state == util.NOT_FOUND {
+		return nil, fmt.Errorf("Brand %d of the product is deleted, restore it first", products[0].Brand.ID), util.INVALID_STATE
+	}
+	if err != nil {
+		return nil, err, state
+	}
+
+	restored, err := s.productRepository.Restore(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !restored {
+		return nil, errors.New("Product is not deleted"), util.INVALID_STATE
+	}
+
+	s.notifyChange(ctx, id)
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
+
+func (s *Service) CreateVariant(ctx context.Context, productId int, payload dto.InsertVariantDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	product, err, state := s.GetProductById(ctx, productId)
+	if err != nil {
+		return nil, err, state
+	}
+
+	//a variant sets exactly one value for every option type of the product
+	if len(payload.Options) != len(product.Options) {
+		return nil, fmt.Errorf("Variant must set the options %v", product.Options), util.VALIDATION_ERROR
+	}
+	for _, name := range product.Options {
+		if _, ok := payload.Options[name]; !ok {
+			return nil, fmt.Errorf("Variant must set the options %v", product.Options), util.VALIDATION_ERROR
+		}
+	}
+	for _, variant := range product.Variants {
+		if reflect.DeepEqual(variant.Options, payload.Options) {
+			return nil, fmt.Errorf("Variant with the same options already Exists as %s", variant.Sku), util.DUPLICATE
+		}
+	}
+
+	//Check Variant By Sku
+	variants, err := s.productRepository.GetVariants(ctx, dto.FilterVariantDto{Sku: payload.Sku})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(variants) > 0 {
+		return nil, errors.New("Sku already Exists"), util.DUPLICATE
+	}
+
+	payload.ProductId = productId
+	result, err := s.productRepository.CreateVariant(ctx, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+
+	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
+}
+
+// AdjustStock adds or takes out stock of variants, all adjustments are applied or none
+func (s *Service) AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	return s.productRepository.AdjustStock(ctx, adjustments)
+}
+
+// CreatePrice schedules a price of the product, from now when it has no start. A price with an end, like a flash
+// sale, applies over the prices started before it until it ends.
+func (s *Service) CreatePrice(ctx context.Context, productId int, payload dto.InsertPriceDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	_, err, state := s.GetProductById(ctx, productId)
+	if err != nil {
+		return nil, err, state
+	}
+
+	//the history only grows forward, a price cannot start in the past
+	now := time.Now()
+	if payload.StartAt == "" {
+		payload.StartAt = now.Format(util.DateTimeLayout)
+	}
+	startAt, err := util.ParseDateTime(payload.StartAt)
+	if err != nil {
+		return nil, err, util.VALIDATION_ERROR
+	}
+	if startAt.Before(now.Truncate(time.Second)) {
+		return nil, errors.New("startAt must not be in the past"), util.VALIDATION_ERROR
+	}
+	if payload.EndAt != "" && payload.EndAt <= payload.StartAt {
+		return nil, errors.New("endAt must be after startAt"), util.VALIDATION_ERROR
+	}
+
+	payload.ProductId = productId
+	result, err := s.productRepository.CreatePrice(ctx, payload)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	s.notifyChange(ctx, productId)
+	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
+}
+
+// GetPrices returns the price history of the product, deleted or not, with the prices scheduled ahead
+func (s *Service) GetPrices(ctx context.Context, productId int) ([]dto.PriceDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	products, err := s.productRepository.GetProduct(ctx, dto.FilterProductDto{ID: productId, Limit: 1, IncludeDeleted: true})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(products) == 0 {
+		return nil, errors.New("Product Not Found"), util.NOT_FOUND
+	}
+
+	prices, err := s.productRepository.GetPrices(ctx, productId)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	active := repository.ActivePrice(prices, time.Now())
+	data := []dto.PriceDto{}
+	for i, price := range prices {
+		data = append(data, dto.PriceDto{
+			ID:        price.ID,
+			Price:     price.Price,
+			StartAt:   price.StartAt,
+			EndAt:     price.EndAt,
+			Active:    i == active,
+			CreatedBy: price.CreatedBy,
+			CreatedAt: price.CreatedAt,
+		})
+	}
+
+	return data, nil, util.SUCCESS
+}
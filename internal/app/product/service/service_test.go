@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/go-faker/faker/v4"
+	"github.com/go-faker/faker/v4/pkg/options"
 	BrandService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
+	CategoryService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/service"
 	dto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	ProductService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
 	mockBrandRepositores "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/brand/repository"
+	mockCategoryRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/repository"
 	mockProductRepositores "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/product/repository"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -20,12 +23,14 @@ import (
 const contextTimeout = 2 * time.Second
 
 var (
-	mockBrand             []model.Brand
-	mockProduct           []dto.GetProduct
-	mockProductRepository = new(mockProductRepositores.ProductRepository)
-	mockBrandRepository   = new(mockBrandRepositores.BrandRepository)
-	brandService          = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
-	productService        = ProductService.NewProductService(mockProductRepository, brandService, contextTimeout)
+	mockBrand              []model.Brand
+	mockProduct            []dto.GetProduct
+	mockProductRepository  = new(mockProductRepositores.ProductRepository)
+	mockBrandRepository    = new(mockBrandRepositores.BrandRepository)
+	mockCategoryRepository = new(mockCategoryRepositories.CategoryRepository)
+	brandService           = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
+	categoryService        = CategoryService.NewCategoryService(mockCategoryRepository, contextTimeout)
+	productService         = ProductService.NewProductService(mockProductRepository, brandService, categoryService, contextTimeout)
 )
 
 func reset() {
@@ -33,9 +38,11 @@ func reset() {
 	mockBrand = []model.Brand{}
 	mockProductRepository = new(mockProductRepositores.ProductRepository)
 	mockBrandRepository = new(mockBrandRepositores.BrandRepository)
+	mockCategoryRepository = new(mockCategoryRepositories.CategoryRepository)
 
 	brandService = BrandService.NewBrandService(mockBrandRepository, contextTimeout)
-	productService = ProductService.NewProductService(mockProductRepository, brandService, contextTimeout)
+	categoryService = CategoryService.NewCategoryService(mockCategoryRepository, contextTimeout)
+	productService = ProductService.NewProductService(mockProductRepository, brandService, categoryService, contextTimeout)
 
 }
 
@@ -43,7 +50,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Test Create Product Success", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockBrand)
+		err := faker.FakeData(&mockBrand, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		modelProduct := &model.Product{ID: 1}
@@ -68,7 +75,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Test Create Product Failed From Database", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockBrand)
+		err := faker.FakeData(&mockBrand, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		payload := dto.InsertProductDto{
@@ -110,6 +117,48 @@ func TestCreateProduct(t *testing.T) {
 		assert.Nil(t, res)
 	})
 
+	t.Run("Test Create Product With Categories", func(t *testing.T) {
+		defer reset()
+
+		payload := dto.InsertProductDto{
+			Title:       "Adidas",
+			BrandId:     1,
+			Price:       25000000,
+			CategoryIds: []int{3, 5},
+		}
+
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{{ID: 1}}, nil)
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return([]model.Category{{ID: 3}, {ID: 5}}, nil)
+		mockProductRepository.On("Create", mock.Anything, payload).Return(&model.Product{ID: 1}, nil)
+
+		res, err, state := productService.Create(context.TODO(), payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.NotNil(t, res)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Create Product Failed Category Not Found", func(t *testing.T) {
+		defer reset()
+
+		payload := dto.InsertProductDto{
+			Title:       "Adidas",
+			BrandId:     1,
+			Price:       25000000,
+			CategoryIds: []int{3, 5},
+		}
+
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{{ID: 1}}, nil)
+		mockCategoryRepository.On("GetCategories", mock.Anything, mock.Anything).Return([]model.Category{{ID: 3}}, nil)
+
+		res, err, state := productService.Create(context.TODO(), payload)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockProductRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
 }
 
 func TestProductGetById(t *testing.T) {
@@ -120,7 +169,7 @@ func TestProductGetById(t *testing.T) {
 	t.Run("Test Produt Get By ID Success", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockProduct)
+		err := faker.FakeData(&mockProduct, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		filter := dto.FilterProductDto{
@@ -165,7 +214,7 @@ func TestGetProductByBrand(t *testing.T) {
 	t.Run("Test Get Product By Brand Success", func(t *testing.T) {
 		defer reset()
 
-		err := faker.FakeData(&mockProduct)
+		err := faker.FakeData(&mockProduct, options.WithRandomMapAndSliceMinSize(1))
 		assert.NoError(t, err)
 
 		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(mockProduct, nil)
@@ -201,3 +250,413 @@ func TestGetProductByBrand(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+func TestGetProductByCategory(t *testing.T) {
+	filter := dto.FilterProductDto{CategoryId: 5}
+
+	t.Run("Test Get Product By Category Success", func(t *testing.T) {
+		defer reset()
+
+		err := faker.FakeData(&mockProduct, options.WithRandomMapAndSliceMinSize(1))
+		assert.NoError(t, err)
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(mockProduct, nil)
+
+		res, err, state := productService.GetProductByCategory(context.TODO(), filter.CategoryId)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.NotNil(t, res)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Get Product By Category Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(mockProduct, nil)
+
+		res, err, state := productService.GetProductByCategory(context.TODO(), filter.CategoryId)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCreateVariant(t *testing.T) {
+	product := func() []dto.GetProduct {
+		return []dto.GetProduct{{
+			ID:      1,
+			Title:   "Basic T-Shirt",
+			Price:   100000,
+			Options: []string{"size", "colour"},
+			Variants: []dto.VariantDto{
+				{ID: 4, Sku: "TSHIRT-M-RED", Options: map[string]string{"size": "M", "colour": "Red"}},
+			},
+		}}
+	}
+	payload := dto.InsertVariantDto{Sku: "TSHIRT-L-RED", Stock: 10, Options: map[string]string{"size": "L", "colour": "Red"}}
+
+	t.Run("Test Create Variant Success", func(t *testing.T) {
+		defer reset()
+		expected := payload
+		expected.ProductId = 1
+
+		mockProductRepository.On("GetProduct", mock.Anything, dto.FilterProductDto{ID: 1, Limit: 1}).Return(product(), nil)
+		mockProductRepository.On("GetVariants", mock.Anything, dto.FilterVariantDto{Sku: "TSHIRT-L-RED"}).Return(nil, nil)
+		mockProductRepository.On("CreateVariant", mock.Anything, expected).Return(&model.ProductVariant{ID: 5}, nil)
+
+		res, err, state := productService.CreateVariant(context.TODO(), 1, payload)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, res.(map[string]interface{})["id"])
+	})
+
+	t.Run("Test Create Variant Missing Option", func(t *testing.T) {
+		defer reset()
+		invalid := dto.InsertVariantDto{Sku: "TSHIRT-L", Options: map[string]string{"size": "L"}}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(product(), nil)
+
+		_, err, state := productService.CreateVariant(context.TODO(), 1, invalid)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Variant Unknown Option", func(t *testing.T) {
+		defer reset()
+		invalid := dto.InsertVariantDto{Sku: "TSHIRT-L", Options: map[string]string{"size": "L", "fit": "Slim"}}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(product(), nil)
+
+		_, err, state := productService.CreateVariant(context.TODO(), 1, invalid)
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Variant Duplicate Options", func(t *testing.T) {
+		defer reset()
+		duplicate := dto.InsertVariantDto{Sku: "TSHIRT-M-RED-2", Options: map[string]string{"size": "M", "colour": "Red"}}
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(product(), nil)
+
+		_, err, state := productService.CreateVariant(context.TODO(), 1, duplicate)
+
+		assert.Equal(t, "DUPLICATE", state)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Create Variant Duplicate Sku", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(product(), nil)
+		mockProductRepository.On("GetVariants", mock.Anything, mock.Anything).Return([]model.ProductVariant{{ID: 9, Sku: "TSHIRT-L-RED"}}, nil)
+
+		_, err, state := productService.CreateVariant(context.TODO(), 1, payload)
+
+		assert.Equal(t, "DUPLICATE", state)
+		assert.NotNil(t, err)
+		mockProductRepository.AssertNotCalled(t, "CreateVariant", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Variant Product Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(nil, nil)
+
+		_, err, state := productService.CreateVariant(context.TODO(), 1, payload)
+
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestProductImages(t *testing.T) {
+	products := func() []dto.GetProduct {
+		return []dto.GetProduct{{ID: 1, Title: "Basic T-Shirt"}, {ID: 2, Title: "Polo Shirt"}}
+	}
+
+	t.Run("Test Get Product Attaches Images", func(t *testing.T) {
+		defer reset()
+		var loaded []int
+		productService.RegisterImageLoader(func(ctx context.Context, productIds []int) (map[int][]dto.ImageDto, error) {
+			loaded = productIds
+			return map[int][]dto.ImageDto{2: {{ID: 7, Url: "/media/files/products/2/abc/original.png"}}}, nil
+		})
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(products(), nil)
+
+		res, err, state := productService.GetProductByBrand(context.TODO(), 1)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, loaded)
+		result := res.([]dto.GetProduct)
+		assert.Len(t, result[0].Images, 0)
+		assert.Equal(t, 7, result[1].Images[0].ID)
+	})
+
+	t.Run("Test Get Product Image Loader Error", func(t *testing.T) {
+		defer reset()
+		productService.RegisterImageLoader(func(ctx context.Context, productIds []int) (map[int][]dto.ImageDto, error) {
+			return nil, errors.New("Database Error")
+		})
+
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return(products(), nil)
+
+		_, err, state := productService.GetProductById(context.TODO(), 1)
+
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestProductChangeHandlers(t *testing.T) {
+	t.Run("Test Create Product Notifies Change Handlers", func(t *testing.T) {
+		defer reset()
+		var changed []int
+		productService.RegisterChangeHandler(func(ctx context.Context, productId int) error {
+			changed = append(changed, productId)
+			return errors.New("Index Error")
+		})
+
+		payload := dto.InsertProductDto{Title: "Basic T-Shirt", BrandId: 1, Price: 99000}
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{{ID: 1}}, nil)
+		mockProductRepository.On("Create", mock.Anything, payload).Return(&model.Product{ID: 5}, nil)
+
+		_, err, state := productService.Create(context.TODO(), payload)
+
+		//a failing handler doesn't undo the created product
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5}, changed)
+	})
+
+	t.Run("Test Create Product Failure Skips Change Handlers", func(t *testing.T) {
+		defer reset()
+		called := false
+		productService.RegisterChangeHandler(func(ctx context.Context, productId int) error {
+			called = true
+			return nil
+		})
+
+		payload := dto.InsertProductDto{Title: "Basic T-Shirt", BrandId: 1, Price: 99000}
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{{ID: 1}}, nil)
+		mockProductRepository.On("Create", mock.Anything, payload).Return(nil, errors.New("Database Error"))
+
+		_, err, _ := productService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestProductDelete(t *testing.T) {
+	t.Run("Test Delete Product Success", func(t *testing.T) {
+		defer reset()
+		var changed []int
+		productService.RegisterChangeHandler(func(ctx context.Context, productId int) error {
+			changed = append(changed, productId)
+			return nil
+		})
+
+		mockProductRepository.On("Delete", mock.Anything, 5).Return(true, nil)
+
+		res, err, state := productService.Delete(context.TODO(), 5)
+
+		assert.Equal(t, map[string]interface{}{"id": 5}, res)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5}, changed)
+	})
+
+	t.Run("Test Delete Product Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("Delete", mock.Anything, 5).Return(false, nil)
+
+		res, err, state := productService.Delete(context.TODO(), 5)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+	})
+}
+
+func TestProductRestore(t *testing.T) {
+	filter := dto.FilterProductDto{ID: 5, Limit: 1, IncludeDeleted: true}
+	deleted := []dto.GetProduct{{ID: 5, Brand: dto.BrandDto{ID: 1}, DeletedAt: "2026-10-19 09:00:00"}}
+
+	t.Run("Test Restore Product Success", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(deleted, nil)
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{{ID: 1}}, nil)
+		mockProductRepository.On("Restore", mock.Anything, 5).Return(true, nil)
+
+		res, err, state := productService.Restore(context.TODO(), 5)
+
+		assert.Equal(t, map[string]interface{}{"id": 5}, res)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Restore Product Not Deleted", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return([]dto.GetProduct{{ID: 5, Brand: dto.BrandDto{ID: 1}}}, nil)
+
+		res, err, state := productService.Restore(context.TODO(), 5)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "INVALID_STATE", state)
+	})
+
+	t.Run("Test Restore Product Of Deleted Brand", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(deleted, nil)
+		mockBrandRepository.On("GetBrand", mock.Anything, mock.Anything).Return([]model.Brand{}, nil)
+
+		res, err, state := productService.Restore(context.TODO(), 5)
+
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), "restore it first")
+		assert.Equal(t, "INVALID_STATE", state)
+		mockProductRepository.AssertNotCalled(t, "Restore", mock.Anything, 5)
+	})
+}
+
+func TestProductCreatePrice(t *testing.T) {
+	filter := dto.FilterProductDto{ID: 5, Limit: 1}
+	product := []dto.GetProduct{{ID: 5, Price: 120000}}
+	future := func(d time.Duration) string {
+		return time.Now().Add(d).Format("2006-01-02 15:04:05")
+	}
+
+	t.Run("Test Create Price Success", func(t *testing.T) {
+		defer reset()
+		var changed []int
+		productService.RegisterChangeHandler(func(ctx context.Context, productId int) error {
+			changed = append(changed, productId)
+			return nil
+		})
+
+		payload := dto.InsertPriceDto{Price: 99000, StartAt: future(time.Hour), EndAt: future(2 * time.Hour)}
+		expected := payload
+		expected.ProductId = 5
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(product, nil)
+		mockProductRepository.On("CreatePrice", mock.Anything, expected).Return(&model.ProductPrice{ID: 3}, nil)
+
+		res, err, state := productService.CreatePrice(context.TODO(), 5, payload)
+
+		assert.Equal(t, map[string]interface{}{"id": 3}, res)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5}, changed)
+	})
+
+	t.Run("Test Create Price Starts Now Without Start", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(product, nil)
+		mockProductRepository.On("CreatePrice", mock.Anything, mock.MatchedBy(func(payload dto.InsertPriceDto) bool {
+			return payload.StartAt != "" && payload.StartAt <= future(0)
+		})).Return(&model.ProductPrice{ID: 3}, nil)
+
+		_, err, state := productService.CreatePrice(context.TODO(), 5, dto.InsertPriceDto{Price: 99000})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Create Price In The Past", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(product, nil)
+
+		res, err, state := productService.CreatePrice(context.TODO(), 5, dto.InsertPriceDto{Price: 99000, StartAt: future(-time.Hour)})
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		mockProductRepository.AssertNotCalled(t, "CreatePrice", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Create Price Ending Before Start", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return(product, nil)
+
+		res, err, state := productService.CreatePrice(context.TODO(), 5, dto.InsertPriceDto{Price: 99000, StartAt: future(2 * time.Hour), EndAt: future(time.Hour)})
+
+		assert.Nil(t, res)
+		assert.Equal(t, "endAt must be after startAt", err.Error())
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Create Price Product Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return([]dto.GetProduct{}, nil)
+
+		res, err, state := productService.CreatePrice(context.TODO(), 5, dto.InsertPriceDto{Price: 99000})
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+	})
+}
+
+func TestProductGetPrices(t *testing.T) {
+	filter := dto.FilterProductDto{ID: 5, Limit: 1, IncludeDeleted: true}
+
+	t.Run("Test Get Prices Success", func(t *testing.T) {
+		defer reset()
+
+		prices := []model.ProductPrice{
+			{ID: 1, ProductId: 5, Price: 120000, StartAt: "2026-10-01 00:00:00", CreatedBy: "system"},
+			{ID: 2, ProductId: 5, Price: 99000, StartAt: "2026-10-02 00:00:00", EndAt: "2026-10-03 00:00:00", CreatedBy: "merchandiser"},
+			{ID: 3, ProductId: 5, Price: 150000, StartAt: "2999-01-01 00:00:00", CreatedBy: "merchandiser"},
+		}
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return([]dto.GetProduct{{ID: 5}}, nil)
+		mockProductRepository.On("GetPrices", mock.Anything, 5).Return(prices, nil)
+
+		res, err, state := productService.GetPrices(context.TODO(), 5)
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Len(t, res, 3)
+		//the sale is over and the last price has not started
+		assert.Equal(t, []bool{true, false, false}, []bool{res[0].Active, res[1].Active, res[2].Active})
+		assert.Equal(t, "2026-10-03 00:00:00", res[1].EndAt)
+	})
+
+	t.Run("Test Get Prices Product Not Found", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return([]dto.GetProduct{}, nil)
+
+		res, err, state := productService.GetPrices(context.TODO(), 5)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+	})
+
+	t.Run("Test Get Prices Database Error", func(t *testing.T) {
+		defer reset()
+
+		mockProductRepository.On("GetProduct", mock.Anything, filter).Return([]dto.GetProduct{{ID: 5}}, nil)
+		mockProductRepository.On("GetPrices", mock.Anything, 5).Return(nil, errors.New("Database Error"))
+
+		res, err, state := productService.GetPrices(context.TODO(), 5)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
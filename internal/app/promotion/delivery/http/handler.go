@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type PromotionHandler struct {
+	PromotionService service.PromotionService
+}
+
+func NewPromotionHandler(mux *http.ServeMux, service service.PromotionService) {
+	handler := PromotionHandler{PromotionService: service}
+
+	mux.HandleFunc("/promotion", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.Create(w, r)
+		case "GET":
+			handler.GetPromotionByCode(w, r)
+		}
+	})
+}
+
+func (b *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	var payload dto.InsertPromotionDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.PromotionService.Create(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *PromotionHandler) GetPromotionByCode(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+	code := r.URL.Query().Get("code")
+	result, err, state := b.PromotionService.GetPromotionByCode(r.Context(), code)
+
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func isRequestValid(dto *dto.InsertPromotionDto) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(dto)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	promotionHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/promotion/service"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	mux := http.NewServeMux()
+
+	payload := dto.InsertPromotionDto{
+		Code:    "HEMAT10",
+		Title:   "Hemat 10%",
+		Type:    "PERCENTAGE",
+		Value:   10,
+		StartAt: "2026-10-01 00:00:00",
+	}
+	j, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	mockService := new(mocks.PromotionService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.PromotionService)
+	}
+
+	t.Run("Test Create Promotion Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+
+		promotionHttp.NewPromotionHandler(mux, mockService)
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Promotion Duplicate", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(nil, errors.New("Promotion code already Exists"), "DUPLICATE")
+
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Promotion Invalid Type", func(t *testing.T) {
+		defer reset()
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(`{"code":"X","title":"X","type":"BOGO","value":1,"startAt":"2026-10-01 00:00:00"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Promotion Invalid Date", func(t *testing.T) {
+		defer reset()
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(`{"code":"X","title":"X","type":"FIXED","value":1,"startAt":"01-10-2026"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetPromotionByCode(t *testing.T) {
+	mockService := new(mocks.PromotionService)
+
+	t.Run("Test Get Promotion By Code Success", func(t *testing.T) {
+		mockService.On("GetPromotionByCode", context.Background(), "HEMAT10").Return(&dto.GetPromotion{ID: 1, Code: "HEMAT10"}, nil, "SUCCESS").Once()
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/promotion?code=HEMAT10", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetPromotionByCode(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Get Promotion By Code Not Found", func(t *testing.T) {
+		mockService.On("GetPromotionByCode", context.Background(), "UNKNOWN").Return(nil, errors.New("Promotion Not Found"), "NOT_FOUND").Once()
+		handler := promotionHttp.PromotionHandler{PromotionService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/promotion?code=UNKNOWN", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetPromotionByCode(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
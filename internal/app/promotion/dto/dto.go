@@ -0,0 +1,66 @@
+package dto
+
+type InsertPromotionDto struct {
+	Code             string  `json:"code" validate:"required,max=50"`
+	Title            string  `json:"title" validate:"required"`
+	Type             string  `json:"type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value            float32 `json:"value" validate:"required,gt=0"`
+	MinOrderValue    float32 `json:"minOrderValue" validate:"gte=0"`
+	BrandId          int     `json:"brandId"`
+	ProductId        int     `json:"productId"`
+	StartAt          string  `json:"startAt" validate:"required,datetime=2006-01-02 15:04:05"`
+	EndAt            string  `json:"endAt" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	UsageLimit       int     `json:"usageLimit" validate:"gte=0"`
+	PerCustomerLimit int     `json:"perCustomerLimit" validate:"gte=0"`
+	Stackable        bool    `json:"stackable"`
+}
+
+type FilterPromotionDto struct {
+	ID    int    `json:"id"`
+	Code  string `json:"code"`
+	Limit int    `json:"limit"`
+}
+
+type GetPromotion struct {
+	ID               int     `json:"id"`
+	Code             string  `json:"code"`
+	Title            string  `json:"title"`
+	Type             string  `json:"type"`
+	Value            float32 `json:"value"`
+	MinOrderValue    float32 `json:"minOrderValue"`
+	BrandId          int     `json:"brandId,omitempty"`
+	ProductId        int     `json:"productId,omitempty"`
+	StartAt          string  `json:"startAt"`
+	EndAt            string  `json:"endAt,omitempty"`
+	UsageLimit       int     `json:"usageLimit"`
+	UsageCount       int     `json:"usageCount"`
+	PerCustomerLimit int     `json:"perCustomerLimit"`
+	Stackable        bool    `json:"stackable"`
+}
+
+// ApplyPromotionDto describes the order the coupons are applied to
+type ApplyPromotionDto struct {
+	Codes    []string
+	Customer string
+	Lines    []PromotionLine
+}
+
+type PromotionLine struct {
+	ProductId int
+	BrandId   int
+	Amount    float32
+}
+
+// AppliedPromotionDto holds the discount of every coupon and of every line, in the order of ApplyPromotionDto.Lines
+type AppliedPromotionDto struct {
+	Discounts     []AppliedDiscount
+	LineDiscounts []float32
+	Total         float32
+}
+
+type AppliedDiscount struct {
+	PromotionId      int
+	Code             string
+	PerCustomerLimit int
+	Amount           float32
+}
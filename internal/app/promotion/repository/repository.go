@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, payload dto.InsertPromotionDto) (*model.Promotion, error)
+	GetPromotion(ctx context.Context, filter dto.FilterPromotionDto) (data []model.Promotion, err error)
+	CountCustomerUsage(ctx context.Context, promotionId int, customer string) (int, error)
+	ClaimUsage(ctx context.Context, usage model.PromotionUsage) error
+	ReleaseUsage(ctx context.Context, promotionId int, transactionId int) error
+}
+
+// ErrUsageLimitReached is returned when a coupon has no usage left to claim
+var ErrUsageLimitReached = errors.New("Coupon has reached its usage limit")
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewPromotion(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) Create(ctx context.Context, payload dto.InsertPromotionDto) (*model.Promotion, error) {
+	query := `INSERT INTO promotion (code, title, type, value, minOrderValue, brandId, productId, startAt, endAt,
+	usageLimit, perCustomerLimit, stackable, createdAt, updatedAt)
+	values(?, ?, ?, ?, ?, NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query,
+		payload.Code,
+		payload.Title,
+		payload.Type,
+		payload.Value,
+		payload.MinOrderValue,
+		payload.BrandId,
+		payload.ProductId,
+		payload.StartAt,
+		sql.NullString{String: payload.EndAt, Valid: payload.EndAt != ""},
+		payload.UsageLimit,
+		payload.PerCustomerLimit,
+		payload.Stackable,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Promotion{ID: int(id)}, nil
+}
+
+func (r *Repository) GetPromotion(ctx context.Context, filter dto.FilterPromotionDto) (data []model.Promotion, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, code, title, type, value, minOrderValue, brandId, productId, startAt, endAt,
+	usageLimit, usageCount, perCustomerLimit, stackable
+	FROM promotion`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.Code != "" {
+		query += util.FilterHandler(filterValues) + ` code = ?`
+		filterValues = append(filterValues, filter.Code)
+	}
+
+	query += ` ORDER BY id `
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			brandId, productId sql.NullInt64
+			endAt              sql.NullString
+		)
+		promotion := model.Promotion{}
+		err = rows.Scan(
+			&promotion.ID,
+			&promotion.Code,
+			&promotion.Title,
+			&promotion.Type,
+			&promotion.Value,
+			&promotion.MinOrderValue,
+			&brandId,
+			&productId,
+			&promotion.StartAt,
+			&endAt,
+			&promotion.UsageLimit,
+			&promotion.UsageCount,
+			&promotion.PerCustomerLimit,
+			&promotion.Stackable,
+		)
+		if err != nil {
+			return nil, err
+		}
+		promotion.BrandId = int(brandId.Int64)
+		promotion.ProductId = int(productId.Int64)
+		promotion.EndAt = endAt.String
+
+		data = append(data, promotion)
+	}
+
+	return data, nil
+}
+
+func (r *Repository) CountCustomerUsage(ctx context.Context, promotionId int, customer string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM transaction_discount WHERE promotionId = ? AND customer = ?`
+	err := r.DB.QueryRowContext(ctx, r.Dialect.Rebind(query), promotionId, customer).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ClaimUsage takes one usage of the promotion for an order kept outside the database, the order repository
+// claims it in the transaction creating the order otherwise
+func (r *Repository) ClaimUsage(ctx context.Context, usage model.PromotionUsage) error {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE promotion SET usageCount = usageCount + 1, updatedAt = CURRENT_TIMESTAMP WHERE id = ? AND (usageLimit = 0 OR usageCount < usageLimit)`
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind(query), usage.PromotionId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return ErrUsageLimitReached
+	}
+
+	query = `INSERT INTO transaction_discount (transactionId, promotionId, code, customer, amount, createdAt) values(?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), usage.TransactionId, usage.PromotionId, usage.Code, usage.Customer, usage.Amount)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReleaseUsage gives back the usage an order claimed with ClaimUsage
+func (r *Repository) ReleaseUsage(ctx context.Context, promotionId int, transactionId int) error {
+
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM transaction_discount WHERE promotionId = ? AND transactionId = ?`
+	result, err := tx.ExecContext(ctx, r.Dialect.Rebind(query), promotionId, transactionId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = `UPDATE promotion SET usageCount = usageCount - ?, updatedAt = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err = tx.ExecContext(ctx, r.Dialect.Rebind(query), affected, promotionId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
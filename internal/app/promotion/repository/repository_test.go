@@ -0,0 +1,175 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+var promotionColumns = []string{"id", "code", "title", "type", "value", "minOrderValue", "brandId", "productId", "startAt", "endAt",
+	"usageLimit", "usageCount", "perCustomerLimit", "stackable"}
+
+func TestCreatePromotion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertPromotionDto{
+		Code:      "HEMAT10",
+		Title:     "Hemat 10%",
+		Type:      "PERCENTAGE",
+		Value:     10,
+		BrandId:   1,
+		StartAt:   "2026-10-01 00:00:00",
+		Stackable: true,
+	}
+	query := "INSERT INTO promotion"
+
+	t.Run("Test Create Promotion Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.Code, payload.Title, payload.Type, payload.Value, payload.MinOrderValue, payload.BrandId, payload.ProductId,
+			payload.StartAt, nil, payload.UsageLimit, payload.PerCustomerLimit, payload.Stackable).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+	})
+
+	t.Run("Test Create Promotion Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetPromotion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, code, title, type, value, minOrderValue, brandId, productId, startAt, endAt"
+
+	t.Run("Test Get Promotion With Filter", func(t *testing.T) {
+		rows := sqlmock.NewRows(promotionColumns).
+			AddRow(1, "HEMAT10", "Hemat 10%", "PERCENTAGE", 10, 0, 1, nil, "2026-10-01 00:00:00", nil, 100, 3, 1, true)
+		filter := dto.FilterPromotionDto{Code: "HEMAT10", Limit: 1}
+		mock.ExpectQuery(query).WithArgs(filter.Code, filter.Limit).WillReturnRows(rows)
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		result, err := r.GetPromotion(context.TODO(), filter)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, 1, result[0].BrandId)
+		assert.Equal(t, 0, result[0].ProductId)
+		assert.Equal(t, "", result[0].EndAt)
+		assert.True(t, result[0].Stackable)
+	})
+
+	t.Run("Test Get Promotion Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		result, err := r.GetPromotion(context.TODO(), dto.FilterPromotionDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestCountCustomerUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := `SELECT COUNT\(\*\) FROM transaction_discount`
+
+	t.Run("Test Count Customer Usage Success", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1, "budi").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		result, err := r.CountCustomerUsage(context.TODO(), 1, "budi")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result)
+	})
+
+	t.Run("Test Count Customer Usage Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		_, err := r.CountCustomerUsage(context.TODO(), 1, "budi")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestClaimUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	usage := model.PromotionUsage{PromotionId: 1, TransactionId: 7, Code: "TEN", Customer: "budi", Amount: 10}
+	update := `UPDATE promotion SET usageCount = usageCount \+ 1`
+	insert := `INSERT INTO transaction_discount`
+
+	t.Run("Test Claim Usage Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insert).WithArgs(7, 1, "TEN", "budi", usage.Amount).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		err := r.ClaimUsage(context.TODO(), usage)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Claim Usage Limit Reached", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		r := repository.NewPromotion(db, dialect.MySQL)
+		err := r.ClaimUsage(context.TODO(), usage)
+
+		assert.Equal(t, repository.ErrUsageLimitReached, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM transaction_discount`).WithArgs(1, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE promotion SET usageCount = usageCount - \?`).WithArgs(int64(1), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := repository.NewPromotion(db, dialect.MySQL)
+	err = r.ReleaseUsage(context.TODO(), 1, 7)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
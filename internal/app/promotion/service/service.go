@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type PromotionService interface {
+	Create(ctx context.Context, payload dto.InsertPromotionDto) (interface{}, error, string)
+	GetPromotionByCode(ctx context.Context, code string) (*dto.GetPromotion, error, string)
+	Apply(ctx context.Context, payload dto.ApplyPromotionDto) (*dto.AppliedPromotionDto, error, string)
+	ClaimUsage(ctx context.Context, usage model.PromotionUsage) error
+	ReleaseUsage(ctx context.Context, promotionId int, transactionId int) error
+}
+
+type Service struct {
+	promotionRepository repository.PromotionRepository
+	contextTimeout      time.Duration
+}
+
+func NewPromotionService(r repository.PromotionRepository, timeout time.Duration) PromotionService {
+	return &Service{
+		promotionRepository: r,
+		contextTimeout:      timeout,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, payload dto.InsertPromotionDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.Type == model.PromotionTypePercentage && payload.Value > 100 {
+		return nil, errors.New("Percentage value cannot be more than 100"), util.VALIDATION_ERROR
+	}
+
+	if payload.BrandId > 0 && payload.ProductId > 0 {
+		return nil, errors.New("Promotion can only be scoped to a brand or a product"), util.VALIDATION_ERROR
+	}
+
+	if payload.EndAt != "" && payload.EndAt <= payload.StartAt {
+		return nil, errors.New("endAt must be after startAt"), util.VALIDATION_ERROR
+	}
+
+	//Check Promotion By Code
+	promotion, err := s.promotionRepository.GetPromotion(ctx, dto.FilterPromotionDto{Code: payload.Code, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if len(promotion) > 0 {
+		return nil, errors.New("Promotion code already Exists"), util.DUPLICATE
+	}
+
+	result, err := s.promotionRepository.Create(ctx, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+
+	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
+}
+
+func (s *Service) GetPromotionByCode(ctx context.Context, code string) (*dto.GetPromotion, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	result, err := s.promotionRepository.GetPromotion(ctx, dto.FilterPromotionDto{Code: code, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("Promotion Not Found"), util.NOT_FOUND
+	}
+
+	promotion := result[0]
+	return &dto.GetPromotion{
+		ID:               promotion.ID,
+		Code:             promotion.Code,
+		Title:            promotion.Title,
+		Type:             promotion.Type,
+		Value:            promotion.Value,
+		MinOrderValue:    promotion.MinOrderValue,
+		BrandId:          promotion.BrandId,
+		ProductId:        promotion.ProductId,
+		StartAt:          promotion.StartAt,
+		EndAt:            promotion.EndAt,
+		UsageLimit:       promotion.UsageLimit,
+		UsageCount:       promotion.UsageCount,
+		PerCustomerLimit: promotion.PerCustomerLimit,
+		Stackable:        promotion.Stackable,
+	}, nil, util.SUCCESS
+}
+
+// Apply validates the coupon codes against the order and computes the discount of every code and every line.
+// Coupons are applied in the given order, each one on the line amounts left by the previous ones.
+func (s *Service) Apply(ctx context.Context, payload dto.ApplyPromotionDto) (*dto.AppliedPromotionDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	result := &dto.AppliedPromotionDto{LineDiscounts: make([]float32, len(payload.Lines))}
+	if len(payload.Codes) == 0 {
+		return result, nil, util.SUCCESS
+	}
+
+	var subtotal float32
+	remaining := make([]float32, len(payload.Lines))
+	for i, line := range payload.Lines {
+		remaining[i] = line.Amount
+		subtotal += line.Amount
+	}
+
+	//PROCESS VALIDATE COUPONS
+	now := time.Now()
+	seen := map[string]bool{}
+	var promotions []model.Promotion
+	for _, code := range payload.Codes {
+		if seen[code] {
+			return nil, fmt.Errorf("Coupon %s is applied more than once", code), util.VALIDATION_ERROR
+		}
+		seen[code] = true
+
+		data, err := s.promotionRepository.GetPromotion(ctx, dto.FilterPromotionDto{Code: code, Limit: 1})
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("Coupon %s is not valid", code), util.VALIDATION_ERROR
+		}
+		promotion := data[0]
+
+		active, err := isActive(promotion, now)
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		if !active {
+			return nil, fmt.Errorf("Coupon %s is not active", code), util.VALIDATION_ERROR
+		}
+
+		if subtotal < promotion.MinOrderValue {
+			return nil, fmt.Errorf("Coupon %s requires a minimum order of %.2f", code, promotion.MinOrderValue), util.VALIDATION_ERROR
+		}
+
+		if promotion.UsageLimit > 0 && promotion.UsageCount >= promotion.UsageLimit {
+			return nil, fmt.Errorf("Coupon %s has reached its usage limit", code), util.VALIDATION_ERROR
+		}
+
+		if promotion.PerCustomerLimit > 0 {
+			//anonymous orders would all count as one customer
+			if payload.Customer == "" || payload.Customer == util.AnonymousActor {
+				return nil, fmt.Errorf("Coupon %s requires an identified customer, send the X-Actor header", code), util.VALIDATION_ERROR
+			}
+			used, err := s.promotionRepository.CountCustomerUsage(ctx, promotion.ID, payload.Customer)
+			if err != nil {
+				return nil, err, util.SYSTEM_ERROR
+			}
+			if used >= promotion.PerCustomerLimit {
+				return nil, fmt.Errorf("Coupon %s has reached its usage limit for this customer", code), util.VALIDATION_ERROR
+			}
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	if len(promotions) > 1 {
+		for _, promotion := range promotions {
+			if !promotion.Stackable {
+				return nil, fmt.Errorf("Coupon %s cannot be combined with other coupons", promotion.Code), util.VALIDATION_ERROR
+			}
+		}
+	}
+	//END OF PROCESS VALIDATE COUPONS
+
+	//PROCESS COMPUTE DISCOUNTS
+	for _, promotion := range promotions {
+		var eligible []int
+		for i, line := range payload.Lines {
+			if isEligible(promotion, line) && remaining[i] > 0 {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			return nil, fmt.Errorf("Coupon %s does not apply to any product in this order", promotion.Code), util.VALIDATION_ERROR
+		}
+
+		discounts := computeDiscount(promotion, eligible, remaining)
+
+		var amount float32
+		for i, idx := range eligible {
+			remaining[idx] = util.RoundMoney(remaining[idx] - discounts[i])
+			result.LineDiscounts[idx] = util.RoundMoney(result.LineDiscounts[idx] + discounts[i])
+			amount += discounts[i]
+		}
+		amount = util.RoundMoney(amount)
+
+		result.Discounts = append(result.Discounts, dto.AppliedDiscount{
+			PromotionId:      promotion.ID,
+			Code:             promotion.Code,
+			PerCustomerLimit: promotion.PerCustomerLimit,
+			Amount:           amount,
+		})
+		result.Total = util.RoundMoney(result.Total + amount)
+	}
+	//END OF PROCESS COMPUTE DISCOUNTS
+
+	return result, nil, util.SUCCESS
+}
+
+func isActive(promotion model.Promotion, now time.Time) (bool, error) {
+	startAt, err := util.ParseDateTime(promotion.StartAt)
+	if err != nil {
+		return false, err
+	}
+	if now.Before(startAt) {
+		return false, nil
+	}
+
+	if promotion.EndAt != "" {
+		endAt, err := util.ParseDateTime(promotion.EndAt)
+		if err != nil {
+			return false, err
+		}
+		if now.After(endAt) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isEligible(promotion model.Promotion, line dto.PromotionLine) bool {
+	if promotion.ProductId > 0 {
+		return line.ProductId == promotion.ProductId
+	}
+	if promotion.BrandId > 0 {
+		return line.BrandId == promotion.BrandId
+	}
+	return true
+}
+
+// computeDiscount returns the discount of every eligible line. A fixed amount is split across the lines
+// proportionally to their remaining amount, the last line takes the rounding difference.
+func computeDiscount(promotion model.Promotion, eligible []int, remaining []float32) []float32 {
+	discounts := make([]float32, len(eligible))
+
+	if promotion.Type == model.PromotionTypePercentage {
+		for i, idx := range eligible {
+			discounts[i] = util.RoundMoney(remaining[idx] * promotion.Value / 100)
+		}
+		return discounts
+	}
+
+	var base float32
+	for _, idx := range eligible {
+		base += remaining[idx]
+	}
+
+	amount := promotion.Value
+	if amount > base {
+		amount = base
+	}
+
+	var allocated float32
+	for i, idx := range eligible {
+		if i == len(eligible)-1 {
+			discounts[i] = util.RoundMoney(amount - allocated)
+		} else {
+			discounts[i] = util.RoundMoney(amount * remaining[idx] / base)
+		}
+		if discounts[i] > remaining[idx] {
+			discounts[i] = remaining[idx]
+		}
+		allocated += discounts[i]
+	}
+	return discounts
+}
+
+// ClaimUsage takes one usage of the promotion for an order, it fails when the coupon reached its limit
+func (s *Service) ClaimUsage(ctx context.Context, usage model.PromotionUsage) error {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	return s.promotionRepository.ClaimUsage(ctx, usage)
+}
+
+// ReleaseUsage gives back the usage an order took of the promotion
+func (s *Service) ReleaseUsage(ctx context.Context, promotionId int, transactionId int) error {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	return s.promotionRepository.ReleaseUsage(ctx, promotionId, transactionId)
+}
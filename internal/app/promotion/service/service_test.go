@@ -0,0 +1,363 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/dto"
+	PromotionService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+	mockPromotionRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/promotion/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockPromotionRepository = new(mockPromotionRepositories.PromotionRepository)
+	promotionService        = PromotionService.NewPromotionService(mockPromotionRepository, contextTimeout)
+)
+
+func reset() {
+	mockPromotionRepository = new(mockPromotionRepositories.PromotionRepository)
+	promotionService = PromotionService.NewPromotionService(mockPromotionRepository, contextTimeout)
+}
+
+func dateTime(d time.Duration) string {
+	return time.Now().Add(d).Format(util.DateTimeLayout)
+}
+
+func activePromotion(id int, code, promotionType string, value float32) model.Promotion {
+	return model.Promotion{ID: id, Code: code, Type: promotionType, Value: value, StartAt: dateTime(-time.Hour)}
+}
+
+func onCode(promotion model.Promotion) {
+	mockPromotionRepository.On("GetPromotion", mock.Anything, dto.FilterPromotionDto{Code: promotion.Code, Limit: 1}).Return([]model.Promotion{promotion}, nil)
+}
+
+func TestCreatePromotion(t *testing.T) {
+	payload := dto.InsertPromotionDto{Code: "HEMAT10", Title: "Hemat", Type: "PERCENTAGE", Value: 10, StartAt: "2026-10-01 00:00:00"}
+
+	t.Run("Test Create Promotion Success", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{}, nil)
+		mockPromotionRepository.On("Create", mock.Anything, payload).Return(&model.Promotion{ID: 1}, nil)
+
+		res, err, state := promotionService.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 1, res.(map[string]interface{})["id"])
+	})
+
+	t.Run("Test Create Promotion Duplicate Code", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{{ID: 1}}, nil)
+
+		res, err, state := promotionService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "DUPLICATE", state)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Promotion Percentage Over 100", func(t *testing.T) {
+		defer reset()
+		invalid := payload
+		invalid.Value = 120
+
+		_, err, state := promotionService.Create(context.TODO(), invalid)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Create Promotion Both Brand And Product Scope", func(t *testing.T) {
+		defer reset()
+		invalid := payload
+		invalid.BrandId = 1
+		invalid.ProductId = 1
+
+		_, err, state := promotionService.Create(context.TODO(), invalid)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Create Promotion Error Database", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{}, nil)
+		mockPromotionRepository.On("Create", mock.Anything, payload).Return(nil, errors.New("Database Error"))
+
+		_, err, state := promotionService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
+
+func TestGetPromotionByCode(t *testing.T) {
+	t.Run("Test Get Promotion By Code Success", func(t *testing.T) {
+		defer reset()
+		onCode(activePromotion(1, "HEMAT10", "PERCENTAGE", 10))
+
+		res, err, state := promotionService.GetPromotionByCode(context.TODO(), "HEMAT10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, "HEMAT10", res.Code)
+	})
+
+	t.Run("Test Get Promotion By Code Not Found", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{}, nil)
+
+		res, err, state := promotionService.GetPromotionByCode(context.TODO(), "UNKNOWN")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, res)
+	})
+}
+
+func TestApply(t *testing.T) {
+	lines := []dto.PromotionLine{
+		{ProductId: 1, BrandId: 1, Amount: 100000},
+		{ProductId: 2, BrandId: 2, Amount: 50000},
+	}
+
+	t.Run("Test Apply Without Coupons", func(t *testing.T) {
+		defer reset()
+
+		res, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, float32(0), res.Total)
+		assert.Equal(t, []float32{0, 0}, res.LineDiscounts)
+		mockPromotionRepository.AssertNotCalled(t, "GetPromotion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Apply Percentage", func(t *testing.T) {
+		defer reset()
+		onCode(activePromotion(1, "HEMAT10", "PERCENTAGE", 10))
+
+		res, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"HEMAT10"}, Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, float32(15000), res.Total)
+		assert.Equal(t, []float32{10000, 5000}, res.LineDiscounts)
+		assert.Equal(t, []dto.AppliedDiscount{{PromotionId: 1, Code: "HEMAT10", Amount: 15000}}, res.Discounts)
+	})
+
+	t.Run("Test Apply Fixed Split Across Lines", func(t *testing.T) {
+		defer reset()
+		onCode(activePromotion(1, "POTONG30", "FIXED", 30000))
+
+		res, err, _ := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"POTONG30"}, Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(30000), res.Total)
+		assert.Equal(t, []float32{20000, 10000}, res.LineDiscounts)
+	})
+
+	t.Run("Test Apply Fixed Capped To Eligible Amount", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "ADIDAS", "FIXED", 80000)
+		promotion.BrandId = 2
+		onCode(promotion)
+
+		res, err, _ := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"ADIDAS"}, Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(50000), res.Total)
+		assert.Equal(t, []float32{0, 50000}, res.LineDiscounts)
+	})
+
+	t.Run("Test Apply Stackable Coupons In Order", func(t *testing.T) {
+		defer reset()
+		percentage := activePromotion(1, "HEMAT10", "PERCENTAGE", 10)
+		percentage.Stackable = true
+		fixed := activePromotion(2, "NIKE5", "FIXED", 5000)
+		fixed.ProductId = 1
+		fixed.Stackable = true
+		onCode(percentage)
+		onCode(fixed)
+
+		res, err, _ := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"HEMAT10", "NIKE5"}, Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(20000), res.Total)
+		assert.Equal(t, []float32{15000, 5000}, res.LineDiscounts)
+	})
+
+	t.Run("Test Apply Non Stackable Coupon Combined", func(t *testing.T) {
+		defer reset()
+		percentage := activePromotion(1, "HEMAT10", "PERCENTAGE", 10)
+		percentage.Stackable = true
+		onCode(percentage)
+		onCode(activePromotion(2, "SOLO", "FIXED", 5000))
+
+		res, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"HEMAT10", "SOLO"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Apply Same Coupon Twice", func(t *testing.T) {
+		defer reset()
+		onCode(activePromotion(1, "HEMAT10", "PERCENTAGE", 10))
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"HEMAT10", "HEMAT10"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Unknown Coupon", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return([]model.Promotion{}, nil)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"UNKNOWN"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Coupon Not Started", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "SOON", "PERCENTAGE", 10)
+		promotion.StartAt = dateTime(time.Hour)
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"SOON"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Coupon Expired", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "OLD", "PERCENTAGE", 10)
+		promotion.EndAt = dateTime(-time.Minute)
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"OLD"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Minimum Order Value", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "BIG", "PERCENTAGE", 10)
+		promotion.MinOrderValue = 200000
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"BIG"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Usage Limit Reached", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "LIMITED", "PERCENTAGE", 10)
+		promotion.UsageLimit = 5
+		promotion.UsageCount = 5
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"LIMITED"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Per Customer Limit Reached", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "ONCE", "PERCENTAGE", 10)
+		promotion.PerCustomerLimit = 1
+		onCode(promotion)
+		mockPromotionRepository.On("CountCustomerUsage", mock.Anything, 1, "budi").Return(1, nil)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"ONCE"}, Customer: "budi", Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Per Customer Limit Requires Customer", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "ONCE", "PERCENTAGE", 10)
+		promotion.PerCustomerLimit = 1
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"ONCE"}, Customer: "anonymous", Lines: lines})
+
+		assert.EqualError(t, err, "Coupon ONCE requires an identified customer, send the X-Actor header")
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		mockPromotionRepository.AssertNotCalled(t, "CountCustomerUsage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Apply No Eligible Line", func(t *testing.T) {
+		defer reset()
+		promotion := activePromotion(1, "PUMA", "PERCENTAGE", 10)
+		promotion.BrandId = 3
+		onCode(promotion)
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"PUMA"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Apply Error Database", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("GetPromotion", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		_, err, state := promotionService.Apply(context.TODO(), dto.ApplyPromotionDto{Codes: []string{"HEMAT10"}, Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
+
+func TestUsage(t *testing.T) {
+	usage := model.PromotionUsage{PromotionId: 1, TransactionId: 7, Code: "HEMAT10", Customer: "budi", Amount: 10}
+
+	t.Run("Test Claim Usage Success", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("ClaimUsage", mock.Anything, usage).Return(nil).Once()
+
+		err := promotionService.ClaimUsage(context.TODO(), usage)
+
+		assert.Nil(t, err)
+		mockPromotionRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test Claim Usage Limit Reached", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("ClaimUsage", mock.Anything, usage).Return(errors.New("Coupon has reached its usage limit")).Once()
+
+		err := promotionService.ClaimUsage(context.TODO(), usage)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test Release Usage Success", func(t *testing.T) {
+		defer reset()
+		mockPromotionRepository.On("ReleaseUsage", mock.Anything, 1, 7).Return(nil).Once()
+
+		err := promotionService.ReleaseUsage(context.TODO(), 1, 7)
+
+		assert.Nil(t, err)
+		mockPromotionRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type SearchHandler struct {
+	SearchService service.SearchService
+}
+
+func NewSearchHandler(mux *http.ServeMux, service service.SearchService) {
+	handler := SearchHandler{SearchService: service}
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			handler.Search(w, r)
+		}
+	})
+}
+
+func (b *SearchHandler) Search(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	payload, err := parseSearch(r.URL.Query())
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	validate := validator.New()
+	if err = validate.Struct(&payload); err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.SearchService.Search(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func parseSearch(query url.Values) (payload dto.SearchDto, err error) {
+	payload.Q = query.Get("q")
+
+	ints := map[string]*int{"brandId": &payload.BrandId, "limit": &payload.Limit, "offset": &payload.Offset}
+	for name, target := range ints {
+		if value := query.Get(name); value != "" {
+			if *target, err = strconv.Atoi(value); err != nil {
+				return payload, fmt.Errorf("%s must be a number", name)
+			}
+		}
+	}
+
+	floats := map[string]*float32{"minPrice": &payload.MinPrice, "maxPrice": &payload.MaxPrice}
+	for name, target := range floats {
+		if value := query.Get(name); value != "" {
+			price, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return payload, fmt.Errorf("%s must be a number", name)
+			}
+			*target = float32(price)
+		}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,61 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	searchHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/search/service"
+)
+
+func TestSearch(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mockService := new(mocks.SearchService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.SearchService)
+	}
+
+	t.Run("Test Search Success", func(t *testing.T) {
+		defer reset()
+		payload := dto.SearchDto{Q: "cotton shirt", BrandId: 2, MinPrice: 50000, Limit: 10}
+		mockService.On("Search", context.Background(), payload).Return(&dto.SearchResult{Total: 1}, nil, "SUCCESS")
+
+		searchHttp.NewSearchHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/search?q=cotton+shirt&brandId=2&minPrice=50000&limit=10", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Search Without Query", func(t *testing.T) {
+		defer reset()
+
+		searchHttp.NewSearchHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Search Invalid Number", func(t *testing.T) {
+		defer reset()
+
+		searchHttp.NewSearchHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/search?q=shirt&maxPrice=cheap", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
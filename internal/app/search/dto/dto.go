@@ -0,0 +1,49 @@
+package dto
+
+type SearchDto struct {
+	Q        string  `json:"q" validate:"required,max=200"`
+	BrandId  int     `json:"brandId" validate:"gte=0"`
+	MinPrice float32 `json:"minPrice" validate:"gte=0"`
+	MaxPrice float32 `json:"maxPrice" validate:"gte=0"`
+	Limit    int     `json:"limit" validate:"gte=0,lte=100"`
+	Offset   int     `json:"offset" validate:"gte=0"`
+}
+
+type SearchResult struct {
+	Total  int         `json:"total"`
+	Hits   []SearchHit `json:"hits"`
+	Facets Facets      `json:"facets"`
+}
+
+type SearchHit struct {
+	ID          int               `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Brand       BrandDto          `json:"brand"`
+	Price       float32           `json:"price"`
+	Score       float64           `json:"score"`
+	Highlights  map[string]string `json:"highlights"`
+}
+
+type BrandDto struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type Facets struct {
+	Brands []BrandFacet `json:"brands"`
+	Prices []PriceFacet `json:"prices"`
+}
+
+type BrandFacet struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Count int    `json:"count"`
+}
+
+// PriceFacet counts the hits priced from Min up to but not including Max, Max 0 is unbounded
+type PriceFacet struct {
+	Min   float32 `json:"min"`
+	Max   float32 `json:"max"`
+	Count int     `json:"count"`
+}
@@ -0,0 +1,67 @@
+package index
+
+import "context"
+
+// SearchIndex is implemented by every product search backend
+type SearchIndex interface {
+	Index(ctx context.Context, docs ...Document) error
+	Remove(ctx context.Context, ids ...int) error
+	Search(ctx context.Context, query Query) (*Result, error)
+}
+
+// Document is the searchable view of a product
+type Document struct {
+	ID          int
+	Title       string
+	Description string
+	BrandId     int
+	BrandTitle  string
+	Price       float32
+}
+
+// Query matches every word of Text, the brand and price filters narrow the hits but not the facets.
+// MaxPrice 0 means no upper bound.
+type Query struct {
+	Text     string
+	BrandId  int
+	MinPrice float32
+	MaxPrice float32
+	Limit    int
+	Offset   int
+}
+
+// Result holds one page of hits, Total counts every hit
+type Result struct {
+	Total  int
+	Hits   []Hit
+	Brands []BrandFacet
+	Prices []PriceFacet
+}
+
+// Hit is a matching product, Highlights has the matched fields with the matched words in <em> tags
+type Hit struct {
+	Document   Document
+	Score      float64
+	Highlights map[string]string
+}
+
+type BrandFacet struct {
+	BrandId int
+	Title   string
+	Count   int
+}
+
+// PriceFacet counts the hits priced from Min up to but not including Max, Max 0 is unbounded
+type PriceFacet struct {
+	Min   float32
+	Max   float32
+	Count int
+}
+
+var PriceBuckets = []PriceFacet{
+	{Min: 0, Max: 50000},
+	{Min: 50000, Max: 100000},
+	{Min: 100000, Max: 250000},
+	{Min: 250000, Max: 500000},
+	{Min: 500000},
+}
@@ -0,0 +1,317 @@
+package index
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// field weights, a word of the title counts more than one of the description
+const (
+	titleWeight       = 3
+	brandWeight       = 2
+	descriptionWeight = 1
+)
+
+// match quality of an index term against a query word
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.7
+	typoMatch   = 0.5
+)
+
+// Memory is an in-process inverted index, rebuilt from the database on start
+type Memory struct {
+	mu       sync.RWMutex
+	docs     map[int]Document
+	postings map[string]map[int]float64
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		docs:     map[int]Document{},
+		postings: map[string]map[int]float64{},
+	}
+}
+
+func (m *Memory) Index(ctx context.Context, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, doc := range docs {
+		m.remove(doc.ID)
+		m.docs[doc.ID] = doc
+
+		weights := map[string]float64{}
+		for _, term := range Tokenize(doc.Title) {
+			weights[term] += titleWeight
+		}
+		for _, term := range Tokenize(doc.BrandTitle) {
+			weights[term] += brandWeight
+		}
+		for _, term := range Tokenize(doc.Description) {
+			weights[term] += descriptionWeight
+		}
+		for term, weight := range weights {
+			if m.postings[term] == nil {
+				m.postings[term] = map[int]float64{}
+			}
+			m.postings[term][doc.ID] = weight
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Remove(ctx context.Context, ids ...int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		m.remove(id)
+	}
+	return nil
+}
+
+func (m *Memory) remove(id int) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	for _, text := range []string{doc.Title, doc.BrandTitle, doc.Description} {
+		for _, term := range Tokenize(text) {
+			delete(m.postings[term], id)
+			if len(m.postings[term]) == 0 {
+				delete(m.postings, term)
+			}
+		}
+	}
+	delete(m.docs, id)
+}
+
+func (m *Memory) Search(ctx context.Context, query Query) (*Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	words := Tokenize(query.Text)
+	if len(words) == 0 {
+		return &Result{}, nil
+	}
+
+	//every word must match a term of the document, the document keeps its best match per word
+	var scores map[int]float64
+	matched := map[string]bool{}
+	for _, word := range words {
+		wordScores := map[int]float64{}
+		for term, postings := range m.postings {
+			quality := matchQuality(word, term)
+			if quality == 0 {
+				continue
+			}
+			matched[term] = true
+
+			idf := math.Log(1 + float64(len(m.docs))/float64(len(postings)))
+			for id, weight := range postings {
+				if score := quality * weight * idf; score > wordScores[id] {
+					wordScores[id] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for id := range scores {
+			if wordScore, ok := wordScores[id]; ok {
+				scores[id] += wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	result := &Result{Prices: make([]PriceFacet, len(PriceBuckets))}
+	copy(result.Prices, PriceBuckets)
+	brands := map[int]*BrandFacet{}
+
+	var hits []Hit
+	for id, score := range scores {
+		doc := m.docs[id]
+
+		if brands[doc.BrandId] == nil {
+			brands[doc.BrandId] = &BrandFacet{BrandId: doc.BrandId, Title: doc.BrandTitle}
+		}
+		brands[doc.BrandId].Count++
+		for i, bucket := range result.Prices {
+			if doc.Price >= bucket.Min && (bucket.Max == 0 || doc.Price < bucket.Max) {
+				result.Prices[i].Count++
+			}
+		}
+
+		if query.BrandId > 0 && doc.BrandId != query.BrandId {
+			continue
+		}
+		if doc.Price < query.MinPrice || (query.MaxPrice > 0 && doc.Price > query.MaxPrice) {
+			continue
+		}
+		hits = append(hits, Hit{Document: doc, Score: score})
+	}
+
+	for _, facet := range brands {
+		result.Brands = append(result.Brands, *facet)
+	}
+	sort.Slice(result.Brands, func(i, j int) bool {
+		if result.Brands[i].Count != result.Brands[j].Count {
+			return result.Brands[i].Count > result.Brands[j].Count
+		}
+		return result.Brands[i].BrandId < result.Brands[j].BrandId
+	})
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Document.ID < hits[j].Document.ID
+	})
+
+	result.Total = len(hits)
+	if query.Offset >= len(hits) {
+		return result, nil
+	}
+	hits = hits[query.Offset:]
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	for i := range hits {
+		hits[i].Highlights = map[string]string{}
+		if text, ok := highlight(hits[i].Document.Title, matched); ok {
+			hits[i].Highlights["title"] = text
+		}
+		if text, ok := highlight(hits[i].Document.Description, matched); ok {
+			hits[i].Highlights["description"] = text
+		}
+	}
+	result.Hits = hits
+
+	return result, nil
+}
+
+// Tokenize splits text into lower case words of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// matchQuality rates how well an index term matches a query word: exactly, as a prefix of the term,
+// or within the typos allowed for the length of the word. 0 is no match.
+func matchQuality(word string, term string) float64 {
+	if word == term {
+		return exactMatch
+	}
+	if len(word) >= 2 && strings.HasPrefix(term, word) {
+		return prefixMatch
+	}
+
+	maxEdits := allowedTypos(word)
+	if maxEdits > 0 && editDistance(word, term, maxEdits) <= maxEdits {
+		return typoMatch
+	}
+	return 0
+}
+
+// allowedTypos is no typo for short words, one up to 7 letters and two for longer words
+func allowedTypos(word string) int {
+	switch length := len([]rune(word)); {
+	case length <= 3:
+		return 0
+	case length <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the Levenshtein distance of a and b, or max+1 as soon as it exceeds max
+func editDistance(a string, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < best {
+				best = curr[j]
+			}
+		}
+		if best > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// highlight escapes text for html and wraps the matched words in <em> tags, ok is false when nothing matched
+func highlight(text string, matched map[string]bool) (string, bool) {
+	var (
+		builder strings.Builder
+		found   bool
+		start   = -1
+	)
+	flush := func(end int) {
+		word := text[start:end]
+		if matched[strings.ToLower(word)] {
+			found = true
+			builder.WriteString("<em>" + html.EscapeString(word) + "</em>")
+		} else {
+			builder.WriteString(html.EscapeString(word))
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		if isSeparator(r) {
+			if start >= 0 {
+				flush(i)
+			}
+			builder.WriteString(html.EscapeString(string(r)))
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		flush(len(text))
+	}
+	return builder.String(), found
+}
@@ -0,0 +1,102 @@
+package index_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/index"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIndex() *index.Memory {
+	idx := index.NewMemory()
+	idx.Index(context.TODO(),
+		index.Document{ID: 1, Title: "Basic Cotton T-Shirt", Description: "Soft shirt for every day", BrandId: 1, BrandTitle: "Uniqlo", Price: 99000},
+		index.Document{ID: 2, Title: "Running Shoes", Description: "Light shoes with a cotton lining", BrandId: 2, BrandTitle: "Nike", Price: 1200000},
+		index.Document{ID: 3, Title: "Polo Shirt", Description: "Cotton pique", BrandId: 2, BrandTitle: "Nike", Price: 350000},
+	)
+	return idx
+}
+
+func hitIds(result *index.Result) []int {
+	var ids []int
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Document.ID)
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"basic", "cotton", "t", "shirt", "2026"}, index.Tokenize("Basic  Cotton T-Shirt, 2026!"))
+}
+
+func TestMemorySearch(t *testing.T) {
+	t.Run("Test Search Ranks Title Matches First", func(t *testing.T) {
+		result, err := newIndex().Search(context.TODO(), index.Query{Text: "cotton"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 1, result.Hits[0].Document.ID)
+	})
+
+	t.Run("Test Search Matches Every Word Case Insensitive", func(t *testing.T) {
+		result, _ := newIndex().Search(context.TODO(), index.Query{Text: "NIKE shirt"})
+
+		assert.Equal(t, []int{3}, hitIds(result))
+	})
+
+	t.Run("Test Search Tolerates Typos And Prefixes", func(t *testing.T) {
+		idx := newIndex()
+
+		result, _ := idx.Search(context.TODO(), index.Query{Text: "runing"})
+		assert.Equal(t, []int{2}, hitIds(result))
+
+		result, _ = idx.Search(context.TODO(), index.Query{Text: "sho"})
+		assert.Equal(t, []int{2}, hitIds(result))
+
+		//short words must match exactly
+		result, _ = idx.Search(context.TODO(), index.Query{Text: "pol"})
+		assert.Equal(t, []int{3}, hitIds(result))
+		result, _ = idx.Search(context.TODO(), index.Query{Text: "plo"})
+		assert.Equal(t, 0, result.Total)
+	})
+
+	t.Run("Test Search Facets And Filters", func(t *testing.T) {
+		result, _ := newIndex().Search(context.TODO(), index.Query{Text: "cotton", BrandId: 2, MaxPrice: 500000})
+
+		assert.Equal(t, []int{3}, hitIds(result))
+		//facets count every match of the text, before the filters
+		assert.Equal(t, []index.BrandFacet{{BrandId: 2, Title: "Nike", Count: 2}, {BrandId: 1, Title: "Uniqlo", Count: 1}}, result.Brands)
+		assert.Equal(t, 1, result.Prices[1].Count)
+		assert.Equal(t, 1, result.Prices[3].Count)
+		assert.Equal(t, 1, result.Prices[4].Count)
+	})
+
+	t.Run("Test Search Pagination", func(t *testing.T) {
+		result, _ := newIndex().Search(context.TODO(), index.Query{Text: "cotton", Limit: 1, Offset: 1})
+
+		assert.Equal(t, 3, result.Total)
+		assert.Len(t, result.Hits, 1)
+	})
+
+	t.Run("Test Search Highlights", func(t *testing.T) {
+		result, _ := newIndex().Search(context.TODO(), index.Query{Text: "shirt"})
+
+		assert.Equal(t, "Basic Cotton T-<em>Shirt</em>", result.Hits[0].Highlights["title"])
+		assert.Equal(t, "Soft <em>shirt</em> for every day", result.Hits[0].Highlights["description"])
+	})
+
+	t.Run("Test Reindex And Remove", func(t *testing.T) {
+		idx := newIndex()
+		idx.Index(context.TODO(), index.Document{ID: 2, Title: "Trail Boots", BrandId: 2, BrandTitle: "Nike"})
+
+		result, _ := idx.Search(context.TODO(), index.Query{Text: "running"})
+		assert.Equal(t, 0, result.Total)
+		result, _ = idx.Search(context.TODO(), index.Query{Text: "boots"})
+		assert.Equal(t, []int{2}, hitIds(result))
+
+		idx.Remove(context.TODO(), 2)
+		result, _ = idx.Search(context.TODO(), index.Query{Text: "boots"})
+		assert.Equal(t, 0, result.Total)
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	productService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/index"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const DefaultLimit = 20
+
+type SearchService interface {
+	Search(ctx context.Context, payload dto.SearchDto) (*dto.SearchResult, error, string)
+	IndexProduct(ctx context.Context, productId int) error
+	IndexBrand(ctx context.Context, brandId int) error
+	Rebuild(ctx context.Context) error
+}
+
+type Service struct {
+	index          index.SearchIndex
+	productService productService.ProductService
+	contextTimeout time.Duration
+}
+
+func NewSearchService(index index.SearchIndex, productService productService.ProductService, timeout time.Duration) SearchService {
+	return &Service{
+		index:          index,
+		productService: productService,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *Service) Search(ctx context.Context, payload dto.SearchDto) (*dto.SearchResult, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.MaxPrice > 0 && payload.MinPrice > payload.MaxPrice {
+		return nil, errors.New("minPrice must not be more than maxPrice"), util.VALIDATION_ERROR
+	}
+	if payload.Limit == 0 {
+		payload.Limit = DefaultLimit
+	}
+
+	result, err := s.index.Search(ctx, index.Query{
+		Text:     payload.Q,
+		BrandId:  payload.BrandId,
+		MinPrice: payload.MinPrice,
+		MaxPrice: payload.MaxPrice,
+		Limit:    payload.Limit,
+		Offset:   payload.Offset,
+	})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := &dto.SearchResult{Total: result.Total, Hits: []dto.SearchHit{}}
+	for _, hit := range result.Hits {
+		data.Hits = append(data.Hits, dto.SearchHit{
+			ID:          hit.Document.ID,
+			Title:       hit.Document.Title,
+			Description: hit.Document.Description,
+			Brand:       dto.BrandDto{ID: hit.Document.BrandId, Title: hit.Document.BrandTitle},
+			Price:       hit.Document.Price,
+			Score:       math.Round(hit.Score*1000) / 1000,
+			Highlights:  hit.Highlights,
+		})
+	}
+	for _, facet := range result.Brands {
+		data.Facets.Brands = append(data.Facets.Brands, dto.BrandFacet{ID: facet.BrandId, Title: facet.Title, Count: facet.Count})
+	}
+	for _, facet := range result.Prices {
+		data.Facets.Prices = append(data.Facets.Prices, dto.PriceFacet{Min: facet.Min, Max: facet.Max, Count: facet.Count})
+	}
+
+	return data, nil, util.SUCCESS
+}
+
+// IndexProduct brings the index up to date with a created or changed product
+func (s *Service) IndexProduct(ctx context.Context, productId int) error {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	products, err, _ := s.productService.GetProducts(ctx, ProductDto.FilterProductDto{ID: productId, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(products) == 0 {
+		return s.index.Remove(ctx, productId)
+	}
+	return s.index.Index(ctx, document(products[0]))
+}
+
+// IndexBrand brings the index up to date with the products of a deleted or restored brand, which are hidden
+// or shown with it
+func (s *Service) IndexBrand(ctx context.Context, brandId int) error {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	all, err, _ := s.productService.GetProducts(ctx, ProductDto.FilterProductDto{BrandId: brandId, IncludeDeleted: true})
+	if err != nil {
+		return err
+	}
+	shown, err, _ := s.productService.GetProducts(ctx, ProductDto.FilterProductDto{BrandId: brandId})
+	if err != nil {
+		return err
+	}
+
+	visible := map[int]bool{}
+	docs := make([]index.Document, len(shown))
+	for i, product := range shown {
+		visible[product.ID] = true
+		docs[i] = document(product)
+	}
+	var hidden []int
+	for _, product := range all {
+		if !visible[product.ID] {
+			hidden = append(hidden, product.ID)
+		}
+	}
+
+	if len(hidden) > 0 {
+		if err = s.index.Remove(ctx, hidden...); err != nil {
+			return err
+		}
+	}
+	if len(docs) > 0 {
+		return s.index.Index(ctx, docs...)
+	}
+	return nil
+}
+
+// Rebuild indexes every product, it runs on start
+func (s *Service) Rebuild(ctx context.Context) error {
+	products, err, _ := s.productService.GetProducts(ctx, ProductDto.FilterProductDto{})
+	if err != nil {
+		return err
+	}
+
+	docs := make([]index.Document, len(products))
+	for i, product := range products {
+		docs[i] = document(product)
+	}
+	return s.index.Index(ctx, docs...)
+}
+
+func document(product ProductDto.GetProduct) index.Document {
+	return index.Document{
+		ID:          product.ID,
+		Title:       product.Title,
+		Description: product.Description,
+		BrandId:     product.Brand.ID,
+		BrandTitle:  product.Brand.Title,
+		Price:       product.Price,
+	}
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/index"
+	SearchService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/service"
+	mockProductServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/product/service"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockProductService = new(mockProductServices.ProductService)
+	searchIndex        = index.NewMemory()
+	searchService      = SearchService.NewSearchService(searchIndex, mockProductService, contextTimeout)
+	products           = []ProductDto.GetProduct{
+		{ID: 1, Title: "Basic T-Shirt", Description: "Cotton", Brand: ProductDto.BrandDto{ID: 1, Title: "Uniqlo"}, Price: 99000},
+		{ID: 2, Title: "Running Shoes", Description: "Light", Brand: ProductDto.BrandDto{ID: 2, Title: "Nike"}, Price: 1200000},
+	}
+)
+
+func reset() {
+	mockProductService = new(mockProductServices.ProductService)
+	searchIndex = index.NewMemory()
+	searchService = SearchService.NewSearchService(searchIndex, mockProductService, contextTimeout)
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("Test Search Success", func(t *testing.T) {
+		defer reset()
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{}).Return(products, nil, "SUCCESS")
+		assert.Nil(t, searchService.Rebuild(context.TODO()))
+
+		res, err, state := searchService.Search(context.TODO(), dto.SearchDto{Q: "shirt"})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, res.Total)
+		assert.Equal(t, dto.BrandDto{ID: 1, Title: "Uniqlo"}, res.Hits[0].Brand)
+		assert.Equal(t, "Basic T-<em>Shirt</em>", res.Hits[0].Highlights["title"])
+		assert.Equal(t, []dto.BrandFacet{{ID: 1, Title: "Uniqlo", Count: 1}}, res.Facets.Brands)
+		assert.Len(t, res.Facets.Prices, len(index.PriceBuckets))
+	})
+
+	t.Run("Test Search No Hits", func(t *testing.T) {
+		defer reset()
+
+		res, err, state := searchService.Search(context.TODO(), dto.SearchDto{Q: "shirt"})
+
+		assert.Equal(t, "SUCCESS", state)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, res.Total)
+		assert.NotNil(t, res.Hits)
+	})
+
+	t.Run("Test Search Invalid Price Range", func(t *testing.T) {
+		defer reset()
+
+		_, err, state := searchService.Search(context.TODO(), dto.SearchDto{Q: "shirt", MinPrice: 200000, MaxPrice: 100000})
+
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestIndexProduct(t *testing.T) {
+	t.Run("Test Index Created Product", func(t *testing.T) {
+		defer reset()
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{ID: 2, Limit: 1}).Return(products[1:], nil, "SUCCESS")
+
+		err := searchService.IndexProduct(context.TODO(), 2)
+
+		assert.Nil(t, err)
+		res, _, _ := searchService.Search(context.TODO(), dto.SearchDto{Q: "nike"})
+		assert.Equal(t, 1, res.Total)
+	})
+
+	t.Run("Test Index Missing Product Removes It", func(t *testing.T) {
+		defer reset()
+		searchIndex.Index(context.TODO(), index.Document{ID: 2, Title: "Running Shoes"})
+		mockProductService.On("GetProducts", mock.Anything, mock.Anything).Return(nil, nil, "SUCCESS")
+
+		err := searchService.IndexProduct(context.TODO(), 2)
+
+		assert.Nil(t, err)
+		res, _, _ := searchService.Search(context.TODO(), dto.SearchDto{Q: "running"})
+		assert.Equal(t, 0, res.Total)
+	})
+
+	t.Run("Test Index Product Error", func(t *testing.T) {
+		defer reset()
+		mockProductService.On("GetProducts", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"), "SYSTEM_ERROR")
+
+		err := searchService.IndexProduct(context.TODO(), 2)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestIndexBrand(t *testing.T) {
+	t.Run("Test Index Deleted Brand Removes Its Products", func(t *testing.T) {
+		defer reset()
+		searchIndex.Index(context.TODO(), index.Document{ID: 2, Title: "Running Shoes", BrandId: 2})
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{BrandId: 2, IncludeDeleted: true}).Return(products[1:], nil, "SUCCESS")
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{BrandId: 2}).Return(nil, nil, "SUCCESS")
+
+		err := searchService.IndexBrand(context.TODO(), 2)
+
+		assert.Nil(t, err)
+		res, _, _ := searchService.Search(context.TODO(), dto.SearchDto{Q: "running"})
+		assert.Equal(t, 0, res.Total)
+	})
+
+	t.Run("Test Index Restored Brand Adds Its Products", func(t *testing.T) {
+		defer reset()
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{BrandId: 2, IncludeDeleted: true}).Return(products[1:], nil, "SUCCESS")
+		mockProductService.On("GetProducts", mock.Anything, ProductDto.FilterProductDto{BrandId: 2}).Return(products[1:], nil, "SUCCESS")
+
+		err := searchService.IndexBrand(context.TODO(), 2)
+
+		assert.Nil(t, err)
+		res, _, _ := searchService.Search(context.TODO(), dto.SearchDto{Q: "running"})
+		assert.Equal(t, 1, res.Total)
+	})
+}
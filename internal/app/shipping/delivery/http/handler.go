@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type ShippingHandler struct {
+	ShippingService service.ShippingService
+}
+
+func NewShippingHandler(mux *http.ServeMux, service service.ShippingService) {
+	handler := ShippingHandler{ShippingService: service}
+
+	mux.HandleFunc("/shipping/rates", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.CreateRate(w, r)
+		}
+	})
+
+	mux.HandleFunc("/shipping/quotes", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.Quote(w, r)
+		}
+	})
+}
+
+func (b *ShippingHandler) CreateRate(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	var payload dto.InsertShippingRateDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.ShippingService.CreateRate(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), result)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func (b *ShippingHandler) Quote(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	var payload dto.QuoteShippingDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.ShippingService.QuoteProducts(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "Success", result)
+}
+
+func isRequestValid(payload interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(payload)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	shippingHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/shipping/service"
+)
+
+func TestCreateRate(t *testing.T) {
+	mux := http.NewServeMux()
+
+	payload := dto.InsertShippingRateDto{Region: "JAKARTA", Method: "REG", MaxWeight: 1000, Cost: 9000, EstimatedDays: 2}
+	j, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	mockService := new(mocks.ShippingService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.ShippingService)
+	}
+
+	t.Run("Test Create Rate Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("CreateRate", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+
+		shippingHttp.NewShippingHandler(mux, mockService)
+		handler := shippingHttp.ShippingHandler{ShippingService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/shipping/rates", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.CreateRate(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Rate Missing Region", func(t *testing.T) {
+		defer reset()
+		handler := shippingHttp.ShippingHandler{ShippingService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/shipping/rates", strings.NewReader(`{"method":"REG","cost":9000}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.CreateRate(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestQuote(t *testing.T) {
+	payload := dto.QuoteShippingDto{Region: "JAKARTA", Items: []dto.QuoteShippingItem{{ProductId: 1, Qty: 2}}}
+	j, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	mockService := new(mocks.ShippingService)
+
+	t.Run("Test Quote Success", func(t *testing.T) {
+		mockService.On("QuoteProducts", context.Background(), payload).Return([]dto.GetShippingQuote{{Method: "REG", Cost: 9000}}, nil, "SUCCESS").Once()
+		handler := shippingHttp.ShippingHandler{ShippingService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/shipping/quotes", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Quote(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Quote Invalid Qty", func(t *testing.T) {
+		handler := shippingHttp.ShippingHandler{ShippingService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/shipping/quotes", strings.NewReader(`{"region":"JAKARTA","items":[{"productId":1,"qty":0}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Quote(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Quote No Rate", func(t *testing.T) {
+		mockService.On("QuoteProducts", context.Background(), payload).Return(nil, errors.New("No shipping method available"), "VALIDATION_ERROR").Once()
+		handler := shippingHttp.ShippingHandler{ShippingService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/shipping/quotes", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Quote(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,49 @@
+package dto
+
+type InsertShippingRateDto struct {
+	Region        string  `json:"region" validate:"required,max=50"`
+	Method        string  `json:"method" validate:"required,max=50"`
+	MinWeight     int     `json:"minWeight" validate:"gte=0"`
+	MaxWeight     int     `json:"maxWeight" validate:"gte=0"`
+	Cost          float32 `json:"cost" validate:"gte=0"`
+	EstimatedDays int     `json:"estimatedDays" validate:"gte=0"`
+}
+
+type FilterShippingRateDto struct {
+	Region string `json:"region"`
+	Method string `json:"method"`
+	Limit  int    `json:"limit"`
+}
+
+type QuoteShippingDto struct {
+	Region string              `json:"region" validate:"required"`
+	Items  []QuoteShippingItem `json:"items" validate:"required,min=1,dive"`
+}
+
+type QuoteShippingItem struct {
+	ProductId int `json:"productId" validate:"required"`
+	Qty       int `json:"qty" validate:"required,gt=0"`
+}
+
+// ShippingParcelDto describes the parcel to ship, weights in grams and dimensions in cm
+type ShippingParcelDto struct {
+	Region string
+	Items  []ParcelItem
+}
+
+type ParcelItem struct {
+	Weight int
+	Length int
+	Width  int
+	Height int
+	Qty    int
+}
+
+type GetShippingQuote struct {
+	Provider      string  `json:"provider"`
+	Method        string  `json:"method"`
+	Region        string  `json:"region"`
+	Weight        int     `json:"weight"`
+	Cost          float32 `json:"cost"`
+	EstimatedDays int     `json:"estimatedDays"`
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNoRate = errors.New("No shipping rate available for this destination")
+
+// ShippingRateProvider is implemented by every carrier or rate table integration.
+type ShippingRateProvider interface {
+	Name() string
+	Quote(ctx context.Context, request QuoteRequest) ([]Rate, error)
+}
+
+// QuoteRequest is the destination region and the billable weight of the parcel in grams
+type QuoteRequest struct {
+	Region string
+	Weight int
+}
+
+type Rate struct {
+	Method        string
+	Cost          float32
+	EstimatedDays int
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/repository"
+)
+
+// TableRate quotes from the shipping_rate table: for every method, the weight band of the region
+// that contains the parcel weight.
+type TableRate struct {
+	repository repository.ShippingRepository
+}
+
+func NewTableRate(repository repository.ShippingRepository) *TableRate {
+	return &TableRate{repository: repository}
+}
+
+func (p *TableRate) Name() string {
+	return "table"
+}
+
+func (p *TableRate) Quote(ctx context.Context, request QuoteRequest) ([]Rate, error) {
+	rates, err := p.repository.GetRates(ctx, dto.FilterShippingRateDto{Region: request.Region})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		quotes []Rate
+		quoted = map[string]bool{}
+	)
+	for _, rate := range rates {
+		if quoted[rate.Method] || request.Weight < rate.MinWeight {
+			continue
+		}
+		if rate.MaxWeight > 0 && request.Weight > rate.MaxWeight {
+			continue
+		}
+
+		quoted[rate.Method] = true
+		quotes = append(quotes, Rate{Method: rate.Method, Cost: rate.Cost, EstimatedDays: rate.EstimatedDays})
+	}
+
+	if len(quotes) == 0 {
+		return nil, ErrNoRate
+	}
+	return quotes, nil
+}
@@ -0,0 +1,53 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/provider"
+	mockShippingRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/shipping/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestTableRateQuote(t *testing.T) {
+	rates := []model.ShippingRate{
+		{ID: 1, Region: "JAKARTA", Method: "REG", MinWeight: 0, MaxWeight: 1000, Cost: 9000, EstimatedDays: 2},
+		{ID: 2, Region: "JAKARTA", Method: "REG", MinWeight: 1001, MaxWeight: 0, Cost: 15000, EstimatedDays: 2},
+		{ID: 3, Region: "JAKARTA", Method: "YES", MinWeight: 0, MaxWeight: 2000, Cost: 20000, EstimatedDays: 1},
+	}
+
+	t.Run("Test Quote Picks Weight Band Per Method", func(t *testing.T) {
+		repository := new(mockShippingRepositories.ShippingRepository)
+		repository.On("GetRates", mock.Anything, dto.FilterShippingRateDto{Region: "JAKARTA"}).Return(rates, nil)
+
+		result, err := provider.NewTableRate(repository).Quote(context.TODO(), provider.QuoteRequest{Region: "JAKARTA", Weight: 1500})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []provider.Rate{{Method: "REG", Cost: 15000, EstimatedDays: 2}, {Method: "YES", Cost: 20000, EstimatedDays: 1}}, result)
+	})
+
+	t.Run("Test Quote Heavier Than Every Band", func(t *testing.T) {
+		repository := new(mockShippingRepositories.ShippingRepository)
+		repository.On("GetRates", mock.Anything, mock.Anything).Return(rates[2:], nil)
+
+		result, err := provider.NewTableRate(repository).Quote(context.TODO(), provider.QuoteRequest{Region: "JAKARTA", Weight: 2500})
+
+		assert.Equal(t, provider.ErrNoRate, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test Quote Error Database", func(t *testing.T) {
+		repository := new(mockShippingRepositories.ShippingRepository)
+		repository.On("GetRates", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		result, err := provider.NewTableRate(repository).Quote(context.TODO(), provider.QuoteRequest{Region: "JAKARTA", Weight: 100})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type ShippingRepository interface {
+	CreateRate(ctx context.Context, payload dto.InsertShippingRateDto) (*model.ShippingRate, error)
+	GetRates(ctx context.Context, filter dto.FilterShippingRateDto) (data []model.ShippingRate, err error)
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewShipping(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) CreateRate(ctx context.Context, payload dto.InsertShippingRateDto) (*model.ShippingRate, error) {
+	query := `INSERT INTO shipping_rate (region, method, minWeight, maxWeight, cost, estimatedDays, createdAt, updatedAt)
+	values(?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query, payload.Region, payload.Method, payload.MinWeight, payload.MaxWeight, payload.Cost, payload.EstimatedDays)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ShippingRate{ID: int(id)}, nil
+}
+
+func (r *Repository) GetRates(ctx context.Context, filter dto.FilterShippingRateDto) (data []model.ShippingRate, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, region, method, minWeight, maxWeight, cost, estimatedDays FROM shipping_rate`
+
+	if filter.Region != "" {
+		query += util.FilterHandler(filterValues) + ` region = ?`
+		filterValues = append(filterValues, filter.Region)
+	}
+
+	if filter.Method != "" {
+		query += util.FilterHandler(filterValues) + ` method = ?`
+		filterValues = append(filterValues, filter.Method)
+	}
+
+	query += ` ORDER BY method, minWeight `
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		rate := model.ShippingRate{}
+		err = rows.Scan(&rate.ID, &rate.Region, &rate.Method, &rate.MinWeight, &rate.MaxWeight, &rate.Cost, &rate.EstimatedDays)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, rate)
+	}
+
+	return data, nil
+}
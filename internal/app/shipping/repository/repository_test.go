@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/repository"
+)
+
+func TestCreateRate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertShippingRateDto{Region: "JAKARTA", Method: "REG", MinWeight: 0, MaxWeight: 1000, Cost: 9000, EstimatedDays: 2}
+	query := "INSERT INTO shipping_rate"
+
+	t.Run("Test Create Rate Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.Region, payload.Method, payload.MinWeight, payload.MaxWeight, payload.Cost, payload.EstimatedDays).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewShipping(db, dialect.MySQL)
+		result, err := r.CreateRate(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+	})
+
+	t.Run("Test Create Rate Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewShipping(db, dialect.MySQL)
+		result, err := r.CreateRate(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetRates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, region, method, minWeight, maxWeight, cost, estimatedDays FROM shipping_rate"
+
+	t.Run("Test Get Rates With Filter", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "region", "method", "minWeight", "maxWeight", "cost", "estimatedDays"}).
+			AddRow(1, "JAKARTA", "REG", 0, 1000, 9000, 2).
+			AddRow(2, "JAKARTA", "REG", 1001, 0, 15000, 2)
+		filter := dto.FilterShippingRateDto{Region: "JAKARTA", Method: "REG", Limit: 10}
+		mock.ExpectQuery(query).WithArgs(filter.Region, filter.Method, filter.Limit).WillReturnRows(rows)
+
+		r := repository.NewShipping(db, dialect.MySQL)
+		result, err := r.GetRates(context.TODO(), filter)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 0, result[1].MaxWeight)
+	})
+
+	t.Run("Test Get Rates Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewShipping(db, dialect.MySQL)
+		result, err := r.GetRates(context.TODO(), dto.FilterShippingRateDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	productService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/provider"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+// volumetricDivisor converts cm³ to grams of billable weight, couriers bill 6000 cm³ as 1 kg
+const volumetricDivisor = 6
+
+type ShippingService interface {
+	CreateRate(ctx context.Context, payload dto.InsertShippingRateDto) (interface{}, error, string)
+	Quote(ctx context.Context, parcel dto.ShippingParcelDto) ([]dto.GetShippingQuote, error, string)
+	QuoteMethod(ctx context.Context, parcel dto.ShippingParcelDto, method string) (*dto.GetShippingQuote, error, string)
+	QuoteProducts(ctx context.Context, payload dto.QuoteShippingDto) ([]dto.GetShippingQuote, error, string)
+}
+
+type Service struct {
+	shippingRepository repository.ShippingRepository
+	provider           provider.ShippingRateProvider
+	productService     productService.ProductService
+	contextTimeout     time.Duration
+}
+
+func NewShippingService(r repository.ShippingRepository, provider provider.ShippingRateProvider, productService productService.ProductService, timeout time.Duration) ShippingService {
+	return &Service{
+		shippingRepository: r,
+		provider:           provider,
+		productService:     productService,
+		contextTimeout:     timeout,
+	}
+}
+
+func (s *Service) CreateRate(ctx context.Context, payload dto.InsertShippingRateDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.MaxWeight > 0 && payload.MaxWeight < payload.MinWeight {
+		return nil, errors.New("maxWeight must be greater than minWeight"), util.VALIDATION_ERROR
+	}
+
+	result, err := s.shippingRepository.CreateRate(ctx, payload)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
+}
+
+func (s *Service) Quote(ctx context.Context, parcel dto.ShippingParcelDto) ([]dto.GetShippingQuote, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	weight := BillableWeight(parcel.Items)
+	rates, err := s.provider.Quote(ctx, provider.QuoteRequest{Region: parcel.Region, Weight: weight})
+	if errors.Is(err, provider.ErrNoRate) {
+		return nil, fmt.Errorf("No shipping method available to %s for %d gram", parcel.Region, weight), util.VALIDATION_ERROR
+	}
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	var quotes []dto.GetShippingQuote
+	for _, rate := range rates {
+		quotes = append(quotes, dto.GetShippingQuote{
+			Provider:      s.provider.Name(),
+			Method:        rate.Method,
+			Region:        parcel.Region,
+			Weight:        weight,
+			Cost:          rate.Cost,
+			EstimatedDays: rate.EstimatedDays,
+		})
+	}
+
+	return quotes, nil, util.SUCCESS
+}
+
+func (s *Service) QuoteMethod(ctx context.Context, parcel dto.ShippingParcelDto, method string) (*dto.GetShippingQuote, error, string) {
+	quotes, err, state := s.Quote(ctx, parcel)
+	if err != nil {
+		return nil, err, state
+	}
+
+	for _, quote := range quotes {
+		if quote.Method == method {
+			return &quote, nil, util.SUCCESS
+		}
+	}
+
+	return nil, fmt.Errorf("Shipping method %s is not available to %s", method, parcel.Region), util.VALIDATION_ERROR
+}
+
+func (s *Service) QuoteProducts(ctx context.Context, payload dto.QuoteShippingDto) ([]dto.GetShippingQuote, error, string) {
+	parcel := dto.ShippingParcelDto{Region: payload.Region}
+	for _, item := range payload.Items {
+		product, err, state := s.productService.GetProductById(ctx, item.ProductId)
+		if err != nil {
+			return nil, err, state
+		}
+
+		parcel.Items = append(parcel.Items, dto.ParcelItem{
+			Weight: product.Weight,
+			Length: product.Length,
+			Width:  product.Width,
+			Height: product.Height,
+			Qty:    item.Qty,
+		})
+	}
+
+	return s.Quote(ctx, parcel)
+}
+
+// BillableWeight sums, for every item, the greater of its actual and volumetric weight in grams
+func BillableWeight(items []dto.ParcelItem) int {
+	var weight int
+	for _, item := range items {
+		volumetric := item.Length * item.Width * item.Height / volumetricDivisor
+		if volumetric > item.Weight {
+			weight += volumetric * item.Qty
+		} else {
+			weight += item.Weight * item.Qty
+		}
+	}
+	return weight
+}
@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	BrandService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
+	ProductDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
+	ProductService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/provider"
+	ShippingService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/service"
+	mockBrandRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/brand/repository"
+	mockCategoryServices "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/category/service"
+	mockProductRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/product/repository"
+	mockShippingRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/shipping/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockShippingRepository = new(mockShippingRepositories.ShippingRepository)
+	mockProductRepository  = new(mockProductRepositories.ProductRepository)
+	shippingService        = newService()
+	rates                  = []model.ShippingRate{
+		{ID: 1, Region: "JAKARTA", Method: "REG", MinWeight: 0, MaxWeight: 2000, Cost: 9000, EstimatedDays: 2},
+		{ID: 2, Region: "JAKARTA", Method: "YES", MinWeight: 0, MaxWeight: 2000, Cost: 20000, EstimatedDays: 1},
+	}
+)
+
+func newService() ShippingService.ShippingService {
+	brandService := BrandService.NewBrandService(new(mockBrandRepositories.BrandRepository), contextTimeout)
+	productService := ProductService.NewProductService(mockProductRepository, brandService, new(mockCategoryServices.CategoryService), contextTimeout)
+	return ShippingService.NewShippingService(mockShippingRepository, provider.NewTableRate(mockShippingRepository), productService, contextTimeout)
+}
+
+func reset() {
+	mockShippingRepository = new(mockShippingRepositories.ShippingRepository)
+	mockProductRepository = new(mockProductRepositories.ProductRepository)
+	shippingService = newService()
+}
+
+func TestBillableWeight(t *testing.T) {
+	//30x20x12 cm is 1200 gram volumetric, heavier than the 800 gram actual weight
+	assert.Equal(t, 2400, ShippingService.BillableWeight([]dto.ParcelItem{{Weight: 800, Length: 30, Width: 20, Height: 12, Qty: 2}}))
+	assert.Equal(t, 1500, ShippingService.BillableWeight([]dto.ParcelItem{{Weight: 1500, Length: 10, Width: 10, Height: 10, Qty: 1}}))
+}
+
+func TestCreateRate(t *testing.T) {
+	payload := dto.InsertShippingRateDto{Region: "JAKARTA", Method: "REG", MaxWeight: 1000, Cost: 9000}
+
+	t.Run("Test Create Rate Success", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("CreateRate", mock.Anything, payload).Return(&model.ShippingRate{ID: 1}, nil)
+
+		res, err, state := shippingService.CreateRate(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 1, res.(map[string]interface{})["id"])
+	})
+
+	t.Run("Test Create Rate Invalid Weight Band", func(t *testing.T) {
+		defer reset()
+		invalid := payload
+		invalid.MinWeight = 2000
+
+		_, err, state := shippingService.CreateRate(context.TODO(), invalid)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Create Rate Error Database", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("CreateRate", mock.Anything, payload).Return(nil, errors.New("Database Error"))
+
+		_, err, state := shippingService.CreateRate(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
+
+func TestQuote(t *testing.T) {
+	parcel := dto.ShippingParcelDto{Region: "JAKARTA", Items: []dto.ParcelItem{{Weight: 500, Qty: 2}}}
+
+	t.Run("Test Quote Success", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return(rates, nil)
+
+		res, err, state := shippingService.Quote(context.TODO(), parcel)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, dto.GetShippingQuote{Provider: "table", Method: "REG", Region: "JAKARTA", Weight: 1000, Cost: 9000, EstimatedDays: 2}, res[0])
+		assert.Len(t, res, 2)
+	})
+
+	t.Run("Test Quote No Rate", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return([]model.ShippingRate{}, nil)
+
+		res, err, state := shippingService.Quote(context.TODO(), parcel)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Quote Error Database", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		_, err, state := shippingService.Quote(context.TODO(), parcel)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
+
+func TestQuoteMethod(t *testing.T) {
+	parcel := dto.ShippingParcelDto{Region: "JAKARTA", Items: []dto.ParcelItem{{Weight: 500, Qty: 1}}}
+
+	t.Run("Test Quote Method Success", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return(rates, nil)
+
+		res, err, state := shippingService.QuoteMethod(context.TODO(), parcel, "YES")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, float32(20000), res.Cost)
+	})
+
+	t.Run("Test Quote Method Not Available", func(t *testing.T) {
+		defer reset()
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return(rates, nil)
+
+		res, err, state := shippingService.QuoteMethod(context.TODO(), parcel, "SAMEDAY")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+		assert.Nil(t, res)
+	})
+}
+
+func TestQuoteProducts(t *testing.T) {
+	payload := dto.QuoteShippingDto{Region: "JAKARTA", Items: []dto.QuoteShippingItem{{ProductId: 1, Qty: 2}}}
+
+	t.Run("Test Quote Products Success", func(t *testing.T) {
+		defer reset()
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return([]ProductDto.GetProduct{{ID: 1, Weight: 700}}, nil)
+		mockShippingRepository.On("GetRates", mock.Anything, mock.Anything).Return(rates, nil)
+
+		res, err, state := shippingService.QuoteProducts(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 1400, res[0].Weight)
+	})
+
+	t.Run("Test Quote Products Product Not Found", func(t *testing.T) {
+		defer reset()
+		mockProductRepository.On("GetProduct", mock.Anything, mock.Anything).Return([]ProductDto.GetProduct{}, nil)
+
+		res, err, state := shippingService.QuoteProducts(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "NOT_FOUND", state)
+		assert.Nil(t, res)
+	})
+}
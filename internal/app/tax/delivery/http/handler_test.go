@@ -0,0 +1,87 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	taxHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/tax/service"
+)
+
+func TestCreateTaxRule(t *testing.T) {
+	mux := http.NewServeMux()
+
+	payload := dto.InsertTaxRuleDto{Name: "PPN", Rate: 11}
+	j, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	mockService := new(mocks.TaxService)
+
+	reset := func() {
+		mux = http.NewServeMux()
+		mockService = new(mocks.TaxService)
+	}
+
+	t.Run("Test Create Tax Rule Success", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1}, nil, "SUCCESS")
+
+		taxHttp.NewTaxHandler(mux, mockService)
+		handler := taxHttp.TaxHandler{TaxService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/tax", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Tax Rule Rate Over 100", func(t *testing.T) {
+		defer reset()
+		handler := taxHttp.TaxHandler{TaxService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/tax", strings.NewReader(`{"name":"PPN","rate":110}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Tax Rule Error Database", func(t *testing.T) {
+		defer reset()
+		mockService.On("Create", context.Background(), payload).Return(nil, errors.New("Database Error"), "SYSTEM_ERROR")
+		handler := taxHttp.TaxHandler{TaxService: mockService}
+
+		req := httptest.NewRequest(http.MethodPost, "/tax", strings.NewReader(string(j)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err = handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestGetTaxRules(t *testing.T) {
+	mockService := new(mocks.TaxService)
+
+	t.Run("Test Get Tax Rules Success", func(t *testing.T) {
+		mockService.On("GetTaxRules", context.Background()).Return([]dto.GetTaxRule{{ID: 1, Name: "PPN", Rate: 11}}, nil, "SUCCESS").Once()
+		handler := taxHttp.TaxHandler{TaxService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/tax", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetTaxRules(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package dto
+
+type InsertTaxRuleDto struct {
+	Name      string  `json:"name" validate:"required,max=50"`
+	Rate      float32 `json:"rate" validate:"required,gt=0,lte=100"`
+	Inclusive bool    `json:"inclusive"`
+	BrandId   int     `json:"brandId"`
+	ProductId int     `json:"productId"`
+}
+
+type FilterTaxRuleDto struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+}
+
+type GetTaxRule struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Rate      float32 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	BrandId   int     `json:"brandId,omitempty"`
+	ProductId int     `json:"productId,omitempty"`
+}
+
+// CalculateTaxDto describes the order lines to tax, Amount is the line amount after discounts
+type CalculateTaxDto struct {
+	Lines []TaxLine
+}
+
+type TaxLine struct {
+	ProductId int
+	BrandId   int
+	Amount    float32
+}
+
+// CalculatedTaxDto holds the tax of every line, in the order of CalculateTaxDto.Lines, and the breakdown per rule.
+// Exclusive is the part of the tax that is added on top of the prices.
+type CalculatedTaxDto struct {
+	Lines     []LineTax
+	Taxes     []AppliedTax
+	Total     float32
+	Exclusive float32
+}
+
+type LineTax struct {
+	Tax       float32
+	Exclusive float32
+}
+
+type AppliedTax struct {
+	TaxRuleId int
+	Name      string
+	Rate      float32
+	Inclusive bool
+	Amount    float32
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type TaxRepository interface {
+	Create(ctx context.Context, payload dto.InsertTaxRuleDto) (*model.TaxRule, error)
+	GetTaxRules(ctx context.Context, filter dto.FilterTaxRuleDto) (data []model.TaxRule, err error)
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewTax(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+func (r *Repository) Create(ctx context.Context, payload dto.InsertTaxRuleDto) (*model.TaxRule, error) {
+	query := `INSERT INTO tax_rule (name, rate, inclusive, brandId, productId, createdAt, updatedAt)
+	values(?, ?, ?, NULLIF(?, 0), NULLIF(?, 0), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query, payload.Name, payload.Rate, payload.Inclusive, payload.BrandId, payload.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TaxRule{ID: int(id)}, nil
+}
+
+func (r *Repository) GetTaxRules(ctx context.Context, filter dto.FilterTaxRuleDto) (data []model.TaxRule, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, name, rate, inclusive, brandId, productId FROM tax_rule`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.Name != "" {
+		query += util.FilterHandler(filterValues) + ` name = ?`
+		filterValues = append(filterValues, filter.Name)
+	}
+
+	query += ` ORDER BY id `
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	var rows *sql.Rows
+	rows, err = r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var brandId, productId sql.NullInt64
+		rule := model.TaxRule{}
+		err = rows.Scan(&rule.ID, &rule.Name, &rule.Rate, &rule.Inclusive, &brandId, &productId)
+		if err != nil {
+			return nil, err
+		}
+		rule.BrandId = int(brandId.Int64)
+		rule.ProductId = int(productId.Int64)
+
+		data = append(data, rule)
+	}
+
+	return data, nil
+}
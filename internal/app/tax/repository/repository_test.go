@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/repository"
+)
+
+func TestCreateTaxRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	payload := dto.InsertTaxRuleDto{Name: "PPN", Rate: 11}
+	query := "INSERT INTO tax_rule"
+
+	t.Run("Test Create Tax Rule Success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(payload.Name, payload.Rate, payload.Inclusive, payload.BrandId, payload.ProductId).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		r := repository.NewTax(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.ID)
+	})
+
+	t.Run("Test Create Tax Rule Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewTax(db, dialect.MySQL)
+		result, err := r.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetTaxRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, name, rate, inclusive, brandId, productId FROM tax_rule"
+
+	t.Run("Test Get Tax Rules Without Filter", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "rate", "inclusive", "brandId", "productId"}).
+			AddRow(1, "PPN", 11, false, nil, nil).
+			AddRow(2, "PPnBM", 20, true, 1, nil)
+		mock.ExpectQuery(query + " ORDER BY id").WillReturnRows(rows)
+
+		r := repository.NewTax(db, dialect.MySQL)
+		result, err := r.GetTaxRules(context.TODO(), dto.FilterTaxRuleDto{})
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 0, result[0].BrandId)
+		assert.Equal(t, 1, result[1].BrandId)
+		assert.True(t, result[1].Inclusive)
+	})
+
+	t.Run("Test Get Tax Rules With Filter", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "rate", "inclusive", "brandId", "productId"}).
+			AddRow(1, "PPN", 11, false, nil, nil)
+		filter := dto.FilterTaxRuleDto{ID: 1, Name: "PPN", Limit: 1}
+		mock.ExpectQuery(query).WithArgs(filter.ID, filter.Name, filter.Limit).WillReturnRows(rows)
+
+		r := repository.NewTax(db, dialect.MySQL)
+		result, err := r.GetTaxRules(context.TODO(), filter)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("Test Get Tax Rules Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewTax(db, dialect.MySQL)
+		result, err := r.GetTaxRules(context.TODO(), dto.FilterTaxRuleDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type TaxService interface {
+	Create(ctx context.Context, payload dto.InsertTaxRuleDto) (interface{}, error, string)
+	GetTaxRules(ctx context.Context) ([]dto.GetTaxRule, error, string)
+	Calculate(ctx context.Context, payload dto.CalculateTaxDto) (*dto.CalculatedTaxDto, error, string)
+}
+
+type Service struct {
+	taxRepository  repository.TaxRepository
+	contextTimeout time.Duration
+}
+
+func NewTaxService(r repository.TaxRepository, timeout time.Duration) TaxService {
+	return &Service{
+		taxRepository:  r,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, payload dto.InsertTaxRuleDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.BrandId > 0 && payload.ProductId > 0 {
+		return nil, errors.New("Tax rule can only be scoped to a brand or a product"), util.VALIDATION_ERROR
+	}
+
+	//Check Tax Rule with the same name and scope
+	rules, err := s.taxRepository.GetTaxRules(ctx, dto.FilterTaxRuleDto{Name: payload.Name})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	for _, rule := range rules {
+		if rule.BrandId == payload.BrandId && rule.ProductId == payload.ProductId {
+			return nil, errors.New("Tax rule already Exists"), util.DUPLICATE
+		}
+	}
+
+	result, err := s.taxRepository.Create(ctx, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+
+	return map[string]interface{}{"id": result.ID}, nil, util.SUCCESS
+}
+
+func (s *Service) GetTaxRules(ctx context.Context) ([]dto.GetTaxRule, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	rules, err := s.taxRepository.GetTaxRules(ctx, dto.FilterTaxRuleDto{})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := []dto.GetTaxRule{}
+	for _, rule := range rules {
+		data = append(data, dto.GetTaxRule{
+			ID:        rule.ID,
+			Name:      rule.Name,
+			Rate:      rule.Rate,
+			Inclusive: rule.Inclusive,
+			BrandId:   rule.BrandId,
+			ProductId: rule.ProductId,
+		})
+	}
+
+	return data, nil, util.SUCCESS
+}
+
+// Calculate applies, for every tax name, the most specific rule matching each line.
+// Inclusive taxes are taken out of the line amount, exclusive taxes are computed on the same base and added on top.
+func (s *Service) Calculate(ctx context.Context, payload dto.CalculateTaxDto) (*dto.CalculatedTaxDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	rules, err := s.taxRepository.GetTaxRules(ctx, dto.FilterTaxRuleDto{})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	result := &dto.CalculatedTaxDto{Lines: make([]dto.LineTax, len(payload.Lines))}
+	index := map[int]int{}
+
+	for i, line := range payload.Lines {
+		applied := matchRules(rules, line)
+		if len(applied) == 0 || line.Amount <= 0 {
+			continue
+		}
+
+		var inclusiveRate float32
+		for _, rule := range applied {
+			if rule.Inclusive {
+				inclusiveRate += rule.Rate
+			}
+		}
+		base := line.Amount / (1 + inclusiveRate/100)
+
+		for _, rule := range applied {
+			amount := util.RoundMoney(base * rule.Rate / 100)
+
+			result.Lines[i].Tax = util.RoundMoney(result.Lines[i].Tax + amount)
+			if !rule.Inclusive {
+				result.Lines[i].Exclusive = util.RoundMoney(result.Lines[i].Exclusive + amount)
+			}
+
+			idx, ok := index[rule.ID]
+			if !ok {
+				idx = len(result.Taxes)
+				index[rule.ID] = idx
+				result.Taxes = append(result.Taxes, dto.AppliedTax{
+					TaxRuleId: rule.ID,
+					Name:      rule.Name,
+					Rate:      rule.Rate,
+					Inclusive: rule.Inclusive,
+				})
+			}
+			result.Taxes[idx].Amount = util.RoundMoney(result.Taxes[idx].Amount + amount)
+		}
+
+		result.Total = util.RoundMoney(result.Total + result.Lines[i].Tax)
+		result.Exclusive = util.RoundMoney(result.Exclusive + result.Lines[i].Exclusive)
+	}
+
+	return result, nil, util.SUCCESS
+}
+
+// matchRules returns the rule to apply for every tax name, in the order the names were first defined
+func matchRules(rules []model.TaxRule, line dto.TaxLine) []model.TaxRule {
+	var (
+		names    []string
+		selected = map[string]model.TaxRule{}
+	)
+	for _, rule := range rules {
+		if !matches(rule, line) {
+			continue
+		}
+		current, ok := selected[rule.Name]
+		if !ok {
+			names = append(names, rule.Name)
+		}
+		if !ok || specificity(rule) > specificity(current) {
+			selected[rule.Name] = rule
+		}
+	}
+
+	var applied []model.TaxRule
+	for _, name := range names {
+		applied = append(applied, selected[name])
+	}
+	return applied
+}
+
+func matches(rule model.TaxRule, line dto.TaxLine) bool {
+	if rule.ProductId > 0 {
+		return rule.ProductId == line.ProductId
+	}
+	if rule.BrandId > 0 {
+		return rule.BrandId == line.BrandId
+	}
+	return true
+}
+
+func specificity(rule model.TaxRule) int {
+	if rule.ProductId > 0 {
+		return 2
+	}
+	if rule.BrandId > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/dto"
+	TaxService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/service"
+	mockTaxRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/tax/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+const contextTimeout = 2 * time.Second
+
+var (
+	mockTaxRepository = new(mockTaxRepositories.TaxRepository)
+	taxService        = TaxService.NewTaxService(mockTaxRepository, contextTimeout)
+)
+
+func reset() {
+	mockTaxRepository = new(mockTaxRepositories.TaxRepository)
+	taxService = TaxService.NewTaxService(mockTaxRepository, contextTimeout)
+}
+
+func TestCreateTaxRule(t *testing.T) {
+	payload := dto.InsertTaxRuleDto{Name: "PPN", Rate: 11, BrandId: 1}
+
+	t.Run("Test Create Tax Rule Success", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, dto.FilterTaxRuleDto{Name: "PPN"}).Return([]model.TaxRule{{ID: 1, Name: "PPN", Rate: 11}}, nil)
+		mockTaxRepository.On("Create", mock.Anything, payload).Return(&model.TaxRule{ID: 2}, nil)
+
+		res, err, state := taxService.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, 2, res.(map[string]interface{})["id"])
+	})
+
+	t.Run("Test Create Tax Rule Duplicate Scope", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{{ID: 1, Name: "PPN", Rate: 11, BrandId: 1}}, nil)
+
+		res, err, state := taxService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "DUPLICATE", state)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test Create Tax Rule Both Brand And Product Scope", func(t *testing.T) {
+		defer reset()
+		invalid := payload
+		invalid.ProductId = 1
+
+		_, err, state := taxService.Create(context.TODO(), invalid)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", state)
+	})
+
+	t.Run("Test Create Tax Rule Error Database", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		_, err, state := taxService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+	})
+}
+
+func TestGetTaxRules(t *testing.T) {
+	t.Run("Test Get Tax Rules Success", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, dto.FilterTaxRuleDto{}).Return([]model.TaxRule{{ID: 1, Name: "PPN", Rate: 11}}, nil)
+
+		res, err, state := taxService.GetTaxRules(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, []dto.GetTaxRule{{ID: 1, Name: "PPN", Rate: 11}}, res)
+	})
+}
+
+func TestCalculate(t *testing.T) {
+	lines := []dto.TaxLine{
+		{ProductId: 1, BrandId: 1, Amount: 100000},
+		{ProductId: 2, BrandId: 2, Amount: 111000},
+	}
+
+	t.Run("Test Calculate Exclusive Default Rule", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{{ID: 1, Name: "PPN", Rate: 11}}, nil)
+
+		res, err, state := taxService.Calculate(context.TODO(), dto.CalculateTaxDto{Lines: lines[:1]})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SUCCESS", state)
+		assert.Equal(t, []dto.LineTax{{Tax: 11000, Exclusive: 11000}}, res.Lines)
+		assert.Equal(t, float32(11000), res.Total)
+		assert.Equal(t, float32(11000), res.Exclusive)
+		assert.Equal(t, []dto.AppliedTax{{TaxRuleId: 1, Name: "PPN", Rate: 11, Amount: 11000}}, res.Taxes)
+	})
+
+	t.Run("Test Calculate Brand Rule Overrides Default", func(t *testing.T) {
+		defer reset()
+		rules := []model.TaxRule{
+			{ID: 1, Name: "PPN", Rate: 11},
+			{ID: 2, Name: "PPN", Rate: 11, Inclusive: true, BrandId: 2},
+		}
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(rules, nil)
+
+		res, err, _ := taxService.Calculate(context.TODO(), dto.CalculateTaxDto{Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.LineTax{{Tax: 11000, Exclusive: 11000}, {Tax: 11000}}, res.Lines)
+		assert.Equal(t, float32(22000), res.Total)
+		assert.Equal(t, float32(11000), res.Exclusive)
+		assert.Len(t, res.Taxes, 2)
+	})
+
+	t.Run("Test Calculate Different Taxes Stack", func(t *testing.T) {
+		defer reset()
+		rules := []model.TaxRule{
+			{ID: 1, Name: "PPN", Rate: 11},
+			{ID: 2, Name: "PPnBM", Rate: 20, ProductId: 1},
+		}
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(rules, nil)
+
+		res, err, _ := taxService.Calculate(context.TODO(), dto.CalculateTaxDto{Lines: lines[:1]})
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(31000), res.Lines[0].Tax)
+		assert.Equal(t, float32(31000), res.Exclusive)
+	})
+
+	t.Run("Test Calculate Without Rules", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return([]model.TaxRule{}, nil)
+
+		res, err, _ := taxService.Calculate(context.TODO(), dto.CalculateTaxDto{Lines: lines})
+
+		assert.Nil(t, err)
+		assert.Equal(t, float32(0), res.Total)
+		assert.Len(t, res.Lines, 2)
+		assert.Nil(t, res.Taxes)
+	})
+
+	t.Run("Test Calculate Error Database", func(t *testing.T) {
+		defer reset()
+		mockTaxRepository.On("GetTaxRules", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error"))
+
+		res, err, state := taxService.Calculate(context.TODO(), dto.CalculateTaxDto{Lines: lines})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", state)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/service"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type WebhookHandler struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookHandler(mux *http.ServeMux, service service.WebhookService) {
+	handler := WebhookHandler{WebhookService: service}
+
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			handler.Create(w, r)
+		case "GET":
+			handler.GetSubscriptions(w, r)
+		}
+	})
+
+	mux.HandleFunc("/webhook/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/webhook/")
+		switch {
+		case action == "" && r.Method == "GET":
+			handler.GetSubscription(w, r)
+		case action == "" && r.Method == "PUT":
+			handler.Update(w, r)
+		case action == "" && r.Method == "DELETE":
+			handler.Delete(w, r)
+		case action == "deliveries" && r.Method == "GET":
+			handler.GetDeliveries(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mux.HandleFunc("/webhook/deliveries/", func(w http.ResponseWriter, r *http.Request) {
+		_, action := util.GetPathId(r.URL.Path, "/webhook/deliveries/")
+		switch {
+		case action == "redeliver" && r.Method == "POST":
+			handler.Redeliver(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func (b *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	var payload dto.InsertSubscriptionDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.WebhookService.Create(r.Context(), payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *WebhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	result, err, state := b.WebhookService.GetSubscriptions(r.Context())
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/webhook/")
+	result, err, state := b.WebhookService.GetSubscription(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/webhook/")
+
+	var payload dto.UpdateSubscriptionDto
+	err := util.Decode(r, &payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(util.GetDecodeState(err)), err.Error(), nil)
+	}
+
+	var valid bool
+	if valid, err = isRequestValid(&payload); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.WebhookService.Update(r.Context(), id, payload)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func (b *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/webhook/")
+	result, err, state := b.WebhookService.Delete(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+// GetDeliveries is the delivery log of the subscription of the path, newest first, e.g.
+// GET /webhook/3/deliveries?status=FAILED
+func (b *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/webhook/")
+	query := r.URL.Query()
+	filter := dto.FilterDeliveryDto{SubscriptionId: id, Status: query.Get("status")}
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	if valid, err := isRequestValid(&filter); !valid {
+		return res.Render(w, r, false, util.GetResCode(util.VALIDATION_ERROR), err.Error(), nil)
+	}
+
+	result, err, state := b.WebhookService.GetDeliveries(r.Context(), filter)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+// Redeliver sends the delivery of the path again, e.g. POST /webhook/deliveries/12/redeliver
+func (b *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) error {
+	var res *util.Response
+
+	id, _ := util.GetPathId(r.URL.Path, "/webhook/deliveries/")
+	result, err, state := b.WebhookService.Redeliver(r.Context(), id)
+	if err != nil {
+		return res.Render(w, r, false, util.GetResCode(state), err.Error(), nil)
+	}
+	return res.Render(w, r, true, util.GetResCode(state), "success", result)
+}
+
+func isRequestValid(payload interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(payload)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,142 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	webhookHttp "github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/delivery/http"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	mocks "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/webhook/service"
+)
+
+func TestCreate(t *testing.T) {
+	mockService := new(mocks.WebhookService)
+
+	t.Run("Test Create Success", func(t *testing.T) {
+		payload := dto.InsertSubscriptionDto{Url: "https://partner.test/hook", EventTypes: []string{"OrderCreated", "OrderStatusChanged"}}
+		mockService.On("Create", context.Background(), payload).Return(map[string]interface{}{"id": 1, "secret": "secret"}, nil, "SUCCESS").Once()
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		body := `{"url": "https://partner.test/hook", "eventTypes": ["OrderCreated", "OrderStatusChanged"]}`
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Create Unknown Event Type", func(t *testing.T) {
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		body := `{"url": "https://partner.test/hook", "eventTypes": ["OrderDeleted"]}`
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Test Create Invalid Url", func(t *testing.T) {
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		body := `{"url": "partner", "eventTypes": ["OrderCreated"]}`
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Create(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockService := new(mocks.WebhookService)
+
+	t.Run("Test Update Not Found", func(t *testing.T) {
+		active := false
+		payload := dto.UpdateSubscriptionDto{Url: "https://partner.test/hook", EventTypes: []string{"BrandCreated"}, Active: &active}
+		mockService.On("Update", context.Background(), 9, payload).Return(nil, errors.New("Webhook subscription not found"), "NOT_FOUND").Once()
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		body := `{"url": "https://partner.test/hook", "eventTypes": ["BrandCreated"], "active": false}`
+		req := httptest.NewRequest(http.MethodPut, "/webhook/9", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Update(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Test Update Without Active", func(t *testing.T) {
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		body := `{"url": "https://partner.test/hook", "eventTypes": ["BrandCreated"]}`
+		req := httptest.NewRequest(http.MethodPut, "/webhook/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		err := handler.Update(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	mockService := new(mocks.WebhookService)
+
+	t.Run("Test Get Deliveries Of Subscription", func(t *testing.T) {
+		filter := dto.FilterDeliveryDto{SubscriptionId: 3, Status: "FAILED", Limit: 20}
+		mockService.On("GetDeliveries", context.Background(), filter).Return([]dto.DeliveryDto{{ID: 1, SubscriptionId: 3}}, nil, "SUCCESS").Once()
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/webhook/3/deliveries?status=FAILED&limit=20", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetDeliveries(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Test Get Deliveries Invalid Status", func(t *testing.T) {
+		handler := webhookHttp.WebhookHandler{WebhookService: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/webhook/3/deliveries?status=LOST", nil)
+		w := httptest.NewRecorder()
+		err := handler.GetDeliveries(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	mockService := new(mocks.WebhookService)
+
+	t.Run("Test Redeliver Success", func(t *testing.T) {
+		mockService.On("Redeliver", context.Background(), 12).Return(&dto.DeliveryDto{ID: 12, Status: "DELIVERED"}, nil, "SUCCESS").Once()
+
+		mux := http.NewServeMux()
+		webhookHttp.NewWebhookHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook/deliveries/12/redeliver", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Test Redeliver Wrong Method", func(t *testing.T) {
+		mux := http.NewServeMux()
+		webhookHttp.NewWebhookHandler(mux, mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/webhook/deliveries/12/redeliver", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,71 @@
+package dto
+
+// InsertSubscriptionDto subscribes Url to EventTypes. A secret is made up when Secret is empty, Active is true
+// when left out.
+type InsertSubscriptionDto struct {
+	Url        string   `json:"url" validate:"required,url,max=500"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=100"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,unique,dive,oneof=OrderCreated OrderStatusChanged ProductPriceChanged BrandCreated"`
+	Active     *bool    `json:"active"`
+}
+
+// UpdateSubscriptionDto replaces the subscription, an empty Secret keeps the one it has
+type UpdateSubscriptionDto struct {
+	Url        string   `json:"url" validate:"required,url,max=500"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=100"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,unique,dive,oneof=OrderCreated OrderStatusChanged ProductPriceChanged BrandCreated"`
+	Active     *bool    `json:"active" validate:"required"`
+}
+
+type FilterSubscriptionDto struct {
+	ID         int
+	ActiveOnly bool
+}
+
+// SubscriptionDto leaves the secret out, it is only shown when the subscription is created
+type SubscriptionDto struct {
+	ID         int      `json:"id"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+// InsertDeliveryDto is an event to send to a subscription, Payload is the body to post
+type InsertDeliveryDto struct {
+	SubscriptionId int
+	EventId        int
+	EventType      string
+	Payload        string
+}
+
+type FilterDeliveryDto struct {
+	ID             int    `json:"id"`
+	SubscriptionId int    `json:"subscriptionId"`
+	Status         string `json:"status" validate:"omitempty,oneof=PENDING DELIVERED FAILED"`
+	Limit          int    `json:"limit" validate:"gte=0,lte=1000"`
+}
+
+// DeliveryAttemptDto is the outcome of an attempt: the status the delivery is left in, the response code, 0 when
+// none came back, the error and, for a delivery still pending, when to try it next
+type DeliveryAttemptDto struct {
+	Status        string
+	ResponseCode  int
+	LastError     string
+	NextAttemptAt string
+}
+
+type DeliveryDto struct {
+	ID             int    `json:"id"`
+	SubscriptionId int    `json:"subscriptionId"`
+	EventId        int    `json:"eventId"`
+	EventType      string `json:"eventType"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseCode   int    `json:"responseCode,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+	NextAttemptAt  string `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    string `json:"deliveredAt,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+}
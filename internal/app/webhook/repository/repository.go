@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, payload dto.InsertSubscriptionDto) (*model.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, filter dto.FilterSubscriptionDto) (data []model.WebhookSubscription, err error)
+	UpdateSubscription(ctx context.Context, id int, payload dto.UpdateSubscriptionDto) (bool, error)
+	// DeleteSubscription deletes the subscription with its deliveries
+	DeleteSubscription(ctx context.Context, id int) (bool, error)
+	// CreateDeliveries queues the deliveries, skipping the events a subscription already has a delivery of
+	CreateDeliveries(ctx context.Context, payloads []dto.InsertDeliveryDto) error
+	GetDeliveries(ctx context.Context, filter dto.FilterDeliveryDto) (data []model.WebhookDelivery, err error)
+	// GetPendingDeliveries returns the oldest pending deliveries of the active subscriptions that are due at now.
+	// Those of an inactive subscription stay PENDING and are returned again once it is reactivated.
+	GetPendingDeliveries(ctx context.Context, now time.Time, limit int) (data []model.WebhookDelivery, err error)
+	// UpdateDelivery counts an attempt of the delivery and keeps its outcome
+	UpdateDelivery(ctx context.Context, id int, attempt dto.DeliveryAttemptDto) error
+}
+
+type Repository struct {
+	DB      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func NewWebhook(db *sql.DB, dialect dialect.Dialect) *Repository {
+	return &Repository{db, dialect}
+}
+
+// the delivery dates are written by the app in UTC, like the outbox ones
+func utc(t time.Time) string {
+	return t.UTC().Format(util.DateTimeLayout)
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, payload dto.InsertSubscriptionDto) (*model.WebhookSubscription, error) {
+	active := payload.Active == nil || *payload.Active
+
+	query := `INSERT INTO webhook_subscription (url, secret, eventTypes, active, createdAt, updatedAt)
+	values(?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+	id, err := r.Dialect.Insert(ctx, r.DB, query, payload.Url, payload.Secret, strings.Join(payload.EventTypes, ","), active)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebhookSubscription{ID: int(id), Url: payload.Url, Secret: payload.Secret, EventTypes: payload.EventTypes, Active: active}, nil
+}
+
+func (r *Repository) GetSubscriptions(ctx context.Context, filter dto.FilterSubscriptionDto) (data []model.WebhookSubscription, err error) {
+	var filterValues []interface{}
+	query := `SELECT id, url, secret, eventTypes, active, createdAt, updatedAt FROM webhook_subscription`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.ActiveOnly {
+		query += util.FilterHandler(filterValues) + ` active = ?`
+		filterValues = append(filterValues, true)
+	}
+
+	query += ` ORDER BY id`
+
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), filterValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			subscription model.WebhookSubscription
+			eventTypes   string
+		)
+		err = rows.Scan(&subscription.ID, &subscription.Url, &subscription.Secret, &eventTypes, &subscription.Active,
+			&subscription.CreatedAt, &subscription.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subscription.EventTypes = strings.Split(eventTypes, ",")
+
+		data = append(data, subscription)
+	}
+
+	return data, rows.Err()
+}
+
+func (r *Repository) UpdateSubscription(ctx context.Context, id int, payload dto.UpdateSubscriptionDto) (bool, error) {
+	query := `UPDATE webhook_subscription SET url = ?, secret = COALESCE(NULLIF(?, ''), secret), eventTypes = ?, active = ?,
+	updatedAt = CURRENT_TIMESTAMP WHERE id = ?`
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), payload.Url, payload.Secret, strings.Join(payload.EventTypes, ","),
+		*payload.Active, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, id int) (bool, error) {
+	result, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(`DELETE FROM webhook_subscription WHERE id = ?`), id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) CreateDeliveries(ctx context.Context, payloads []dto.InsertDeliveryDto) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	var (
+		placeholders []string
+		values       []interface{}
+	)
+	now := utc(time.Now())
+	for _, payload := range payloads {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)")
+		values = append(values, payload.SubscriptionId, payload.EventId, payload.EventType, payload.Payload, model.WebhookDeliveryPending, now)
+	}
+
+	//an event published again, the outbox delivers at least once, keeps the delivery it has
+	query := `INSERT INTO webhook_delivery (subscriptionId, eventId, eventType, payload, status, nextAttemptAt, createdAt, updatedAt) VALUES ` +
+		strings.Join(placeholders, ",")
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(r.Dialect.InsertIgnore(query)), values...)
+	return err
+}
+
+const deliveryColumns = `d.id, d.subscriptionId, d.eventId, d.eventType, d.payload, d.status, d.attempts, d.responseCode, d.lastError,
+	d.nextAttemptAt, d.deliveredAt, d.createdAt, d.updatedAt`
+
+func (r *Repository) GetDeliveries(ctx context.Context, filter dto.FilterDeliveryDto) (data []model.WebhookDelivery, err error) {
+	var filterValues []interface{}
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_delivery d`
+
+	if filter.ID > 0 {
+		query += util.FilterHandler(filterValues) + ` d.id = ?`
+		filterValues = append(filterValues, filter.ID)
+	}
+
+	if filter.SubscriptionId > 0 {
+		query += util.FilterHandler(filterValues) + ` d.subscriptionId = ?`
+		filterValues = append(filterValues, filter.SubscriptionId)
+	}
+
+	if filter.Status != "" {
+		query += util.FilterHandler(filterValues) + ` d.status = ?`
+		filterValues = append(filterValues, filter.Status)
+	}
+
+	//newest first, like the audit log
+	query += ` ORDER BY d.id DESC`
+
+	if filter.Limit > 0 {
+		filterValues = append(filterValues, filter.Limit)
+		query += ` LIMIT ?`
+	}
+
+	return r.queryDeliveries(ctx, query, filterValues...)
+}
+
+func (r *Repository) GetPendingDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_delivery d
+	JOIN webhook_subscription s ON s.id = d.subscriptionId
+	WHERE d.status = ? AND d.nextAttemptAt <= ? AND s.active = ? ORDER BY d.id LIMIT ?`
+	return r.queryDeliveries(ctx, query, model.WebhookDeliveryPending, utc(now), true, limit)
+}
+
+func (r *Repository) queryDeliveries(ctx context.Context, query string, values ...interface{}) (data []model.WebhookDelivery, err error) {
+	rows, err := r.DB.QueryContext(ctx, r.Dialect.Rebind(query), values...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			delivery                              model.WebhookDelivery
+			responseCode                          sql.NullInt64
+			lastError, nextAttemptAt, deliveredAt sql.NullString
+		)
+		err = rows.Scan(&delivery.ID, &delivery.SubscriptionId, &delivery.EventId, &delivery.EventType, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &responseCode, &lastError, &nextAttemptAt, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		delivery.ResponseCode = int(responseCode.Int64)
+		delivery.LastError = lastError.String
+		delivery.NextAttemptAt = nextAttemptAt.String
+		delivery.DeliveredAt = deliveredAt.String
+
+		data = append(data, delivery)
+	}
+
+	return data, rows.Err()
+}
+
+func (r *Repository) UpdateDelivery(ctx context.Context, id int, attempt dto.DeliveryAttemptDto) error {
+	var deliveredAt interface{}
+	if attempt.Status == model.WebhookDeliveryDelivered {
+		deliveredAt = utc(time.Now())
+	}
+
+	query := `UPDATE webhook_delivery SET status = ?, attempts = attempts + 1, responseCode = NULLIF(?, 0), lastError = NULLIF(?, ''),
+	nextAttemptAt = ?, deliveredAt = COALESCE(?, deliveredAt), updatedAt = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, r.Dialect.Rebind(query), attempt.Status, attempt.ResponseCode, attempt.LastError,
+		sql.NullString{String: attempt.NextAttemptAt, Valid: attempt.NextAttemptAt != ""}, deliveredAt, id)
+	return err
+}
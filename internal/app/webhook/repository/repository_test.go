@@ -0,0 +1,248 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+)
+
+func TestCreateSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO webhook_subscription"
+	payload := dto.InsertSubscriptionDto{Url: "https://partner.test/hook", Secret: "0123456789abcdef",
+		EventTypes: []string{"OrderCreated", "OrderStatusChanged"}}
+
+	t.Run("Test Create Subscription Active By Default", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("https://partner.test/hook", "0123456789abcdef", "OrderCreated,OrderStatusChanged", true).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.CreateSubscription(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 4, result.ID)
+		assert.True(t, result.Active)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Subscription Inactive", func(t *testing.T) {
+		inactive := false
+		payload := payload
+		payload.Active = &inactive
+		mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.CreateSubscription(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.False(t, result.Active)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create Subscription Error Database", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.CreateSubscription(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	columns := []string{"id", "url", "secret", "eventTypes", "active", "createdAt", "updatedAt"}
+
+	t.Run("Test Get Active Subscriptions", func(t *testing.T) {
+		query := regexp.QuoteMeta(`SELECT id, url, secret, eventTypes, active, createdAt, updatedAt FROM webhook_subscription WHERE active = ? ORDER BY id`)
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "https://partner.test/hook", "secret", "OrderCreated,BrandCreated", true, "2026-10-19 10:00:00", "2026-10-19 10:00:00")
+		mock.ExpectQuery(query).WithArgs(true).WillReturnRows(rows)
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.GetSubscriptions(context.TODO(), dto.FilterSubscriptionDto{ActiveOnly: true})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.WebhookSubscription{{ID: 1, Url: "https://partner.test/hook", Secret: "secret",
+			EventTypes: []string{"OrderCreated", "BrandCreated"}, Active: true, CreatedAt: "2026-10-19 10:00:00",
+			UpdatedAt: "2026-10-19 10:00:00"}}, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Get Subscription By Id", func(t *testing.T) {
+		query := regexp.QuoteMeta(`FROM webhook_subscription WHERE id = ? ORDER BY id`)
+		mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.GetSubscriptions(context.TODO(), dto.FilterSubscriptionDto{ID: 2})
+
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Get Subscriptions Error Database", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.GetSubscriptions(context.TODO(), dto.FilterSubscriptionDto{})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta("UPDATE webhook_subscription SET url = ?, secret = COALESCE(NULLIF(?, ''), secret)")
+	active := false
+	payload := dto.UpdateSubscriptionDto{Url: "https://partner.test/v2", EventTypes: []string{"BrandCreated"}, Active: &active}
+
+	t.Run("Test Update Subscription Keeps Secret", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("https://partner.test/v2", "", "BrandCreated", false, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		updated, err := r.UpdateSubscription(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.True(t, updated)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Subscription Not Found", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		updated, err := r.UpdateSubscription(context.TODO(), 9, payload)
+
+		assert.Nil(t, err)
+		assert.False(t, updated)
+	})
+}
+
+func TestCreateDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	t.Run("Test Create Deliveries Ignores Duplicates", func(t *testing.T) {
+		query := regexp.QuoteMeta("INSERT IGNORE INTO webhook_delivery")
+		mock.ExpectExec(query).
+			WithArgs(1, 7, "OrderCreated", `{"id":7}`, "PENDING", sqlmock.AnyArg(), 2, 7, "OrderCreated", `{"id":7}`, "PENDING", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		err := r.CreateDeliveries(context.TODO(), []dto.InsertDeliveryDto{
+			{SubscriptionId: 1, EventId: 7, EventType: "OrderCreated", Payload: `{"id":7}`},
+			{SubscriptionId: 2, EventId: 7, EventType: "OrderCreated", Payload: `{"id":7}`},
+		})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Create No Deliveries", func(t *testing.T) {
+		r := repository.NewWebhook(db, dialect.MySQL)
+		err := r.CreateDeliveries(context.TODO(), nil)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPendingDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta(`WHERE d.status = ? AND d.nextAttemptAt <= ? AND s.active = ? ORDER BY d.id LIMIT ?`)
+	columns := []string{"id", "subscriptionId", "eventId", "eventType", "payload", "status", "attempts", "responseCode", "lastError",
+		"nextAttemptAt", "deliveredAt", "createdAt", "updatedAt"}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Test Get Pending Deliveries Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, 1, 7, "OrderCreated", `{"id":7}`, "PENDING", 0, nil, nil, "2026-10-19 09:59:00", nil, "2026-10-19 09:59:00", "2026-10-19 09:59:00").
+			AddRow(2, 1, 8, "OrderCreated", `{"id":8}`, "PENDING", 1, 503, "Webhook answered 503", "2026-10-19 10:00:00", nil,
+				"2026-10-19 09:58:00", "2026-10-19 09:59:50")
+		mock.ExpectQuery(query).WithArgs("PENDING", "2026-10-19 10:00:00", true, 100).WillReturnRows(rows)
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.GetPendingDeliveries(context.TODO(), now, 100)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 0, result[0].ResponseCode)
+		assert.Equal(t, 503, result[1].ResponseCode)
+		assert.Equal(t, "Webhook answered 503", result[1].LastError)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Get Pending Deliveries Error Database", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("Database Error"))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		result, err := r.GetPendingDeliveries(context.TODO(), now, 100)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := regexp.QuoteMeta("UPDATE webhook_delivery SET status = ?, attempts = attempts + 1")
+
+	t.Run("Test Update Delivery Delivered", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("DELIVERED", 200, "", nil, sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		err := r.UpdateDelivery(context.TODO(), 3, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryDelivered, ResponseCode: 200})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Test Update Delivery Retry", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("PENDING", 503, "Webhook answered 503", "2026-10-19 10:00:10", nil, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		r := repository.NewWebhook(db, dialect.MySQL)
+		err := r.UpdateDelivery(context.TODO(), 3, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryPending, ResponseCode: 503,
+			LastError: "Webhook answered 503", NextAttemptAt: "2026-10-19 10:00:10"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,133 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/database/databasetest"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestWebhookSuite(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *sql.DB, d dialect.Dialect) {
+		r := repository.NewWebhook(db, d)
+		ctx := context.TODO()
+
+		partner, err := r.CreateSubscription(ctx, dto.InsertSubscriptionDto{Url: "https://partner.test/hook", Secret: "0123456789abcdef",
+			EventTypes: []string{model.EventOrderCreated, model.EventBrandCreated}})
+		assert.Nil(t, err)
+		inactive := false
+		paused, err := r.CreateSubscription(ctx, dto.InsertSubscriptionDto{Url: "https://paused.test/hook", Secret: "0123456789abcdef",
+			EventTypes: []string{model.EventOrderCreated}, Active: &inactive})
+		assert.Nil(t, err)
+
+		t.Run("Test Subscriptions Active Only", func(t *testing.T) {
+			result, err := r.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ActiveOnly: true})
+
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, partner.ID, result[0].ID)
+			assert.Equal(t, []string{model.EventOrderCreated, model.EventBrandCreated}, result[0].EventTypes)
+			assert.True(t, result[0].Active)
+		})
+
+		t.Run("Test Deliveries Are Queued Once", func(t *testing.T) {
+			deliveries := []dto.InsertDeliveryDto{
+				{SubscriptionId: partner.ID, EventId: 1, EventType: model.EventOrderCreated, Payload: `{"id":1}`},
+				{SubscriptionId: paused.ID, EventId: 1, EventType: model.EventOrderCreated, Payload: `{"id":1}`},
+			}
+			assert.Nil(t, r.CreateDeliveries(ctx, deliveries))
+			//the outbox published the event again
+			assert.Nil(t, r.CreateDeliveries(ctx, deliveries[:1]))
+
+			result, err := r.GetDeliveries(ctx, dto.FilterDeliveryDto{SubscriptionId: partner.ID})
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, model.WebhookDeliveryPending, result[0].Status)
+			assert.Equal(t, `{"id":1}`, result[0].Payload)
+		})
+
+		t.Run("Test Pending Deliveries Of Active Subscriptions", func(t *testing.T) {
+			result, err := r.GetPendingDeliveries(ctx, time.Now(), 10)
+
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, partner.ID, result[0].SubscriptionId)
+		})
+
+		t.Run("Test Failed Attempt Waits For Its Retry", func(t *testing.T) {
+			pending, err := r.GetPendingDeliveries(ctx, time.Now(), 10)
+			assert.Nil(t, err)
+			id := pending[0].ID
+
+			next := time.Now().Add(time.Minute).UTC().Format(util.DateTimeLayout)
+			err = r.UpdateDelivery(ctx, id, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryPending, ResponseCode: 503,
+				LastError: "Webhook answered 503", NextAttemptAt: next})
+			assert.Nil(t, err)
+
+			result, err := r.GetPendingDeliveries(ctx, time.Now(), 10)
+			assert.Nil(t, err)
+			assert.Empty(t, result)
+			result, err = r.GetPendingDeliveries(ctx, time.Now().Add(2*time.Minute), 10)
+			assert.Nil(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, 1, result[0].Attempts)
+			assert.Equal(t, 503, result[0].ResponseCode)
+			assert.Equal(t, "Webhook answered 503", result[0].LastError)
+
+			err = r.UpdateDelivery(ctx, id, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryDelivered, ResponseCode: 200})
+			assert.Nil(t, err)
+
+			delivered, err := r.GetDeliveries(ctx, dto.FilterDeliveryDto{ID: id})
+			assert.Nil(t, err)
+			assert.Equal(t, model.WebhookDeliveryDelivered, delivered[0].Status)
+			assert.Equal(t, 2, delivered[0].Attempts)
+			assert.Equal(t, 200, delivered[0].ResponseCode)
+			assert.Empty(t, delivered[0].LastError)
+			assert.Empty(t, delivered[0].NextAttemptAt)
+			assert.NotEmpty(t, delivered[0].DeliveredAt)
+		})
+
+		t.Run("Test Update Subscription Keeps Secret", func(t *testing.T) {
+			active := true
+			updated, err := r.UpdateSubscription(ctx, paused.ID, dto.UpdateSubscriptionDto{Url: "https://paused.test/v2",
+				EventTypes: []string{model.EventOrderStatusChanged}, Active: &active})
+			assert.Nil(t, err)
+			assert.True(t, updated)
+
+			result, err := r.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ID: paused.ID})
+			assert.Nil(t, err)
+			assert.Equal(t, "https://paused.test/v2", result[0].Url)
+			assert.Equal(t, "0123456789abcdef", result[0].Secret)
+			assert.True(t, result[0].Active)
+
+			//active again, its delivery is sent
+			pending, err := r.GetPendingDeliveries(ctx, time.Now(), 10)
+			assert.Nil(t, err)
+			assert.Len(t, pending, 1)
+			assert.Equal(t, paused.ID, pending[0].SubscriptionId)
+		})
+
+		t.Run("Test Delete Subscription With Its Deliveries", func(t *testing.T) {
+			deleted, err := r.DeleteSubscription(ctx, paused.ID)
+			assert.Nil(t, err)
+			assert.True(t, deleted)
+
+			result, err := r.GetDeliveries(ctx, dto.FilterDeliveryDto{SubscriptionId: paused.ID})
+			assert.Nil(t, err)
+			assert.Empty(t, result)
+
+			deleted, err = r.DeleteSubscription(ctx, paused.ID)
+			assert.Nil(t, err)
+			assert.False(t, deleted)
+		})
+	})
+}
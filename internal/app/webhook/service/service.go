@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+const (
+	DefaultLimit = 100
+	// MaxAttempts a delivery gets before it is left FAILED, RetryBackoff is the wait after the first failed
+	// attempt, doubled after every other one
+	MaxAttempts  = 8
+	RetryBackoff = 10 * time.Second
+	// BatchSize is how many due deliveries DeliverPending sends at most
+	BatchSize = 100
+)
+
+// WebhookService keeps the subscriptions and sends them the events they subscribed to. It is an
+// publisher.EventPublisher: Publish queues a delivery per subscription, DeliverPending sends them.
+type WebhookService interface {
+	Create(ctx context.Context, payload dto.InsertSubscriptionDto) (interface{}, error, string)
+	GetSubscriptions(ctx context.Context) ([]dto.SubscriptionDto, error, string)
+	GetSubscription(ctx context.Context, id int) (*dto.SubscriptionDto, error, string)
+	Update(ctx context.Context, id int, payload dto.UpdateSubscriptionDto) (interface{}, error, string)
+	Delete(ctx context.Context, id int) (interface{}, error, string)
+	GetDeliveries(ctx context.Context, filter dto.FilterDeliveryDto) ([]dto.DeliveryDto, error, string)
+	// Redeliver sends the delivery again right away, whatever its status
+	Redeliver(ctx context.Context, id int) (*dto.DeliveryDto, error, string)
+	Publish(ctx context.Context, message eventDto.Message) error
+	// DeliverPending sends the deliveries due now and returns how many went through. The deliveries of a
+	// deactivated subscription are kept PENDING and sent once it is active again. Sending stops when ctx is
+	// done, the deliveries left keep their attempts for the next run.
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+type Service struct {
+	webhookRepository repository.WebhookRepository
+	client            *http.Client
+	contextTimeout    time.Duration
+}
+
+func NewWebhookService(r repository.WebhookRepository, client *http.Client, timeout time.Duration) WebhookService {
+	return &Service{
+		webhookRepository: r,
+		client:            client,
+		contextTimeout:    timeout,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, payload dto.InsertSubscriptionDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if payload.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err, util.SYSTEM_ERROR
+		}
+		payload.Secret = secret
+	}
+
+	result, err := s.webhookRepository.CreateSubscription(ctx, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+
+	//the only time the secret is shown, the partner needs it to verify the signatures
+	return map[string]interface{}{"id": result.ID, "secret": result.Secret}, nil, util.SUCCESS
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (s *Service) GetSubscriptions(ctx context.Context) ([]dto.SubscriptionDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, dto.FilterSubscriptionDto{})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := []dto.SubscriptionDto{}
+	for _, subscription := range subscriptions {
+		data = append(data, subscriptionDto(subscription))
+	}
+
+	return data, nil, util.SUCCESS
+}
+
+func (s *Service) GetSubscription(ctx context.Context, id int) (*dto.SubscriptionDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ID: id})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(subscriptions) == 0 {
+		return nil, errors.New("Webhook subscription not found"), util.NOT_FOUND
+	}
+
+	data := subscriptionDto(subscriptions[0])
+	return &data, nil, util.SUCCESS
+}
+
+func subscriptionDto(subscription model.WebhookSubscription) dto.SubscriptionDto {
+	return dto.SubscriptionDto{
+		ID:         subscription.ID,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func (s *Service) Update(ctx context.Context, id int, payload dto.UpdateSubscriptionDto) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	updated, err := s.webhookRepository.UpdateSubscription(ctx, id, payload)
+	if err != nil {
+		return nil, err, util.ErrorState(err)
+	}
+	if !updated {
+		return nil, errors.New("Webhook subscription not found"), util.NOT_FOUND
+	}
+
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
+
+func (s *Service) Delete(ctx context.Context, id int) (interface{}, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	deleted, err := s.webhookRepository.DeleteSubscription(ctx, id)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if !deleted {
+		return nil, errors.New("Webhook subscription not found"), util.NOT_FOUND
+	}
+
+	return map[string]interface{}{"id": id}, nil, util.SUCCESS
+}
+
+func (s *Service) GetDeliveries(ctx context.Context, filter dto.FilterDeliveryDto) ([]dto.DeliveryDto, error, string) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	deliveries, err := s.webhookRepository.GetDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := []dto.DeliveryDto{}
+	for _, delivery := range deliveries {
+		data = append(data, deliveryDto(delivery))
+	}
+
+	return data, nil, util.SUCCESS
+}
+
+func deliveryDto(delivery model.WebhookDelivery) dto.DeliveryDto {
+	return dto.DeliveryDto{
+		ID:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseCode:   delivery.ResponseCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+// Redeliver runs without the context timeout, the partner gets as long to answer as on any other attempt
+func (s *Service) Redeliver(ctx context.Context, id int) (*dto.DeliveryDto, error, string) {
+	deliveries, err := s.webhookRepository.GetDeliveries(ctx, dto.FilterDeliveryDto{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(deliveries) == 0 {
+		return nil, errors.New("Webhook delivery not found"), util.NOT_FOUND
+	}
+
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ID: deliveries[0].SubscriptionId})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+	if len(subscriptions) == 0 {
+		return nil, errors.New("Webhook subscription not found"), util.NOT_FOUND
+	}
+
+	if _, err = s.deliver(ctx, subscriptions[0], deliveries[0], time.Now()); err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	deliveries, err = s.webhookRepository.GetDeliveries(ctx, dto.FilterDeliveryDto{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err, util.SYSTEM_ERROR
+	}
+
+	data := deliveryDto(deliveries[0])
+	return &data, nil, util.SUCCESS
+}
+
+// Publish queues a delivery of the event for every active subscription to its type. A subscription already
+// queued for the event keeps the delivery it has, so the event is sent once however many times it is published.
+func (s *Service) Publish(ctx context.Context, message eventDto.Message) error {
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ActiveOnly: true})
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []dto.InsertDeliveryDto
+	for _, subscription := range subscriptions {
+		if subscribed(subscription, message.Type) {
+			deliveries = append(deliveries, dto.InsertDeliveryDto{SubscriptionId: subscription.ID, EventId: message.ID, EventType: message.Type,
+				Payload: string(body)})
+		}
+	}
+
+	return s.webhookRepository.CreateDeliveries(ctx, deliveries)
+}
+
+func subscribed(subscription model.WebhookSubscription, eventType string) bool {
+	for _, subscribedType := range subscription.EventTypes {
+		if subscribedType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) DeliverPending(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.webhookRepository.GetPendingDeliveries(ctx, now, BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, dto.FilterSubscriptionDto{ActiveOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	byId := map[int]model.WebhookSubscription{}
+	for _, subscription := range subscriptions {
+		byId[subscription.ID] = subscription
+	}
+
+	delivered := 0
+	var failed []error
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			failed = append(failed, ctx.Err())
+			break
+		}
+		subscription, ok := byId[delivery.SubscriptionId]
+		if !ok {
+			//deactivated since, the delivery waits for it to be active again
+			continue
+		}
+		status, err := s.deliver(ctx, subscription, delivery, now)
+		if err != nil {
+			log.Printf("failed to deliver webhook %d: %s", delivery.ID, err.Error())
+			failed = append(failed, err)
+			continue
+		}
+		if status == model.WebhookDeliveryDelivered {
+			delivered++
+		}
+	}
+
+	return delivered, errors.Join(failed...)
+}
+
+// deliver posts the delivery to the subscription and keeps the outcome of the attempt, it returns the status the
+// delivery is left in. A failed attempt is tried again after the backoff, the last one leaves the delivery FAILED.
+func (s *Service) deliver(ctx context.Context, subscription model.WebhookSubscription, delivery model.WebhookDelivery, now time.Time) (string, error) {
+	sender := publisher.HTTP{URL: subscription.Url, Secret: subscription.Secret, Client: s.client}
+	code, err := sender.Post(ctx, delivery.EventId, []byte(delivery.Payload))
+	if err != nil && ctx.Err() != nil {
+		//the job ran out of time, not the subscriber, the attempt isn't counted
+		return delivery.Status, ctx.Err()
+	}
+
+	attempt := dto.DeliveryAttemptDto{Status: model.WebhookDeliveryDelivered, ResponseCode: code}
+	if err != nil {
+		attempt.Status = model.WebhookDeliveryFailed
+		attempt.LastError = truncate(err.Error(), 255)
+		if delivery.Attempts+1 < MaxAttempts {
+			attempt.Status = model.WebhookDeliveryPending
+			attempt.NextAttemptAt = now.Add(backoff(delivery.Attempts)).UTC().Format(util.DateTimeLayout)
+		}
+	}
+
+	return attempt.Status, s.webhookRepository.UpdateDelivery(ctx, delivery.ID, attempt)
+}
+
+// backoff is the wait before the next attempt of a delivery that failed attempts times before this one
+func backoff(attempts int) time.Duration {
+	return RetryBackoff << attempts
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
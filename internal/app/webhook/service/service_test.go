@@ -0,0 +1,386 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	eventDto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/dto"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/service"
+	mockRepositories "github.com/ranggabudipangestu/simple-ecommerce/internal/mocks/app/webhook/repository"
+	"github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+var timeoutContext = time.Duration(2) * time.Second
+
+func TestCreate(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+	payload := dto.InsertSubscriptionDto{Url: "https://partner.test/hook", EventTypes: []string{model.EventOrderCreated}}
+
+	t.Run("Test Create Makes Up A Secret", func(t *testing.T) {
+		mockRepo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(payload dto.InsertSubscriptionDto) bool {
+			return len(payload.Secret) == 64
+		})).Return(func(ctx context.Context, payload dto.InsertSubscriptionDto) *model.WebhookSubscription {
+			return &model.WebhookSubscription{ID: 1, Secret: payload.Secret}
+		}, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, 1, res.(map[string]interface{})["id"])
+		assert.Len(t, res.(map[string]interface{})["secret"], 64)
+	})
+
+	t.Run("Test Create With Secret", func(t *testing.T) {
+		payload := payload
+		payload.Secret = "0123456789abcdef"
+		mockRepo.On("CreateSubscription", mock.Anything, payload).Return(&model.WebhookSubscription{ID: 2, Secret: payload.Secret}, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, map[string]interface{}{"id": 2, "secret": "0123456789abcdef"}, res)
+	})
+
+	t.Run("Test Create Error Database", func(t *testing.T) {
+		mockRepo.On("CreateSubscription", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error")).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Create(context.TODO(), payload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, util.SYSTEM_ERROR, state)
+	})
+}
+
+func TestGetSubscription(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+
+	t.Run("Test Get Subscription Leaves Secret Out", func(t *testing.T) {
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ID: 1}).Return([]model.WebhookSubscription{{ID: 1,
+			Url: "https://partner.test/hook", Secret: "secret", EventTypes: []string{model.EventOrderCreated}, Active: true}}, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.GetSubscription(context.TODO(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, &dto.SubscriptionDto{ID: 1, Url: "https://partner.test/hook", EventTypes: []string{model.EventOrderCreated}, Active: true}, res)
+	})
+
+	t.Run("Test Get Subscription Not Found", func(t *testing.T) {
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ID: 9}).Return(nil, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.GetSubscription(context.TODO(), 9)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, util.NOT_FOUND, state)
+	})
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+	active := true
+	payload := dto.UpdateSubscriptionDto{Url: "https://partner.test/hook", EventTypes: []string{model.EventOrderCreated}, Active: &active}
+
+	t.Run("Test Update Success", func(t *testing.T) {
+		mockRepo.On("UpdateSubscription", mock.Anything, 1, payload).Return(true, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Update(context.TODO(), 1, payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, map[string]interface{}{"id": 1}, res)
+	})
+
+	t.Run("Test Update Not Found", func(t *testing.T) {
+		mockRepo.On("UpdateSubscription", mock.Anything, 9, payload).Return(false, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		_, err, state := webhookService.Update(context.TODO(), 9, payload)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, util.NOT_FOUND, state)
+	})
+
+	t.Run("Test Delete Not Found", func(t *testing.T) {
+		mockRepo.On("DeleteSubscription", mock.Anything, 9).Return(false, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		_, err, state := webhookService.Delete(context.TODO(), 9)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, util.NOT_FOUND, state)
+	})
+
+	t.Run("Test Delete Error Database", func(t *testing.T) {
+		mockRepo.On("DeleteSubscription", mock.Anything, 1).Return(false, errors.New("Database Error")).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		_, err, state := webhookService.Delete(context.TODO(), 1)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, util.SYSTEM_ERROR, state)
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+
+	t.Run("Test Get Deliveries Default Limit", func(t *testing.T) {
+		mockRepo.On("GetDeliveries", mock.Anything, dto.FilterDeliveryDto{SubscriptionId: 1, Limit: 100}).Return([]model.WebhookDelivery{
+			{ID: 2, SubscriptionId: 1, EventId: 7, EventType: model.EventOrderCreated, Payload: `{"id":7}`, Status: model.WebhookDeliveryFailed,
+				Attempts: 8, ResponseCode: 500, LastError: "Webhook answered 500"},
+		}, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.GetDeliveries(context.TODO(), dto.FilterDeliveryDto{SubscriptionId: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, []dto.DeliveryDto{{ID: 2, SubscriptionId: 1, EventId: 7, EventType: model.EventOrderCreated,
+			Status: model.WebhookDeliveryFailed, Attempts: 8, ResponseCode: 500, LastError: "Webhook answered 500"}}, res)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+
+	message := eventDto.Message{ID: 7, Type: model.EventOrderCreated, EntityId: 3, Payload: json.RawMessage(`{"id":3}`), OccurredAt: "2026-10-19 10:00:00"}
+	body, _ := json.Marshal(message)
+
+	t.Run("Test Publish Queues Subscribed Only", func(t *testing.T) {
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return([]model.WebhookSubscription{
+			{ID: 1, EventTypes: []string{model.EventBrandCreated, model.EventOrderCreated}},
+			{ID: 2, EventTypes: []string{model.EventBrandCreated}},
+			{ID: 3, EventTypes: []string{model.EventOrderCreated}},
+		}, nil).Once()
+		mockRepo.On("CreateDeliveries", mock.Anything, []dto.InsertDeliveryDto{
+			{SubscriptionId: 1, EventId: 7, EventType: model.EventOrderCreated, Payload: string(body)},
+			{SubscriptionId: 3, EventId: 7, EventType: model.EventOrderCreated, Payload: string(body)},
+		}).Return(nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		err := webhookService.Publish(context.TODO(), message)
+
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Test Publish Error Database", func(t *testing.T) {
+		mockRepo.On("GetSubscriptions", mock.Anything, mock.Anything).Return(nil, errors.New("Database Error")).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		err := webhookService.Publish(context.TODO(), message)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDeliverPending(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+
+	reset := func() {
+		mockRepo = new(mockRepositories.WebhookRepository)
+	}
+
+	status := http.StatusOK
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		assert.Equal(t, "7", r.Header.Get(publisher.EventIdHeader))
+		assert.True(t, util.VerifySignature("0123456789abcdef", r.Header.Get(util.TimestampHeader), r.Header.Get(util.SignatureHeader),
+			received, time.Minute, time.Now()))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	subscriptions := []model.WebhookSubscription{{ID: 1, Url: server.URL, Secret: "0123456789abcdef", Active: true}}
+	delivery := model.WebhookDelivery{ID: 4, SubscriptionId: 1, EventId: 7, EventType: model.EventOrderCreated, Payload: `{"id":7}`,
+		Status: model.WebhookDeliveryPending}
+
+	t.Run("Test Deliver Pending Signed", func(t *testing.T) {
+		defer reset()
+		status = http.StatusNoContent
+
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{delivery}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(subscriptions, nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, 4, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryDelivered, ResponseCode: 204}).
+			Return(nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, `{"id":7}`, string(received))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Test Deliver Pending Backs Off", func(t *testing.T) {
+		defer reset()
+		status = http.StatusServiceUnavailable
+
+		failed := delivery
+		failed.Attempts = 2
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{failed}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(subscriptions, nil).Once()
+
+		var attempt dto.DeliveryAttemptDto
+		start := time.Now()
+		mockRepo.On("UpdateDelivery", mock.Anything, 4, mock.AnythingOfType("dto.DeliveryAttemptDto")).
+			Run(func(args mock.Arguments) { attempt = args.Get(2).(dto.DeliveryAttemptDto) }).Return(nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, model.WebhookDeliveryPending, attempt.Status)
+		assert.Equal(t, 503, attempt.ResponseCode)
+		assert.Equal(t, "Webhook answered 503", attempt.LastError)
+		//10s after the first failure, doubled for each one before it
+		next, err := time.Parse(util.DateTimeLayout, attempt.NextAttemptAt)
+		assert.Nil(t, err)
+		assert.InDelta(t, 40*time.Second, next.Sub(start), float64(2*time.Second))
+	})
+
+	t.Run("Test Deliver Pending Gives Up", func(t *testing.T) {
+		defer reset()
+		status = http.StatusInternalServerError
+
+		failed := delivery
+		failed.Attempts = service.MaxAttempts - 1
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{failed}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(subscriptions, nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, 4, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryFailed, ResponseCode: 500,
+			LastError: "Webhook answered 500"}).Return(nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Test Deliver Pending Skips Deactivated", func(t *testing.T) {
+		defer reset()
+
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{delivery}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(nil, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+		mockRepo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Deliver Pending Continues After An Error", func(t *testing.T) {
+		defer reset()
+		status = http.StatusOK
+
+		next := delivery
+		next.ID = 5
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{delivery, next}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(subscriptions, nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, 4, mock.Anything).Return(errors.New("Database Error")).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, 5, mock.Anything).Return(nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Test Deliver Pending Stops When The Job Is Done", func(t *testing.T) {
+		defer reset()
+
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]model.WebhookDelivery{delivery}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ActiveOnly: true}).Return(subscriptions, nil).Once()
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		delivered, err := webhookService.DeliverPending(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, delivered)
+		mockRepo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test Deliver Pending Error Database", func(t *testing.T) {
+		defer reset()
+
+		mockRepo.On("GetPendingDeliveries", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Database Error")).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		_, err := webhookService.DeliverPending(context.TODO())
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	mockRepo := new(mockRepositories.WebhookRepository)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("Test Redeliver Failed Delivery", func(t *testing.T) {
+		failed := model.WebhookDelivery{ID: 4, SubscriptionId: 1, EventId: 7, Status: model.WebhookDeliveryFailed, Attempts: service.MaxAttempts}
+		mockRepo.On("GetDeliveries", mock.Anything, dto.FilterDeliveryDto{ID: 4, Limit: 1}).Return([]model.WebhookDelivery{failed}, nil).Once()
+		mockRepo.On("GetSubscriptions", mock.Anything, dto.FilterSubscriptionDto{ID: 1}).
+			Return([]model.WebhookSubscription{{ID: 1, Url: server.URL, Secret: "secret"}}, nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, 4, dto.DeliveryAttemptDto{Status: model.WebhookDeliveryDelivered, ResponseCode: 200}).
+			Return(nil).Once()
+		delivered := failed
+		delivered.Status, delivered.Attempts, delivered.ResponseCode = model.WebhookDeliveryDelivered, service.MaxAttempts+1, 200
+		mockRepo.On("GetDeliveries", mock.Anything, dto.FilterDeliveryDto{ID: 4, Limit: 1}).Return([]model.WebhookDelivery{delivered}, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Redeliver(context.TODO(), 4)
+
+		assert.Nil(t, err)
+		assert.Equal(t, util.SUCCESS, state)
+		assert.Equal(t, model.WebhookDeliveryDelivered, res.Status)
+		assert.Equal(t, 200, res.ResponseCode)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Test Redeliver Not Found", func(t *testing.T) {
+		mockRepo.On("GetDeliveries", mock.Anything, dto.FilterDeliveryDto{ID: 9, Limit: 1}).Return(nil, nil).Once()
+
+		webhookService := service.NewWebhookService(mockRepo, http.DefaultClient, timeoutContext)
+		res, err, state := webhookService.Redeliver(context.TODO(), 9)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, util.NOT_FOUND, state)
+	})
+}
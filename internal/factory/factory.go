@@ -1,14 +1,51 @@
 package factory
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
+	"github.com/ranggabudipangestu/simple-ecommerce/database"
+	"github.com/ranggabudipangestu/simple-ecommerce/database/dialect"
+
+	auditHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/delivery/http"
+	AuditRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/repository"
+	AuditService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/audit/service"
+
 	brandHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/delivery/http"
 	BrandRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/repository"
 	BrandService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
 
+	EventPublisher "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/publisher"
+	EventRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/repository"
+	EventService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/event/service"
+
+	jobHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/delivery/http"
+	JobRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/repository"
+	JobService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/job/service"
+
+	webhookHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/delivery/http"
+	WebhookRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/repository"
+	WebhookService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/webhook/service"
+
+	categoryHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/delivery/http"
+	CategoryRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/repository"
+	CategoryService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/category/service"
+
+	mediaHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/delivery/http"
+	MediaRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/repository"
+	MediaService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/service"
+	MediaStorage "github.com/ranggabudipangestu/simple-ecommerce/internal/app/media/storage"
+
+	searchHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/delivery/http"
+	SearchIndex "github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/index"
+	SearchService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/search/service"
+
 	productHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/delivery/http"
 	ProductRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/repository"
 	ProductService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
@@ -16,22 +53,201 @@ import (
 	orderHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/delivery/http"
 	OrderRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/repository"
 	OrderService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/service"
+
+	paymentHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/delivery/http"
+	PaymentProvider "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/provider"
+	PaymentRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/repository"
+	PaymentService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/payment/service"
+
+	promotionHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/delivery/http"
+	PromotionRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/repository"
+	PromotionService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/promotion/service"
+
+	taxHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/delivery/http"
+	TaxRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/repository"
+	TaxService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/tax/service"
+
+	shippingHandler "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/delivery/http"
+	ShippingProvider "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/provider"
+	ShippingRepository "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/repository"
+	ShippingService "github.com/ranggabudipangestu/simple-ecommerce/internal/app/shipping/service"
 )
 
-func RegisterHandlers(mux *http.ServeMux, db *sql.DB) {
+// SelfNegotiatedPaths answer in formats of their own, exports and media files, and skip the Accept check
+var SelfNegotiatedPaths = []string{"/product/export", "/order/export", "/media/files/"}
+
+// RegisterHandlers wires the app into mux and returns the runner of its background jobs, for the caller to run
+func RegisterHandlers(mux *http.ServeMux, db *sql.DB, dbDialect dialect.Dialect) *JobService.Runner {
 
 	const contextTimeout = 2 * time.Second
+	//uploads decode the image and write its thumbnails
+	const mediaTimeout = 15 * time.Second
+
+	//brands, products and orders are kept by the repositories themselves when running without a database
+	memory := database.Memory()
 
-	brandRepository := BrandRepository.NewBrand(db)
+	//the repositories record their changes themselves, this one reads them back
+	auditRepository := AuditRepository.NewAudit(db, dbDialect)
+	auditService := AuditService.NewAuditService(auditRepository, contextTimeout)
+	auditHandler.NewAuditHandler(mux, auditService)
+
+	//the webhook subscriptions get a delivery of every event they subscribed to, sent by a background job
+	webhookRepository := WebhookRepository.NewWebhook(db, dbDialect)
+	webhookService := WebhookService.NewWebhookService(webhookRepository, &http.Client{Timeout: 10 * time.Second}, contextTimeout)
+	webhookHandler.NewWebhookHandler(mux, webhookService)
+
+	//the repositories write their domain events to the outbox with their changes, the relay publishes them
+	outboxRepository := EventRepository.NewOutbox(db, dbDialect)
+	eventPublisher := EventPublisher.NewFanout(newEventPublisher(), webhookService)
+	relay := EventService.NewRelay(outboxRepository, eventPublisher)
+
+	var brandRepository BrandRepository.BrandRepository = BrandRepository.NewBrand(db, dbDialect)
+	if memory {
+		brandRepository = BrandRepository.NewMemoryBrand(auditRepository, outboxRepository)
+	}
 	brandService := BrandService.NewBrandService(brandRepository, contextTimeout)
 	brandHandler.NewBrandHandlers(mux, brandService)
 
-	productRepository := ProductRepository.NewProduct(db)
-	productService := ProductService.NewProductService(productRepository, brandService, contextTimeout)
+	categoryRepository := CategoryRepository.NewCategory(db, dbDialect)
+	categoryService := CategoryService.NewCategoryService(categoryRepository, contextTimeout)
+	categoryHandler.NewCategoryHandler(mux, categoryService)
+
+	var productRepository ProductRepository.ProductRepository = ProductRepository.NewProduct(db, dbDialect)
+	if memory {
+		productRepository = ProductRepository.NewMemoryProduct(brandRepository, categoryRepository, auditRepository, outboxRepository)
+	}
+	productService := ProductService.NewProductService(productRepository, brandService, categoryService, contextTimeout)
 	productHandler.NewProductHandler(mux, productService)
 
-	orderRepository := OrderRepository.NewOrder(db)
-	orderService := OrderService.NewOrderService(orderRepository, productService, contextTimeout)
+	mediaStorage := MediaStorage.NewLocal(getEnv("MEDIA_DIR", "storage/media"), getEnv("MEDIA_BASE_URL", "/media/files"))
+	mediaRepository := MediaRepository.NewMedia(db, dbDialect)
+	mediaService := MediaService.NewMediaService(mediaRepository, mediaStorage, productService, mediaTimeout)
+	productService.RegisterImageLoader(mediaService.GetProductImages)
+	mediaHandler.NewMediaHandler(mux, mediaService)
+	mux.Handle("/media/files/", http.StripPrefix("/media/files/", mediaStorage.Handler()))
+
+	searchService := SearchService.NewSearchService(SearchIndex.NewMemory(), productService, contextTimeout)
+	productService.RegisterChangeHandler(searchService.IndexProduct)
+	brandService.RegisterChangeHandler(searchService.IndexBrand)
+	go func() {
+		if err := searchService.Rebuild(context.Background()); err != nil {
+			log.Println("Failed to build the search index:", err)
+		}
+	}()
+	searchHandler.NewSearchHandler(mux, searchService)
+
+	paymentRepository := PaymentRepository.NewPayment(db, dbDialect)
+	paymentProvider := PaymentProvider.NewFake(os.Getenv("PAYMENT_FAKE_MODE"))
+	webhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		log.Println("PAYMENT_WEBHOOK_SECRET is not set, every payment webhook will be rejected")
+	}
+	paymentService := PaymentService.NewPaymentService(paymentRepository, paymentProvider, webhookSecret, contextTimeout)
+	paymentHandler.NewPaymentHandler(mux, paymentService)
+
+	promotionRepository := PromotionRepository.NewPromotion(db, dbDialect)
+	promotionService := PromotionService.NewPromotionService(promotionRepository, contextTimeout)
+	promotionHandler.NewPromotionHandler(mux, promotionService)
+
+	taxRepository := TaxRepository.NewTax(db, dbDialect)
+	taxService := TaxService.NewTaxService(taxRepository, contextTimeout)
+	taxHandler.NewTaxHandler(mux, taxService)
+
+	shippingRepository := ShippingRepository.NewShipping(db, dbDialect)
+	shippingService := ShippingService.NewShippingService(shippingRepository, ShippingProvider.NewTableRate(shippingRepository), productService, contextTimeout)
+	shippingHandler.NewShippingHandler(mux, shippingService)
+
+	var orderRepository OrderRepository.OrderRepository = OrderRepository.NewOrder(db, dbDialect)
+	if memory {
+		orderRepository = OrderRepository.NewMemoryOrder(productRepository, promotionRepository, auditRepository, outboxRepository)
+	}
+	orderService := OrderService.NewOrderService(orderRepository, productService, paymentService, promotionService, taxService, shippingService, contextTimeout)
+	orderService.RegisterReversalHook(OrderService.NewStockReversalHook(productService))
+	orderService.RegisterReversalHook(OrderService.NewPromotionReversalHook(promotionService))
+	orderService.RegisterReversalHook(OrderService.NewPaymentReversalHook(paymentService))
+	paymentService.RegisterStatusHandler(orderService.HandlePaymentStatus)
 	orderHandler.NewOrderHandler(mux, orderService)
 
+	jobRepository := JobRepository.NewJob(db, dbDialect)
+	jobService := JobService.NewJobService(jobRepository, contextTimeout)
+	jobHandler.NewJobHandler(mux, jobService)
+
+	jobRunner := JobService.NewRunner(jobRepository)
+	jobRunner.Workers = getEnvInt("JOB_WORKERS", jobRunner.Workers)
+	jobRunner.VisibilityTimeout = getEnvDuration("JOB_VISIBILITY_TIMEOUT", jobRunner.VisibilityTimeout)
+	paymentTimeout := getEnvDuration("ORDER_PAYMENT_TIMEOUT", 24*time.Hour)
+
+	jobRunner.Register("event.relay", func(ctx context.Context, payload json.RawMessage) error {
+		_, err := relay.RunOnce(ctx)
+		return err
+	})
+	jobRunner.Register("webhook.deliver", func(ctx context.Context, payload json.RawMessage) error {
+		_, err := webhookService.DeliverPending(ctx)
+		return err
+	})
+	jobRunner.Register("order.expire", func(ctx context.Context, payload json.RawMessage) error {
+		_, err := orderService.ExpireUnpaidOrders(ctx, paymentTimeout)
+		return err
+	})
+	jobRunner.Register("job.cleanup", func(ctx context.Context, payload json.RawMessage) error {
+		_, err := jobService.DeleteFinished(ctx, 24*time.Hour)
+		return err
+	})
+
+	schedules := [][2]string{
+		{"event.relay", "@every 1s"},
+		{"webhook.deliver", "@every 1s"},
+		{"order.expire", "@every 1m"},
+		{"job.cleanup", "@hourly"},
+	}
+	for _, schedule := range schedules {
+		if err := jobRunner.Schedule(schedule[0], schedule[1]); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	return jobRunner
+}
+
+// newEventPublisher is the publisher of EVENT_PUBLISHER: log by default, http posting to EVENT_WEBHOOK_URL or memory
+func newEventPublisher() EventPublisher.EventPublisher {
+	switch os.Getenv("EVENT_PUBLISHER") {
+	case "http":
+		return EventPublisher.NewHTTP(os.Getenv("EVENT_WEBHOOK_URL"), os.Getenv("EVENT_WEBHOOK_SECRET"))
+	case "memory":
+		return EventPublisher.NewMemory()
+	default:
+		return EventPublisher.NewLog()
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		log.Fatalf("%s must be a positive number, got %q", key, value)
+	}
+	return number
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("%s must be a duration like 30s or 5m, got %q", key, value)
+	}
+	return duration
 }
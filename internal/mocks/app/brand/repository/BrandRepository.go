@@ -39,6 +39,27 @@ func (_m *BrandRepository) Create(ctx context.Context, _a1 dto.InsertBrandDto) (
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *BrandRepository) Delete(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBrand provides a mock function with given fields: ctx, _a1
 func (_m *BrandRepository) GetBrand(ctx context.Context, _a1 dto.FilterBrandDto) ([]model.Brand, error) {
 	ret := _m.Called(ctx, _a1)
@@ -62,6 +83,27 @@ func (_m *BrandRepository) GetBrand(ctx context.Context, _a1 dto.FilterBrandDto)
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: ctx, id
+func (_m *BrandRepository) Restore(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewBrandRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -8,6 +8,8 @@ import (
 	dto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/dto"
 	mock "github.com/stretchr/testify/mock"
 
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/brand/service"
+
 	model "github.com/ranggabudipangestu/simple-ecommerce/internal/model"
 )
 
@@ -46,6 +48,36 @@ func (_m *BrandService) CheckBrandById(ctx context.Context, id int) (*model.Bran
 	return r0, r1, r2
 }
 
+// CheckBrandByTitle provides a mock function with given fields: ctx, title
+func (_m *BrandService) CheckBrandByTitle(ctx context.Context, title string) (*model.Brand, error, string) {
+	ret := _m.Called(ctx, title)
+
+	var r0 *model.Brand
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Brand); ok {
+		r0 = rf(ctx, title)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Brand)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, title)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, string) string); ok {
+		r2 = rf(ctx, title)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 // Create provides a mock function with given fields: ctx, payload
 func (_m *BrandService) Create(ctx context.Context, payload dto.InsertBrandDto) (interface{}, error, string) {
 	ret := _m.Called(ctx, payload)
@@ -76,6 +108,71 @@ func (_m *BrandService) Create(ctx context.Context, payload dto.InsertBrandDto)
 	return r0, r1, r2
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *BrandService) Delete(ctx context.Context, id int) (interface{}, error, string) {
+	ret := _m.Called(ctx, id)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// RegisterChangeHandler provides a mock function with given fields: handler
+func (_m *BrandService) RegisterChangeHandler(handler service.ChangeHandler) {
+	_m.Called(handler)
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *BrandService) Restore(ctx context.Context, id int) (interface{}, error, string) {
+	ret := _m.Called(ctx, id)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 type mockConstructorTestingTNewBrandService interface {
 	mock.TestingT
 	Cleanup(func())
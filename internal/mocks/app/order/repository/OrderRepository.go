@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/ranggabudipangestu/simple-ecommerce/internal/model"
+
+	time "time"
 )
 
 // OrderRepository is an autogenerated mock type for the OrderRepository type
@@ -16,6 +18,27 @@ type OrderRepository struct {
 	mock.Mock
 }
 
+// CancelOrder provides a mock function with given fields: ctx, id, payload
+func (_m *OrderRepository) CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (bool, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CancelOrderDto) bool); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CancelOrderDto) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateOrder provides a mock function with given fields: ctx, _a1, transactionNumber
 func (_m *OrderRepository) CreateOrder(ctx context.Context, _a1 dto.CreateOrderDto, transactionNumber string) (*model.Transaction, error) {
 	ret := _m.Called(ctx, _a1, transactionNumber)
@@ -39,6 +62,80 @@ func (_m *OrderRepository) CreateOrder(ctx context.Context, _a1 dto.CreateOrderD
 	return r0, r1
 }
 
+// CreateRefund provides a mock function with given fields: ctx, orderId, payload
+func (_m *OrderRepository) CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*model.Refund, error) {
+	ret := _m.Called(ctx, orderId, payload)
+
+	var r0 *model.Refund
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CreateRefundDto) *model.Refund); ok {
+		r0 = rf(ctx, orderId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Refund)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CreateRefundDto) error); ok {
+		r1 = rf(ctx, orderId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateShipment provides a mock function with given fields: ctx, orderId, payload
+func (_m *OrderRepository) CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*model.Shipment, error) {
+	ret := _m.Called(ctx, orderId, payload)
+
+	var r0 *model.Shipment
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CreateShipmentDto) *model.Shipment); ok {
+		r0 = rf(ctx, orderId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Shipment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CreateShipmentDto) error); ok {
+		r1 = rf(ctx, orderId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteRefund provides a mock function with given fields: ctx, id
+func (_m *OrderRepository) DeleteRefund(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportOrders provides a mock function with given fields: ctx, filter, fn
+func (_m *OrderRepository) ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(dto.ExportOrder) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterOrderDto, func(dto.ExportOrder) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetOrderDetails provides a mock function with given fields: ctx, id
 func (_m *OrderRepository) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error) {
 	ret := _m.Called(ctx, id)
@@ -62,6 +159,92 @@ func (_m *OrderRepository) GetOrderDetails(ctx context.Context, id int) (*dto.Ge
 	return r0, r1
 }
 
+// GetUnpaidOrders provides a mock function with given fields: ctx, olderThan, limit
+func (_m *OrderRepository) GetUnpaidOrders(ctx context.Context, olderThan time.Duration, limit int) ([]int, error) {
+	ret := _m.Called(ctx, olderThan, limit)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, int) []int); ok {
+		r0 = rf(ctx, olderThan, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration, int) error); ok {
+		r1 = rf(ctx, olderThan, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreCancelledOrder provides a mock function with given fields: ctx, id, status
+func (_m *OrderRepository) RestoreCancelledOrder(ctx context.Context, id int, status string) (bool, error) {
+	ret := _m.Called(ctx, id, status)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: ctx, id, from, to
+func (_m *OrderRepository) UpdateOrderStatus(ctx context.Context, id int, from []string, to string) (bool, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string, string) bool); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, []string, string) error); ok {
+		r1 = rf(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateShipment provides a mock function with given fields: ctx, orderId, shipmentId, from, payload
+func (_m *OrderRepository) UpdateShipment(ctx context.Context, orderId int, shipmentId int, from string, payload dto.UpdateShipmentDto) (bool, error) {
+	ret := _m.Called(ctx, orderId, shipmentId, from, payload)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, dto.UpdateShipmentDto) bool); ok {
+		r0 = rf(ctx, orderId, shipmentId, from, payload)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, dto.UpdateShipmentDto) error); ok {
+		r1 = rf(ctx, orderId, shipmentId, from, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewOrderRepository interface {
 	mock.TestingT
 	Cleanup(func())
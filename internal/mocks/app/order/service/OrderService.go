@@ -7,6 +7,10 @@ import (
 
 	dto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/dto"
 	mock "github.com/stretchr/testify/mock"
+
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/order/service"
+
+	time "time"
 )
 
 // OrderService is an autogenerated mock type for the OrderService type
@@ -14,6 +18,36 @@ type OrderService struct {
 	mock.Mock
 }
 
+// CancelOrder provides a mock function with given fields: ctx, id, payload
+func (_m *OrderService) CancelOrder(ctx context.Context, id int, payload dto.CancelOrderDto) (*dto.GetOrderDto, error, string) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *dto.GetOrderDto
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CancelOrderDto) *dto.GetOrderDto); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetOrderDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CancelOrderDto) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, dto.CancelOrderDto) string); ok {
+		r2 = rf(ctx, id, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 // CreateOrder provides a mock function with given fields: ctx, payload
 func (_m *OrderService) CreateOrder(ctx context.Context, payload dto.CreateOrderDto) (interface{}, error, string) {
 	ret := _m.Called(ctx, payload)
@@ -44,6 +78,108 @@ func (_m *OrderService) CreateOrder(ctx context.Context, payload dto.CreateOrder
 	return r0, r1, r2
 }
 
+// CreateRefund provides a mock function with given fields: ctx, orderId, payload
+func (_m *OrderService) CreateRefund(ctx context.Context, orderId int, payload dto.CreateRefundDto) (*dto.GetOrderDto, error, string) {
+	ret := _m.Called(ctx, orderId, payload)
+
+	var r0 *dto.GetOrderDto
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CreateRefundDto) *dto.GetOrderDto); ok {
+		r0 = rf(ctx, orderId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetOrderDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CreateRefundDto) error); ok {
+		r1 = rf(ctx, orderId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, dto.CreateRefundDto) string); ok {
+		r2 = rf(ctx, orderId, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateShipment provides a mock function with given fields: ctx, orderId, payload
+func (_m *OrderService) CreateShipment(ctx context.Context, orderId int, payload dto.CreateShipmentDto) (*dto.GetOrderDto, error, string) {
+	ret := _m.Called(ctx, orderId, payload)
+
+	var r0 *dto.GetOrderDto
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.CreateShipmentDto) *dto.GetOrderDto); ok {
+		r0 = rf(ctx, orderId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetOrderDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.CreateShipmentDto) error); ok {
+		r1 = rf(ctx, orderId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, dto.CreateShipmentDto) string); ok {
+		r2 = rf(ctx, orderId, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// ExpireUnpaidOrders provides a mock function with given fields: ctx, olderThan
+func (_m *OrderService) ExpireUnpaidOrders(ctx context.Context, olderThan time.Duration) (int, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportOrders provides a mock function with given fields: ctx, filter, fn
+func (_m *OrderService) ExportOrders(ctx context.Context, filter dto.FilterOrderDto, fn func(dto.ExportOrder) error) (error, string) {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterOrderDto, func(dto.ExportOrder) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, dto.FilterOrderDto, func(dto.ExportOrder) error) string); ok {
+		r1 = rf(ctx, filter, fn)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	return r0, r1
+}
+
 // GetOrderDetails provides a mock function with given fields: ctx, id
 func (_m *OrderService) GetOrderDetails(ctx context.Context, id int) (*dto.GetOrderDto, error, string) {
 	ret := _m.Called(ctx, id)
@@ -74,6 +210,85 @@ func (_m *OrderService) GetOrderDetails(ctx context.Context, id int) (*dto.GetOr
 	return r0, r1, r2
 }
 
+// HandlePaymentStatus provides a mock function with given fields: ctx, orderId, paymentStatus
+func (_m *OrderService) HandlePaymentStatus(ctx context.Context, orderId int, paymentStatus string) error {
+	ret := _m.Called(ctx, orderId, paymentStatus)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, orderId, paymentStatus)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PayOrder provides a mock function with given fields: ctx, id
+func (_m *OrderService) PayOrder(ctx context.Context, id int) (*dto.GetOrderDto, error, string) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *dto.GetOrderDto
+	if rf, ok := ret.Get(0).(func(context.Context, int) *dto.GetOrderDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetOrderDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// RegisterReversalHook provides a mock function with given fields: hook
+func (_m *OrderService) RegisterReversalHook(hook service.ReversalHook) {
+	_m.Called(hook)
+}
+
+// UpdateShipment provides a mock function with given fields: ctx, orderId, shipmentId, payload
+func (_m *OrderService) UpdateShipment(ctx context.Context, orderId int, shipmentId int, payload dto.UpdateShipmentDto) (*dto.GetOrderDto, error, string) {
+	ret := _m.Called(ctx, orderId, shipmentId, payload)
+
+	var r0 *dto.GetOrderDto
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, dto.UpdateShipmentDto) *dto.GetOrderDto); ok {
+		r0 = rf(ctx, orderId, shipmentId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetOrderDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, dto.UpdateShipmentDto) error); ok {
+		r1 = rf(ctx, orderId, shipmentId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, dto.UpdateShipmentDto) string); ok {
+		r2 = rf(ctx, orderId, shipmentId, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 type mockConstructorTestingTNewOrderService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -16,6 +16,20 @@ type ProductRepository struct {
 	mock.Mock
 }
 
+// AdjustStock provides a mock function with given fields: ctx, adjustments
+func (_m *ProductRepository) AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error {
+	ret := _m.Called(ctx, adjustments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.StockAdjustment) error); ok {
+		r0 = rf(ctx, adjustments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, payload
 func (_m *ProductRepository) Create(ctx context.Context, payload dto.InsertProductDto) (*model.Product, error) {
 	ret := _m.Called(ctx, payload)
@@ -39,6 +53,133 @@ func (_m *ProductRepository) Create(ctx context.Context, payload dto.InsertProdu
 	return r0, r1
 }
 
+// CreateMany provides a mock function with given fields: ctx, payloads
+func (_m *ProductRepository) CreateMany(ctx context.Context, payloads []dto.InsertProductDto) ([]model.Product, error) {
+	ret := _m.Called(ctx, payloads)
+
+	var r0 []model.Product
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.InsertProductDto) []model.Product); ok {
+		r0 = rf(ctx, payloads)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []dto.InsertProductDto) error); ok {
+		r1 = rf(ctx, payloads)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePrice provides a mock function with given fields: ctx, payload
+func (_m *ProductRepository) CreatePrice(ctx context.Context, payload dto.InsertPriceDto) (*model.ProductPrice, error) {
+	ret := _m.Called(ctx, payload)
+
+	var r0 *model.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, dto.InsertPriceDto) *model.ProductPrice); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, dto.InsertPriceDto) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateVariant provides a mock function with given fields: ctx, payload
+func (_m *ProductRepository) CreateVariant(ctx context.Context, payload dto.InsertVariantDto) (*model.ProductVariant, error) {
+	ret := _m.Called(ctx, payload)
+
+	var r0 *model.ProductVariant
+	if rf, ok := ret.Get(0).(func(context.Context, dto.InsertVariantDto) *model.ProductVariant); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductVariant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, dto.InsertVariantDto) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductRepository) Delete(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportProducts provides a mock function with given fields: ctx, filter, fn
+func (_m *ProductRepository) ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(dto.ExportProduct) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterProductDto, func(dto.ExportProduct) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPrices provides a mock function with given fields: ctx, productId
+func (_m *ProductRepository) GetPrices(ctx context.Context, productId int) ([]model.ProductPrice, error) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []model.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.ProductPrice); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProduct provides a mock function with given fields: ctx, filter
 func (_m *ProductRepository) GetProduct(ctx context.Context, filter dto.FilterProductDto) ([]dto.GetProduct, error) {
 	ret := _m.Called(ctx, filter)
@@ -62,6 +203,50 @@ func (_m *ProductRepository) GetProduct(ctx context.Context, filter dto.FilterPr
 	return r0, r1
 }
 
+// GetVariants provides a mock function with given fields: ctx, filter
+func (_m *ProductRepository) GetVariants(ctx context.Context, filter dto.FilterVariantDto) ([]model.ProductVariant, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []model.ProductVariant
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterVariantDto) []model.ProductVariant); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductVariant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, dto.FilterVariantDto) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ProductRepository) Restore(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewProductRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -5,8 +5,12 @@ package mocks
 import (
 	context "context"
 
+	io "io"
+
 	dto "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/dto"
 	mock "github.com/stretchr/testify/mock"
+
+	service "github.com/ranggabudipangestu/simple-ecommerce/internal/app/product/service"
 )
 
 // ProductService is an autogenerated mock type for the ProductService type
@@ -14,6 +18,20 @@ type ProductService struct {
 	mock.Mock
 }
 
+// AdjustStock provides a mock function with given fields: ctx, adjustments
+func (_m *ProductService) AdjustStock(ctx context.Context, adjustments []dto.StockAdjustment) error {
+	ret := _m.Called(ctx, adjustments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.StockAdjustment) error); ok {
+		r0 = rf(ctx, adjustments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, _a1
 func (_m *ProductService) Create(ctx context.Context, _a1 dto.InsertProductDto) (interface{}, error, string) {
 	ret := _m.Called(ctx, _a1)
@@ -44,6 +62,147 @@ func (_m *ProductService) Create(ctx context.Context, _a1 dto.InsertProductDto)
 	return r0, r1, r2
 }
 
+// CreatePrice provides a mock function with given fields: ctx, productId, payload
+func (_m *ProductService) CreatePrice(ctx context.Context, productId int, payload dto.InsertPriceDto) (interface{}, error, string) {
+	ret := _m.Called(ctx, productId, payload)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.InsertPriceDto) interface{}); ok {
+		r0 = rf(ctx, productId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.InsertPriceDto) error); ok {
+		r1 = rf(ctx, productId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, dto.InsertPriceDto) string); ok {
+		r2 = rf(ctx, productId, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateVariant provides a mock function with given fields: ctx, productId, payload
+func (_m *ProductService) CreateVariant(ctx context.Context, productId int, payload dto.InsertVariantDto) (interface{}, error, string) {
+	ret := _m.Called(ctx, productId, payload)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int, dto.InsertVariantDto) interface{}); ok {
+		r0 = rf(ctx, productId, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, dto.InsertVariantDto) error); ok {
+		r1 = rf(ctx, productId, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int, dto.InsertVariantDto) string); ok {
+		r2 = rf(ctx, productId, payload)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductService) Delete(ctx context.Context, id int) (interface{}, error, string) {
+	ret := _m.Called(ctx, id)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// ExportProducts provides a mock function with given fields: ctx, filter, fn
+func (_m *ProductService) ExportProducts(ctx context.Context, filter dto.FilterProductDto, fn func(dto.ExportProduct) error) (error, string) {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterProductDto, func(dto.ExportProduct) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, dto.FilterProductDto, func(dto.ExportProduct) error) string); ok {
+		r1 = rf(ctx, filter, fn)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	return r0, r1
+}
+
+// GetPrices provides a mock function with given fields: ctx, productId
+func (_m *ProductService) GetPrices(ctx context.Context, productId int) ([]dto.PriceDto, error, string) {
+	ret := _m.Called(ctx, productId)
+
+	var r0 []dto.PriceDto
+	if rf, ok := ret.Get(0).(func(context.Context, int) []dto.PriceDto); ok {
+		r0 = rf(ctx, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.PriceDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, productId)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 // GetProductByBrand provides a mock function with given fields: ctx, brandId
 func (_m *ProductService) GetProductByBrand(ctx context.Context, brandId int) (interface{}, error, string) {
 	ret := _m.Called(ctx, brandId)
@@ -74,6 +233,36 @@ func (_m *ProductService) GetProductByBrand(ctx context.Context, brandId int) (i
 	return r0, r1, r2
 }
 
+// GetProductByCategory provides a mock function with given fields: ctx, categoryId
+func (_m *ProductService) GetProductByCategory(ctx context.Context, categoryId int) (interface{}, error, string) {
+	ret := _m.Called(ctx, categoryId)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) interface{}); ok {
+		r0 = rf(ctx, categoryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, categoryId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, categoryId)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 // GetProductById provides a mock function with given fields: ctx, id
 func (_m *ProductService) GetProductById(ctx context.Context, id int) (*dto.GetProduct, error, string) {
 	ret := _m.Called(ctx, id)
@@ -104,6 +293,106 @@ func (_m *ProductService) GetProductById(ctx context.Context, id int) (*dto.GetP
 	return r0, r1, r2
 }
 
+// GetProducts provides a mock function with given fields: ctx, filter
+func (_m *ProductService) GetProducts(ctx context.Context, filter dto.FilterProductDto) ([]dto.GetProduct, error, string) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []dto.GetProduct
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FilterProductDto) []dto.GetProduct); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.GetProduct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, dto.FilterProductDto) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, dto.FilterProductDto) string); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// Import provides a mock function with given fields: ctx, data, options
+func (_m *ProductService) Import(ctx context.Context, data io.Reader, options dto.ImportOptions) (*dto.ImportReport, error, string) {
+	ret := _m.Called(ctx, data, options)
+
+	var r0 *dto.ImportReport
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, dto.ImportOptions) *dto.ImportReport); ok {
+		r0 = rf(ctx, data, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ImportReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, dto.ImportOptions) error); ok {
+		r1 = rf(ctx, data, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, io.Reader, dto.ImportOptions) string); ok {
+		r2 = rf(ctx, data, options)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
+// RegisterChangeHandler provides a mock function with given fields: handler
+func (_m *ProductService) RegisterChangeHandler(handler service.ChangeHandler) {
+	_m.Called(handler)
+}
+
+// RegisterImageLoader provides a mock function with given fields: loader
+func (_m *ProductService) RegisterImageLoader(loader service.ImageLoader) {
+	_m.Called(loader)
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ProductService) Restore(ctx context.Context, id int) (interface{}, error, string) {
+	ret := _m.Called(ctx, id)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, int) string); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	return r0, r1, r2
+}
+
 type mockConstructorTestingTNewProductService interface {
 	mock.TestingT
 	Cleanup(func())
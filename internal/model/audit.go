@@ -0,0 +1,34 @@
+package model
+
+import "encoding/json"
+
+const (
+	AuditActionCreate = "CREATE"
+	AuditActionUpdate = "UPDATE"
+	AuditActionDelete = "DELETE"
+)
+
+// Entity types the audit log records changes of
+const (
+	AuditEntityBrand    = "brand"
+	AuditEntityProduct  = "product"
+	AuditEntityVariant  = "product_variant"
+	AuditEntityPrice    = "product_price"
+	AuditEntityOrder    = "order"
+	AuditEntityRefund   = "refund"
+	AuditEntityShipment = "shipment"
+)
+
+// AuditLog is one change of an entity. Before and After are JSON objects with the fields that changed,
+// Before is null for a created entity.
+type AuditLog struct {
+	ID         int
+	Actor      string
+	Action     string
+	EntityType string
+	EntityId   int
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestId  string
+	CreatedAt  string
+}
@@ -5,4 +5,5 @@ type Brand struct {
 	Title     string
 	CreatedAt string
 	UpdatedAt string
+	DeletedAt string
 }
@@ -0,0 +1,12 @@
+package model
+
+// Category is a node of the category tree, ParentId is 0 for a root category
+type Category struct {
+	ID        int
+	ParentId  int
+	Title     string
+	Slug      string
+	SortOrder int
+	CreatedAt string
+	UpdatedAt string
+}
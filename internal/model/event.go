@@ -0,0 +1,25 @@
+package model
+
+import "encoding/json"
+
+// Types of the domain events downstream systems are told about
+const (
+	EventOrderCreated        = "OrderCreated"
+	EventOrderStatusChanged  = "OrderStatusChanged"
+	EventProductPriceChanged = "ProductPriceChanged"
+	EventBrandCreated        = "BrandCreated"
+)
+
+// OutboxEvent is a domain event written with the change it tells about, kept until it is published.
+// NextAttemptAt is when the relay tries it next, PublishedAt is empty until it gets through.
+type OutboxEvent struct {
+	ID            int
+	Type          string
+	EntityId      int
+	Payload       json.RawMessage
+	Attempts      int
+	NextAttemptAt string
+	PublishedAt   string
+	LastError     string
+	CreatedAt     string
+}
@@ -0,0 +1,31 @@
+package model
+
+import "encoding/json"
+
+// Statuses of a background job: QUEUED until a worker claims it, RUNNING while it runs, DONE once it went through
+// and DEAD, the dead letter, once it ran out of attempts
+const (
+	JobQueued  = "QUEUED"
+	JobRunning = "RUNNING"
+	JobDone    = "DONE"
+	JobDead    = "DEAD"
+)
+
+// Job is work run in the background by the handler registered for its Name. RunAt is when it is due, LockedBy
+// is the claim of the worker running it, until LockedUntil.
+type Job struct {
+	ID          int
+	Name        string
+	Payload     json.RawMessage
+	UniqueKey   string
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	RunAt       string
+	LockedBy    string
+	LockedUntil string
+	LastError   string
+	FinishedAt  string
+	CreatedAt   string
+	UpdatedAt   string
+}
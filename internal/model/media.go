@@ -0,0 +1,15 @@
+package model
+
+// ProductImage is an uploaded product image. StorageKey is the blob store prefix holding the original
+// and its thumbnails, SortOrder is the position of the image in the product gallery.
+type ProductImage struct {
+	ID          int
+	ProductId   int
+	StorageKey  string
+	ContentType string
+	Size        int
+	Width       int
+	Height      int
+	SortOrder   int
+	CreatedAt   string
+}
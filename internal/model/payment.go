@@ -0,0 +1,41 @@
+package model
+
+const (
+	PaymentStatusPending    = "PENDING"
+	PaymentStatusAuthorized = "AUTHORIZED"
+	PaymentStatusCaptured   = "CAPTURED"
+	PaymentStatusDeclined   = "DECLINED"
+	PaymentStatusFailed     = "FAILED"
+	PaymentStatusVoided     = "VOIDED"
+	PaymentStatusRefunded   = "REFUNDED"
+)
+
+type Payment struct {
+	ID                int
+	TransactionId     int
+	Provider          string
+	ProviderReference string
+	Amount            float32
+	RefundedAmount    float32
+	Status            string
+	ErrorMessage      string
+	CreatedAt         string
+	UpdatedAt         string
+}
+
+const (
+	WebhookEventProcessed = "PROCESSED"
+	WebhookEventDuplicate = "DUPLICATE"
+	WebhookEventRejected  = "REJECTED"
+	WebhookEventFailed    = "FAILED"
+)
+
+type PaymentWebhookEvent struct {
+	ID           int
+	Provider     string
+	EventId      string
+	Payload      string
+	Status       string
+	ErrorMessage string
+	CreatedAt    string
+}
@@ -0,0 +1,13 @@
+package model
+
+// ProductPrice is a price of a product from StartAt, until EndAt when it is set. At a time the product sells at
+// the price that started last among the ones in effect, and at its own price when there is none.
+type ProductPrice struct {
+	ID        int
+	ProductId int
+	Price     float32
+	StartAt   string
+	EndAt     string
+	CreatedBy string
+	CreatedAt string
+}
@@ -6,6 +6,10 @@ type Product struct {
 	Description string
 	BrandId     string
 	Price       float32
+	Weight      int
+	Length      int
+	Width       int
+	Height      int
 	CreatedAt   string
 	UpdatedAt   string
 }
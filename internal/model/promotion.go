@@ -0,0 +1,34 @@
+package model
+
+const (
+	PromotionTypePercentage = "PERCENTAGE"
+	PromotionTypeFixed      = "FIXED"
+)
+
+type Promotion struct {
+	ID               int
+	Code             string
+	Title            string
+	Type             string
+	Value            float32
+	MinOrderValue    float32
+	BrandId          int
+	ProductId        int
+	StartAt          string
+	EndAt            string
+	UsageLimit       int
+	UsageCount       int
+	PerCustomerLimit int
+	Stackable        bool
+	CreatedAt        string
+	UpdatedAt        string
+}
+
+// PromotionUsage is a coupon used by an order
+type PromotionUsage struct {
+	PromotionId   int
+	TransactionId int
+	Code          string
+	Customer      string
+	Amount        float32
+}
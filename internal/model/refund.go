@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Refund struct {
+	ID            int
+	TransactionId int
+	Amount        float32
+	Reason        string
+	CreatedBy     string
+	CreatedAt     time.Time
+}
+
+type RefundDetails struct {
+	ID                  int
+	RefundId            int
+	TransactionDetailId int
+	Qty                 int
+	Amount              float32
+}
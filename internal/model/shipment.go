@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	ShipmentStatusShipped   = "SHIPPED"
+	ShipmentStatusInTransit = "IN_TRANSIT"
+	ShipmentStatusDelivered = "DELIVERED"
+)
+
+// shipmentTransitions lists the statuses a shipment can move to from each status.
+var shipmentTransitions = map[string][]string{
+	ShipmentStatusShipped:   {ShipmentStatusInTransit, ShipmentStatusDelivered},
+	ShipmentStatusInTransit: {ShipmentStatusDelivered},
+}
+
+func CanShipmentMoveTo(from string, to string) bool {
+	for _, s := range shipmentTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Shipment struct {
+	ID             int
+	TransactionId  int
+	Carrier        string
+	TrackingNumber string
+	Status         string
+	CreatedBy      string
+	ShippedAt      time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ShipmentDetails struct {
+	ID                  int
+	ShipmentId          int
+	TransactionDetailId int
+	Qty                 int
+}
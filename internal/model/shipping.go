@@ -0,0 +1,15 @@
+package model
+
+// ShippingRate is the cost of a shipping method to a region for parcels between MinWeight and MaxWeight grams.
+// A MaxWeight of 0 means the band has no upper limit.
+type ShippingRate struct {
+	ID            int
+	Region        string
+	Method        string
+	MinWeight     int
+	MaxWeight     int
+	Cost          float32
+	EstimatedDays int
+	CreatedAt     string
+	UpdatedAt     string
+}
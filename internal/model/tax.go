@@ -0,0 +1,14 @@
+package model
+
+// TaxRule is a tax rate applied to order lines. A rule scoped to a product overrides one scoped to a brand,
+// which overrides an unscoped one with the same name, so different taxes (e.g. PPN and PPnBM) can stack.
+type TaxRule struct {
+	ID        int
+	Name      string
+	Rate      float32
+	Inclusive bool
+	BrandId   int
+	ProductId int
+	CreatedAt string
+	UpdatedAt string
+}
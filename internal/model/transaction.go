@@ -2,11 +2,73 @@ package model
 
 import "time"
 
+const (
+	OrderStatusPending          = "PENDING"
+	OrderStatusPaid             = "PAID"
+	OrderStatusPaymentFailed    = "PAYMENT_FAILED"
+	OrderStatusCancelled        = "CANCELLED"
+	OrderStatusPartiallyShipped = "PARTIALLY_SHIPPED"
+	OrderStatusShipped          = "SHIPPED"
+	OrderStatusDelivered        = "DELIVERED"
+)
+
+// CancellableOrderStatuses lists the statuses an order can be cancelled from.
+var CancellableOrderStatuses = []string{OrderStatusPending, OrderStatusPaid, OrderStatusPaymentFailed}
+
+// PayableOrderStatuses lists the statuses a payment can be attempted from.
+var PayableOrderStatuses = []string{OrderStatusPending, OrderStatusPaymentFailed}
+
+// RefundableOrderStatuses lists the statuses an order can be refunded from.
+var RefundableOrderStatuses = []string{OrderStatusPaid, OrderStatusPartiallyShipped, OrderStatusShipped, OrderStatusDelivered}
+
+// ShippableOrderStatuses lists the statuses shipments can be recorded or updated in.
+var ShippableOrderStatuses = []string{OrderStatusPaid, OrderStatusPartiallyShipped, OrderStatusShipped}
+
+func IsOrderCancellable(status string) bool {
+	for _, s := range CancellableOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsOrderRefundable(status string) bool {
+	for _, s := range RefundableOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsOrderShippable(status string) bool {
+	for _, s := range ShippableOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsOrderPayable(status string) bool {
+	for _, s := range PayableOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 type Transaction struct {
 	ID                int
 	DeliveryAddress   int
 	TransactionNumber string
 	TotalTransaction  float32
+	Status            string
+	CancelReason      string
+	CancelledBy       string
+	CancelledAt       *time.Time
 	CreatedAt         time.Time
 }
 
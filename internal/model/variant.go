@@ -0,0 +1,14 @@
+package model
+
+// ProductVariant is a sellable SKU of a product, e.g. the M size of a t-shirt.
+// Options holds the value of every option type of the product, Price is 0 when the product price applies.
+type ProductVariant struct {
+	ID        int
+	ProductId int
+	Sku       string
+	Price     float32
+	Stock     int
+	Options   map[string]string
+	CreatedAt string
+	UpdatedAt string
+}
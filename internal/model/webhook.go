@@ -0,0 +1,36 @@
+package model
+
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliveryDelivered = "DELIVERED"
+	WebhookDeliveryFailed    = "FAILED"
+)
+
+// WebhookSubscription is a partner URL getting the events of EventTypes while Active
+type WebhookSubscription struct {
+	ID         int
+	Url        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+// WebhookDelivery is an event sent to a subscription. Payload is the body posted on every attempt, ResponseCode
+// and LastError tell how the last attempt went, ResponseCode is 0 when no response came back.
+type WebhookDelivery struct {
+	ID             int
+	SubscriptionId int
+	EventId        int
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int
+	ResponseCode   int
+	LastError      string
+	NextAttemptAt  string
+	DeliveredAt    string
+	CreatedAt      string
+	UpdatedAt      string
+}
@@ -1,33 +1,90 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/ranggabudipangestu/simple-ecommerce/database"
 	"github.com/ranggabudipangestu/simple-ecommerce/internal/factory"
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
 )
 
 func main() {
 
-	db, err := database.Connect()
+	db, dbDialect, err := database.Connect()
 
 	if err != nil {
 		log.Fatalln(err)
 	}
+
+	migrator := database.NewMigrator(db, dbDialect)
+	migrator.Log = log.Printf
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err = database.MigrateCommand(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if autoMigrate, _ := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); autoMigrate && !database.Memory() {
+		if err = migrator.Up(context.Background()); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
 	mux := http.NewServeMux()
 
-	factory.RegisterHandlers(mux, db)
+	jobRunner := factory.RegisterHandlers(mux, db, dbDialect)
 
-	port := fmt.Sprintf(`:%s`, os.Getenv("APP_PORT"))
-	log.Println("Listening Server On Port " + port)
+	//SIGINT or SIGTERM stops the app once the requests and the jobs in flight are finished
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err = http.ListenAndServe(port, mux)
+	jobsDone := make(chan struct{})
+	go func() {
+		jobRunner.Run(ctx)
+		close(jobsDone)
+	}()
 
-	if err != nil {
+	port := fmt.Sprintf(`:%s`, os.Getenv("APP_PORT"))
+	server := &http.Server{Addr: port, Handler: util.WithRequestContext(util.RequireAcceptable(mux, factory.SelfNegotiatedPaths...))}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Println("Listening Server On Port " + port)
+		//ErrServerClosed once Shutdown is called
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-serverErr:
 		log.Fatalln("Failed to start Server")
+	case <-ctx.Done():
+	}
+	stop()
+	log.Println("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to finish the requests in flight:", err)
+	}
+
+	select {
+	case <-jobsDone:
+	case <-time.After(30 * time.Second):
+		log.Println("Stopped before the running jobs finished, they run again once their lock expires")
+	}
+
+	if err = db.Close(); err != nil {
+		log.Println("Failed to close the database:", err)
 	}
 
 }
@@ -0,0 +1,19 @@
+package util
+
+import "time"
+
+// DateTimeLayout is the format datetime columns are read and written with
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// ParseDateTime reads a datetime column scanned into a string. Drivers parsing the column into a time.Time
+// turn it into RFC3339 with the wall clock of the column labelled UTC, which is read back as local time.
+func ParseDateTime(value string) (time.Time, error) {
+	t, err := time.ParseInLocation(DateTimeLayout, value, time.Local)
+	if err == nil {
+		return t, nil
+	}
+	if rfc, rfcErr := time.Parse(time.RFC3339, value); rfcErr == nil {
+		return time.Date(rfc.Year(), rfc.Month(), rfc.Day(), rfc.Hour(), rfc.Minute(), rfc.Second(), 0, time.Local), nil
+	}
+	return t, err
+}
@@ -0,0 +1,181 @@
+package util
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxBodySize is the largest body Decode reads
+const MaxBodySize = 1 << 20
+
+// ErrUnsupportedMediaType is returned by Decode for a Content-Type it cannot read
+var ErrUnsupportedMediaType = errors.New("Content-Type must be application/json, application/msgpack or application/xml")
+
+// Decode reads the body of r into v in the format of its Content-Type, JSON when it has none. MessagePack and
+// XML bodies use the same field names as JSON, XML arrays being item elements like the responses have them.
+// Bodies over MaxBodySize and MessagePack or XML nested over 100 levels deep are rejected.
+func Decode(r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+
+	mediaType := "application/json"
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return ErrUnsupportedMediaType
+		}
+	}
+
+	var tree interface{}
+	var err error
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return json.NewDecoder(r.Body).Decode(v)
+	case mediaType == "application/msgpack" || mediaType == "application/x-msgpack" || mediaType == "application/vnd.msgpack":
+		tree, err = readMsgpack(bufio.NewReader(r.Body))
+	case mediaType == "application/xml" || mediaType == "text/xml":
+		var element interface{}
+		if element, err = readXML(r.Body); err == nil {
+			tree = coerce(element, reflect.TypeOf(v))
+		}
+	default:
+		return ErrUnsupportedMediaType
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// GetDecodeState is the state to answer a Decode error with
+func GetDecodeState(err error) string {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return UNSUPPORTED_MEDIA_TYPE
+	case errors.As(err, &tooLarge), errors.Is(err, errMsgpackDepth), errors.Is(err, errXMLDepth):
+		return VALIDATION_ERROR
+	}
+	return SYSTEM_ERROR
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// coerce gives the strings read from XML the JSON types of t: numbers and booleans for the fields holding
+// them and arrays for slices, whose items are the item elements
+func coerce(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := v.(map[string]interface{}); ok {
+			coerceFields(m, t)
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		var items []interface{}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			switch item := value[xmlItem].(type) {
+			case []interface{}:
+				items = item
+			case nil:
+			default:
+				items = []interface{}{item}
+			}
+		case string:
+			if strings.TrimSpace(value) != "" {
+				return v
+			}
+		default:
+			return v
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = coerce(item, t.Elem())
+		}
+		return list
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for key, value := range m {
+				m[key] = coerce(value, t.Elem())
+			}
+		}
+	case reflect.Bool:
+		if s, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return b
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s, ok := v.(string); ok {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				return nil
+			}
+			//an invalid number fails when the tree is marshalled
+			return json.Number(s)
+		}
+	}
+	return v
+}
+
+func coerceFields(m map[string]interface{}, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+				name = tagName
+			} else if field.Anonymous {
+				name = ""
+			}
+		} else if field.Anonymous {
+			name = ""
+		}
+
+		if name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				coerceFields(m, embedded)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		//encoding/json matches keys without regard to case
+		for key, value := range m {
+			if strings.EqualFold(key, name) {
+				m[key] = coerce(value, field.Type)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicate is returned by the in-memory repositories for a write breaking a unique key
+var ErrDuplicate = errors.New("duplicate key")
+
+// IsDuplicate tells whether err is a write breaking a unique key, in any of the databases the app runs on
+func IsDuplicate(err error) bool {
+	if errors.Is(err, ErrDuplicate) {
+		return true
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+
+	//go-sqlite3 only has its error type when built with cgo
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// ErrorState is the state of a failed write: DUPLICATE when it broke a unique key, SYSTEM_ERROR otherwise
+func ErrorState(err error) string {
+	if IsDuplicate(err) {
+		return DUPLICATE
+	}
+	return SYSTEM_ERROR
+}
@@ -0,0 +1,34 @@
+package util_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+)
+
+func TestErrorState(t *testing.T) {
+	duplicates := []error{
+		&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Acme' for key 'uq_brand_title'"},
+		&pq.Error{Code: "23505"},
+		errors.New("UNIQUE constraint failed: brand.title"),
+		fmt.Errorf("brand: %w", util.ErrDuplicate),
+	}
+	for _, err := range duplicates {
+		assert.Equal(t, util.DUPLICATE, util.ErrorState(err), err.Error())
+	}
+
+	others := []error{
+		&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"},
+		&pq.Error{Code: "23503"},
+		errors.New("FOREIGN KEY constraint failed"),
+	}
+	for _, err := range others {
+		assert.Equal(t, util.SYSTEM_ERROR, util.ErrorState(err), err.Error())
+	}
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+// exportFlushEvery is how many rows are written between two flushes to the client
+const exportFlushEvery = 100
+
+// GetExportFormat takes the format from the format query param, else from the Accept header, CSV by default
+func GetExportFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format != ExportCSV && format != ExportNDJSON {
+			return "", fmt.Errorf("format must be %s or %s", ExportCSV, ExportNDJSON)
+		}
+		return format, nil
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(accept))
+		switch mediaType {
+		case "text/csv":
+			return ExportCSV, nil
+		case "application/x-ndjson", "application/jsonl":
+			return ExportNDJSON, nil
+		}
+	}
+	return ExportCSV, nil
+}
+
+// ExportWriter streams rows of values in the order of the columns to the response, as CSV with a header line
+// or as one JSON object per line. The response starts with the first row, so a failure before it can still be
+// answered with a JSON error.
+type ExportWriter struct {
+	w       http.ResponseWriter
+	format  string
+	name    string
+	columns []string
+	csv     *csv.Writer
+	started bool
+	rows    int
+}
+
+// NewExportWriter names the download after name, e.g. products.csv
+func NewExportWriter(w http.ResponseWriter, format string, name string, columns []string) *ExportWriter {
+	export := &ExportWriter{w: w, format: format, name: name, columns: columns}
+	if format == ExportCSV {
+		export.csv = csv.NewWriter(w)
+	}
+	return export
+}
+
+// Started tells whether the response has been sent
+func (e *ExportWriter) Started() bool {
+	return e.started
+}
+
+func (e *ExportWriter) start() error {
+	e.started = true
+	if e.format == ExportCSV {
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.name, e.format))
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.csv != nil {
+		return e.csv.Write(e.columns)
+	}
+	return nil
+}
+
+func (e *ExportWriter) Write(values []interface{}) error {
+	if len(values) != len(e.columns) {
+		return fmt.Errorf("export row has %d values for %d columns", len(values), len(e.columns))
+	}
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if e.csv != nil {
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = csvValue(value)
+		}
+		err = e.csv.Write(record)
+	} else {
+		err = e.writeJSON(values)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close starts the response of an empty export and sends the rows left
+func (e *ExportWriter) Close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *ExportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// writeJSON writes the object key by key so the keys keep the column order
+func (e *ExportWriter) writeJSON(values []interface{}) error {
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.columns[i])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		line.Write(key)
+		line.WriteByte(':')
+		line.Write(encoded)
+	}
+	line.WriteString("}\n")
+
+	_, err := io.WriteString(e.w, line.String())
+	return err
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		//keep spreadsheets from running text as a formula
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
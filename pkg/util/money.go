@@ -0,0 +1,8 @@
+package util
+
+import "math"
+
+// RoundMoney rounds an amount to 2 decimals
+func RoundMoney(amount float32) float32 {
+	return float32(math.Round(float64(amount)*100) / 100)
+}
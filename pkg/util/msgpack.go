@@ -0,0 +1,268 @@
+package util
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// writeMsgpack encodes a tree made by toTree in MessagePack, with every number in its smallest form
+func writeMsgpack(w io.Writer, tree interface{}) error {
+	buf := bufio.NewWriter(w)
+	if err := encodeMsgpack(buf, tree); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+func encodeMsgpack(w *bufio.Writer, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+	case bool:
+		if value {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			writeMsgpackInt(w, i)
+			return nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return err
+		}
+		w.WriteByte(0xcb)
+		return binary.Write(w, binary.BigEndian, math.Float64bits(f))
+	case string:
+		writeMsgpackHeader(w, len(value), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		_, err := w.WriteString(value)
+		return err
+	case []interface{}:
+		writeMsgpackHeader(w, len(value), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range value {
+			if err := encodeMsgpack(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case object:
+		writeMsgpackHeader(w, len(value), 0x80, 15, 0, 0xde, 0xdf)
+		for _, m := range value {
+			if err := encodeMsgpack(w, m.Key); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(w, m.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("msgpack: cannot encode %T", v)
+}
+
+func writeMsgpackInt(w *bufio.Writer, i int64) {
+	switch {
+	case i >= 0 && i <= 127, i < 0 && i >= -32:
+		w.WriteByte(byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		w.Write([]byte{0xcc, byte(i)})
+	case i >= 0 && i <= math.MaxUint16:
+		w.WriteByte(0xcd)
+		binary.Write(w, binary.BigEndian, uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		w.WriteByte(0xce)
+		binary.Write(w, binary.BigEndian, uint32(i))
+	case i >= 0:
+		w.WriteByte(0xcf)
+		binary.Write(w, binary.BigEndian, uint64(i))
+	case i >= math.MinInt8:
+		w.Write([]byte{0xd0, byte(i)})
+	case i >= math.MinInt16:
+		w.WriteByte(0xd1)
+		binary.Write(w, binary.BigEndian, int16(i))
+	case i >= math.MinInt32:
+		w.WriteByte(0xd2)
+		binary.Write(w, binary.BigEndian, int32(i))
+	default:
+		w.WriteByte(0xd3)
+		binary.Write(w, binary.BigEndian, i)
+	}
+}
+
+// writeMsgpackHeader writes the type and length of a string, array or map: fixed when n fits in fixMax,
+// else with an 8, 16 or 32 bit length (code8 is 0 for types without an 8 bit form)
+func writeMsgpackHeader(w *bufio.Writer, n int, fix byte, fixMax int, code8 byte, code16 byte, code32 byte) {
+	switch {
+	case n <= fixMax:
+		w.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		w.Write([]byte{code8, byte(n)})
+	case n <= math.MaxUint16:
+		w.WriteByte(code16)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(code32)
+		binary.Write(w, binary.BigEndian, uint32(n))
+	}
+}
+
+// maxMsgpackLength bounds the lengths read from a request so a few bytes cannot claim gigabytes
+const maxMsgpackLength = 1 << 20
+
+// maxMsgpackDepth bounds the nesting of arrays and maps, deeper input would overflow the stack of the decoder
+const maxMsgpackDepth = 100
+
+var (
+	errMsgpackLength = errors.New("msgpack: length too large")
+	errMsgpackDepth  = errors.New("msgpack: nested too deep")
+)
+
+// readMsgpack decodes one MessagePack value into nil, bool, int64, uint64, float64, string, []interface{}
+// and map[string]interface{}, which encoding/json can marshal again
+func readMsgpack(r *bufio.Reader) (interface{}, error) {
+	return readMsgpackValue(r, 0)
+}
+
+// readMsgpackValue reads a value nested in depth arrays and maps
+func readMsgpackValue(r *bufio.Reader, depth int) (interface{}, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code >= 0xa0 && code <= 0xbf:
+		return readMsgpackString(r, int(code&0x1f))
+	case code >= 0x90 && code <= 0x9f:
+		return readMsgpackArray(r, int(code&0x0f), depth)
+	case code >= 0x80 && code <= 0x8f:
+		return readMsgpackMap(r, int(code&0x0f), depth)
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := readMsgpackUint(r, 1<<(code-0xcc))
+		return n, err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		n, err := readMsgpackUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		//sign extend from the width of the value
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, nil
+	case 0xca:
+		n, err := readMsgpackUint(r, 4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := readMsgpackUint(r, 8)
+		return math.Float64frombits(n), err
+	case 0xd9, 0xda, 0xdb, 0xc4, 0xc5, 0xc6:
+		size := 1
+		switch code {
+		case 0xda, 0xc5:
+			size = 2
+		case 0xdb, 0xc6:
+			size = 4
+		}
+		n, err := readMsgpackUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, int(n))
+	case 0xdc, 0xdd:
+		n, err := readMsgpackUint(r, 2<<(code-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackArray(r, int(n), depth)
+	case 0xde, 0xdf:
+		n, err := readMsgpackUint(r, 2<<(code-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackMap(r, int(n), depth)
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type 0x%02x", code)
+}
+
+func readMsgpackUint(r *bufio.Reader, size int) (uint64, error) {
+	buf := make([]byte, 8)
+	if _, err := io.ReadFull(r, buf[8-size:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+func readMsgpackString(r *bufio.Reader, n int) (string, error) {
+	if n > maxMsgpackLength {
+		return "", errMsgpackLength
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+func readMsgpackArray(r *bufio.Reader, n int, depth int) ([]interface{}, error) {
+	if n > maxMsgpackLength {
+		return nil, errMsgpackLength
+	}
+	if depth >= maxMsgpackDepth {
+		return nil, errMsgpackDepth
+	}
+	list := make([]interface{}, 0, minInt(n, 64))
+	for i := 0; i < n; i++ {
+		value, err := readMsgpackValue(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+func readMsgpackMap(r *bufio.Reader, n int, depth int) (map[string]interface{}, error) {
+	if n > maxMsgpackLength {
+		return nil, errMsgpackLength
+	}
+	if depth >= maxMsgpackDepth {
+		return nil, errMsgpackDepth
+	}
+	m := make(map[string]interface{}, minInt(n, 64))
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackValue(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readMsgpackValue(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(key)] = value
+	}
+	return m, nil
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,241 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Renderer encodes a response body in one media type
+type Renderer interface {
+	ContentType() string
+	Render(w io.Writer, v interface{}) error
+}
+
+type jsonRenderer struct {
+	pretty bool
+}
+
+func (j jsonRenderer) ContentType() string {
+	return "application/json"
+}
+
+func (j jsonRenderer) Render(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	if j.pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
+
+type msgpackRenderer struct{}
+
+func (msgpackRenderer) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackRenderer) Render(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+	return writeMsgpack(w, tree)
+}
+
+type xmlRenderer struct{}
+
+func (xmlRenderer) ContentType() string {
+	return "application/xml"
+}
+
+func (xmlRenderer) Render(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+	return writeXML(w, "response", tree)
+}
+
+var (
+	JSONRenderer       Renderer = jsonRenderer{}
+	PrettyJSONRenderer Renderer = jsonRenderer{pretty: true}
+	MsgpackRenderer    Renderer = msgpackRenderer{}
+	XMLRenderer        Renderer = xmlRenderer{}
+)
+
+// renderTypes are the media types a response can be rendered in, the first one wins a tie
+var renderTypes = []struct {
+	mediaType string
+	renderer  Renderer
+}{
+	{"application/json", JSONRenderer},
+	{"application/msgpack", MsgpackRenderer},
+	{"application/x-msgpack", MsgpackRenderer},
+	{"application/vnd.msgpack", MsgpackRenderer},
+	{"application/xml", XMLRenderer},
+	{"text/xml", XMLRenderer},
+}
+
+type mediaRange struct {
+	mediaType string
+	params    map[string]string
+	q         float64
+}
+
+// matches reports how specific the range is for mediaType: 3 for the type itself, 2 for type/*, 1 for */*
+// and 0 when it does not match
+func (m mediaRange) matches(mediaType string) int {
+	switch {
+	case m.mediaType == mediaType:
+		return 3
+	case m.mediaType == "*/*":
+		return 1
+	case strings.HasSuffix(m.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(m.mediaType, "*")):
+		return 2
+	}
+	return 0
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, params: params, q: q})
+	}
+	return ranges
+}
+
+// GetRenderer picks the renderer for the Accept header of r. Every type is weighed by the most specific range
+// naming it, e.g. "*/*;q=0.5, application/xml" prefers XML. JSON is indented for "application/json;pretty=true".
+// The second value is false when none of the types is acceptable; a request without Accept gets JSON.
+func GetRenderer(r *http.Request) (Renderer, bool) {
+	accept := strings.TrimSpace(r.Header.Get("Accept"))
+	if accept == "" {
+		return JSONRenderer, true
+	}
+	ranges := parseAccept(accept)
+
+	var best Renderer
+	var bestRange mediaRange
+	bestQ := 0.0
+	for _, candidate := range renderTypes {
+		specificity, weight := 0, mediaRange{}
+		for _, m := range ranges {
+			if s := m.matches(candidate.mediaType); s > specificity {
+				specificity, weight = s, m
+			}
+		}
+		if specificity > 0 && weight.q > bestQ {
+			best, bestRange, bestQ = candidate.renderer, weight, weight.q
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	if best == JSONRenderer && bestRange.mediaType == "application/json" {
+		if pretty, _ := strconv.ParseBool(bestRange.params["pretty"]); pretty {
+			return PrettyJSONRenderer, true
+		}
+	}
+	return best, true
+}
+
+// RequireAcceptable answers 406 before next runs when the Accept header names no type a response can be
+// rendered in, so nothing is changed for a client that cannot read the answer. Requests to the exempt paths,
+// or below them for paths ending with "/", are passed on as they negotiate their own formats.
+func RequireAcceptable(next http.Handler, exempt ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range exempt {
+			if r.URL.Path == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if _, ok := GetRenderer(r); !ok {
+			var res *Response
+			res.JSON(w, false, GetResCode(NOT_ACCEPTABLE), "Accept must allow one of "+strings.Join(RenderTypes(), ", "), nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RenderTypes lists the media types a response can be rendered in
+func RenderTypes() []string {
+	types := make([]string, len(renderTypes))
+	for i, candidate := range renderTypes {
+		types[i] = candidate.mediaType
+	}
+	return types
+}
+
+// toTree turns v into the values JSON would give it, with the keys of objects kept in their order so the
+// other encoders name things the same way as the JSON responses do
+func toTree(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return readTree(decoder)
+}
+
+type member struct {
+	Key   string
+	Value interface{}
+}
+
+// object is a JSON object with its keys in order
+type object []member
+
+func readTree(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{Key: key.(string), Value: value})
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := readTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+	return token, nil
+}
@@ -0,0 +1,227 @@
+package util_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ranggabudipangestu/simple-ecommerce/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type detail struct {
+	ProductId int     `json:"productId"`
+	Qty       int     `json:"qty"`
+	Price     float32 `json:"price"`
+}
+
+type order struct {
+	Customer string   `json:"customer"`
+	Paid     bool     `json:"paid"`
+	Balance  int      `json:"balance"`
+	Tags     []string `json:"tags"`
+	Details  []detail `json:"details"`
+}
+
+func TestGetRenderer(t *testing.T) {
+	cases := []struct {
+		accept   string
+		renderer util.Renderer
+	}{
+		{"", util.JSONRenderer},
+		{"application/json", util.JSONRenderer},
+		{"application/json; pretty=true", util.PrettyJSONRenderer},
+		{"application/xml", util.XMLRenderer},
+		{"text/*", util.XMLRenderer},
+		{"application/x-msgpack", util.MsgpackRenderer},
+		{"*/*;q=0.5, application/xml", util.XMLRenderer},
+		{"application/json;q=0, */*", util.MsgpackRenderer},
+		{"text/html, application/xml;q=0.9, */*;q=0.8", util.XMLRenderer},
+		{"text/html", nil},
+		{"application/json;q=0", nil},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/brand", nil)
+		req.Header.Set("Accept", c.accept)
+
+		renderer, ok := util.GetRenderer(req)
+		assert.Equal(t, c.renderer != nil, ok, c.accept)
+		assert.Equal(t, c.renderer, renderer, c.accept)
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := order{Customer: "a&b", Paid: true, Balance: -300, Tags: []string{}, Details: []detail{{ProductId: 1, Qty: 2, Price: 1.5}}}
+
+	t.Run("Test Render Xml", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/order", nil)
+		req.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+
+		var res *util.Response
+		err := res.Render(w, req, true, http.StatusOK, "success", data)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<response><success>true</success><statusCode>200</statusCode>`+
+			`<message>success</message><data><customer>a&amp;b</customer><paid>true</paid><balance>-300</balance><tags></tags>`+
+			`<details><item><productId>1</productId><qty>2</qty><price>1.5</price></item></details></data></response>`, w.Body.String())
+	})
+
+	t.Run("Test Render Pretty Json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/order", nil)
+		req.Header.Set("Accept", "application/json;pretty=true")
+		w := httptest.NewRecorder()
+
+		var res *util.Response
+		err := res.Render(w, req, false, http.StatusNotFound, "not found", nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "{\n  \"success\": false,\n  \"statusCode\": 404,\n  \"message\": \"not found\",\n  \"data\": null\n}\n", w.Body.String())
+	})
+
+	t.Run("Test Render Msgpack Decodes Back", func(t *testing.T) {
+		big := order{Customer: strings.Repeat("x", 300), Balance: 1 << 40, Tags: []string{"a", "b"}, Details: data.Details}
+		var body bytes.Buffer
+		err := util.MsgpackRenderer.Render(&body, big)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/order", &body)
+		req.Header.Set("Content-Type", "application/msgpack")
+		var decoded order
+		err = util.Decode(req, &decoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, big, decoded)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("Test Decode Xml", func(t *testing.T) {
+		body := `<order><customer>a</customer><paid>true</paid><balance> 12 </balance><tags><item>new</item></tags>` +
+			`<details><item><productId>1</productId><qty>2</qty></item><item><productId>3</productId><qty>1</qty><price>9.5</price></item></details></order>`
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+		var decoded order
+		err := util.Decode(req, &decoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, order{Customer: "a", Paid: true, Balance: 12, Tags: []string{"new"}, Details: []detail{{ProductId: 1, Qty: 2}, {ProductId: 3, Qty: 1, Price: 9.5}}}, decoded)
+	})
+
+	t.Run("Test Decode Xml Invalid Number", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`<order><balance>many</balance></order>`))
+		req.Header.Set("Content-Type", "text/xml")
+
+		var decoded order
+		err := util.Decode(req, &decoded)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "SYSTEM_ERROR", util.GetDecodeState(err))
+	})
+
+	t.Run("Test Decode Without Content Type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"customer":"a"}`))
+
+		var decoded order
+		err := util.Decode(req, &decoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "a", decoded.Customer)
+	})
+
+	t.Run("Test Decode Unsupported Content Type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`customer=a`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var decoded order
+		err := util.Decode(req, &decoded)
+
+		assert.True(t, errors.Is(err, util.ErrUnsupportedMediaType))
+		assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", util.GetDecodeState(err))
+	})
+
+	t.Run("Test Decode Msgpack Nested Too Deep", func(t *testing.T) {
+		//fixarrays of one item down to a nil, past the depth the decoder takes
+		body := append(bytes.Repeat([]byte{0x91}, 10000), 0xc0)
+		req := httptest.NewRequest(http.MethodPost, "/order", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/msgpack")
+
+		var decoded interface{}
+		err := util.Decode(req, &decoded)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", util.GetDecodeState(err))
+	})
+
+	t.Run("Test Decode Msgpack Nested Within Depth", func(t *testing.T) {
+		body := append(bytes.Repeat([]byte{0x91}, 50), 0xc0)
+		req := httptest.NewRequest(http.MethodPost, "/order", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/msgpack")
+
+		var decoded interface{}
+		err := util.Decode(req, &decoded)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Test Decode Xml Nested Too Deep", func(t *testing.T) {
+		body := strings.Repeat("<a>", 10000) + strings.Repeat("</a>", 10000)
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/xml")
+
+		var decoded interface{}
+		err := util.Decode(req, &decoded)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", util.GetDecodeState(err))
+	})
+
+	t.Run("Test Decode Body Too Large", func(t *testing.T) {
+		body := `{"customer":"` + strings.Repeat("x", util.MaxBodySize) + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(body))
+
+		var decoded order
+		err := util.Decode(req, &decoded)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", util.GetDecodeState(err))
+	})
+}
+
+func TestRequireAcceptable(t *testing.T) {
+	called := false
+	handler := util.RequireAcceptable(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), "/order/export", "/media/files/")
+
+	cases := []struct {
+		path   string
+		accept string
+		called bool
+		code   int
+	}{
+		{"/order", "text/html", false, http.StatusNotAcceptable},
+		{"/order", "text/html, */*;q=0.1", true, http.StatusOK},
+		{"/order/export", "text/csv", true, http.StatusOK},
+		{"/media/files/products/1/a/small.jpg", "image/jpeg", true, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		called = false
+		req := httptest.NewRequest(http.MethodPost, c.path, nil)
+		req.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, c.called, called, c.path+" "+c.accept)
+		assert.Equal(t, c.code, w.Code, c.path+" "+c.accept)
+	}
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const ActorHeader = "X-Actor"
+
+// RequestIdHeader carries the id of a request, given by the client or made up by WithRequestContext
+const RequestIdHeader = "X-Request-Id"
+
+// SystemActor is the actor of the changes made outside of a request
+const SystemActor = "system"
+
+// AnonymousActor is the actor of a request sent without the X-Actor header
+const AnonymousActor = "anonymous"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIdKey
+)
+
+// GetActor returns who is performing the request, taken from the X-Actor header.
+func GetActor(r *http.Request) string {
+	actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+	if actor == "" {
+		return AnonymousActor
+	}
+	return actor
+}
+
+// GetPathId reads the id segment that follows prefix in path, e.g. "/order/12/cancel"
+// with prefix "/order/" returns 12 and "cancel".
+func GetPathId(path string, prefix string) (id int, rest string) {
+	segments := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
+	id, _ = strconv.Atoi(segments[0])
+	if len(segments) > 1 {
+		rest = strings.Trim(segments[1], "/")
+	}
+	return id, rest
+}
+
+// WithRequestContext keeps the actor and the id of the request in its context, for the code below the handlers
+// to read with ActorOf and RequestIdOf. The id is taken from the X-Request-Id header, or made up when missing,
+// and sent back in the response.
+func WithRequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := strings.TrimSpace(r.Header.Get(RequestIdHeader))
+		if requestId == "" || len(requestId) > 64 {
+			requestId = newRequestId()
+		}
+		w.Header().Set(RequestIdHeader, requestId)
+
+		ctx := context.WithValue(r.Context(), actorKey, GetActor(r))
+		ctx = context.WithValue(ctx, requestIdKey, requestId)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ActorOf returns the actor of the request ctx comes from, SystemActor when it does not come from one
+func ActorOf(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok {
+		return actor
+	}
+	return SystemActor
+}
+
+// RequestIdOf returns the id of the request ctx comes from, empty when it does not come from one
+func RequestIdOf(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey).(string)
+	return requestId
+}
+
+func newRequestId() string {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(random)
+}
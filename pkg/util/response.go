@@ -1,7 +1,7 @@
 package util
 
 import (
-	"encoding/json"
+	"bytes"
 	"net/http"
 )
 
@@ -12,7 +12,22 @@ type Response struct {
 	Data       interface{} `json:"data"`
 }
 
+// JSON writes the response as JSON whatever the request accepts
 func (r *Response) JSON(w http.ResponseWriter, success bool, statusCode int, message string, data interface{}) error {
+	return r.write(w, JSONRenderer, success, statusCode, message, data)
+}
+
+// Render writes the response in the format the Accept header of req asks for, see GetRenderer. It falls back
+// to JSON when nothing is acceptable, RequireAcceptable having turned such requests away already.
+func (r *Response) Render(w http.ResponseWriter, req *http.Request, success bool, statusCode int, message string, data interface{}) error {
+	renderer, ok := GetRenderer(req)
+	if !ok {
+		renderer = JSONRenderer
+	}
+	return r.write(w, renderer, success, statusCode, message, data)
+}
+
+func (r *Response) write(w http.ResponseWriter, renderer Renderer, success bool, statusCode int, message string, data interface{}) error {
 	res := &Response{
 		Success:    success,
 		StatusCode: statusCode,
@@ -20,13 +35,15 @@ func (r *Response) JSON(w http.ResponseWriter, success bool, statusCode int, mes
 		Data:       data,
 	}
 
-	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	encoder := json.NewEncoder(w)
-	err := encoder.Encode(res)
-	if err != nil {
+	//encoded before the header is sent so a failure still gets a status of its own
+	var body bytes.Buffer
+	if err := renderer.Render(&body, res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return err
 	}
 
-	return nil
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.WriteHeader(statusCode)
+	_, err := body.WriteTo(w)
+	return err
 }
@@ -10,6 +10,11 @@ const (
 	NOT_FOUND        = "NOT_FOUND"
 	SYSTEM_ERROR     = "SYSTEM_ERROR"
 	VALIDATION_ERROR = "VALIDATION_ERROR"
+	INVALID_STATE    = "INVALID_STATE"
+	UNAUTHORIZED     = "UNAUTHORIZED"
+
+	NOT_ACCEPTABLE         = "NOT_ACCEPTABLE"
+	UNSUPPORTED_MEDIA_TYPE = "UNSUPPORTED_MEDIA_TYPE"
 )
 
 func GetResCode(state string) int {
@@ -23,6 +28,14 @@ func GetResCode(state string) int {
 		code = http.StatusNotFound
 	case VALIDATION_ERROR:
 		code = http.StatusBadRequest
+	case INVALID_STATE:
+		code = http.StatusConflict
+	case UNAUTHORIZED:
+		code = http.StatusUnauthorized
+	case NOT_ACCEPTABLE:
+		code = http.StatusNotAcceptable
+	case UNSUPPORTED_MEDIA_TYPE:
+		code = http.StatusUnsupportedMediaType
 	default:
 		code = http.StatusInternalServerError
 	}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// xmlItem names the elements of an array
+const xmlItem = "item"
+
+// writeXML encodes a tree made by toTree as XML. Keys become element names, array values are item elements
+// and null is an empty element, e.g. {"data":[1]} is <response><data><item>1</item></data></response>.
+// Keys that are not valid names are written as <entry key="...">.
+func writeXML(w io.Writer, root string, tree interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if err := encodeXML(encoder, xml.StartElement{Name: xml.Name{Local: root}}, tree); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func encodeXML(encoder *xml.Encoder, start xml.StartElement, v interface{}) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	var err error
+	switch value := v.(type) {
+	case nil:
+	case bool:
+		err = encoder.EncodeToken(xml.CharData(fmt.Sprint(value)))
+	case json.Number:
+		err = encoder.EncodeToken(xml.CharData(value.String()))
+	case string:
+		err = encoder.EncodeToken(xml.CharData(value))
+	case []interface{}:
+		for _, item := range value {
+			if err = encodeXML(encoder, xml.StartElement{Name: xml.Name{Local: xmlItem}}, item); err != nil {
+				return err
+			}
+		}
+	case object:
+		for _, m := range value {
+			if err = encodeXML(encoder, xmlElement(m.Key), m.Value); err != nil {
+				return err
+			}
+		}
+	default:
+		err = fmt.Errorf("xml: cannot encode %T", v)
+	}
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+func xmlElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// readXML decodes the root element of an XML document into strings and map[string]interface{}, the way
+// writeXML writes them. Repeated elements are collected in a []interface{}; the types the values should
+// have are only known to the target, see coerce.
+func readXML(r io.Reader) (interface{}, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return readXMLElement(decoder, start, 0)
+		}
+	}
+}
+
+// maxXMLDepth bounds the nesting of elements like maxMsgpackDepth does for MessagePack
+const maxXMLDepth = 100
+
+var errXMLDepth = errors.New("xml: nested too deep")
+
+func readXMLElement(decoder *xml.Decoder, start xml.StartElement, depth int) (interface{}, error) {
+	if depth >= maxXMLDepth {
+		return nil, errXMLDepth
+	}
+
+	var text []byte
+	var children map[string]interface{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.StartElement:
+			value, err := readXMLElement(decoder, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			key := t.Name.Local
+			for _, attr := range t.Attr {
+				if key == "entry" && attr.Name.Local == "key" {
+					key = attr.Value
+				}
+			}
+			if children == nil {
+				children = map[string]interface{}{}
+			}
+			switch existing := children[key].(type) {
+			case nil:
+				children[key] = value
+			case repeated:
+				children[key] = append(existing, value)
+			default:
+				children[key] = repeated{existing, value}
+			}
+		case xml.EndElement:
+			if children != nil {
+				for key, value := range children {
+					if list, ok := value.(repeated); ok {
+						children[key] = []interface{}(list)
+					}
+				}
+				return children, nil
+			}
+			return string(text), nil
+		}
+	}
+}
+
+// repeated collects the values of an element that occurs more than once
+type repeated []interface{}